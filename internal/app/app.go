@@ -1,18 +1,26 @@
 package app
 
 import (
+	hydrometer_model "brewday/internal/hydrometer"
+	inventory_model "brewday/internal/inventory"
 	"brewday/internal/recipe"
 	"brewday/internal/routers/common"
 	"brewday/internal/routers/cooling"
+	"brewday/internal/routers/equipment"
 	"brewday/internal/routers/fermentation"
 	"brewday/internal/routers/hopping"
+	"brewday/internal/routers/hydrometer"
 	"brewday/internal/routers/import_recipe"
+	"brewday/internal/routers/inventory"
 	"brewday/internal/routers/lautern"
 	"brewday/internal/routers/mash"
 	"brewday/internal/routers/recipes"
 	secondaryferm "brewday/internal/routers/secondary_ferm"
 	"brewday/internal/routers/stats"
 	summary "brewday/internal/routers/summary"
+	"brewday/internal/routers/temperature"
+	temperature_model "brewday/internal/temperature"
+	"brewday/internal/tools"
 	"context"
 	"encoding/json"
 	"errors"
@@ -40,11 +48,25 @@ type App struct {
 	TLStore     TimelineStore
 	notifier    Notifier
 	recipeStore RecipeStore
+	// background is cancelled to stop the background work of the routers
+	background context.Context
+	cancel     context.CancelFunc
 }
 
 type ProcessConfiguration struct {
 	LauternRestTimeMin int
 	RefractometerWCF   float32
+	SourceWater        tools.WaterProfile
+	GrainTemperature   float32
+	TunThermalMass     float32
+	MashHeating        tools.MashHeating
+	Hydrometers        []hydrometer_model.Device
+	// FermentationCompletion are the conditions for the gravity to be stable at the end of the main fermentation
+	FermentationCompletion recipe.CompletionCriteria
+	// TemperatureSensors are the sensors of the fermentation temperature
+	TemperatureSensors []temperature_model.Sensor
+	// TemperatureTolerance is how many °C the fermentation temperature can differ from its target before an alert
+	TemperatureTolerance float32
 }
 
 // AppComponents is the structure that contains the external components of the application
@@ -54,6 +76,10 @@ type AppComponents struct {
 	Notifier     Notifier
 	Store        RecipeStore
 	SummaryStore SummaryStore
+	Inventory    InventoryStore
+	Equipment    EquipmentStore
+	Hydrometers  HydrometerStore
+	Temperatures TemperatureStore
 	Config       ProcessConfiguration
 }
 
@@ -72,6 +98,7 @@ func NewApp(staticFS fs.FS, components *AppComponents) (*App, error) {
 // Initialize initializes the application
 func (a *App) Initialize(components *AppComponents) error {
 	a.server = echo.New()
+	a.background, a.cancel = context.WithCancel(context.Background())
 	// Register global middlewares
 	a.server.Use(middleware.Recover())
 	// Initialize internal components
@@ -81,18 +108,36 @@ func (a *App) Initialize(components *AppComponents) error {
 	a.notifier = components.Notifier
 	ss := components.SummaryStore
 	timer := common.NewTimer(a.recipeStore, a.TLStore, a.notifier)
+	fermentationRouter := &fermentation.FermentationRouter{
+		TLStore:              a.TLStore,
+		SummaryStore:         ss,
+		Store:                a.recipeStore,
+		Notifier:             a.notifier,
+		RefractometerWCF:     components.Config.RefractometerWCF,
+		Equipment:            components.Equipment,
+		Completion:           components.Config.FermentationCompletion,
+		Temperatures:         components.Temperatures,
+		TemperatureTolerance: components.Config.TemperatureTolerance,
+	}
 	// Register routers
 	a.routers = []common.Router{
 		&import_recipe.ImportRouter{
 			Store:                a.recipeStore,
 			SummaryRecorderStore: ss,
 			TLStore:              a.TLStore,
+			Equipment:            components.Equipment,
 		},
 		&mash.MashRouter{
-			Store:        a.recipeStore,
-			TLStore:      a.TLStore,
-			SummaryStore: ss,
-			Timer:        timer,
+			Store:            a.recipeStore,
+			TLStore:          a.TLStore,
+			SummaryStore:     ss,
+			Timer:            timer,
+			Inventory:        components.Inventory,
+			Equipment:        components.Equipment,
+			SourceWater:      components.Config.SourceWater,
+			GrainTemperature: components.Config.GrainTemperature,
+			TunThermalMass:   components.Config.TunThermalMass,
+			MashHeating:      components.Config.MashHeating,
 		},
 		&lautern.LauternRouter{
 			Store:           a.recipeStore,
@@ -106,20 +151,17 @@ func (a *App) Initialize(components *AppComponents) error {
 			TLStore:      a.TLStore,
 			SummaryStore: ss,
 			Timer:        timer,
+			Inventory:    components.Inventory,
+			Equipment:    components.Equipment,
 		},
 		&cooling.CoolingRouter{
 			Store:        a.recipeStore,
 			TLStore:      a.TLStore,
 			SummaryStore: ss,
 			Timer:        timer,
+			Equipment:    components.Equipment,
 		},
-		&fermentation.FermentationRouter{
-			TLStore:          a.TLStore,
-			SummaryStore:     ss,
-			Store:            a.recipeStore,
-			Notifier:         a.notifier,
-			RefractometerWCF: components.Config.RefractometerWCF,
-		},
+		fermentationRouter,
 		&secondaryferm.SecondaryFermentationRouter{
 			TLStore:      a.TLStore,
 			SummaryStore: ss,
@@ -132,11 +174,35 @@ func (a *App) Initialize(components *AppComponents) error {
 		},
 		&recipes.RecipesRouter{
 			Store:        a.recipeStore,
+			Library:      a.recipeStore,
 			TLStore:      a.TLStore,
 			SummaryStore: ss,
+			Inventory:    components.Inventory,
+			Equipment:    components.Equipment,
 		},
 		&stats.StatsRouter{
 			StatsStore: ss,
+			Equipment:  components.Equipment,
+		},
+		&inventory.InventoryRouter{
+			Store: components.Inventory,
+		},
+		&equipment.EquipmentRouter{
+			Store: components.Equipment,
+		},
+		&hydrometer.HydrometerRouter{
+			Store:           a.recipeStore,
+			HydrometerStore: components.Hydrometers,
+			Devices:         components.Config.Hydrometers,
+			Completion:      fermentationRouter,
+		},
+		&temperature.TemperatureRouter{
+			Store:            a.recipeStore,
+			TemperatureStore: components.Temperatures,
+			Sensors:          components.Config.TemperatureSensors,
+			Targets:          fermentationRouter,
+			TLStore:          a.TLStore,
+			Notifier:         a.notifier,
 		},
 	}
 	a.RegisterStaticFiles()
@@ -176,6 +242,22 @@ func (a *App) RegisterTemplates() error {
 		b, err := json.Marshal(o)
 		return template.JS(b), err
 	})
+	a.renderer.AddFunc("formatAmount", inventory_model.FormatAmount)
+	// dict builds a map from key and value pairs, to pass several values to a sub-template
+	a.renderer.AddFunc("dict", func(pairs ...any) (map[string]any, error) {
+		if len(pairs)%2 != 0 {
+			return nil, errors.New("dict needs pairs of keys and values")
+		}
+		d := make(map[string]any, len(pairs)/2)
+		for i := 0; i < len(pairs); i += 2 {
+			key, ok := pairs[i].(string)
+			if !ok {
+				return nil, errors.New("dict keys must be strings")
+			}
+			d[key] = pairs[i+1]
+		}
+		return d, nil
+	})
 
 	fs := echo.MustSubFS(a.staticFs, "web/template")
 	err := a.renderer.RegisterTemplates(fs)
@@ -220,16 +302,30 @@ func (a *App) CheckWatchers() error {
 	return nil
 }
 
+// StartBackground starts the background work of the routers, until the application stops
+func (a *App) StartBackground() {
+	for _, router := range a.routers {
+		br, ok := router.(common.BackgroundRouter)
+		if ok {
+			br.Start(a.background)
+		}
+	}
+}
+
 // Run starts the application
 func (a *App) Run(address string) error {
 	err := a.CheckWatchers()
 	if err != nil {
 		return err
 	}
+	a.StartBackground()
 	return a.server.Start(address)
 }
 
 // Stop stops the application
 func (a *App) Stop(ctx context.Context) error {
+	if a.cancel != nil {
+		a.cancel()
+	}
 	return a.server.Shutdown(ctx)
 }
@@ -1,8 +1,11 @@
 package app
 
 import (
+	"brewday/internal/equipment"
+	"brewday/internal/inventory"
 	"brewday/internal/recipe"
 	"brewday/internal/summary"
+	"brewday/internal/temperature"
 	"io"
 	"io/fs"
 	"time"
@@ -50,6 +53,8 @@ type RecipeStore interface {
 	List() ([]*recipe.Recipe, error)
 	// Delete deletes a recipe based on an identifier
 	Delete(id string) error
+	// Update replaces the definition of a stored recipe, keeping its status and results
+	Update(id string, r *recipe.Recipe) error
 	// UpdateStatus updates the status of a recipe in the store
 	UpdateStatus(id string, status recipe.RecipeStatus, statusParams ...string) error
 	// UpdateResult updates a certain result of a recipe
@@ -75,6 +80,14 @@ type RecipeStore interface {
 	AddBoolFlag(id, name string, flag bool) error
 	// RetrieveBoolFlag gets a bool flag from the store given its name
 	RetrieveBoolFlag(id, name string) (bool, error)
+	// AddToLibrary stores a copy of the recipe definition in the library and returns its identifier in the library
+	AddToLibrary(r *recipe.Recipe) (string, error)
+	// ListLibrary lists all the recipes in the library
+	ListLibrary() ([]*recipe.Recipe, error)
+	// RetrieveFromLibrary retrieves a recipe from the library based on its identifier
+	RetrieveFromLibrary(id string) (*recipe.Recipe, error)
+	// DeleteFromLibrary deletes a recipe from the library
+	DeleteFromLibrary(id string) error
 }
 
 // SummaryStore is the interface that helps decouple the summary store from the application
@@ -87,10 +100,13 @@ type SummaryStore interface {
 	AddLauternNotes(id, notes string, duration float32) error
 	AddHopping(id string, name string, amount float32, alpha float32, duration float32, notes string) error
 	AddVolumeBeforeBoil(id string, amount float32, notes string) error
+	AddBoilAdjustment(id string, adjustment *summary.BoilAdjustment) error
 	AddVolumeAfterBoil(id string, amount float32, notes string) error
 	AddCooling(id string, finalTemp, coolingTime float32, notes string) error
 	AddPreFermentationVolume(id string, volume float32, sg float32, notes string) error
 	AddYeastStart(id string, temperature, notes string) error
+	AddYeastPitch(id string, pitch *summary.YeastPitch) error
+	AddYeastExpectation(id string, expectation *summary.YeastExpectation) error
 	AddMainFermentationSGMeasurement(id string, date string, gravity float32, final bool, notes string) error
 	AddMainFermentationAlcohol(id string, alcohol float32) error
 	AddDryHopStart(id string, name string, amount, alpha float32, notes string) error
@@ -102,10 +118,76 @@ type SummaryStore interface {
 	AddEvaporation(id string, amount float32) error
 	AddEfficiency(id string, efficiencyPercentage float32) error
 	GetSummary(id string) (*summary.Summary, error)
-	GetAllStats() (map[string]*summary.Statistics, error)
+	GetAllStats() ([]*summary.Statistics, error)
 	AddStatsExternal(recipeName string, stats *summary.Statistics) error
 }
 
+// InventoryStore is the interface that helps decouple the inventory store from the application
+// It represents a store of the ingredients in stock
+type InventoryStore interface {
+	// AddItem adds an item to the inventory and returns its identifier
+	AddItem(item *inventory.Item) (string, error)
+	// UpdateItem replaces an item of the inventory, the identifier of the item is used to find it
+	UpdateItem(item *inventory.Item) error
+	// ListItems lists all the items of the inventory
+	ListItems() ([]*inventory.Item, error)
+	// DeleteItem deletes an item from the inventory
+	DeleteItem(id string) error
+	// Consume deducts the given amount (in grams) of an ingredient and returns the amount that was not in stock
+	Consume(t inventory.IngredientType, name string, amount float32) (float32, error)
+}
+
+// EquipmentStore is the interface that helps decouple the equipment store from the application
+// It represents a store of the equipment profiles and the profile used in each brew
+type EquipmentStore interface {
+	// AddProfile adds an equipment profile and returns its identifier
+	AddProfile(p *equipment.Profile) (string, error)
+	// UpdateProfile replaces an equipment profile, the identifier of the profile is used to find it
+	UpdateProfile(p *equipment.Profile) error
+	// ListProfiles lists all the equipment profiles
+	ListProfiles() ([]*equipment.Profile, error)
+	// RetrieveProfile retrieves an equipment profile based on its identifier
+	RetrieveProfile(id string) (*equipment.Profile, error)
+	// DeleteProfile deletes an equipment profile
+	DeleteProfile(id string) error
+	// SetActiveProfile makes a profile the one used by default. An empty id leaves no profile active
+	SetActiveProfile(id string) error
+	// RetrieveActiveProfile retrieves the profile used by default, nil if no profile is active
+	RetrieveActiveProfile() (*equipment.Profile, error)
+	// SetBrewProfile selects the equipment profile used to brew a recipe. An empty profile id removes the selection
+	SetBrewProfile(recipeID, profileID string) error
+	// RetrieveBrewProfile retrieves the equipment profile used to brew a recipe, nil if none was selected
+	RetrieveBrewProfile(recipeID string) (*equipment.Profile, error)
+}
+
+// HydrometerStore is the interface that helps decouple the hydrometer store from the application
+// It represents a store of the recipe each wireless hydrometer is assigned to
+type HydrometerStore interface {
+	// AssignDevice assigns a hydrometer to the recipe whose fermentation it measures. An empty recipe id removes the assignment
+	AssignDevice(device, recipeID string) error
+	// RetrieveDeviceRecipe retrieves the id of the recipe a hydrometer is assigned to, empty if it is not assigned
+	RetrieveDeviceRecipe(device string) (string, error)
+	// ListAssignments lists the recipe ids of the assigned hydrometers, by device
+	ListAssignments() (map[string]string, error)
+}
+
+// TemperatureStore is the interface that helps decouple the temperature store from the application
+// It represents a store of the recipe each temperature sensor is assigned to and of their readings
+type TemperatureStore interface {
+	// AssignSensor assigns a temperature sensor to the recipe whose fermentation it measures. An empty recipe id removes the assignment
+	AssignSensor(sensor, recipeID string) error
+	// RetrieveSensorRecipe retrieves the id of the recipe a temperature sensor is assigned to, empty if it is not assigned
+	RetrieveSensorRecipe(sensor string) (string, error)
+	// ListAssignments lists the recipe ids of the assigned temperature sensors, by sensor
+	ListAssignments() (map[string]string, error)
+	// AddReading adds a temperature reading to a recipe
+	AddReading(recipeID string, reading *temperature.Reading) error
+	// RetrieveReadings retrieves the temperature readings of a recipe, from the oldest to the newest
+	RetrieveReadings(recipeID string) ([]*temperature.Reading, error)
+	// RetrieveLastReading retrieves the newest temperature reading of a recipe, nil if there is none
+	RetrieveLastReading(recipeID string) (*temperature.Reading, error)
+}
+
 // ReqPostTimelineEvent represents the request body for the postTimelineEvent
 type ReqPostTimelineEvent struct {
 	Message string `json:"message" form:"message"`
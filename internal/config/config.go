@@ -19,6 +19,14 @@ const EnvPrefix = "BREWDAY_"
 var defaultValues = map[string]any{
 	"process.lautern-rest-time-min": 15,
 	"process.refractometer-wcf":     1.00,
+	"process.grain-temperature":     20,
+	"process.mash-heating":          "direct",
+	"process.stable-readings":       3,
+	"process.stable-days":           2,
+	"process.stable-tolerance":      0.001,
+	"process.stable-slope":          0.0002,
+	"process.stable-attenuation":    50,
+	"process.temperature-tolerance": 1,
 }
 
 // LoadConfig loads the configuration from the given path.
@@ -108,6 +116,62 @@ func validateConfig(config *Config) error {
 			return fmt.Errorf("invalid notification type %s", config.Notification.Type)
 		}
 	}
+	switch config.Process.MashHeating {
+	case "", "direct", "infusion", "decoction":
+	default:
+		return fmt.Errorf("invalid mash heating %s", config.Process.MashHeating)
+	}
+	names := make(map[string]bool, len(config.Hydrometers))
+	for _, h := range config.Hydrometers {
+		name := strings.ToLower(strings.TrimSpace(h.Name))
+		if name == "" {
+			return fmt.Errorf("hydrometer name is missing")
+		}
+		if names[name] {
+			return fmt.Errorf("duplicated hydrometer %s", h.Name)
+		}
+		names[name] = true
+		switch h.Type {
+		case "ispindel", "tilt":
+		default:
+			return fmt.Errorf("invalid hydrometer type %s for %s", h.Type, h.Name)
+		}
+		switch h.Unit {
+		case "", "sg", "plato":
+		default:
+			return fmt.Errorf("invalid gravity unit %s for %s", h.Unit, h.Name)
+		}
+	}
+	sensors := make(map[string]bool, len(config.TemperatureSensors))
+	for _, s := range config.TemperatureSensors {
+		name := strings.ToLower(strings.TrimSpace(s.Name))
+		if name == "" {
+			return fmt.Errorf("temperature sensor name is missing")
+		}
+		if sensors[name] {
+			return fmt.Errorf("duplicated temperature sensor %s", s.Name)
+		}
+		sensors[name] = true
+		switch s.Type {
+		case "http":
+		case "onewire":
+			if s.Path == "" {
+				return fmt.Errorf("1-Wire temperature sensor %s has no path", s.Name)
+			}
+		case "mqtt":
+			if s.Broker == "" || s.Topic == "" {
+				return fmt.Errorf("MQTT temperature sensor %s needs a broker and a topic", s.Name)
+			}
+		default:
+			return fmt.Errorf("invalid temperature sensor type %s for %s", s.Type, s.Name)
+		}
+	}
+	if config.Process.TemperatureTolerance < 0 {
+		return fmt.Errorf("temperature tolerance can not be negative")
+	}
+	if config.Process.StableReadings < 0 || config.Process.StableDays < 0 || config.Process.StableTolerance < 0 || config.Process.StableSlope < 0 || config.Process.StableAttenuation < 0 {
+		return fmt.Errorf("fermentation stability parameters can not be negative")
+	}
 	switch config.Store.StoreType {
 	case "sql":
 		if config.Store.Path == "" {
@@ -46,8 +46,16 @@ func TestConfig(t *testing.T) {
 					Path:      "./bd.sqlite",
 				},
 				Process: ProcessParameters{
-					LauternRestTimeMin: 15,
-					RefractometerWCF:   1.00,
+					LauternRestTimeMin:   15,
+					RefractometerWCF:     1.00,
+					GrainTemperature:     20,
+					MashHeating:          "direct",
+					StableReadings:       3,
+					StableDays:           2,
+					StableTolerance:      0.001,
+					StableSlope:          0.0002,
+					StableAttenuation:    50,
+					TemperatureTolerance: 1,
 				},
 			},
 			Error: false,
@@ -72,8 +80,16 @@ func TestConfig(t *testing.T) {
 					Path:      "./bd.sqlite",
 				},
 				Process: ProcessParameters{
-					LauternRestTimeMin: 15,
-					RefractometerWCF:   1.00,
+					LauternRestTimeMin:   15,
+					RefractometerWCF:     1.00,
+					GrainTemperature:     20,
+					MashHeating:          "direct",
+					StableReadings:       3,
+					StableDays:           2,
+					StableTolerance:      0.001,
+					StableSlope:          0.0002,
+					StableAttenuation:    50,
+					TemperatureTolerance: 1,
 				},
 			},
 			Error: false,
@@ -98,8 +114,25 @@ func TestConfig(t *testing.T) {
 					Path:      "./bd.sqlite",
 				},
 				Process: ProcessParameters{
-					LauternRestTimeMin: 10,
-					RefractometerWCF:   1.04,
+					LauternRestTimeMin:   10,
+					RefractometerWCF:     1.04,
+					GrainTemperature:     18,
+					TunThermalMass:       1.5,
+					MashHeating:          "infusion",
+					StableReadings:       4,
+					StableDays:           3,
+					StableTolerance:      0.002,
+					StableSlope:          0.0005,
+					StableAttenuation:    60,
+					TemperatureTolerance: 0.5,
+				},
+				Water: WaterConfig{
+					Calcium:     80,
+					Magnesium:   12.5,
+					Sodium:      9,
+					Chloride:    20,
+					Sulfate:     35,
+					Bicarbonate: 250,
 				},
 			},
 			Error: false,
@@ -118,6 +151,7 @@ func TestConfig(t *testing.T) {
 				"BREWDAY_STORE_PATH":                            "./bd.sqlite",
 				"BREWDAY_PROCESS_LAUTERN-REST-TIME-MIN":         "5",
 				"BREWDAY_PROCESS_REFRACTOMETER-WCF":             "1.05",
+				"BREWDAY_PROCESS_MASH-HEATING":                  "decoction",
 			},
 			Expected: Config{
 				App: AppConfig{Port: 8080},
@@ -135,8 +169,16 @@ func TestConfig(t *testing.T) {
 					Path:      "./bd.sqlite",
 				},
 				Process: ProcessParameters{
-					LauternRestTimeMin: 5,
-					RefractometerWCF:   1.05,
+					LauternRestTimeMin:   5,
+					RefractometerWCF:     1.05,
+					GrainTemperature:     20,
+					MashHeating:          "decoction",
+					StableReadings:       3,
+					StableDays:           2,
+					StableTolerance:      0.001,
+					StableSlope:          0.0002,
+					StableAttenuation:    50,
+					TemperatureTolerance: 1,
 				},
 			},
 			Error: false,
@@ -172,8 +214,16 @@ func TestConfig(t *testing.T) {
 					Path:      "./bd.sqlite",
 				},
 				Process: ProcessParameters{
-					LauternRestTimeMin: 5,
-					RefractometerWCF:   1.05,
+					LauternRestTimeMin:   5,
+					RefractometerWCF:     1.05,
+					GrainTemperature:     20,
+					MashHeating:          "direct",
+					StableReadings:       3,
+					StableDays:           2,
+					StableTolerance:      0.001,
+					StableSlope:          0.0002,
+					StableAttenuation:    50,
+					TemperatureTolerance: 1,
 				},
 			},
 			Error: false,
@@ -201,8 +251,16 @@ func TestConfig(t *testing.T) {
 					StoreType: "memory",
 				},
 				Process: ProcessParameters{
-					LauternRestTimeMin: 15,
-					RefractometerWCF:   1.00,
+					LauternRestTimeMin:   15,
+					RefractometerWCF:     1.00,
+					GrainTemperature:     20,
+					MashHeating:          "direct",
+					StableReadings:       3,
+					StableDays:           2,
+					StableTolerance:      0.001,
+					StableSlope:          0.0002,
+					StableAttenuation:    50,
+					TemperatureTolerance: 1,
 				},
 			},
 			Error: false,
@@ -230,8 +288,16 @@ func TestConfig(t *testing.T) {
 					StoreType: "memory",
 				},
 				Process: ProcessParameters{
-					LauternRestTimeMin: 15,
-					RefractometerWCF:   1.00,
+					LauternRestTimeMin:   15,
+					RefractometerWCF:     1.00,
+					GrainTemperature:     20,
+					MashHeating:          "direct",
+					StableReadings:       3,
+					StableDays:           2,
+					StableTolerance:      0.001,
+					StableSlope:          0.0002,
+					StableAttenuation:    50,
+					TemperatureTolerance: 1,
 				},
 			},
 			Error: false,
@@ -260,8 +326,16 @@ func TestConfig(t *testing.T) {
 					StoreType: "memory",
 				},
 				Process: ProcessParameters{
-					LauternRestTimeMin: 15,
-					RefractometerWCF:   1.00,
+					LauternRestTimeMin:   15,
+					RefractometerWCF:     1.00,
+					GrainTemperature:     20,
+					MashHeating:          "direct",
+					StableReadings:       3,
+					StableDays:           2,
+					StableTolerance:      0.001,
+					StableSlope:          0.0002,
+					StableAttenuation:    50,
+					TemperatureTolerance: 1,
 				},
 			},
 			Error: false,
@@ -290,8 +364,16 @@ func TestConfig(t *testing.T) {
 					StoreType: "memory",
 				},
 				Process: ProcessParameters{
-					LauternRestTimeMin: 15,
-					RefractometerWCF:   1.00,
+					LauternRestTimeMin:   15,
+					RefractometerWCF:     1.00,
+					GrainTemperature:     20,
+					MashHeating:          "direct",
+					StableReadings:       3,
+					StableDays:           2,
+					StableTolerance:      0.001,
+					StableSlope:          0.0002,
+					StableAttenuation:    50,
+					TemperatureTolerance: 1,
 				},
 			},
 			Error: false,
@@ -319,8 +401,16 @@ func TestConfig(t *testing.T) {
 					StoreType: "memory",
 				},
 				Process: ProcessParameters{
-					LauternRestTimeMin: 15,
-					RefractometerWCF:   1.00,
+					LauternRestTimeMin:   15,
+					RefractometerWCF:     1.00,
+					GrainTemperature:     20,
+					MashHeating:          "direct",
+					StableReadings:       3,
+					StableDays:           2,
+					StableTolerance:      0.001,
+					StableSlope:          0.0002,
+					StableAttenuation:    50,
+					TemperatureTolerance: 1,
 				},
 			},
 			Error: false,
@@ -378,8 +468,16 @@ func TestConfig(t *testing.T) {
 					StoreType: "memory",
 				},
 				Process: ProcessParameters{
-					LauternRestTimeMin: 15,
-					RefractometerWCF:   1.00,
+					LauternRestTimeMin:   15,
+					RefractometerWCF:     1.00,
+					GrainTemperature:     20,
+					MashHeating:          "direct",
+					StableReadings:       3,
+					StableDays:           2,
+					StableTolerance:      0.001,
+					StableSlope:          0.0002,
+					StableAttenuation:    50,
+					TemperatureTolerance: 1,
 				},
 			},
 			Error: false,
@@ -408,17 +506,107 @@ func TestConfig(t *testing.T) {
 					Path:      "./bd.sqlite",
 				},
 				Process: ProcessParameters{
-					LauternRestTimeMin: 15,
-					RefractometerWCF:   1.00,
+					LauternRestTimeMin:   15,
+					RefractometerWCF:     1.00,
+					GrainTemperature:     20,
+					MashHeating:          "direct",
+					StableReadings:       3,
+					StableDays:           2,
+					StableTolerance:      0.001,
+					StableSlope:          0.0002,
+					StableAttenuation:    50,
+					TemperatureTolerance: 1,
 				},
 			},
 			Error: false,
 		},
+		{
+			Name:  "Invalid mash heating",
+			Path:  "yaml/invalid_mash_heating.yaml",
+			Error: true,
+		},
 		{
 			Name:  "Invalid notification type",
 			Path:  "yaml/invalid_notification_type.yaml",
 			Error: true,
 		},
+		{
+			Name: "Hydrometers",
+			Path: "yaml/hydrometers.yaml",
+			Env:  map[string]string{},
+			Expected: Config{
+				App: AppConfig{Port: 8080},
+				Store: StoreConfig{
+					StoreType: "memory",
+				},
+				Process: ProcessParameters{
+					LauternRestTimeMin:   15,
+					RefractometerWCF:     1.00,
+					GrainTemperature:     20,
+					MashHeating:          "direct",
+					StableReadings:       3,
+					StableDays:           2,
+					StableTolerance:      0.001,
+					StableSlope:          0.0002,
+					StableAttenuation:    50,
+					TemperatureTolerance: 1,
+				},
+				Hydrometers: []HydrometerConfig{
+					{Name: "iSpindel001", Type: "ispindel", Calibration: []float32{-10.4, 0.2, 0.002}, Unit: "plato"},
+					{Name: "red", Type: "tilt"},
+				},
+			},
+			Error: false,
+		},
+		{
+			Name:  "Invalid hydrometer type",
+			Path:  "yaml/invalid_hydrometer_type.yaml",
+			Error: true,
+		},
+		{
+			Name:  "Invalid hydrometer unit",
+			Path:  "yaml/invalid_hydrometer_unit.yaml",
+			Error: true,
+		},
+		{
+			Name: "Temperature sensors",
+			Path: "yaml/temperature_sensors.yaml",
+			Env:  map[string]string{},
+			Expected: Config{
+				App: AppConfig{Port: 8080},
+				Store: StoreConfig{
+					StoreType: "memory",
+				},
+				Process: ProcessParameters{
+					LauternRestTimeMin:   15,
+					RefractometerWCF:     1.00,
+					GrainTemperature:     20,
+					MashHeating:          "direct",
+					StableReadings:       3,
+					StableDays:           2,
+					StableTolerance:      0.001,
+					StableSlope:          0.0002,
+					StableAttenuation:    50,
+					TemperatureTolerance: 1,
+				},
+				TemperatureSensors: []TemperatureSensorConfig{
+					{Name: "chamber", Type: "http"},
+					{Name: "fridge", Type: "onewire", Path: "/sys/bus/w1/devices/28-0000075a1b2c/w1_slave", Interval: 30},
+					{Name: "cellar", Type: "mqtt", Broker: "tcp://localhost:1883", Topic: "brewery/cellar/temperature", Field: "DS18B20-2.Temperature", Username: "brewer", Password: "secret"},
+				},
+			},
+			Error: false,
+		},
+		{
+			Name:  "Invalid temperature sensor type",
+			Path:  "yaml/invalid_temperature_sensor_type.yaml",
+			Error: true,
+		},
+		{
+			Name:  "MQTT temperature sensor without topic",
+			Path:  "yaml/missing_topic_temperature_sensor.yaml",
+			Error: true,
+		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.Name, func(t *testing.T) {
@@ -738,6 +926,86 @@ func TestValidateConfig(t *testing.T) {
 			},
 			Error: true,
 		},
+		{
+			Name: "Duplicated hydrometer",
+			Config: Config{
+				App: AppConfig{Port: 8080},
+				Store: StoreConfig{
+					StoreType: "memory",
+				},
+				Hydrometers: []HydrometerConfig{
+					{Name: "red", Type: "tilt"},
+					{Name: "RED", Type: "tilt"},
+				},
+			},
+			Error: true,
+		},
+		{
+			Name: "Negative stability tolerance",
+			Config: Config{
+				App: AppConfig{Port: 8080},
+				Store: StoreConfig{
+					StoreType: "memory",
+				},
+				Process: ProcessParameters{
+					StableTolerance: -0.001,
+				},
+			},
+			Error: true,
+		},
+		{
+			Name: "Duplicated temperature sensor",
+			Config: Config{
+				App: AppConfig{Port: 8080},
+				Store: StoreConfig{
+					StoreType: "memory",
+				},
+				TemperatureSensors: []TemperatureSensorConfig{
+					{Name: "fridge", Type: "http"},
+					{Name: "Fridge ", Type: "http"},
+				},
+			},
+			Error: true,
+		},
+		{
+			Name: "1-Wire temperature sensor without path",
+			Config: Config{
+				App: AppConfig{Port: 8080},
+				Store: StoreConfig{
+					StoreType: "memory",
+				},
+				TemperatureSensors: []TemperatureSensorConfig{
+					{Name: "fridge", Type: "onewire"},
+				},
+			},
+			Error: true,
+		},
+		{
+			Name: "Negative temperature tolerance",
+			Config: Config{
+				App: AppConfig{Port: 8080},
+				Store: StoreConfig{
+					StoreType: "memory",
+				},
+				Process: ProcessParameters{
+					TemperatureTolerance: -1,
+				},
+			},
+			Error: true,
+		},
+		{
+			Name: "Hydrometer without name",
+			Config: Config{
+				App: AppConfig{Port: 8080},
+				Store: StoreConfig{
+					StoreType: "memory",
+				},
+				Hydrometers: []HydrometerConfig{
+					{Type: "ispindel"},
+				},
+			},
+			Error: true,
+		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.Name, func(t *testing.T) {
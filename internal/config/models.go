@@ -6,6 +6,10 @@ type Config struct {
 	App          AppConfig          `koanf:"app"`
 	Store        StoreConfig        `koanf:"store"`
 	Process      ProcessParameters  `koanf:"process"`
+	Water        WaterConfig        `koanf:"water"`
+	Hydrometers  []HydrometerConfig `koanf:"hydrometers"`
+	// TemperatureSensors are the OPTIONAL sensors of the fermentation temperature
+	TemperatureSensors []TemperatureSensorConfig `koanf:"temperature-sensors"`
 }
 
 type NotificationSettings struct {
@@ -40,4 +44,66 @@ type StoreConfig struct {
 type ProcessParameters struct {
 	LauternRestTimeMin int     `koanf:"lautern-rest-time-min"`
 	RefractometerWCF   float32 `koanf:"refractometer-wcf"`
+	// GrainTemperature is the usual temperature of the grain and the mash tun in °C, used for the strike temperature
+	GrainTemperature float32 `koanf:"grain-temperature"`
+	// TunThermalMass is the thermal mass of the mash tun in kg of water equivalent
+	TunThermalMass float32 `koanf:"tun-thermal-mass"`
+	// MashHeating is the default way to heat the mash between rasts: direct, infusion or decoction
+	MashHeating string `koanf:"mash-heating"`
+	// StableReadings is the minimum number of gravity readings compared to tell that the main fermentation is complete
+	StableReadings int `koanf:"stable-readings"`
+	// StableDays is how many days the gravity has to be stable for the main fermentation to be complete
+	StableDays float32 `koanf:"stable-days"`
+	// StableTolerance is the maximum difference between the compared gravities
+	StableTolerance float32 `koanf:"stable-tolerance"`
+	// StableSlope is the maximum change of the gravity per day of the compared readings. Zero disables it
+	StableSlope float32 `koanf:"stable-slope"`
+	// StableAttenuation is the minimum apparent attenuation in % before the main fermentation can be complete. Zero disables it
+	StableAttenuation float32 `koanf:"stable-attenuation"`
+	// TemperatureTolerance is how many °C the fermentation temperature can differ from its target before an alert is sent
+	TemperatureTolerance float32 `koanf:"temperature-tolerance"`
+}
+
+// WaterConfig is the OPTIONAL profile of the source (tap) water, with the concentrations in ppm (mg/l)
+// It is used to calculate the water treatment. If it is not given, distilled water is assumed
+type WaterConfig struct {
+	Calcium     float32 `koanf:"calcium"`
+	Magnesium   float32 `koanf:"magnesium"`
+	Sodium      float32 `koanf:"sodium"`
+	Chloride    float32 `koanf:"chloride"`
+	Sulfate     float32 `koanf:"sulfate"`
+	Bicarbonate float32 `koanf:"bicarbonate"`
+}
+
+// HydrometerConfig is a wireless hydrometer (iSpindel or Tilt) that sends its readings to the app
+type HydrometerConfig struct {
+	// Name of the device. For an iSpindel it is its name, for a Tilt its color
+	Name string `koanf:"name"`
+	// Type is ispindel or tilt
+	Type string `koanf:"type"`
+	// Calibration are the OPTIONAL coefficients of the calibration polynomial, from the constant term up
+	Calibration []float32 `koanf:"calibration"`
+	// Unit is the OPTIONAL unit of the calibrated gravity, sg (the default) or plato
+	Unit string `koanf:"unit"`
+}
+
+// TemperatureSensorConfig is a sensor of the fermentation temperature (e.g. in a fermentation chamber)
+type TemperatureSensorConfig struct {
+	// Name of the sensor
+	Name string `koanf:"name"`
+	// Type is http (readings pushed to the app), onewire (read by the app) or mqtt (published to a topic)
+	Type string `koanf:"type"`
+	// Path is the sysfs file of a 1-Wire sensor
+	Path string `koanf:"path"`
+	// Interval is how often a 1-Wire sensor is read in seconds. It defaults to a minute
+	Interval int `koanf:"interval"`
+	// Broker is the address of the MQTT broker (e.g. tcp://localhost:1883)
+	Broker string `koanf:"broker"`
+	// Topic is the MQTT topic the readings are published to
+	Topic string `koanf:"topic"`
+	// Field is the OPTIONAL dot separated path of the temperature in the JSON messages of the MQTT topic (e.g. DS18B20-2.Temperature)
+	Field string `koanf:"field"`
+	// Username and Password are the OPTIONAL credentials for the MQTT broker
+	Username string `koanf:"username"`
+	Password string `koanf:"password"`
 }
@@ -56,8 +56,8 @@ func TestRunMigrations(t *testing.T) {
 			Error:   false,
 			FS:      []fs.FS{},
 			Path:    "migrations",
-			Tables:  []string{"bool_flags", "dates", "main_ferm_sgs", "recipe_results", "recipes", "stats", "sugar_results", "summaries", "timelines"},
-			Indexes: []string{"ix_bool_flags", "ix_dates", "ix_main_ferm_sgs", "ix_stats", "ix_sugar_results", "ix_summaries", "ix_timelines"},
+			Tables:  []string{"bool_flags", "dates", "main_ferm_sgs", "recipe_results", "recipes", "stats", "sugar_results", "summaries", "timelines", "library", "inventory"},
+			Indexes: []string{"ix_bool_flags", "ix_dates", "ix_main_ferm_sgs", "ix_stats", "ix_sugar_results", "ix_summaries", "ix_timelines", "ix_stats_recipe_id", "ix_inventory"},
 		},
 	}
 	for _, tc := range testCases {
@@ -0,0 +1,19 @@
+CREATE TABLE
+    IF NOT EXISTS "stats_per_title" (
+        id INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+        recipe_title TEXT UNIQUE,
+        finished_epoch INTEGER,
+        evaporation REAL,
+        efficiency REAL
+    );
+
+-- Only the latest brew of each recipe is kept
+INSERT INTO stats_per_title (recipe_title, finished_epoch, evaporation, efficiency)
+SELECT recipe_title, finished_epoch, evaporation, efficiency FROM stats
+WHERE id IN (SELECT MAX(id) FROM stats GROUP BY recipe_title);
+
+DROP TABLE stats;
+
+ALTER TABLE stats_per_title RENAME TO stats;
+
+CREATE INDEX IF NOT EXISTS ix_stats ON "stats" (recipe_title);
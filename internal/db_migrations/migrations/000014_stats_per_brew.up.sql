@@ -0,0 +1,22 @@
+-- Several brews can share a recipe title, so stats are identified by the recipe id of the brew
+-- External stats have no recipe id
+CREATE TABLE
+    IF NOT EXISTS "stats_per_brew" (
+        id INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+        recipe_title TEXT,
+        recipe_id INTEGER,
+        finished_epoch INTEGER,
+        evaporation REAL,
+        efficiency REAL
+    );
+
+INSERT INTO stats_per_brew (id, recipe_title, finished_epoch, evaporation, efficiency)
+SELECT id, recipe_title, finished_epoch, evaporation, efficiency FROM stats;
+
+DROP TABLE stats;
+
+ALTER TABLE stats_per_brew RENAME TO stats;
+
+CREATE INDEX IF NOT EXISTS ix_stats ON "stats" (recipe_title);
+
+CREATE INDEX IF NOT EXISTS ix_stats_recipe_id ON "stats" (recipe_id);
@@ -0,0 +1,8 @@
+CREATE TABLE
+    IF NOT EXISTS "library" (
+        id INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+        name TEXT NOT NULL,
+        style TEXT,
+        recipe TEXT NOT NULL,
+        added_epoch INTEGER
+    );
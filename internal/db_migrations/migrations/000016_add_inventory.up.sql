@@ -0,0 +1,12 @@
+CREATE TABLE
+    IF NOT EXISTS "inventory" (
+        id INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+        type TEXT NOT NULL,
+        name TEXT NOT NULL,
+        amount REAL NOT NULL,
+        lot TEXT,
+        alpha REAL,
+        best_before_epoch INTEGER
+    );
+
+CREATE INDEX IF NOT EXISTS ix_inventory ON "inventory" (type, name);
@@ -0,0 +1,2 @@
+ALTER TABLE "recipes" DROP COLUMN mash_decoctions;
+ALTER TABLE "recipes" DROP COLUMN mash_type;
@@ -0,0 +1,2 @@
+ALTER TABLE "recipes" ADD COLUMN mash_type TEXT NOT NULL DEFAULT '';
+ALTER TABLE "recipes" ADD COLUMN mash_decoctions TEXT NOT NULL DEFAULT 'null';
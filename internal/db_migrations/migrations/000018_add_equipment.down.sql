@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS "brew_equipment";
+DROP TABLE IF EXISTS "equipment";
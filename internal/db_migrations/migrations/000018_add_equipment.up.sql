@@ -0,0 +1,20 @@
+CREATE TABLE
+    IF NOT EXISTS "equipment" (
+        id INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+        name TEXT NOT NULL,
+        kettle_size REAL NOT NULL DEFAULT 0,
+        dead_space REAL NOT NULL DEFAULT 0,
+        trub_loss REAL NOT NULL DEFAULT 0,
+        evaporation_rate REAL NOT NULL DEFAULT 0,
+        hop_absorption REAL NOT NULL DEFAULT 0,
+        chiller_loss REAL NOT NULL DEFAULT 0,
+        tun_thermal_mass REAL NOT NULL DEFAULT 0
+    );
+
+CREATE TABLE
+    IF NOT EXISTS "brew_equipment" (
+        recipe_id INTEGER NOT NULL PRIMARY KEY,
+        equipment_id INTEGER NOT NULL,
+        FOREIGN KEY (recipe_id) REFERENCES recipes (id) ON DELETE CASCADE ON UPDATE CASCADE,
+        FOREIGN KEY (equipment_id) REFERENCES equipment (id) ON DELETE CASCADE ON UPDATE CASCADE
+    );
@@ -0,0 +1,2 @@
+ALTER TABLE "equipment" DROP COLUMN active;
+ALTER TABLE "equipment" DROP COLUMN efficiency;
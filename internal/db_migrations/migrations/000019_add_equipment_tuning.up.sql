@@ -0,0 +1,2 @@
+ALTER TABLE "equipment" ADD COLUMN efficiency REAL NOT NULL DEFAULT 0;
+ALTER TABLE "equipment" ADD COLUMN active INTEGER NOT NULL DEFAULT 0;
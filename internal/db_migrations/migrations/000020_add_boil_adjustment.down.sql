@@ -0,0 +1,2 @@
+ALTER TABLE "summaries" DROP COLUMN hopping_boil_adjustment;
+ALTER TABLE "recipe_results" DROP COLUMN extra_boil_time;
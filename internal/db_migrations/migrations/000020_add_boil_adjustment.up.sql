@@ -0,0 +1,2 @@
+ALTER TABLE "recipe_results" ADD COLUMN extra_boil_time REAL NOT NULL DEFAULT 0;
+ALTER TABLE "summaries" ADD COLUMN hopping_boil_adjustment TEXT;
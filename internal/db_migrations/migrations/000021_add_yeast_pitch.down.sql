@@ -0,0 +1 @@
+ALTER TABLE "summaries" DROP COLUMN yeast_pitch;
@@ -0,0 +1 @@
+ALTER TABLE "summaries" ADD COLUMN yeast_pitch TEXT;
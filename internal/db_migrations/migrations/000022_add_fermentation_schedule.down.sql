@@ -0,0 +1 @@
+ALTER TABLE "recipes" DROP COLUMN ferm_schedule;
@@ -0,0 +1 @@
+ALTER TABLE "recipes" ADD COLUMN ferm_schedule TEXT NOT NULL DEFAULT 'null';
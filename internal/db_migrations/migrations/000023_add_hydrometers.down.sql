@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS "hydrometers";
+ALTER TABLE "main_ferm_sgs" DROP COLUMN device;
+ALTER TABLE "main_ferm_sgs" DROP COLUMN temperature;
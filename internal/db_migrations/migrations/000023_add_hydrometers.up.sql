@@ -0,0 +1,9 @@
+ALTER TABLE "main_ferm_sgs" ADD COLUMN temperature REAL NOT NULL DEFAULT 0;
+ALTER TABLE "main_ferm_sgs" ADD COLUMN device TEXT NOT NULL DEFAULT '';
+
+CREATE TABLE
+    IF NOT EXISTS "hydrometers" (
+        device TEXT NOT NULL PRIMARY KEY,
+        recipe_id INTEGER NOT NULL,
+        FOREIGN KEY (recipe_id) REFERENCES recipes (id) ON DELETE CASCADE ON UPDATE CASCADE
+    );
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS ix_temperature_readings;
+DROP TABLE IF EXISTS "temperature_readings";
+DROP TABLE IF EXISTS "temperature_sensors";
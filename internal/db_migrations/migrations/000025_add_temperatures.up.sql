@@ -0,0 +1,18 @@
+CREATE TABLE
+    IF NOT EXISTS "temperature_sensors" (
+        sensor TEXT NOT NULL PRIMARY KEY,
+        recipe_id INTEGER NOT NULL,
+        FOREIGN KEY (recipe_id) REFERENCES recipes (id) ON DELETE CASCADE ON UPDATE CASCADE
+    );
+
+CREATE TABLE
+    IF NOT EXISTS "temperature_readings" (
+        id INTEGER NOT NULL PRIMARY KEY,
+        sensor TEXT NOT NULL,
+        temperature REAL NOT NULL,
+        timestamp_unix INTEGER NOT NULL,
+        recipe_id INTEGER NOT NULL,
+        FOREIGN KEY (recipe_id) REFERENCES recipes (id) ON DELETE CASCADE ON UPDATE CASCADE
+    );
+
+CREATE INDEX IF NOT EXISTS ix_temperature_readings ON "temperature_readings" (recipe_id, timestamp_unix);
@@ -0,0 +1 @@
+ALTER TABLE "inventory" ADD COLUMN unit TEXT NOT NULL DEFAULT '';
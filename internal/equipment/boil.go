@@ -0,0 +1,79 @@
+package equipment
+
+import (
+	"brewday/internal/recipe"
+	"brewday/internal/tools"
+	"math"
+)
+
+// GravityTolerance is the difference in SG from the gravity of the recipe that is not worth correcting
+const GravityTolerance float32 = 0.002
+
+// BoilAdvice predicts the boil from the volume measured before it and suggests how to reach the gravity of the recipe
+// At most one of ExtraBoilTime and TopUpWater is set
+type BoilAdvice struct {
+	// PreBoilVolume is the measured volume before the boil in liters
+	PreBoilVolume float32
+	// PreBoilSG is the gravity expected before the boil
+	PreBoilSG float32
+	// PostBoilVolume is the hot wort volume in liters expected after the planned boil
+	PostBoilVolume float32
+	// PostBoilSG is the gravity expected after the planned boil
+	PostBoilSG float32
+	// TargetSG is the initial gravity of the recipe, zero if the recipe has none
+	TargetSG float32
+	// ExtraBoilTime is the time in minutes the boil needs to be extended because the wort is too thin
+	ExtraBoilTime float32
+	// ExtendedVolume is the hot wort volume in liters expected after the extended boil
+	ExtendedVolume float32
+	// TopUpWater is the water in liters to add before the boil because the wort is too strong
+	TopUpWater float32
+	// ToppedUpVolume is the hot wort volume in liters expected after boiling with the added water
+	ToppedUpVolume float32
+	// Overflow is true if the volume with the added water does not fit in the kettle
+	Overflow bool
+}
+
+// NeedsCorrection returns whether the boil should be extended or water added to reach the gravity of the recipe
+func (a *BoilAdvice) NeedsCorrection() bool {
+	return a.ExtraBoilTime > 0 || a.TopUpWater > 0
+}
+
+// AdviseBoil predicts the gravity and the volume after the boil from the measured volume before it
+// It uses the efficiency and the evaporation rate of the profile, the extract of the malts stays in the wort while water evaporates
+// It returns nil if the efficiency of the profile, the malts of the recipe or the volume are unknown
+func (p *Profile) AdviseBoil(re *recipe.Recipe, preBoil float32) *BoilAdvice {
+	malt := re.Mashing.GetTotalMaltWeight()
+	if p.Efficiency <= 0 || malt <= 0 || preBoil <= 0 {
+		return nil
+	}
+	boilTime := re.Hopping.TotalCookingTime
+	hopKg := BoilHopWeight(re)
+	factor := p.boilFactor(boilTime)
+	if factor <= 0 {
+		return nil
+	}
+	a := &BoilAdvice{
+		PreBoilVolume:  preBoil,
+		PreBoilSG:      tools.EstimateOriginalGravity(malt, preBoil, p.Efficiency),
+		PostBoilVolume: p.PostBoilVolume(preBoil, boilTime, hopKg),
+		// The hops absorb wort, they do not change its gravity
+		PostBoilSG: tools.EstimateOriginalGravity(malt, preBoil*factor, p.Efficiency),
+	}
+	if re.InitialSG <= 1 {
+		return a
+	}
+	a.TargetSG = re.InitialSG
+	target := tools.VolumeForGravity(malt, re.InitialSG, p.Efficiency)
+	switch {
+	case a.PostBoilSG < a.TargetSG-GravityTolerance && p.EvaporationRate > 0:
+		minutes := (1 - target/preBoil) * 6000 / p.EvaporationRate
+		a.ExtraBoilTime = float32(math.Ceil(float64(minutes - boilTime)))
+		a.ExtendedVolume = p.PostBoilVolume(preBoil, boilTime+a.ExtraBoilTime, hopKg)
+	case a.PostBoilSG > a.TargetSG+GravityTolerance:
+		a.TopUpWater = tools.RoundTo(target/factor-preBoil, 1)
+		a.ToppedUpVolume = p.PostBoilVolume(preBoil+a.TopUpWater, boilTime, hopKg)
+		a.Overflow = !p.Fits(preBoil + a.TopUpWater)
+	}
+	return a
+}
@@ -0,0 +1,61 @@
+package equipment
+
+import (
+	"brewday/internal/recipe"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdviseBoil(t *testing.T) {
+	// 4.5 kg of malt at 65 % give 20.2 l of hot wort at 1.058
+	re := &recipe.Recipe{
+		InitialSG: 1.058,
+		Mashing:   recipe.MashInstructions{Malts: []recipe.Malt{{Name: "Pilsner", Amount: 4500}}},
+		Hopping:   recipe.HopInstructions{TotalCookingTime: 60},
+	}
+	p := &Profile{Name: "Kettle", KettleSize: 30, EvaporationRate: 10, Efficiency: 65}
+	type testCase struct {
+		Name           string
+		Profile        *Profile
+		PreBoil        float32
+		Nil            bool
+		PostBoilVolume float32
+		ExtraBoilTime  float32
+		ExtendedVolume float32
+		TopUpWater     float32
+		ToppedUpVolume float32
+		Overflow       bool
+	}
+	testCases := []testCase{
+		{Name: "On target", Profile: p, PreBoil: 22.2, PostBoilVolume: 19.98},
+		{Name: "Too thin, extend the boil", Profile: p, PreBoil: 25, PostBoilVolume: 22.5, ExtraBoilTime: 56, ExtendedVolume: 20.17},
+		{Name: "Too strong, top up", Profile: p, PreBoil: 20, PostBoilVolume: 18, TopUpWater: 2.4, ToppedUpVolume: 20.16},
+		{
+			Name: "Top up does not fit", Profile: &Profile{Name: "Small", KettleSize: 21, EvaporationRate: 10, Efficiency: 65},
+			PreBoil: 20, PostBoilVolume: 18, TopUpWater: 2.4, ToppedUpVolume: 20.16, Overflow: true,
+		},
+		{Name: "No evaporation, boil can not be extended", Profile: &Profile{Name: "Lid", Efficiency: 65}, PreBoil: 25, PostBoilVolume: 25},
+		{Name: "Unknown efficiency", Profile: &Profile{Name: "Kettle", EvaporationRate: 10}, PreBoil: 22, Nil: true},
+		{Name: "No volume", Profile: p, PreBoil: 0, Nil: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			require := require.New(t)
+			a := tc.Profile.AdviseBoil(re, tc.PreBoil)
+			if tc.Nil {
+				require.Nil(a)
+				return
+			}
+			require.NotNil(a)
+			require.InDelta(tc.PostBoilVolume, a.PostBoilVolume, 0.05)
+			require.InDelta(tc.ExtraBoilTime, a.ExtraBoilTime, 1)
+			require.InDelta(tc.ExtendedVolume, a.ExtendedVolume, 0.2)
+			require.InDelta(tc.TopUpWater, a.TopUpWater, 0.1)
+			require.InDelta(tc.ToppedUpVolume, a.ToppedUpVolume, 0.2)
+			require.Equal(tc.Overflow, a.Overflow)
+			require.Equal(tc.ExtraBoilTime > 0 || tc.TopUpWater > 0, a.NeedsCorrection())
+			require.Greater(a.PostBoilSG, a.PreBoilSG-0.0001)
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package equipment
+
+import (
+	"brewday/internal/recipe"
+	"errors"
+	"strings"
+)
+
+// CoolingShrinkage is the fraction of volume the wort loses when it is cooled from boiling to pitching temperature
+const CoolingShrinkage float32 = 0.04
+
+// Profile describes a brewing system with the volumes it loses in each step
+// It is used to predict the volumes expected during a brew day
+type Profile struct {
+	// ID is the identifier of the profile. This is populated by the appropriate store and should not be set manually
+	ID string
+	// Name of the profile, e.g. the name of the kettle
+	Name string
+	// KettleSize is the total volume of the kettle in liters
+	KettleSize float32
+	// DeadSpace is the wort in liters that stays in the kettle when draining it
+	DeadSpace float32
+	// TrubLoss is the wort in liters that is left behind with the trub (hot break and hops)
+	TrubLoss float32
+	// EvaporationRate is the volume evaporated during the boil in %/h, the same unit as the evaporation in the statistics
+	EvaporationRate float32
+	// HopAbsorption is the wort in liters absorbed by each kg of hops in the boil
+	HopAbsorption float32
+	// ChillerLoss is the wort in liters that stays in the chiller or the tubes when transferring to the fermenter
+	ChillerLoss float32
+	// TunThermalMass is the thermal mass of the mash tun in kg of water equivalent
+	TunThermalMass float32
+	// Efficiency is the brewhouse efficiency in % used to plan recipes. It is zero if unknown
+	Efficiency float32
+	// Active is true for the profile used by default, for planning recipes and starting brews. It is set by the store
+	Active bool
+}
+
+// Validate returns an error if the profile has no name or any negative value
+func (p *Profile) Validate() error {
+	if strings.TrimSpace(p.Name) == "" {
+		return errors.New("the name of the equipment profile can not be empty")
+	}
+	values := []float32{p.KettleSize, p.DeadSpace, p.TrubLoss, p.EvaporationRate, p.HopAbsorption, p.ChillerLoss, p.TunThermalMass, p.Efficiency}
+	for _, v := range values {
+		if v < 0 {
+			return errors.New("the values of the equipment profile can not be negative")
+		}
+	}
+	if p.EvaporationRate >= 100 {
+		return errors.New("the evaporation rate must be lower than 100 %/h")
+	}
+	if p.Efficiency > 100 {
+		return errors.New("the efficiency can not be higher than 100 %")
+	}
+	return nil
+}
+
+// BoilHopWeight returns the weight in kg of the hops of the recipe that go into the boil
+func BoilHopWeight(re *recipe.Recipe) float32 {
+	var grams float32
+	for _, h := range re.Hopping.Hops {
+		if !h.DryHop {
+			grams += h.Amount
+		}
+	}
+	return grams / 1000
+}
+
+// boilFactor returns the fraction of the volume that remains after boiling for the given minutes
+func (p *Profile) boilFactor(boilMinutes float32) float32 {
+	return 1 - p.EvaporationRate/100*boilMinutes/60
+}
+
+// transferLosses returns the liters lost between the end of the boil and the fermenter
+func (p *Profile) transferLosses() float32 {
+	return p.DeadSpace + p.TrubLoss + p.ChillerLoss
+}
+
+// PostBoilVolume returns the hot wort volume in liters expected after boiling the given volume
+// The boil time is in minutes and the hops in kg
+func (p *Profile) PostBoilVolume(preBoil, boilMinutes, hopKg float32) float32 {
+	return max(preBoil*p.boilFactor(boilMinutes)-p.HopAbsorption*hopKg, 0)
+}
+
+// FermenterVolume returns the volume in liters expected in the fermenter from the hot wort volume
+// The wort shrinks when cooled, and the dead space, trub and chiller losses stay behind
+func (p *Profile) FermenterVolume(hotWort float32) float32 {
+	return max(hotWort*(1-CoolingShrinkage)-p.transferLosses(), 0)
+}
+
+// PreBoilVolume returns the volume in liters needed before the boil to get the given volume into the fermenter
+// The boil time is in minutes and the hops in kg
+func (p *Profile) PreBoilVolume(fermenter, boilMinutes, hopKg float32) float32 {
+	hotWort := (fermenter + p.transferLosses()) / (1 - CoolingShrinkage)
+	factor := p.boilFactor(boilMinutes)
+	if factor <= 0 {
+		return 0
+	}
+	return (hotWort + p.HopAbsorption*hopKg) / factor
+}
+
+// RecipePreBoilVolume returns the volume in liters needed before the boil to get the batch size of the recipe into the fermenter
+func (p *Profile) RecipePreBoilVolume(re *recipe.Recipe) float32 {
+	return p.PreBoilVolume(re.BatchSize, re.Hopping.TotalCookingTime, BoilHopWeight(re))
+}
+
+// RecipePostBoilVolume returns the hot wort volume in liters expected after boiling the given volume with the recipe
+// The boil of the recipe is extended by extraBoil minutes
+func (p *Profile) RecipePostBoilVolume(re *recipe.Recipe, preBoil, extraBoil float32) float32 {
+	return p.PostBoilVolume(preBoil, re.Hopping.TotalCookingTime+extraBoil, BoilHopWeight(re))
+}
+
+// Fits returns whether the volume fits in the kettle. Kettles of unknown size fit everything
+func (p *Profile) Fits(volume float32) bool {
+	return p.KettleSize <= 0 || volume <= p.KettleSize
+}
+
+// Expectation compares a measured volume with the one expected by the equipment profile
+type Expectation struct {
+	// Expected volume in liters
+	Expected float32
+	// Measured volume in liters
+	Measured float32
+}
+
+// Difference returns the measured volume minus the expected one
+func (e Expectation) Difference() float32 {
+	return e.Measured - e.Expected
+}
@@ -0,0 +1,96 @@
+package equipment
+
+import (
+	"brewday/internal/recipe"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testProfile() *Profile {
+	return &Profile{
+		Name:            "Kettle 30l",
+		KettleSize:      30,
+		DeadSpace:       1,
+		TrubLoss:        0.5,
+		EvaporationRate: 10,
+		HopAbsorption:   10,
+		ChillerLoss:     0.5,
+		TunThermalMass:  1.5,
+	}
+}
+
+func TestValidate(t *testing.T) {
+	testCases := []struct {
+		Name    string
+		Modify  func(p *Profile)
+		WantErr bool
+	}{
+		{Name: "Valid", Modify: func(p *Profile) {}},
+		{Name: "Empty values", Modify: func(p *Profile) { *p = Profile{Name: "Pot"} }},
+		{Name: "No name", Modify: func(p *Profile) { p.Name = " " }, WantErr: true},
+		{Name: "Negative dead space", Modify: func(p *Profile) { p.DeadSpace = -1 }, WantErr: true},
+		{Name: "Evaporation too high", Modify: func(p *Profile) { p.EvaporationRate = 100 }, WantErr: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			p := testProfile()
+			tc.Modify(p)
+			err := p.Validate()
+			if tc.WantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestBoilHopWeight(t *testing.T) {
+	re := &recipe.Recipe{
+		Hopping: recipe.HopInstructions{
+			Hops: []recipe.Hops{
+				{Name: "Magnum", Amount: 20, Duration: 60},
+				{Name: "Cascade", Amount: 30, Vorderwuerze: true},
+				{Name: "Cascade", Amount: 50, DryHop: true},
+			},
+		},
+	}
+	require.InDelta(t, 0.05, BoilHopWeight(re), 0.0001)
+}
+
+func TestVolumes(t *testing.T) {
+	require := require.New(t)
+	p := testProfile()
+	require.InDelta(21.5, p.PostBoilVolume(25, 60, 0.1), 0.001)
+	require.InDelta(18.64, p.FermenterVolume(21.5), 0.001)
+	require.InDelta(25, p.PreBoilVolume(18.64, 60, 0.1), 0.001)
+	require.Equal(float32(0), p.FermenterVolume(1))
+	require.True(p.Fits(25))
+	require.False(p.Fits(31))
+	require.True((&Profile{}).Fits(100))
+	// An empty profile predicts no losses apart from the cooling shrinkage
+	empty := &Profile{}
+	require.InDelta(20, empty.PostBoilVolume(20, 60, 0.1), 0.001)
+	require.InDelta(19.2, empty.FermenterVolume(20), 0.001)
+}
+
+func TestRecipeVolumes(t *testing.T) {
+	require := require.New(t)
+	re := &recipe.Recipe{
+		BatchSize: 18.64,
+		Hopping: recipe.HopInstructions{
+			TotalCookingTime: 60,
+			Hops:             []recipe.Hops{{Name: "Magnum", Amount: 100, Duration: 60}},
+		},
+	}
+	p := testProfile()
+	require.InDelta(25, p.RecipePreBoilVolume(re), 0.001)
+	require.InDelta(21.5, p.RecipePostBoilVolume(re, 25, 0), 0.001)
+	require.InDelta(20.25, p.RecipePostBoilVolume(re, 25, 30), 0.001)
+}
+
+func TestExpectationDifference(t *testing.T) {
+	e := Expectation{Expected: 20, Measured: 18.5}
+	require.InDelta(t, -1.5, e.Difference(), 0.001)
+}
@@ -0,0 +1,163 @@
+package memory
+
+import (
+	"brewday/internal/equipment"
+	"errors"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// EquipmentMemoryStore represents an equipment store stored in memory
+type EquipmentMemoryStore struct {
+	lock     sync.Mutex
+	profiles map[string]*equipment.Profile
+	brews    map[string]string
+	active   string
+	lastID   int
+}
+
+// NewEquipmentMemoryStore creates a new EquipmentMemoryStore
+func NewEquipmentMemoryStore() *EquipmentMemoryStore {
+	return &EquipmentMemoryStore{
+		profiles: make(map[string]*equipment.Profile),
+		brews:    make(map[string]string),
+	}
+}
+
+// AddProfile adds an equipment profile and returns its identifier
+func (s *EquipmentMemoryStore) AddProfile(p *equipment.Profile) (string, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.lastID++
+	id := strconv.Itoa(s.lastID)
+	stored := *p
+	stored.ID = id
+	s.profiles[id] = &stored
+	p.ID = id
+	return id, nil
+}
+
+// UpdateProfile replaces an equipment profile, the identifier of the profile is used to find it
+// Whether the profile is active does not change
+func (s *EquipmentMemoryStore) UpdateProfile(p *equipment.Profile) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	_, ok := s.profiles[p.ID]
+	if !ok {
+		return errors.New("equipment profile not found")
+	}
+	stored := *p
+	s.profiles[p.ID] = &stored
+	return nil
+}
+
+// ListProfiles lists all the equipment profiles, sorted by name
+func (s *EquipmentMemoryStore) ListProfiles() ([]*equipment.Profile, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	profiles := make([]*equipment.Profile, 0, len(s.profiles))
+	for _, p := range s.profiles {
+		profiles = append(profiles, s.copyProfile(p))
+	}
+	sort.Slice(profiles, func(i, j int) bool {
+		if profiles[i].Name != profiles[j].Name {
+			return profiles[i].Name < profiles[j].Name
+		}
+		idI, _ := strconv.Atoi(profiles[i].ID)
+		idJ, _ := strconv.Atoi(profiles[j].ID)
+		return idI < idJ
+	})
+	return profiles, nil
+}
+
+// RetrieveProfile retrieves an equipment profile based on its identifier
+func (s *EquipmentMemoryStore) RetrieveProfile(id string) (*equipment.Profile, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	p, ok := s.profiles[id]
+	if !ok {
+		return nil, errors.New("equipment profile not found")
+	}
+	return s.copyProfile(p), nil
+}
+
+// DeleteProfile deletes an equipment profile. Brews that used it will have no profile
+func (s *EquipmentMemoryStore) DeleteProfile(id string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	_, ok := s.profiles[id]
+	if !ok {
+		return errors.New("equipment profile not found")
+	}
+	delete(s.profiles, id)
+	if s.active == id {
+		s.active = ""
+	}
+	for recipeID, profileID := range s.brews {
+		if profileID == id {
+			delete(s.brews, recipeID)
+		}
+	}
+	return nil
+}
+
+// SetBrewProfile selects the equipment profile used to brew a recipe. An empty profile id removes the selection
+func (s *EquipmentMemoryStore) SetBrewProfile(recipeID, profileID string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if profileID == "" {
+		delete(s.brews, recipeID)
+		return nil
+	}
+	_, ok := s.profiles[profileID]
+	if !ok {
+		return errors.New("equipment profile not found")
+	}
+	s.brews[recipeID] = profileID
+	return nil
+}
+
+// RetrieveBrewProfile retrieves the equipment profile used to brew a recipe
+// It returns nil if no profile was selected for the brew
+func (s *EquipmentMemoryStore) RetrieveBrewProfile(recipeID string) (*equipment.Profile, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	profileID, ok := s.brews[recipeID]
+	if !ok {
+		return nil, nil
+	}
+	return s.copyProfile(s.profiles[profileID]), nil
+}
+
+// copyProfile returns a copy of a stored profile, marked as active if it is. The lock must be held
+func (s *EquipmentMemoryStore) copyProfile(p *equipment.Profile) *equipment.Profile {
+	c := *p
+	c.Active = c.ID == s.active
+	return &c
+}
+
+// SetActiveProfile makes a profile the one used by default. An empty id leaves no profile active
+func (s *EquipmentMemoryStore) SetActiveProfile(id string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if id != "" {
+		_, ok := s.profiles[id]
+		if !ok {
+			return errors.New("equipment profile not found")
+		}
+	}
+	s.active = id
+	return nil
+}
+
+// RetrieveActiveProfile retrieves the profile used by default, nil if no profile is active
+func (s *EquipmentMemoryStore) RetrieveActiveProfile() (*equipment.Profile, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	p, ok := s.profiles[s.active]
+	if !ok {
+		return nil, nil
+	}
+	return s.copyProfile(p), nil
+}
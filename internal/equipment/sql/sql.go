@@ -0,0 +1,154 @@
+package sql
+
+import (
+	"brewday/internal/equipment"
+	"database/sql"
+	"errors"
+	"strconv"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+type EquipmentPersistentStore struct {
+	dbClient *sql.DB
+}
+
+// NewEquipmentPersistentStore creates a new EquipmentStore
+func NewEquipmentPersistentStore(db *sql.DB) (*EquipmentPersistentStore, error) {
+	return &EquipmentPersistentStore{
+		dbClient: db,
+	}, nil
+}
+
+// AddProfile adds an equipment profile and returns its identifier
+func (s *EquipmentPersistentStore) AddProfile(p *equipment.Profile) (string, error) {
+	res, err := s.dbClient.Exec(`INSERT INTO equipment (name, kettle_size, dead_space, trub_loss, evaporation_rate, hop_absorption, chiller_loss, tun_thermal_mass, efficiency) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		p.Name, p.KettleSize, p.DeadSpace, p.TrubLoss, p.EvaporationRate, p.HopAbsorption, p.ChillerLoss, p.TunThermalMass, p.Efficiency)
+	if err != nil {
+		return "", err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return "", err
+	}
+	p.ID = strconv.FormatInt(id, 10)
+	return p.ID, nil
+}
+
+// UpdateProfile replaces an equipment profile, the identifier of the profile is used to find it
+// Whether the profile is active does not change
+func (s *EquipmentPersistentStore) UpdateProfile(p *equipment.Profile) error {
+	if p.ID == "" {
+		return errors.New("invalid empty equipment profile id")
+	}
+	res, err := s.dbClient.Exec(`UPDATE equipment SET name = ?, kettle_size = ?, dead_space = ?, trub_loss = ?, evaporation_rate = ?, hop_absorption = ?, chiller_loss = ?, tun_thermal_mass = ?, efficiency = ? WHERE id == ?`,
+		p.Name, p.KettleSize, p.DeadSpace, p.TrubLoss, p.EvaporationRate, p.HopAbsorption, p.ChillerLoss, p.TunThermalMass, p.Efficiency, p.ID)
+	if err != nil {
+		return err
+	}
+	return checkAffected(res)
+}
+
+// checkAffected returns an error if the statement did not change any profile
+func checkAffected(res sql.Result) error {
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return errors.New("equipment profile not found")
+	}
+	return nil
+}
+
+// scanProfile reads a profile from a row of the equipment table
+func scanProfile(row interface{ Scan(dest ...any) error }) (*equipment.Profile, error) {
+	var p equipment.Profile
+	err := row.Scan(&p.ID, &p.Name, &p.KettleSize, &p.DeadSpace, &p.TrubLoss, &p.EvaporationRate, &p.HopAbsorption, &p.ChillerLoss, &p.TunThermalMass, &p.Efficiency, &p.Active)
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// ListProfiles lists all the equipment profiles, sorted by name
+func (s *EquipmentPersistentStore) ListProfiles() ([]*equipment.Profile, error) {
+	rows, err := s.dbClient.Query(`SELECT id, name, kettle_size, dead_space, trub_loss, evaporation_rate, hop_absorption, chiller_loss, tun_thermal_mass, efficiency, active FROM equipment ORDER BY name, id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	profiles := make([]*equipment.Profile, 0)
+	for rows.Next() {
+		p, err := scanProfile(rows)
+		if err != nil {
+			return nil, err
+		}
+		profiles = append(profiles, p)
+	}
+	return profiles, rows.Err()
+}
+
+// RetrieveProfile retrieves an equipment profile based on its identifier
+func (s *EquipmentPersistentStore) RetrieveProfile(id string) (*equipment.Profile, error) {
+	row := s.dbClient.QueryRow(`SELECT id, name, kettle_size, dead_space, trub_loss, evaporation_rate, hop_absorption, chiller_loss, tun_thermal_mass, efficiency, active FROM equipment WHERE id == ?`, id)
+	p, err := scanProfile(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("equipment profile not found")
+	}
+	return p, err
+}
+
+// DeleteProfile deletes an equipment profile. Brews that used it will have no profile
+func (s *EquipmentPersistentStore) DeleteProfile(id string) error {
+	res, err := s.dbClient.Exec(`DELETE FROM equipment WHERE id == ?`, id)
+	if err != nil {
+		return err
+	}
+	return checkAffected(res)
+}
+
+// SetBrewProfile selects the equipment profile used to brew a recipe. An empty profile id removes the selection
+func (s *EquipmentPersistentStore) SetBrewProfile(recipeID, profileID string) error {
+	if profileID == "" {
+		_, err := s.dbClient.Exec(`DELETE FROM brew_equipment WHERE recipe_id == ?`, recipeID)
+		return err
+	}
+	_, err := s.dbClient.Exec(`INSERT INTO brew_equipment (recipe_id, equipment_id) VALUES (?, ?) ON CONFLICT(recipe_id) DO UPDATE SET equipment_id = excluded.equipment_id`,
+		recipeID, profileID)
+	return err
+}
+
+// RetrieveBrewProfile retrieves the equipment profile used to brew a recipe
+// It returns nil if no profile was selected for the brew
+func (s *EquipmentPersistentStore) RetrieveBrewProfile(recipeID string) (*equipment.Profile, error) {
+	row := s.dbClient.QueryRow(`SELECT e.id, e.name, e.kettle_size, e.dead_space, e.trub_loss, e.evaporation_rate, e.hop_absorption, e.chiller_loss, e.tun_thermal_mass, e.efficiency, e.active
+	FROM equipment e JOIN brew_equipment b ON b.equipment_id == e.id WHERE b.recipe_id == ?`, recipeID)
+	p, err := scanProfile(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return p, err
+}
+
+// SetActiveProfile makes a profile the one used by default. An empty id leaves no profile active
+func (s *EquipmentPersistentStore) SetActiveProfile(id string) error {
+	if id != "" {
+		_, err := s.RetrieveProfile(id)
+		if err != nil {
+			return err
+		}
+	}
+	_, err := s.dbClient.Exec(`UPDATE equipment SET active = (id == ?)`, id)
+	return err
+}
+
+// RetrieveActiveProfile retrieves the profile used by default, nil if no profile is active
+func (s *EquipmentPersistentStore) RetrieveActiveProfile() (*equipment.Profile, error) {
+	row := s.dbClient.QueryRow(`SELECT id, name, kettle_size, dead_space, trub_loss, evaporation_rate, hop_absorption, chiller_loss, tun_thermal_mass, efficiency, active FROM equipment WHERE active == 1`)
+	p, err := scanProfile(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return p, err
+}
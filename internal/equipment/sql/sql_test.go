@@ -0,0 +1,119 @@
+package sql
+
+import (
+	"brewday/internal/equipment"
+	"database/sql"
+	"os"
+	"strings"
+	"testing"
+
+	dbmigrations "brewday/internal/db_migrations"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestStore(t *testing.T) *EquipmentPersistentStore {
+	fileName := strings.ToLower(strings.TrimSpace(t.Name())) + ".sqlite"
+	db, err := sql.Open("sqlite3", "file:"+fileName+"?_foreign_keys=true")
+	require.NoError(t, err)
+	require.NoError(t, dbmigrations.RunMigrations(db, "migrations"))
+	_, err = db.Exec(`INSERT INTO recipes (name, status) VALUES (?, ?)`, "Test", 0)
+	require.NoError(t, err)
+	store, err := NewEquipmentPersistentStore(db)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		db.Close()
+		os.Remove(fileName)
+	})
+	return store
+}
+
+func TestAddUpdateAndDeleteProfile(t *testing.T) {
+	require := require.New(t)
+	store := newTestStore(t)
+	big := &equipment.Profile{Name: "Kettle 50l", KettleSize: 50, DeadSpace: 2, TrubLoss: 1, EvaporationRate: 8, HopAbsorption: 10, ChillerLoss: 0.5, TunThermalMass: 2}
+	small := &equipment.Profile{Name: "Kettle 30l", KettleSize: 30, EvaporationRate: 12}
+	for _, p := range []*equipment.Profile{big, small} {
+		id, err := store.AddProfile(p)
+		require.NoError(err)
+		require.Equal(id, p.ID)
+	}
+	actual, err := store.ListProfiles()
+	require.NoError(err)
+	require.Equal([]*equipment.Profile{small, big}, actual)
+	small.DeadSpace = 1.5
+	require.NoError(store.UpdateProfile(small))
+	p, err := store.RetrieveProfile(small.ID)
+	require.NoError(err)
+	require.Equal(small, p)
+	require.Error(store.UpdateProfile(&equipment.Profile{ID: "100", Name: "None"}))
+	require.Error(store.UpdateProfile(&equipment.Profile{Name: "None"}))
+	_, err = store.RetrieveProfile("100")
+	require.Error(err)
+	require.NoError(store.DeleteProfile(big.ID))
+	require.Error(store.DeleteProfile(big.ID))
+	actual, err = store.ListProfiles()
+	require.NoError(err)
+	require.Equal([]*equipment.Profile{small}, actual)
+}
+
+func TestBrewProfile(t *testing.T) {
+	require := require.New(t)
+	store := newTestStore(t)
+	p, err := store.RetrieveBrewProfile("1")
+	require.NoError(err)
+	require.Nil(p)
+	first := &equipment.Profile{Name: "First", KettleSize: 30}
+	second := &equipment.Profile{Name: "Second", KettleSize: 50}
+	for _, p := range []*equipment.Profile{first, second} {
+		_, err := store.AddProfile(p)
+		require.NoError(err)
+	}
+	require.NoError(store.SetBrewProfile("1", first.ID))
+	require.NoError(store.SetBrewProfile("1", second.ID))
+	p, err = store.RetrieveBrewProfile("1")
+	require.NoError(err)
+	require.Equal(second, p)
+	require.Error(store.SetBrewProfile("100", first.ID))
+	require.NoError(store.DeleteProfile(second.ID))
+	p, err = store.RetrieveBrewProfile("1")
+	require.NoError(err)
+	require.Nil(p)
+	require.NoError(store.SetBrewProfile("1", first.ID))
+	require.NoError(store.SetBrewProfile("1", ""))
+	p, err = store.RetrieveBrewProfile("1")
+	require.NoError(err)
+	require.Nil(p)
+}
+
+func TestActiveProfile(t *testing.T) {
+	require := require.New(t)
+	store := newTestStore(t)
+	p, err := store.RetrieveActiveProfile()
+	require.NoError(err)
+	require.Nil(p)
+	first := &equipment.Profile{Name: "First", EvaporationRate: 10, Efficiency: 68.5}
+	second := &equipment.Profile{Name: "Second", EvaporationRate: 12}
+	for _, p := range []*equipment.Profile{first, second} {
+		_, err := store.AddProfile(p)
+		require.NoError(err)
+	}
+	require.NoError(store.SetActiveProfile(first.ID))
+	require.NoError(store.SetActiveProfile(second.ID))
+	require.Error(store.SetActiveProfile("100"))
+	p, err = store.RetrieveActiveProfile()
+	require.NoError(err)
+	second.Active = true
+	require.Equal(second, p)
+	// Updating the profile keeps it active
+	second.Efficiency = 71
+	require.NoError(store.UpdateProfile(second))
+	actual, err := store.ListProfiles()
+	require.NoError(err)
+	require.Equal([]*equipment.Profile{first, second}, actual)
+	require.NoError(store.SetActiveProfile(""))
+	p, err = store.RetrieveActiveProfile()
+	require.NoError(err)
+	require.Nil(p)
+}
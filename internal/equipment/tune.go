@@ -0,0 +1,112 @@
+package equipment
+
+import (
+	"brewday/internal/summary"
+	"brewday/internal/tools"
+	"fmt"
+	"sort"
+)
+
+// TuneWindow is the number of most recent brews averaged to tune a profile
+const TuneWindow = 5
+
+// Trend summarizes the history of a value measured in each brew
+type Trend struct {
+	// Brews is the number of brews where the value was measured
+	Brews int
+	// Average is the rolling average of the last TuneWindow brews
+	Average float32
+	// Slope is the change of the value per brew, from a least squares regression over all the brews
+	Slope float32
+	// Next is the value the regression predicts for the next brew
+	Next float32
+}
+
+// Tuning are the evaporation rate (%/h) and brewhouse efficiency (%) derived from the statistics of past brews
+type Tuning struct {
+	Evaporation Trend
+	Efficiency  Trend
+}
+
+// Tune derives the evaporation rate and the efficiency from the statistics of past brews
+// Brews are taken in the order they were finished, and values that were not measured (zero) are ignored
+func Tune(stats []*summary.Statistics) Tuning {
+	sorted := make([]*summary.Statistics, len(stats))
+	copy(sorted, stats)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].FinishedTime.Before(sorted[j].FinishedTime)
+	})
+	var evaporation, efficiency []float32
+	for _, s := range sorted {
+		if s.Evaporation > 0 {
+			evaporation = append(evaporation, s.Evaporation)
+		}
+		if s.Efficiency > 0 {
+			efficiency = append(efficiency, s.Efficiency)
+		}
+	}
+	return Tuning{
+		Evaporation: newTrend(evaporation),
+		Efficiency:  newTrend(efficiency),
+	}
+}
+
+// newTrend returns the rolling average and the linear regression of the values, oldest first
+func newTrend(values []float32) Trend {
+	n := len(values)
+	if n == 0 {
+		return Trend{}
+	}
+	var sum float32
+	for _, v := range values[max(n-TuneWindow, 0):] {
+		sum += v
+	}
+	t := Trend{
+		Brews:   n,
+		Average: sum / float32(min(n, TuneWindow)),
+	}
+	// The brews are numbered 0 to n-1 for the regression
+	var meanX, meanY float32
+	for i, v := range values {
+		meanX += float32(i)
+		meanY += v
+	}
+	meanX /= float32(n)
+	meanY /= float32(n)
+	var cov, varX float32
+	for i, v := range values {
+		dx := float32(i) - meanX
+		cov += dx * (v - meanY)
+		varX += dx * dx
+	}
+	if varX > 0 {
+		t.Slope = cov / varX
+	}
+	t.Next = meanY + t.Slope*(float32(n)-meanX)
+	return t
+}
+
+// Apply sets the evaporation rate and the efficiency of the profile to the rolling averages, rounded to one decimal
+// Values without any measured brew are left untouched. It returns whether the profile changed
+// If the tuned profile is not valid (e.g. after a mistyped statistic), the profile is left untouched and an error is returned
+func (t Tuning) Apply(p *Profile) (bool, error) {
+	tuned := *p
+	changed := false
+	if t.Evaporation.Brews > 0 && tuned.EvaporationRate != tools.RoundTo(t.Evaporation.Average, 1) {
+		tuned.EvaporationRate = tools.RoundTo(t.Evaporation.Average, 1)
+		changed = true
+	}
+	if t.Efficiency.Brews > 0 && tuned.Efficiency != tools.RoundTo(t.Efficiency.Average, 1) {
+		tuned.Efficiency = tools.RoundTo(t.Efficiency.Average, 1)
+		changed = true
+	}
+	if !changed {
+		return false, nil
+	}
+	err := tuned.Validate()
+	if err != nil {
+		return false, fmt.Errorf("the tuned equipment profile is not valid: %w", err)
+	}
+	*p = tuned
+	return true, nil
+}
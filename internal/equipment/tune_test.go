@@ -0,0 +1,97 @@
+package equipment
+
+import (
+	"brewday/internal/summary"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTune(t *testing.T) {
+	type testCase struct {
+		Name     string
+		Stats    []*summary.Statistics
+		Expected Tuning
+	}
+	day := func(d int) time.Time {
+		return time.Date(2026, time.January, d, 0, 0, 0, 0, time.UTC)
+	}
+	testCases := []testCase{
+		{
+			Name:     "No stats",
+			Stats:    nil,
+			Expected: Tuning{},
+		},
+		{
+			Name: "One brew",
+			Stats: []*summary.Statistics{
+				{Evaporation: 12, Efficiency: 70, FinishedTime: day(1)},
+			},
+			Expected: Tuning{
+				Evaporation: Trend{Brews: 1, Average: 12, Next: 12},
+				Efficiency:  Trend{Brews: 1, Average: 70, Next: 70},
+			},
+		},
+		{
+			Name: "Rising efficiency in any order, missing values ignored",
+			Stats: []*summary.Statistics{
+				{Evaporation: 10, Efficiency: 64, FinishedTime: day(3)},
+				{Evaporation: 10, Efficiency: 60, FinishedTime: day(1)},
+				{Evaporation: 0, Efficiency: 62, FinishedTime: day(2)},
+			},
+			Expected: Tuning{
+				Evaporation: Trend{Brews: 2, Average: 10, Next: 10},
+				Efficiency:  Trend{Brews: 3, Average: 62, Slope: 2, Next: 66},
+			},
+		},
+		{
+			Name: "Only the last brews are averaged",
+			Stats: []*summary.Statistics{
+				{Efficiency: 50, FinishedTime: day(1)},
+				{Efficiency: 70, FinishedTime: day(2)},
+				{Efficiency: 70, FinishedTime: day(3)},
+				{Efficiency: 70, FinishedTime: day(4)},
+				{Efficiency: 70, FinishedTime: day(5)},
+				{Efficiency: 70, FinishedTime: day(6)},
+			},
+			Expected: Tuning{
+				Efficiency: Trend{Brews: 6, Average: 70, Slope: 20.0 / 7, Next: 66.66667 + 20.0/7*3.5},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			require := require.New(t)
+			actual := Tune(tc.Stats)
+			for _, pair := range [][2]Trend{{tc.Expected.Evaporation, actual.Evaporation}, {tc.Expected.Efficiency, actual.Efficiency}} {
+				require.Equal(pair[0].Brews, pair[1].Brews)
+				require.InDelta(pair[0].Average, pair[1].Average, 0.001)
+				require.InDelta(pair[0].Slope, pair[1].Slope, 0.001)
+				require.InDelta(pair[0].Next, pair[1].Next, 0.001)
+			}
+		})
+	}
+}
+
+func TestTuningApply(t *testing.T) {
+	require := require.New(t)
+	p := &Profile{Name: "Kettle", EvaporationRate: 10, Efficiency: 65}
+	tuning := Tuning{Efficiency: Trend{Brews: 3, Average: 71.26}}
+	changed, err := tuning.Apply(p)
+	require.NoError(err)
+	require.True(changed)
+	require.Equal(float32(10), p.EvaporationRate)
+	require.Equal(float32(71.3), p.Efficiency)
+	changed, err = tuning.Apply(p)
+	require.NoError(err)
+	require.False(changed)
+	changed, err = Tuning{}.Apply(p)
+	require.NoError(err)
+	require.False(changed)
+	// An efficiency above 100 % is refused and the profile is left untouched
+	changed, err = Tuning{Evaporation: Trend{Brews: 1, Average: 12}, Efficiency: Trend{Brews: 1, Average: 710}}.Apply(p)
+	require.Error(err)
+	require.False(changed)
+	require.Equal(&Profile{Name: "Kettle", EvaporationRate: 10, Efficiency: 71.3}, p)
+}
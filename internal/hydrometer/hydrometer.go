@@ -0,0 +1,107 @@
+package hydrometer
+
+import (
+	"brewday/internal/tools"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DeviceType is the kind of wireless hydrometer, it defines the format of its readings
+type DeviceType string
+
+const (
+	// DeviceTypeISpindel is an iSpindel (or compatible, e.g. RAPT Pill) sending the generic HTTP JSON payload
+	DeviceTypeISpindel DeviceType = "ispindel"
+	// DeviceTypeTilt is a Tilt sending readings through TiltPi or the Tilt app cloud logging
+	DeviceTypeTilt DeviceType = "tilt"
+)
+
+// GravityUnit is the unit of the gravity calculated by the calibration of a device, or reported by it without one
+type GravityUnit string
+
+const (
+	// GravityUnitSG is the specific gravity (e.g. 1.048). It is the default
+	GravityUnitSG GravityUnit = "sg"
+	// GravityUnitPlato is degrees Plato (e.g. 12), the unit of many iSpindel calibrations
+	GravityUnitPlato GravityUnit = "plato"
+)
+
+// Device is a wireless hydrometer that sends readings to the app
+type Device struct {
+	// Name of the device. For an iSpindel it is its name, for a Tilt its color
+	Name string
+	// Type is the kind of hydrometer
+	Type DeviceType
+	// Calibration are the coefficients of a polynomial, from the constant term up, that calculates the gravity
+	// For an iSpindel it is a function of the tilt angle, for a Tilt of the gravity it reports
+	// If it is empty, the gravity reported by the device is used
+	Calibration []float32
+	// Unit is the unit of the calibrated gravity, or of the reported one without calibration. Empty means specific gravity
+	Unit GravityUnit
+}
+
+// Reading is a reading sent by a wireless hydrometer
+type Reading struct {
+	// Device is the name of the device that sent the reading
+	Device string
+	// Gravity is the specific gravity reported by the device
+	Gravity float32
+	// Temperature is the temperature of the beer in °C
+	Temperature float32
+	// Angle is the tilt angle in degrees, only sent by an iSpindel
+	Angle float32
+	// Battery is the battery voltage, only sent by an iSpindel
+	Battery float32
+	// Date is when the reading was received
+	Date time.Time
+}
+
+// Validate returns an error if the device has no name, an unknown type or an unknown gravity unit
+func (d *Device) Validate() error {
+	if strings.TrimSpace(d.Name) == "" {
+		return fmt.Errorf("hydrometer name is missing")
+	}
+	switch d.Type {
+	case DeviceTypeISpindel, DeviceTypeTilt:
+	default:
+		return fmt.Errorf("invalid hydrometer type %s for %s", d.Type, d.Name)
+	}
+	switch d.Unit {
+	case "", GravityUnitSG, GravityUnitPlato:
+		return nil
+	default:
+		return fmt.Errorf("invalid gravity unit %s for %s", d.Unit, d.Name)
+	}
+}
+
+// Matches returns whether a reading was sent by the device. Names are compared ignoring the case
+func (d *Device) Matches(r *Reading) bool {
+	return strings.EqualFold(strings.TrimSpace(d.Name), strings.TrimSpace(r.Device))
+}
+
+// Calibrate returns the specific gravity of a reading of the device, applying its calibration
+// The result is converted from °P when the unit of the device is plato
+func (d *Device) Calibrate(r *Reading) float32 {
+	gravity := r.Gravity
+	if len(d.Calibration) > 0 {
+		x := r.Gravity
+		if d.Type == DeviceTypeISpindel {
+			x = r.Angle
+		}
+		gravity = Polynomial(d.Calibration, x)
+	}
+	if d.Unit == GravityUnitPlato {
+		gravity = tools.PlatoToSG(gravity)
+	}
+	return tools.RoundTo(gravity, 4)
+}
+
+// Polynomial evaluates the polynomial with the given coefficients (from the constant term up) at x
+func Polynomial(coefficients []float32, x float32) float32 {
+	var result float64
+	for i := len(coefficients) - 1; i >= 0; i-- {
+		result = result*float64(x) + float64(coefficients[i])
+	}
+	return float32(result)
+}
@@ -0,0 +1,90 @@
+package hydrometer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	require := require.New(t)
+	require.NoError((&Device{Name: "iSpindel001", Type: DeviceTypeISpindel}).Validate())
+	require.NoError((&Device{Name: "red", Type: DeviceTypeTilt}).Validate())
+	require.Error((&Device{Name: " ", Type: DeviceTypeTilt}).Validate())
+	require.Error((&Device{Name: "plaato", Type: "airlock"}).Validate())
+	require.NoError((&Device{Name: "iSpindel001", Type: DeviceTypeISpindel, Unit: GravityUnitPlato}).Validate())
+	require.Error((&Device{Name: "iSpindel001", Type: DeviceTypeISpindel, Unit: "brix"}).Validate())
+}
+
+func TestMatches(t *testing.T) {
+	require := require.New(t)
+	d := &Device{Name: "Red", Type: DeviceTypeTilt}
+	require.True(d.Matches(&Reading{Device: "RED"}))
+	require.False(d.Matches(&Reading{Device: "BLUE"}))
+}
+
+func TestPolynomial(t *testing.T) {
+	require := require.New(t)
+	require.InDelta(9, Polynomial([]float32{1, 2, 1}, 2), 0.0001)
+	require.InDelta(3, Polynomial([]float32{3}, 10), 0.0001)
+	require.Zero(Polynomial(nil, 10))
+}
+
+func TestCalibrate(t *testing.T) {
+	require := require.New(t)
+	testCases := []struct {
+		Name     string
+		Device   Device
+		Reading  Reading
+		Expected float32
+	}{
+		{
+			Name:     "No calibration",
+			Device:   Device{Name: "red", Type: DeviceTypeTilt},
+			Reading:  Reading{Gravity: 1.048},
+			Expected: 1.048,
+		},
+		{
+			Name:     "No calibration in plato",
+			Device:   Device{Name: "iSpindel001", Type: DeviceTypeISpindel, Unit: GravityUnitPlato},
+			Reading:  Reading{Gravity: 12, Angle: 60},
+			Expected: 1.0484,
+		},
+		{
+			Name:     "Tilt offset",
+			Device:   Device{Name: "red", Type: DeviceTypeTilt, Calibration: []float32{-0.002, 1}},
+			Reading:  Reading{Gravity: 1.050},
+			Expected: 1.048,
+		},
+		{
+			Name:     "iSpindel angle to plato",
+			Device:   Device{Name: "iSpindel001", Type: DeviceTypeISpindel, Calibration: []float32{-10.4, 0.2, 0.002}, Unit: GravityUnitPlato},
+			Reading:  Reading{Gravity: 1.1, Angle: 50},
+			Expected: 1.0181,
+		},
+		{
+			Name:     "iSpindel angle to sg",
+			Device:   Device{Name: "iSpindel001", Type: DeviceTypeISpindel, Calibration: []float32{0.9, 0.0025}},
+			Reading:  Reading{Angle: 60},
+			Expected: 1.05,
+		},
+		{
+			Name:     "Explicit sg",
+			Device:   Device{Name: "iSpindel001", Type: DeviceTypeISpindel, Calibration: []float32{0.9, 0.0025}, Unit: GravityUnitSG},
+			Reading:  Reading{Angle: 60},
+			Expected: 1.05,
+		},
+		{
+			// A calibration in °P can give a reading below 2 at the end of a fermentation, it is still °P
+			Name:     "Low plato",
+			Device:   Device{Name: "iSpindel001", Type: DeviceTypeISpindel, Calibration: []float32{-10.4, 0.2, 0.002}, Unit: GravityUnitPlato},
+			Reading:  Reading{Angle: 45},
+			Expected: 1.0103,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			require.InDelta(tc.Expected, tc.Device.Calibrate(&tc.Reading), 0.00011)
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package memory
+
+import (
+	"maps"
+	"sync"
+)
+
+// HydrometerMemoryStore represents a hydrometer store stored in memory
+type HydrometerMemoryStore struct {
+	lock        sync.Mutex
+	assignments map[string]string
+}
+
+// NewHydrometerMemoryStore creates a new HydrometerMemoryStore
+func NewHydrometerMemoryStore() *HydrometerMemoryStore {
+	return &HydrometerMemoryStore{
+		assignments: make(map[string]string),
+	}
+}
+
+// AssignDevice assigns a hydrometer to the recipe whose fermentation it measures. An empty recipe id removes the assignment
+func (s *HydrometerMemoryStore) AssignDevice(device, recipeID string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if recipeID == "" {
+		delete(s.assignments, device)
+		return nil
+	}
+	s.assignments[device] = recipeID
+	return nil
+}
+
+// RetrieveDeviceRecipe retrieves the id of the recipe a hydrometer is assigned to
+// It returns an empty id if the device is not assigned
+func (s *HydrometerMemoryStore) RetrieveDeviceRecipe(device string) (string, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.assignments[device], nil
+}
+
+// ListAssignments lists the recipe ids of the assigned hydrometers, by device
+func (s *HydrometerMemoryStore) ListAssignments() (map[string]string, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return maps.Clone(s.assignments), nil
+}
@@ -0,0 +1,99 @@
+package hydrometer
+
+import (
+	"brewday/internal/tools"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ISpindelPayload is the generic HTTP JSON payload sent by an iSpindel
+// The gravity is in the unit configured in the iSpindel (SG or °P)
+type ISpindelPayload struct {
+	Name        string  `json:"name"`
+	ID          int     `json:"ID"`
+	Angle       float32 `json:"angle"`
+	Temperature float32 `json:"temperature"`
+	// TempUnits is C, F or K. It is C if it is not sent
+	TempUnits string  `json:"temp_units"`
+	Battery   float32 `json:"battery"`
+	Gravity   float32 `json:"gravity"`
+	Interval  int     `json:"interval"`
+	RSSI      int     `json:"RSSI"`
+}
+
+// TiltPayload is the payload sent by TiltPi or the cloud logging of the Tilt app, as JSON or as a form
+// The values are sent as numbers or as strings
+type TiltPayload struct {
+	// Color is the color of the Tilt, it identifies the device
+	Color string `json:"Color" form:"Color"`
+	// Temp is the temperature in °F unless TempUnits is C
+	Temp      json.Number `json:"Temp" form:"Temp"`
+	TempUnits string      `json:"TempUnits" form:"TempUnits"`
+	SG        json.Number `json:"SG" form:"SG"`
+	Beer      string      `json:"Beer" form:"Beer"`
+	Comment   string      `json:"Comment" form:"Comment"`
+	Timepoint string      `json:"Timepoint" form:"Timepoint"`
+}
+
+// ToReading returns the reading of the payload received at the given time
+func (p *ISpindelPayload) ToReading(now time.Time) (*Reading, error) {
+	if strings.TrimSpace(p.Name) == "" {
+		return nil, fmt.Errorf("iSpindel reading without name")
+	}
+	temperature, err := toCelsius(p.Temperature, p.TempUnits, "C")
+	if err != nil {
+		return nil, err
+	}
+	return &Reading{
+		Device:      strings.TrimSpace(p.Name),
+		Gravity:     p.Gravity,
+		Temperature: temperature,
+		Angle:       p.Angle,
+		Battery:     p.Battery,
+		Date:        now,
+	}, nil
+}
+
+// ToReading returns the reading of the payload received at the given time
+func (p *TiltPayload) ToReading(now time.Time) (*Reading, error) {
+	if strings.TrimSpace(p.Color) == "" {
+		return nil, fmt.Errorf("tilt reading without color")
+	}
+	sg, err := p.SG.Float64()
+	if err != nil {
+		return nil, fmt.Errorf("invalid tilt gravity %s: %w", p.SG, err)
+	}
+	temp, err := p.Temp.Float64()
+	if err != nil {
+		return nil, fmt.Errorf("invalid tilt temperature %s: %w", p.Temp, err)
+	}
+	temperature, err := toCelsius(float32(temp), p.TempUnits, "F")
+	if err != nil {
+		return nil, err
+	}
+	return &Reading{
+		Device:      strings.TrimSpace(p.Color),
+		Gravity:     float32(sg),
+		Temperature: temperature,
+		Date:        now,
+	}, nil
+}
+
+// toCelsius converts a temperature in the given unit (C, F or K) to °C. An empty unit is the default one
+func toCelsius(temperature float32, unit, defaultUnit string) (float32, error) {
+	if unit == "" {
+		unit = defaultUnit
+	}
+	switch strings.ToUpper(unit) {
+	case "C":
+		return tools.RoundTo(temperature, 2), nil
+	case "F":
+		return tools.RoundTo(tools.FahrenheitToCelsius(temperature), 2), nil
+	case "K":
+		return tools.RoundTo(temperature-273.15, 2), nil
+	default:
+		return 0, fmt.Errorf("invalid temperature unit %s", unit)
+	}
+}
@@ -0,0 +1,104 @@
+package hydrometer
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestISpindelToReading(t *testing.T) {
+	require := require.New(t)
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		Name     string
+		Body     string
+		Expected *Reading
+		Error    bool
+	}{
+		{
+			Name:     "Celsius",
+			Body:     `{"name":"iSpindel001","ID":1234567,"angle":61.2,"temperature":21.3,"temp_units":"C","battery":4.1,"gravity":1.048,"interval":900,"RSSI":-76}`,
+			Expected: &Reading{Device: "iSpindel001", Gravity: 1.048, Temperature: 21.3, Angle: 61.2, Battery: 4.1, Date: now},
+		},
+		{
+			Name:     "Fahrenheit without unit name",
+			Body:     `{"name":"iSpindel001","angle":40,"temperature":68,"temp_units":"F","gravity":8}`,
+			Expected: &Reading{Device: "iSpindel001", Gravity: 8, Temperature: 20, Angle: 40, Date: now},
+		},
+		{
+			Name:     "Default unit",
+			Body:     `{"name":"iSpindel001","temperature":18}`,
+			Expected: &Reading{Device: "iSpindel001", Temperature: 18, Date: now},
+		},
+		{
+			Name:  "Invalid unit",
+			Body:  `{"name":"iSpindel001","temperature":18,"temp_units":"X"}`,
+			Error: true,
+		},
+		{
+			Name:  "No name",
+			Body:  `{"angle":61.2,"temperature":21.3}`,
+			Error: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			var p ISpindelPayload
+			require.NoError(json.Unmarshal([]byte(tc.Body), &p))
+			actual, err := p.ToReading(now)
+			if tc.Error {
+				require.Error(err)
+				return
+			}
+			require.NoError(err)
+			require.Equal(tc.Expected, actual)
+		})
+	}
+}
+
+func TestTiltToReading(t *testing.T) {
+	require := require.New(t)
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		Name     string
+		Body     string
+		Expected *Reading
+		Error    bool
+	}{
+		{
+			Name:     "TiltPi strings",
+			Body:     `{"Timepoint":"45000.5","Temp":"68","SG":"1.050","Beer":"Helles","Color":"RED","Comment":""}`,
+			Expected: &Reading{Device: "RED", Gravity: 1.05, Temperature: 20, Date: now},
+		},
+		{
+			Name:     "Numbers in celsius",
+			Body:     `{"Temp":18.5,"TempUnits":"C","SG":1.0125,"Color":"BLUE"}`,
+			Expected: &Reading{Device: "BLUE", Gravity: 1.0125, Temperature: 18.5, Date: now},
+		},
+		{
+			Name:  "No gravity",
+			Body:  `{"Temp":"68","Color":"RED"}`,
+			Error: true,
+		},
+		{
+			Name:  "No color",
+			Body:  `{"Temp":"68","SG":"1.050"}`,
+			Error: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			var p TiltPayload
+			require.NoError(json.Unmarshal([]byte(tc.Body), &p))
+			actual, err := p.ToReading(now)
+			if tc.Error {
+				require.Error(err)
+				return
+			}
+			require.NoError(err)
+			require.Equal(tc.Expected, actual)
+		})
+	}
+}
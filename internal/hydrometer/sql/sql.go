@@ -0,0 +1,60 @@
+package sql
+
+import (
+	"database/sql"
+	"errors"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+type HydrometerPersistentStore struct {
+	dbClient *sql.DB
+}
+
+// NewHydrometerPersistentStore creates a new HydrometerStore
+func NewHydrometerPersistentStore(db *sql.DB) (*HydrometerPersistentStore, error) {
+	return &HydrometerPersistentStore{
+		dbClient: db,
+	}, nil
+}
+
+// AssignDevice assigns a hydrometer to the recipe whose fermentation it measures. An empty recipe id removes the assignment
+func (s *HydrometerPersistentStore) AssignDevice(device, recipeID string) error {
+	if recipeID == "" {
+		_, err := s.dbClient.Exec(`DELETE FROM hydrometers WHERE device == ?`, device)
+		return err
+	}
+	_, err := s.dbClient.Exec(`INSERT INTO hydrometers (device, recipe_id) VALUES (?, ?) ON CONFLICT(device) DO UPDATE SET recipe_id = excluded.recipe_id`,
+		device, recipeID)
+	return err
+}
+
+// RetrieveDeviceRecipe retrieves the id of the recipe a hydrometer is assigned to
+// It returns an empty id if the device is not assigned
+func (s *HydrometerPersistentStore) RetrieveDeviceRecipe(device string) (string, error) {
+	var recipeID string
+	err := s.dbClient.QueryRow(`SELECT recipe_id FROM hydrometers WHERE device == ?`, device).Scan(&recipeID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	return recipeID, err
+}
+
+// ListAssignments lists the recipe ids of the assigned hydrometers, by device
+func (s *HydrometerPersistentStore) ListAssignments() (map[string]string, error) {
+	rows, err := s.dbClient.Query(`SELECT device, recipe_id FROM hydrometers`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	assignments := make(map[string]string)
+	for rows.Next() {
+		var device, recipeID string
+		err := rows.Scan(&device, &recipeID)
+		if err != nil {
+			return nil, err
+		}
+		assignments[device] = recipeID
+	}
+	return assignments, rows.Err()
+}
@@ -0,0 +1,55 @@
+package sql
+
+import (
+	"database/sql"
+	"os"
+	"strings"
+	"testing"
+
+	dbmigrations "brewday/internal/db_migrations"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestStore(t *testing.T) (*HydrometerPersistentStore, *sql.DB) {
+	fileName := strings.ToLower(strings.TrimSpace(t.Name())) + ".sqlite"
+	db, err := sql.Open("sqlite3", "file:"+fileName+"?_foreign_keys=true")
+	require.NoError(t, err)
+	require.NoError(t, dbmigrations.RunMigrations(db, "migrations"))
+	for _, name := range []string{"Helles", "Weizen"} {
+		_, err = db.Exec(`INSERT INTO recipes (name, status) VALUES (?, ?)`, name, 0)
+		require.NoError(t, err)
+	}
+	store, err := NewHydrometerPersistentStore(db)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		db.Close()
+		os.Remove(fileName)
+	})
+	return store, db
+}
+
+func TestAssignDevice(t *testing.T) {
+	require := require.New(t)
+	store, db := newTestStore(t)
+	id, err := store.RetrieveDeviceRecipe("iSpindel001")
+	require.NoError(err)
+	require.Empty(id)
+	require.NoError(store.AssignDevice("iSpindel001", "1"))
+	require.NoError(store.AssignDevice("iSpindel001", "2"))
+	require.NoError(store.AssignDevice("red", "1"))
+	require.Error(store.AssignDevice("blue", "100"))
+	id, err = store.RetrieveDeviceRecipe("iSpindel001")
+	require.NoError(err)
+	require.Equal("2", id)
+	assignments, err := store.ListAssignments()
+	require.NoError(err)
+	require.Equal(map[string]string{"iSpindel001": "2", "red": "1"}, assignments)
+	require.NoError(store.AssignDevice("red", ""))
+	_, err = db.Exec(`DELETE FROM recipes WHERE id == 2`)
+	require.NoError(err)
+	assignments, err = store.ListAssignments()
+	require.NoError(err)
+	require.Empty(assignments)
+}
@@ -0,0 +1,207 @@
+package inventory
+
+import (
+	"brewday/internal/recipe"
+	"sort"
+	"strings"
+	"time"
+)
+
+// IngredientType is the kind of ingredient of an item in the inventory
+type IngredientType string
+
+const (
+	// IngredientMalt is a malt (or any fermentable used in the mash)
+	IngredientMalt IngredientType = "malt"
+	// IngredientHop is a hop
+	IngredientHop IngredientType = "hop"
+	// IngredientYeast is a yeast
+	IngredientYeast IngredientType = "yeast"
+	// IngredientOther is any other ingredient (spices, sugar, fruits, ...)
+	IngredientOther IngredientType = "other"
+)
+
+// defaultUnit is the unit of the amount of the items that do not have one
+const defaultUnit = "g"
+
+// IngredientTypes are all the ingredient types, in the order they are shown
+var IngredientTypes = []IngredientType{IngredientMalt, IngredientHop, IngredientYeast, IngredientOther}
+
+// ValidIngredientType returns whether the given type is one of the known ingredient types
+func ValidIngredientType(t IngredientType) bool {
+	for _, it := range IngredientTypes {
+		if it == t {
+			return true
+		}
+	}
+	return false
+}
+
+// Item is an ingredient in stock
+type Item struct {
+	// ID is the identifier of the item. This is populated by the appropriate store and should not be set manually
+	ID string
+	// Type is the kind of ingredient
+	Type IngredientType
+	// Name of the ingredient, it is matched (case insensitive) with the names in the recipes
+	Name string
+	// Amount in stock, in grams unless the item has another unit
+	Amount float32
+	// Unit is the OPTIONAL unit of the amount, as used in the recipes. Other ingredients may be counted (e.g. pcs)
+	Unit string
+	// Lot is the lot number of the ingredient, if known
+	Lot string
+	// Alpha is the alpha acid percentage, only for hops
+	Alpha float32
+	// BestBefore is the best before date. It is zero if unknown
+	BestBefore time.Time
+}
+
+// AmountUnit returns the unit of the amount of the item, grams if it has none
+func (i *Item) AmountUnit() string {
+	if strings.TrimSpace(i.Unit) == "" {
+		return defaultUnit
+	}
+	return i.Unit
+}
+
+// UnitOf returns the unit of the amount of an ingredient, from the first matching item or grams if there is none
+func UnitOf(items []*Item, t IngredientType, name string) string {
+	for _, it := range items {
+		if it.Matches(t, name) {
+			return it.AmountUnit()
+		}
+	}
+	return defaultUnit
+}
+
+// Expired returns whether the item is past its best before date
+func (i *Item) Expired(now time.Time) bool {
+	return !i.BestBefore.IsZero() && now.After(i.BestBefore)
+}
+
+// Matches returns whether the item is the given ingredient
+// Names are compared ignoring case and surrounding spaces
+func (i *Item) Matches(t IngredientType, name string) bool {
+	return i.Type == t && normalizeName(i.Name) == normalizeName(name)
+}
+
+// normalizeName returns the name used to compare ingredients
+func normalizeName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// Requirement is the amount of an ingredient needed to brew a recipe and the amount in stock
+type Requirement struct {
+	Type IngredientType
+	Name string
+	// Required is the amount in grams needed by the recipe
+	Required float32
+	// Available is the amount in grams in stock that is not expired
+	Available float32
+}
+
+// Missing returns the amount in grams that is needed but not in stock
+func (r Requirement) Missing() float32 {
+	if r.Available >= r.Required {
+		return 0
+	}
+	return r.Required - r.Available
+}
+
+// Enough returns whether there is enough stock of the ingredient
+func (r Requirement) Enough() bool {
+	return r.Missing() == 0
+}
+
+// Requirements returns the ingredients needed to brew the recipe
+// Ingredients with the same type and name are added together
+func Requirements(re *recipe.Recipe) []Requirement {
+	var reqs []Requirement
+	add := func(t IngredientType, name string, amount float32) {
+		if strings.TrimSpace(name) == "" || amount <= 0 {
+			return
+		}
+		for i := range reqs {
+			if reqs[i].Type == t && normalizeName(reqs[i].Name) == normalizeName(name) {
+				reqs[i].Required += amount
+				return
+			}
+		}
+		reqs = append(reqs, Requirement{Type: t, Name: strings.TrimSpace(name), Required: amount})
+	}
+	for _, m := range re.Mashing.Malts {
+		add(IngredientMalt, m.Name, m.Amount)
+	}
+	for _, h := range re.Hopping.Hops {
+		add(IngredientHop, h.Name, h.Amount)
+	}
+	for _, a := range re.Hopping.AdditionalIngredients {
+		add(IngredientOther, a.Name, a.Amount)
+	}
+	add(IngredientYeast, re.Fermentation.Yeast.Name, re.Fermentation.Yeast.Amount)
+	for _, a := range re.Fermentation.AdditionalIngredients {
+		add(IngredientOther, a.Name, a.Amount)
+	}
+	return reqs
+}
+
+// Check returns the ingredients needed to brew the recipe with the amount available in the given items
+// Expired items are not counted as available
+func Check(re *recipe.Recipe, items []*Item, now time.Time) []Requirement {
+	reqs := Requirements(re)
+	for i := range reqs {
+		for _, it := range items {
+			if it.Matches(reqs[i].Type, reqs[i].Name) && !it.Expired(now) {
+				reqs[i].Available += it.Amount
+			}
+		}
+	}
+	return reqs
+}
+
+// CanBrew returns whether there is enough stock of all the requirements
+func CanBrew(reqs []Requirement) bool {
+	for _, r := range reqs {
+		if !r.Enough() {
+			return false
+		}
+	}
+	return true
+}
+
+// Deduct takes the given amount of an ingredient from the matching items
+// Items closer to their best before date are used first, items without date are used last
+// It returns the modified items and the amount that could not be deducted because there was not enough stock
+func Deduct(items []*Item, t IngredientType, name string, amount float32) ([]*Item, float32) {
+	var matching []*Item
+	for _, it := range items {
+		if it.Matches(t, name) && it.Amount > 0 {
+			matching = append(matching, it)
+		}
+	}
+	sort.SliceStable(matching, func(i, j int) bool {
+		bi, bj := matching[i].BestBefore, matching[j].BestBefore
+		if bi.IsZero() || bj.IsZero() {
+			return !bi.IsZero() && bj.IsZero()
+		}
+		return bi.Before(bj)
+	})
+	var modified []*Item
+	for _, it := range matching {
+		if amount <= 0 {
+			break
+		}
+		used := amount
+		if it.Amount < used {
+			used = it.Amount
+		}
+		it.Amount -= used
+		amount -= used
+		modified = append(modified, it)
+	}
+	if amount < 0 {
+		amount = 0
+	}
+	return modified, amount
+}
@@ -0,0 +1,168 @@
+package inventory
+
+import (
+	"brewday/internal/recipe"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testRecipe() *recipe.Recipe {
+	return &recipe.Recipe{
+		Name: "Test",
+		Mashing: recipe.MashInstructions{
+			Malts: []recipe.Malt{{Name: "Pilsner", Amount: 4000}, {Name: "Munich", Amount: 500}},
+		},
+		Hopping: recipe.HopInstructions{
+			Hops: []recipe.Hops{
+				{Name: "Magnum", Amount: 20, Duration: 60},
+				{Name: "Cascade", Amount: 30, Duration: 10},
+				{Name: "cascade ", Amount: 50, DryHop: true},
+			},
+			AdditionalIngredients: []recipe.AdditionalIngredient{{Name: "Koriander", Amount: 10, Duration: 5}},
+		},
+		Fermentation: recipe.FermentationInstructions{
+			Yeast: recipe.Yeast{Name: "US-05", Amount: 11.5},
+		},
+	}
+}
+
+func TestRequirements(t *testing.T) {
+	require := require.New(t)
+	expected := []Requirement{
+		{Type: IngredientMalt, Name: "Pilsner", Required: 4000},
+		{Type: IngredientMalt, Name: "Munich", Required: 500},
+		{Type: IngredientHop, Name: "Magnum", Required: 20},
+		{Type: IngredientHop, Name: "Cascade", Required: 80},
+		{Type: IngredientOther, Name: "Koriander", Required: 10},
+		{Type: IngredientYeast, Name: "US-05", Required: 11.5},
+	}
+	require.Equal(expected, Requirements(testRecipe()))
+}
+
+func TestCheck(t *testing.T) {
+	require := require.New(t)
+	now := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+	type testCase struct {
+		Name    string
+		Items   []*Item
+		CanBrew bool
+		Missing map[string]float32
+	}
+	testCases := []testCase{
+		{
+			Name:    "Empty inventory",
+			Items:   nil,
+			CanBrew: false,
+			Missing: map[string]float32{"Pilsner": 4000, "Munich": 500, "Magnum": 20, "Cascade": 80, "Koriander": 10, "US-05": 11.5},
+		},
+		{
+			Name: "Everything in stock",
+			Items: []*Item{
+				{Type: IngredientMalt, Name: "pilsner", Amount: 3000},
+				{Type: IngredientMalt, Name: "Pilsner", Amount: 2000},
+				{Type: IngredientMalt, Name: "Munich", Amount: 500},
+				{Type: IngredientHop, Name: "Magnum", Amount: 100},
+				{Type: IngredientHop, Name: "Cascade", Amount: 100},
+				{Type: IngredientOther, Name: "Koriander", Amount: 10},
+				{Type: IngredientYeast, Name: "US-05", Amount: 23},
+			},
+			CanBrew: true,
+			Missing: map[string]float32{"Pilsner": 0, "Munich": 0, "Magnum": 0, "Cascade": 0, "Koriander": 0, "US-05": 0},
+		},
+		{
+			Name: "Expired and wrong type are not counted",
+			Items: []*Item{
+				{Type: IngredientMalt, Name: "Pilsner", Amount: 5000},
+				{Type: IngredientMalt, Name: "Munich", Amount: 500},
+				{Type: IngredientHop, Name: "Magnum", Amount: 100, BestBefore: now.Add(-24 * time.Hour)},
+				{Type: IngredientHop, Name: "Cascade", Amount: 60, BestBefore: now.Add(24 * time.Hour)},
+				{Type: IngredientMalt, Name: "Koriander", Amount: 10},
+				{Type: IngredientYeast, Name: "US-05", Amount: 11.5},
+			},
+			CanBrew: false,
+			Missing: map[string]float32{"Pilsner": 0, "Munich": 0, "Magnum": 20, "Cascade": 20, "Koriander": 10, "US-05": 0},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			reqs := Check(testRecipe(), tc.Items, now)
+			require.Equal(tc.CanBrew, CanBrew(reqs))
+			require.Len(reqs, len(tc.Missing))
+			for _, r := range reqs {
+				require.InDelta(tc.Missing[r.Name], r.Missing(), 0.001, r.Name)
+			}
+		})
+	}
+}
+
+func TestDeduct(t *testing.T) {
+	require := require.New(t)
+	day := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+	type testCase struct {
+		Name            string
+		Amount          float32
+		ExpectedAmounts []float32
+		ExpectedMissing float32
+		ExpectedChanged int
+	}
+	testCases := []testCase{
+		{
+			Name:            "Closest best before first",
+			Amount:          300,
+			ExpectedAmounts: []float32{1000, 700, 500, 50},
+			ExpectedMissing: 0,
+			ExpectedChanged: 1,
+		},
+		{
+			Name:            "Several items",
+			Amount:          1300,
+			ExpectedAmounts: []float32{700, 0, 500, 50},
+			ExpectedMissing: 0,
+			ExpectedChanged: 2,
+		},
+		{
+			Name:            "Items without date last",
+			Amount:          2100,
+			ExpectedAmounts: []float32{0, 0, 400, 50},
+			ExpectedMissing: 0,
+			ExpectedChanged: 3,
+		},
+		{
+			Name:            "Not enough stock",
+			Amount:          3000,
+			ExpectedAmounts: []float32{0, 0, 0, 50},
+			ExpectedMissing: 500,
+			ExpectedChanged: 3,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			items := []*Item{
+				{ID: "1", Type: IngredientMalt, Name: "Pilsner", Amount: 1000, BestBefore: day.Add(48 * time.Hour)},
+				{ID: "2", Type: IngredientMalt, Name: "Pilsner", Amount: 1000, BestBefore: day},
+				{ID: "3", Type: IngredientMalt, Name: "PILSNER", Amount: 500},
+				{ID: "4", Type: IngredientHop, Name: "Pilsner", Amount: 50},
+			}
+			changed, missing := Deduct(items, IngredientMalt, "Pilsner", tc.Amount)
+			require.InDelta(tc.ExpectedMissing, missing, 0.001)
+			require.Len(changed, tc.ExpectedChanged)
+			for i, it := range items {
+				require.InDelta(tc.ExpectedAmounts[i], it.Amount, 0.001, it.ID)
+			}
+		})
+	}
+}
+
+func TestUnitOf(t *testing.T) {
+	require := require.New(t)
+	items := []*Item{
+		{Type: IngredientHop, Name: "Cascade", Amount: 100},
+		{Type: IngredientOther, Name: "Vanilla pods", Amount: 3, Unit: "pcs"},
+	}
+	require.Equal("g", UnitOf(items, IngredientHop, "cascade"))
+	require.Equal("pcs", UnitOf(items, IngredientOther, "Vanilla Pods"))
+	require.Equal("g", UnitOf(items, IngredientOther, "Koriander"))
+	require.Equal("g", UnitOf(nil, IngredientOther, "Vanilla pods"))
+}
@@ -0,0 +1,101 @@
+package memory
+
+import (
+	"brewday/internal/inventory"
+	"errors"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// InventoryMemoryStore represents an inventory store stored in memory
+type InventoryMemoryStore struct {
+	lock   sync.Mutex
+	items  map[string]*inventory.Item
+	lastID int
+}
+
+// NewInventoryMemoryStore creates a new InventoryMemoryStore
+func NewInventoryMemoryStore() *InventoryMemoryStore {
+	return &InventoryMemoryStore{
+		items: make(map[string]*inventory.Item),
+	}
+}
+
+// AddItem adds an item to the inventory and returns its identifier
+func (s *InventoryMemoryStore) AddItem(item *inventory.Item) (string, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.lastID++
+	id := strconv.Itoa(s.lastID)
+	stored := *item
+	stored.ID = id
+	s.items[id] = &stored
+	item.ID = id
+	return id, nil
+}
+
+// UpdateItem replaces an item of the inventory, the identifier of the item is used to find it
+func (s *InventoryMemoryStore) UpdateItem(item *inventory.Item) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	_, ok := s.items[item.ID]
+	if !ok {
+		return errors.New("item not found in inventory")
+	}
+	stored := *item
+	s.items[item.ID] = &stored
+	return nil
+}
+
+// ListItems lists all the items of the inventory, sorted by type and name
+func (s *InventoryMemoryStore) ListItems() ([]*inventory.Item, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.list(), nil
+}
+
+// list returns copies of all the items sorted by type, name and identifier. The lock must be held
+func (s *InventoryMemoryStore) list() []*inventory.Item {
+	items := make([]*inventory.Item, 0, len(s.items))
+	for _, it := range s.items {
+		c := *it
+		items = append(items, &c)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Type != items[j].Type {
+			return items[i].Type < items[j].Type
+		}
+		if items[i].Name != items[j].Name {
+			return items[i].Name < items[j].Name
+		}
+		idI, _ := strconv.Atoi(items[i].ID)
+		idJ, _ := strconv.Atoi(items[j].ID)
+		return idI < idJ
+	})
+	return items
+}
+
+// DeleteItem deletes an item from the inventory
+func (s *InventoryMemoryStore) DeleteItem(id string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	_, ok := s.items[id]
+	if !ok {
+		return errors.New("item not found in inventory")
+	}
+	delete(s.items, id)
+	return nil
+}
+
+// Consume deducts the given amount (in grams) of an ingredient from the inventory
+// It returns the amount that could not be deducted because there was not enough stock
+func (s *InventoryMemoryStore) Consume(t inventory.IngredientType, name string, amount float32) (float32, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	modified, missing := inventory.Deduct(s.list(), t, name, amount)
+	for _, it := range modified {
+		s.items[it.ID] = it
+	}
+	return missing, nil
+}
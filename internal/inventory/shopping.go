@@ -0,0 +1,177 @@
+package inventory
+
+import (
+	"brewday/internal/recipe"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ShoppingItem is an ingredient of the shopping list
+type ShoppingItem struct {
+	Requirement
+	// Recipes are the names of the recipes that need the ingredient
+	Recipes []string
+}
+
+// ToBuy returns the amount in grams that has to be bought
+func (s ShoppingItem) ToBuy() float32 {
+	return s.Missing()
+}
+
+// ShoppingList adds together the ingredients of all the recipes
+// Ingredients with the same type and name are merged, using the name of the first recipe that needs them
+// If stock is not nil, the items in stock that are not expired are subtracted
+// The list is sorted by ingredient type and name
+func ShoppingList(recipes []*recipe.Recipe, stock []*Item, now time.Time) []ShoppingItem {
+	var list []ShoppingItem
+	for _, re := range recipes {
+		for _, req := range Requirements(re) {
+			found := false
+			for i := range list {
+				if list[i].Type == req.Type && normalizeName(list[i].Name) == normalizeName(req.Name) {
+					list[i].Required += req.Required
+					if !containsString(list[i].Recipes, re.Name) {
+						list[i].Recipes = append(list[i].Recipes, re.Name)
+					}
+					found = true
+					break
+				}
+			}
+			if !found {
+				list = append(list, ShoppingItem{Requirement: req, Recipes: []string{re.Name}})
+			}
+		}
+	}
+	for i := range list {
+		for _, it := range stock {
+			if it.Matches(list[i].Type, list[i].Name) && !it.Expired(now) {
+				list[i].Available += it.Amount
+			}
+		}
+	}
+	sort.SliceStable(list, func(i, j int) bool {
+		if list[i].Type != list[j].Type {
+			return typeOrder(list[i].Type) < typeOrder(list[j].Type)
+		}
+		return normalizeName(list[i].Name) < normalizeName(list[j].Name)
+	})
+	return list
+}
+
+// typeOrder returns the position of the ingredient type in IngredientTypes
+func typeOrder(t IngredientType) int {
+	for i, it := range IngredientTypes {
+		if it == t {
+			return i
+		}
+	}
+	return len(IngredientTypes)
+}
+
+// containsString returns whether the slice contains the string
+func containsString(list []string, s string) bool {
+	for _, l := range list {
+		if l == s {
+			return true
+		}
+	}
+	return false
+}
+
+// FormatAmount returns the amount in grams as a human readable string
+// Amounts of one kilogram or more are given in kg
+func FormatAmount(grams float32) string {
+	if grams >= 1000 {
+		return strings.TrimSuffix(strings.TrimRight(fmt.Sprintf("%.2f", grams/1000), "0"), ".") + " kg"
+	}
+	return formatGrams(grams) + " g"
+}
+
+// ShoppingListMarkdown returns the shopping list as a Markdown document with one table per ingredient type
+// If withStock is true, the amounts in stock and to buy are included
+func ShoppingListMarkdown(list []ShoppingItem, withStock bool) string {
+	var sb strings.Builder
+	sb.WriteString("# Shopping list\n")
+	for _, t := range IngredientTypes {
+		first := true
+		for _, s := range list {
+			if s.Type != t {
+				continue
+			}
+			if first {
+				fmt.Fprintf(&sb, "\n## %s\n\n", typeTitle(t))
+				if withStock {
+					sb.WriteString("| Ingredient | Needed | In stock | To buy | Recipes |\n|---|---:|---:|---:|---|\n")
+				} else {
+					sb.WriteString("| Ingredient | Needed | Recipes |\n|---|---:|---|\n")
+				}
+				first = false
+			}
+			recipes := escapeMarkdown(strings.Join(s.Recipes, ", "))
+			if withStock {
+				fmt.Fprintf(&sb, "| %s | %s | %s | %s | %s |\n", escapeMarkdown(s.Name), FormatAmount(s.Required),
+					FormatAmount(s.Available), FormatAmount(s.ToBuy()), recipes)
+			} else {
+				fmt.Fprintf(&sb, "| %s | %s | %s |\n", escapeMarkdown(s.Name), FormatAmount(s.Required), recipes)
+			}
+		}
+	}
+	return sb.String()
+}
+
+// typeTitle returns the title of the section of an ingredient type
+func typeTitle(t IngredientType) string {
+	switch t {
+	case IngredientMalt:
+		return "Malts"
+	case IngredientHop:
+		return "Hops"
+	case IngredientYeast:
+		return "Yeast"
+	default:
+		return "Other ingredients"
+	}
+}
+
+// escapeMarkdown escapes the characters that would break a Markdown table cell
+func escapeMarkdown(s string) string {
+	return strings.ReplaceAll(s, "|", "\\|")
+}
+
+// ShoppingListCSV returns the shopping list as CSV, with the amounts in grams
+// If withStock is true, the amounts in stock and to buy are included
+func ShoppingListCSV(list []ShoppingItem, withStock bool) (string, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	header := []string{"type", "name", "needed_g"}
+	if withStock {
+		header = append(header, "in_stock_g", "to_buy_g")
+	}
+	header = append(header, "recipes")
+	err := w.Write(header)
+	if err != nil {
+		return "", err
+	}
+	for _, s := range list {
+		record := []string{string(s.Type), s.Name, formatGrams(s.Required)}
+		if withStock {
+			record = append(record, formatGrams(s.Available), formatGrams(s.ToBuy()))
+		}
+		record = append(record, strings.Join(s.Recipes, "; "))
+		err = w.Write(record)
+		if err != nil {
+			return "", err
+		}
+	}
+	w.Flush()
+	return buf.String(), w.Error()
+}
+
+// formatGrams returns the amount in grams without unnecessary decimals
+func formatGrams(grams float32) string {
+	return strings.TrimSuffix(strings.TrimRight(fmt.Sprintf("%.1f", grams), "0"), ".")
+}
@@ -0,0 +1,105 @@
+package inventory
+
+import (
+	"brewday/internal/recipe"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestShoppingList(t *testing.T) {
+	require := require.New(t)
+	now := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+	second := &recipe.Recipe{
+		Name: "Second",
+		Mashing: recipe.MashInstructions{
+			Malts: []recipe.Malt{{Name: "pilsner ", Amount: 1000}, {Name: "Carapils", Amount: 200}},
+		},
+		Hopping: recipe.HopInstructions{
+			Hops: []recipe.Hops{{Name: "Magnum", Amount: 15, Duration: 60}},
+		},
+		Fermentation: recipe.FermentationInstructions{
+			Yeast: recipe.Yeast{Name: "US-05", Amount: 11.5},
+		},
+	}
+	stock := []*Item{
+		{Type: IngredientMalt, Name: "Pilsner", Amount: 3000},
+		{Type: IngredientHop, Name: "Magnum", Amount: 100, BestBefore: now.Add(-24 * time.Hour)},
+		{Type: IngredientYeast, Name: "US-05", Amount: 11.5},
+	}
+	type testCase struct {
+		Name          string
+		Stock         []*Item
+		ExpectedNames []string
+		ExpectedNeed  map[string]float32
+		ExpectedToBuy map[string]float32
+	}
+	testCases := []testCase{
+		{
+			Name:          "Without stock",
+			Stock:         nil,
+			ExpectedNames: []string{"Carapils", "Munich", "Pilsner", "Cascade", "Magnum", "US-05", "Koriander"},
+			ExpectedNeed:  map[string]float32{"Carapils": 200, "Munich": 500, "Pilsner": 5000, "Cascade": 80, "Magnum": 35, "US-05": 23, "Koriander": 10},
+			ExpectedToBuy: map[string]float32{"Carapils": 200, "Munich": 500, "Pilsner": 5000, "Cascade": 80, "Magnum": 35, "US-05": 23, "Koriander": 10},
+		},
+		{
+			Name:          "With stock",
+			Stock:         stock,
+			ExpectedNames: []string{"Carapils", "Munich", "Pilsner", "Cascade", "Magnum", "US-05", "Koriander"},
+			ExpectedNeed:  map[string]float32{"Carapils": 200, "Munich": 500, "Pilsner": 5000, "Cascade": 80, "Magnum": 35, "US-05": 23, "Koriander": 10},
+			ExpectedToBuy: map[string]float32{"Carapils": 200, "Munich": 500, "Pilsner": 2000, "Cascade": 80, "Magnum": 35, "US-05": 11.5, "Koriander": 10},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			list := ShoppingList([]*recipe.Recipe{testRecipe(), second}, tc.Stock, now)
+			names := make([]string, 0, len(list))
+			for _, s := range list {
+				names = append(names, s.Name)
+				require.InDelta(tc.ExpectedNeed[s.Name], s.Required, 0.001, s.Name)
+				require.InDelta(tc.ExpectedToBuy[s.Name], s.ToBuy(), 0.001, s.Name)
+			}
+			require.Equal(tc.ExpectedNames, names)
+		})
+	}
+	list := ShoppingList([]*recipe.Recipe{testRecipe(), second}, nil, now)
+	require.Equal([]string{"Test", "Second"}, list[2].Recipes)
+	require.Equal([]string{"Test"}, list[1].Recipes)
+}
+
+func TestShoppingListExport(t *testing.T) {
+	require := require.New(t)
+	list := []ShoppingItem{
+		{Requirement: Requirement{Type: IngredientMalt, Name: "Pilsner", Required: 5000, Available: 3000}, Recipes: []string{"A", "B"}},
+		{Requirement: Requirement{Type: IngredientHop, Name: "Cascade", Required: 80.5}, Recipes: []string{"A"}},
+	}
+	expectedMarkdown := `# Shopping list
+
+## Malts
+
+| Ingredient | Needed | In stock | To buy | Recipes |
+|---|---:|---:|---:|---|
+| Pilsner | 5 kg | 3 kg | 2 kg | A, B |
+
+## Hops
+
+| Ingredient | Needed | In stock | To buy | Recipes |
+|---|---:|---:|---:|---|
+| Cascade | 80.5 g | 0 g | 80.5 g | A |
+`
+	require.Equal(expectedMarkdown, ShoppingListMarkdown(list, true))
+	expectedCSV := "type,name,needed_g,recipes\nmalt,Pilsner,5000,A; B\nhop,Cascade,80.5,A\n"
+	csv, err := ShoppingListCSV(list, false)
+	require.NoError(err)
+	require.Equal(expectedCSV, csv)
+}
+
+func TestFormatAmount(t *testing.T) {
+	require := require.New(t)
+	require.Equal("0 g", FormatAmount(0))
+	require.Equal("11.5 g", FormatAmount(11.5))
+	require.Equal("999 g", FormatAmount(999))
+	require.Equal("1 kg", FormatAmount(1000))
+	require.Equal("4.25 kg", FormatAmount(4250))
+}
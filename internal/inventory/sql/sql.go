@@ -0,0 +1,152 @@
+package sql
+
+import (
+	"brewday/internal/inventory"
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+type InventoryPersistentStore struct {
+	dbClient *sql.DB
+	// consumeLock serializes the consumptions, as SQLite refuses to turn two concurrent read transactions into
+	// write transactions instead of waiting
+	consumeLock sync.Mutex
+}
+
+// querier is either the database or a transaction
+type querier interface {
+	Query(query string, args ...any) (*sql.Rows, error)
+}
+
+// NewInventoryPersistentStore creates a new InventoryStore
+func NewInventoryPersistentStore(db *sql.DB) (*InventoryPersistentStore, error) {
+	return &InventoryPersistentStore{
+		dbClient: db,
+	}, nil
+}
+
+// bestBeforeEpoch returns the best before date to store, nil if it is unknown
+func bestBeforeEpoch(item *inventory.Item) any {
+	if item.BestBefore.IsZero() {
+		return nil
+	}
+	return item.BestBefore.Unix()
+}
+
+// AddItem adds an item to the inventory and returns its identifier
+func (s *InventoryPersistentStore) AddItem(item *inventory.Item) (string, error) {
+	res, err := s.dbClient.Exec(`INSERT INTO inventory (type, name, amount, unit, lot, alpha, best_before_epoch) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		item.Type, item.Name, item.Amount, item.Unit, item.Lot, item.Alpha, bestBeforeEpoch(item))
+	if err != nil {
+		return "", err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return "", err
+	}
+	item.ID = strconv.FormatInt(id, 10)
+	return item.ID, nil
+}
+
+// UpdateItem replaces an item of the inventory, the identifier of the item is used to find it
+func (s *InventoryPersistentStore) UpdateItem(item *inventory.Item) error {
+	if item.ID == "" {
+		return errors.New("invalid empty item id")
+	}
+	res, err := s.dbClient.Exec(`UPDATE inventory SET type = ?, name = ?, amount = ?, unit = ?, lot = ?, alpha = ?, best_before_epoch = ? WHERE id == ?`,
+		item.Type, item.Name, item.Amount, item.Unit, item.Lot, item.Alpha, bestBeforeEpoch(item), item.ID)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return errors.New("item not found in inventory")
+	}
+	return nil
+}
+
+// ListItems lists all the items of the inventory, sorted by type and name
+func (s *InventoryPersistentStore) ListItems() ([]*inventory.Item, error) {
+	return listItems(s.dbClient)
+}
+
+// listItems lists all the items of the inventory with the given querier, sorted by type and name
+func listItems(q querier) ([]*inventory.Item, error) {
+	rows, err := q.Query(`SELECT id, type, name, amount, unit, lot, alpha, best_before_epoch FROM inventory ORDER BY type, name, id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := make([]*inventory.Item, 0)
+	for rows.Next() {
+		var it inventory.Item
+		var lot sql.NullString
+		var alpha sql.NullFloat64
+		var bestBefore sql.NullInt64
+		err = rows.Scan(&it.ID, &it.Type, &it.Name, &it.Amount, &it.Unit, &lot, &alpha, &bestBefore)
+		if err != nil {
+			return nil, err
+		}
+		it.Lot = lot.String
+		it.Alpha = float32(alpha.Float64)
+		if bestBefore.Valid {
+			it.BestBefore = time.Unix(bestBefore.Int64, 0).UTC()
+		}
+		items = append(items, &it)
+	}
+	return items, rows.Err()
+}
+
+// DeleteItem deletes an item from the inventory
+func (s *InventoryPersistentStore) DeleteItem(id string) error {
+	res, err := s.dbClient.Exec(`DELETE FROM inventory WHERE id == ?`, id)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return errors.New("item not found in inventory")
+	}
+	return nil
+}
+
+// Consume deducts the given amount (in grams) of an ingredient from the inventory
+// It returns the amount that could not be deducted because there was not enough stock
+// The stock is read and updated in a single transaction, so concurrent consumptions do not overwrite each other
+func (s *InventoryPersistentStore) Consume(t inventory.IngredientType, name string, amount float32) (float32, error) {
+	s.consumeLock.Lock()
+	defer s.consumeLock.Unlock()
+	tx, err := s.dbClient.BeginTx(context.Background(), nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+	items, err := listItems(tx)
+	if err != nil {
+		return 0, err
+	}
+	modified, missing := inventory.Deduct(items, t, name, amount)
+	for _, it := range modified {
+		_, err = tx.Exec(`UPDATE inventory SET amount = ? WHERE id == ?`, it.Amount, it.ID)
+		if err != nil {
+			return 0, err
+		}
+	}
+	err = tx.Commit()
+	if err != nil {
+		return 0, err
+	}
+	return missing, nil
+}
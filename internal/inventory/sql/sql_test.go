@@ -0,0 +1,122 @@
+package sql
+
+import (
+	"brewday/internal/inventory"
+	"database/sql"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	dbmigrations "brewday/internal/db_migrations"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestStore(t *testing.T) *InventoryPersistentStore {
+	fileName := strings.ToLower(strings.TrimSpace(t.Name())) + ".sqlite"
+	db, err := sql.Open("sqlite3", "file:"+fileName+"?_foreign_keys=true")
+	require.NoError(t, err)
+	require.NoError(t, dbmigrations.RunMigrations(db, "migrations"))
+	store, err := NewInventoryPersistentStore(db)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		db.Close()
+		os.Remove(fileName)
+	})
+	return store
+}
+
+func TestAddAndListItems(t *testing.T) {
+	require := require.New(t)
+	store := newTestStore(t)
+	bestBefore := time.Date(2026, time.December, 31, 0, 0, 0, 0, time.UTC)
+	items := []*inventory.Item{
+		{Type: inventory.IngredientMalt, Name: "Pilsner", Amount: 5000, Lot: "L123"},
+		{Type: inventory.IngredientHop, Name: "Cascade", Amount: 100, Alpha: 6.5, BestBefore: bestBefore},
+		{Type: inventory.IngredientHop, Name: "Amarillo", Amount: 50, Alpha: 8.2},
+	}
+	for _, it := range items {
+		id, err := store.AddItem(it)
+		require.NoError(err)
+		require.Equal(id, it.ID)
+	}
+	actual, err := store.ListItems()
+	require.NoError(err)
+	require.Equal([]*inventory.Item{items[2], items[1], items[0]}, actual)
+}
+
+func TestUpdateAndDeleteItem(t *testing.T) {
+	require := require.New(t)
+	store := newTestStore(t)
+	item := &inventory.Item{Type: inventory.IngredientYeast, Name: "US-05", Amount: 23}
+	_, err := store.AddItem(item)
+	require.NoError(err)
+	item.Amount = 2
+	item.Unit = "pcs"
+	item.Lot = "A1"
+	require.NoError(store.UpdateItem(item))
+	actual, err := store.ListItems()
+	require.NoError(err)
+	require.Equal([]*inventory.Item{item}, actual)
+	require.Error(store.UpdateItem(&inventory.Item{ID: "100", Name: "None"}))
+	require.Error(store.UpdateItem(&inventory.Item{Name: "None"}))
+	require.NoError(store.DeleteItem(item.ID))
+	require.Error(store.DeleteItem(item.ID))
+	actual, err = store.ListItems()
+	require.NoError(err)
+	require.Empty(actual)
+}
+
+func TestConsume(t *testing.T) {
+	require := require.New(t)
+	store := newTestStore(t)
+	old := &inventory.Item{Type: inventory.IngredientMalt, Name: "Pilsner", Amount: 1000, BestBefore: time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)}
+	fresh := &inventory.Item{Type: inventory.IngredientMalt, Name: "Pilsner", Amount: 3000}
+	for _, it := range []*inventory.Item{fresh, old} {
+		_, err := store.AddItem(it)
+		require.NoError(err)
+	}
+	missing, err := store.Consume(inventory.IngredientMalt, "pilsner", 1500)
+	require.NoError(err)
+	require.Zero(missing)
+	missing, err = store.Consume(inventory.IngredientMalt, "Pilsner", 3000)
+	require.NoError(err)
+	require.Equal(float32(500), missing)
+	missing, err = store.Consume(inventory.IngredientHop, "Pilsner", 10)
+	require.NoError(err)
+	require.Equal(float32(10), missing)
+	actual, err := store.ListItems()
+	require.NoError(err)
+	for _, it := range actual {
+		require.Zero(it.Amount)
+	}
+}
+
+func TestConsumeConcurrently(t *testing.T) {
+	require := require.New(t)
+	store := newTestStore(t)
+	_, err := store.AddItem(&inventory.Item{Type: inventory.IngredientHop, Name: "Cascade", Amount: 100})
+	require.NoError(err)
+	var wg sync.WaitGroup
+	missing := make([]float32, 10)
+	errs := make([]error, 10)
+	for i := range missing {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			missing[i], errs[i] = store.Consume(inventory.IngredientHop, "Cascade", 10)
+		}()
+	}
+	wg.Wait()
+	for i := range missing {
+		require.NoError(errs[i])
+		require.Zero(missing[i])
+	}
+	actual, err := store.ListItems()
+	require.NoError(err)
+	require.Len(actual, 1)
+	require.Zero(actual[0].Amount)
+}
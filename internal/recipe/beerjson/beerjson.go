@@ -0,0 +1,625 @@
+package beerjson
+
+import (
+	"brewday/internal/recipe"
+	"brewday/internal/tools"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// grainAbsorption is the amount of water in liters retained by one kilogram of grain after lautering
+const grainAbsorption = 1.0
+
+// vorderwuerzeSuffix is appended to the name of vorderwürze hops, as BeerJSON has no specific use for them
+const vorderwuerzeSuffix = " (VW)"
+
+// BeerJSONParser is a RecipeParser implementation that parses recipes in BeerJSON 1.0 format
+// Units are converted to the ones used in BrewDay (g, l, °C, min, SG, EBC)
+type BeerJSONParser struct{}
+
+// BeerJSONDocument is the root object of a BeerJSON document
+type BeerJSONDocument struct {
+	BeerJSON BeerJSON `json:"beerjson"`
+}
+
+// BeerJSON contains the version of the standard and the recipes
+type BeerJSON struct {
+	Version float64          `json:"version"`
+	Recipes []BeerJSONRecipe `json:"recipes"`
+}
+
+// BeerJSONRecipe represents a recipe in BeerJSON format
+// Only the fields relevant to BrewDay are included
+type BeerJSONRecipe struct {
+	Name            string                `json:"name"`
+	Type            string                `json:"type"`
+	Author          string                `json:"author"`
+	BatchSize       *Quantity             `json:"batch_size"`
+	Efficiency      BeerJSONEfficiency    `json:"efficiency"`
+	Style           *BeerJSONStyle        `json:"style,omitempty"`
+	Ingredients     BeerJSONIngredients   `json:"ingredients"`
+	Mash            *BeerJSONMash         `json:"mash,omitempty"`
+	OriginalGravity *Quantity             `json:"original_gravity,omitempty"`
+	IBUEstimate     *BeerJSONIBUEstimate  `json:"ibu_estimate,omitempty"`
+	ColorEstimate   *Quantity             `json:"color_estimate,omitempty"`
+	Carbonation     float64               `json:"carbonation,omitempty"`
+	Fermentation    *BeerJSONFermentation `json:"fermentation,omitempty"`
+	Boil            *BeerJSONBoil         `json:"boil,omitempty"`
+}
+
+// BeerJSONEfficiency represents the efficiencies of a recipe
+type BeerJSONEfficiency struct {
+	Brewhouse *Quantity `json:"brewhouse"`
+}
+
+// BeerJSONStyle represents the style of a recipe
+type BeerJSONStyle struct {
+	Name       string `json:"name"`
+	Category   string `json:"category"`
+	StyleGuide string `json:"style_guide"`
+	Type       string `json:"type"`
+}
+
+// BeerJSONIBUEstimate represents the method used to estimate the bitterness
+type BeerJSONIBUEstimate struct {
+	Method string `json:"method"`
+}
+
+// BeerJSONIngredients contains all the ingredient additions of a recipe
+type BeerJSONIngredients struct {
+	Fermentables []BeerJSONFermentable `json:"fermentable_additions"`
+	Hops         []BeerJSONHop         `json:"hop_additions,omitempty"`
+	Miscs        []BeerJSONMisc        `json:"miscellaneous_additions,omitempty"`
+	Cultures     []BeerJSONCulture     `json:"culture_additions,omitempty"`
+}
+
+// BeerJSONTiming describes when and how long an ingredient is added
+type BeerJSONTiming struct {
+	Use      string    `json:"use,omitempty"`
+	Time     *Quantity `json:"time,omitempty"`
+	Duration *Quantity `json:"duration,omitempty"`
+	// Step is the number of the boil step the addition is made in, counted from 1
+	Step int `json:"step,omitempty"`
+}
+
+// BeerJSONFermentable represents a fermentable addition
+type BeerJSONFermentable struct {
+	Name   string    `json:"name"`
+	Type   string    `json:"type"`
+	Color  *Quantity `json:"color,omitempty"`
+	Amount *Quantity `json:"amount"`
+}
+
+// BeerJSONHop represents a hop addition
+type BeerJSONHop struct {
+	Name      string          `json:"name"`
+	AlphaAcid *Quantity       `json:"alpha_acid"`
+	Form      string          `json:"form,omitempty"`
+	Timing    *BeerJSONTiming `json:"timing"`
+	Amount    *Quantity       `json:"amount"`
+}
+
+// BeerJSONMisc represents a miscellaneous addition (spices, finings, water agents, ...)
+type BeerJSONMisc struct {
+	Name   string          `json:"name"`
+	Type   string          `json:"type"`
+	Timing *BeerJSONTiming `json:"timing"`
+	Amount *Quantity       `json:"amount"`
+}
+
+// BeerJSONCulture represents a culture (yeast) addition
+type BeerJSONCulture struct {
+	Name             string                `json:"name"`
+	Type             string                `json:"type"`
+	Form             string                `json:"form"`
+	Producer         string                `json:"producer,omitempty"`
+	Amount           *Quantity             `json:"amount,omitempty"`
+	Attenuation      *Quantity             `json:"attenuation,omitempty"`
+	AttenuationRange *BeerJSONPercentRange `json:"attenuation_range,omitempty"`
+}
+
+// BeerJSONPercentRange represents a range of percentages (e.g. the attenuation of a culture)
+type BeerJSONPercentRange struct {
+	Minimum *Quantity `json:"minimum"`
+	Maximum *Quantity `json:"maximum"`
+}
+
+// BeerJSONMash represents the mash procedure
+type BeerJSONMash struct {
+	Name             string             `json:"name"`
+	GrainTemperature *Quantity          `json:"grain_temperature"`
+	Steps            []BeerJSONMashStep `json:"mash_steps"`
+}
+
+// BeerJSONMashStep represents a step of the mash procedure
+type BeerJSONMashStep struct {
+	Name              string    `json:"name"`
+	Type              string    `json:"type"`
+	Amount            *Quantity `json:"amount,omitempty"`
+	StepTemperature   *Quantity `json:"step_temperature"`
+	StepTime          *Quantity `json:"step_time"`
+	InfuseTemperature *Quantity `json:"infuse_temperature,omitempty"`
+}
+
+// BeerJSONBoil represents the boil procedure
+type BeerJSONBoil struct {
+	PreBoilSize *Quantity          `json:"pre_boil_size,omitempty"`
+	BoilTime    *Quantity          `json:"boil_time"`
+	Steps       []BeerJSONBoilStep `json:"boil_steps,omitempty"`
+}
+
+// BeerJSONBoilStep represents a step of the boil procedure, like the boil itself or a whirlpool after it
+type BeerJSONBoilStep struct {
+	Name             string    `json:"name"`
+	StartTemperature *Quantity `json:"start_temperature,omitempty"`
+	StepTime         *Quantity `json:"step_time,omitempty"`
+}
+
+// BeerJSONFermentation represents the fermentation procedure
+type BeerJSONFermentation struct {
+	Name  string                     `json:"name"`
+	Steps []BeerJSONFermentationStep `json:"fermentation_steps"`
+}
+
+// BeerJSONFermentationStep represents a step of the fermentation procedure
+type BeerJSONFermentationStep struct {
+	Name             string    `json:"name"`
+	StartTemperature *Quantity `json:"start_temperature,omitempty"`
+	EndTemperature   *Quantity `json:"end_temperature,omitempty"`
+	StepTime         *Quantity `json:"step_time,omitempty"`
+}
+
+// Parse parses a recipe from a string
+// If the document contains more than one recipe, only the first one is returned
+func (p *BeerJSONParser) Parse(recipe string) (*recipe.Recipe, error) {
+	var doc BeerJSONDocument
+	err := json.Unmarshal([]byte(recipe), &doc)
+	if err != nil {
+		return nil, err
+	}
+	if len(doc.BeerJSON.Recipes) == 0 {
+		return nil, errors.New("no recipe found in beerjson document")
+	}
+	return beerJSONRecipeToRecipe(&doc.BeerJSON.Recipes[0])
+}
+
+// ParseAll parses all the recipes contained in a BeerJSON document
+// The returned errors are aligned with the recipes, so one invalid recipe does not prevent reading the others
+func (p *BeerJSONParser) ParseAll(document string) ([]*recipe.Recipe, []error, error) {
+	var doc BeerJSONDocument
+	err := json.Unmarshal([]byte(document), &doc)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(doc.BeerJSON.Recipes) == 0 {
+		return nil, nil, errors.New("no recipe found in beerjson document")
+	}
+	recipes := doc.BeerJSON.Recipes
+	result := make([]*recipe.Recipe, len(recipes))
+	errs := make([]error, len(recipes))
+	for i := range recipes {
+		result[i], errs[i] = beerJSONRecipeToRecipe(&recipes[i])
+		if errs[i] != nil {
+			errs[i] = fmt.Errorf("recipe %q: %w", recipes[i].Name, errs[i])
+		}
+	}
+	return result, errs, nil
+}
+
+// beerJSONRecipeToRecipe converts a BeerJSONRecipe to a recipe.Recipe
+func beerJSONRecipeToRecipe(r *BeerJSONRecipe) (*recipe.Recipe, error) {
+	batchSize, err := r.BatchSize.toLiters()
+	if err != nil {
+		return nil, err
+	}
+	og, err := r.OriginalGravity.toSG()
+	if err != nil {
+		return nil, err
+	}
+	color, err := r.ColorEstimate.toEBC()
+	if err != nil {
+		return nil, err
+	}
+	mash, err := getMashInstructions(r)
+	if err != nil {
+		return nil, err
+	}
+	hopping, err := getHopInstructions(r)
+	if err != nil {
+		return nil, err
+	}
+	fermentation, err := getFermentationInstructions(r)
+	if err != nil {
+		return nil, err
+	}
+	var style string
+	if r.Style != nil {
+		style = strings.TrimSpace(r.Style.Name)
+	}
+	return &recipe.Recipe{
+		Name:         strings.TrimSpace(r.Name),
+		Style:        style,
+		BatchSize:    tools.RoundTo(float32(batchSize), 2),
+		InitialSG:    float32(og),
+		Bitterness:   estimateBitterness(hopping, float32(og), float32(batchSize)),
+		ColorEBC:     tools.RoundTo(float32(color), 1),
+		Mashing:      *mash,
+		Hopping:      *hopping,
+		Fermentation: *fermentation,
+	}, nil
+}
+
+// estimateBitterness returns the bitterness of the boil hops using Tinseth's formula
+// BeerJSON only stores the method used to estimate the IBU, not the value itself
+func estimateBitterness(hopping *recipe.HopInstructions, og, batchSize float32) float32 {
+	var ibu float32
+	for _, h := range hopping.Hops {
+		if h.DryHop {
+			continue
+		}
+		ibu += tools.TinsethIBU(h.Alpha, h.Amount, h.Duration, og, batchSize)
+	}
+	return tools.RoundTo(ibu, 1)
+}
+
+// isMashOut returns whether a mash step is the mash out, which BrewDay handles separately from the rasts
+func isMashOut(step *BeerJSONMashStep) bool {
+	name := strings.ToLower(strings.ReplaceAll(step.Name, " ", ""))
+	return strings.Contains(name, "mashout") || strings.Contains(name, "abmaischen")
+}
+
+// getMashInstructions returns the mash instructions for a BeerJSONRecipe
+// Sparge steps define the nachguss and a step named "Mash Out" the mash out temperature. Every other step is a rast
+// Decoction steps are rasts reached by boiling the amount of mash of the step, they make the recipe a decoction one
+// If there is no sparge step, the nachguss is estimated from the pre boil size, the water used in the mash and the grain absorption
+func getMashInstructions(r *BeerJSONRecipe) (*recipe.MashInstructions, error) {
+	mash := &recipe.MashInstructions{}
+	var grainGrams float64
+	for _, f := range r.Ingredients.Fermentables {
+		amount, err := f.Amount.toGrams()
+		if err != nil {
+			return nil, err
+		}
+		color, err := f.Color.toEBC()
+		if err != nil {
+			return nil, err
+		}
+		mash.Malts = append(mash.Malts, recipe.Malt{
+			Name:   strings.TrimSpace(f.Name),
+			Amount: tools.RoundTo(float32(amount), 1),
+			Color:  tools.RoundTo(float32(color), 1),
+		})
+		if strings.EqualFold(f.Type, "grain") {
+			grainGrams += amount
+		}
+	}
+	if r.Mash == nil {
+		return mash, nil
+	}
+	var infused, sparge float64
+	hasSparge := false
+	for i := range r.Mash.Steps {
+		step := &r.Mash.Steps[i]
+		amount, err := step.Amount.toLiters()
+		if err != nil {
+			return nil, err
+		}
+		temperature, err := step.StepTemperature.toCelsius()
+		if err != nil {
+			return nil, err
+		}
+		if strings.EqualFold(step.Type, "sparge") {
+			hasSparge = true
+			sparge += amount
+			continue
+		}
+		if strings.EqualFold(step.Type, "decoction") && len(mash.Rasts) > 0 {
+			// The amount of a decoction step is the mash that is pulled, it does not add water
+			mash.MashType = recipe.MashTypeDecoction
+			mash.Decoctions = append(mash.Decoctions, recipe.Decoction{
+				Rast:   len(mash.Rasts),
+				Volume: tools.RoundTo(float32(amount), 2),
+			})
+		} else {
+			infused += amount
+		}
+		if isMashOut(step) {
+			mash.MashOutTemperature = tools.RoundTo(float32(temperature), 1)
+			continue
+		}
+		duration, err := step.StepTime.toMinutes()
+		if err != nil {
+			return nil, err
+		}
+		if len(mash.Rasts) == 0 {
+			mash.MainWaterVolume = tools.RoundTo(float32(amount), 2)
+			mash.MashTemperature = tools.RoundTo(float32(temperature), 1)
+			if step.InfuseTemperature != nil {
+				infuse, err := step.InfuseTemperature.toCelsius()
+				if err != nil {
+					return nil, err
+				}
+				mash.MashTemperature = tools.RoundTo(float32(infuse), 1)
+			}
+		}
+		mash.Rasts = append(mash.Rasts, recipe.Rast{
+			Temperature: tools.RoundTo(float32(temperature), 1),
+			Duration:    tools.RoundTo(float32(duration), 1),
+		})
+	}
+	if mash.MashType == "" && len(mash.Rasts) > 0 {
+		mash.MashType = recipe.MashTypeInfusion
+	}
+	if mash.MashOutTemperature == 0 && len(mash.Rasts) > 0 {
+		mash.MashOutTemperature = mash.Rasts[len(mash.Rasts)-1].Temperature
+	}
+	if hasSparge {
+		mash.Nachguss = tools.RoundTo(float32(sparge), 2)
+		return mash, nil
+	}
+	if r.Boil != nil {
+		preBoil, err := r.Boil.PreBoilSize.toLiters()
+		if err != nil {
+			return nil, err
+		}
+		nachguss := preBoil - infused + grainGrams/1000*grainAbsorption
+		if nachguss > 0 {
+			mash.Nachguss = tools.RoundTo(float32(nachguss), 1)
+		}
+	}
+	return mash, nil
+}
+
+// getHopInstructions returns the hop instructions for a BeerJSONRecipe
+// Hops added to the fermentation or the package are dry hops. Hops added in a boil step below boiling are whirlpool
+// hops, which steep for the duration of their timing at the temperature of the step
+// Every other hop is treated as a boil addition
+// Hops whose name ends with " (VW)" are vorderwürze hops
+// Miscs added to the boil are added as additional ingredients
+func getHopInstructions(r *BeerJSONRecipe) (*recipe.HopInstructions, error) {
+	hopping := &recipe.HopInstructions{}
+	if r.Boil != nil {
+		boilTime, err := r.Boil.BoilTime.toMinutes()
+		if err != nil {
+			return nil, err
+		}
+		hopping.TotalCookingTime = tools.RoundTo(float32(boilTime), 1)
+	}
+	for _, h := range r.Ingredients.Hops {
+		amount, err := h.Amount.toGrams()
+		if err != nil {
+			return nil, err
+		}
+		hop := recipe.Hops{
+			Name:   strings.TrimSpace(h.Name),
+			Amount: tools.RoundTo(float32(amount), 1),
+		}
+		switch timingUse(h.Timing) {
+		case "add_to_fermentation", "add_to_package":
+			hop.DryHop = true
+		default:
+			if h.AlphaAcid != nil {
+				hop.Alpha = tools.RoundTo(float32(h.AlphaAcid.Value), 2)
+			}
+			temperature, whirlpool, err := whirlpoolTemperature(r.Boil, h.Timing)
+			if err != nil {
+				return nil, err
+			}
+			if whirlpool {
+				steep, err := h.Timing.Duration.toMinutes()
+				if err != nil {
+					return nil, err
+				}
+				hop.Whirlpool = true
+				hop.StandTemperature = tools.RoundTo(float32(temperature), 1)
+				hop.StandTime = tools.RoundTo(float32(steep), 1)
+				break
+			}
+			duration, err := timingMinutes(h.Timing)
+			if err != nil {
+				return nil, err
+			}
+			hop.Duration = tools.RoundTo(float32(duration), 1)
+			hop.Vorderwuerze = strings.HasSuffix(hop.Name, vorderwuerzeSuffix)
+		}
+		hopping.Hops = append(hopping.Hops, hop)
+	}
+	for _, m := range r.Ingredients.Miscs {
+		if timingUse(m.Timing) != "add_to_boil" {
+			continue
+		}
+		ingredient, err := miscToIngredient(&m)
+		if err != nil {
+			return nil, err
+		}
+		hopping.AdditionalIngredients = append(hopping.AdditionalIngredients, *ingredient)
+	}
+	return hopping, nil
+}
+
+// getFermentationInstructions returns the fermentation instructions for a BeerJSONRecipe
+// Only the first culture is used. Its amount is only set if it is expressed as a mass
+// Its attenuation range is read from attenuation_range, or from the single attenuation of the addition
+// The temperature is taken from the first fermentation step, as a range if start and end temperatures differ
+// If there are several fermentation steps, they are also read as the temperature schedule
+func getFermentationInstructions(r *BeerJSONRecipe) (*recipe.FermentationInstructions, error) {
+	fermentation := &recipe.FermentationInstructions{
+		Carbonation: tools.RoundTo(tools.CO2VolumesToGramsPerLiter(float32(r.Carbonation)), 1),
+	}
+	if len(r.Ingredients.Cultures) > 0 {
+		c := r.Ingredients.Cultures[0]
+		fermentation.Yeast.Name = strings.TrimSpace(c.Name)
+		if c.Amount != nil && c.Amount.isMass() {
+			amount, err := c.Amount.toGrams()
+			if err != nil {
+				return nil, err
+			}
+			fermentation.Yeast.Amount = tools.RoundTo(float32(amount), 1)
+		}
+		fermentation.Yeast.Lab = strings.TrimSpace(c.Producer)
+		minAttenuation, maxAttenuation, err := c.attenuation()
+		if err != nil {
+			return nil, err
+		}
+		fermentation.Yeast.MinAttenuation = tools.RoundTo(float32(minAttenuation), 1)
+		fermentation.Yeast.MaxAttenuation = tools.RoundTo(float32(maxAttenuation), 1)
+	}
+	for _, m := range r.Ingredients.Miscs {
+		switch timingUse(m.Timing) {
+		case "add_to_fermentation", "add_to_package":
+			ingredient, err := miscToIngredient(&m)
+			if err != nil {
+				return nil, err
+			}
+			fermentation.AdditionalIngredients = append(fermentation.AdditionalIngredients, *ingredient)
+		}
+	}
+	if r.Fermentation != nil && len(r.Fermentation.Steps) > 0 {
+		step := r.Fermentation.Steps[0]
+		start, err := step.StartTemperature.toCelsius()
+		if err != nil {
+			return nil, err
+		}
+		end, err := step.EndTemperature.toCelsius()
+		if err != nil {
+			return nil, err
+		}
+		fermentation.Temperature = formatTemperatureRange(tools.RoundTo(float32(start), 1), tools.RoundTo(float32(end), 1))
+	}
+	if r.Fermentation != nil && len(r.Fermentation.Steps) > 1 {
+		schedule, err := getFermentationSchedule(r.Fermentation.Steps)
+		if err != nil {
+			return nil, err
+		}
+		fermentation.Schedule = schedule
+	}
+	return fermentation, nil
+}
+
+// getFermentationSchedule returns the temperature schedule from the fermentation steps
+// Each step is held at its end temperature (or start temperature if there is none). A step that ends warmer than it starts is a free rise
+func getFermentationSchedule(steps []BeerJSONFermentationStep) ([]recipe.FermentationStep, error) {
+	schedule := []recipe.FermentationStep{}
+	for _, step := range steps {
+		start, err := step.StartTemperature.toCelsius()
+		if err != nil {
+			return nil, err
+		}
+		end, err := step.EndTemperature.toCelsius()
+		if err != nil {
+			return nil, err
+		}
+		minutes, err := step.StepTime.toMinutes()
+		if err != nil {
+			return nil, err
+		}
+		temperature := end
+		if step.EndTemperature == nil {
+			temperature = start
+		}
+		schedule = append(schedule, recipe.FermentationStep{
+			Name:        strings.TrimSpace(step.Name),
+			Temperature: tools.RoundTo(float32(temperature), 1),
+			Days:        tools.RoundTo(float32(minutes/1440), 1),
+			FreeRise:    step.StartTemperature != nil && step.EndTemperature != nil && end > start,
+		})
+	}
+	return schedule, nil
+}
+
+// attenuation returns the lowest and highest apparent attenuation of the culture in %, 0 if it is not known
+func (c *BeerJSONCulture) attenuation() (float64, float64, error) {
+	if c.AttenuationRange == nil {
+		attenuation, err := c.Attenuation.toPercent()
+		return attenuation, attenuation, err
+	}
+	minAttenuation, err := c.AttenuationRange.Minimum.toPercent()
+	if err != nil {
+		return 0, 0, err
+	}
+	maxAttenuation, err := c.AttenuationRange.Maximum.toPercent()
+	if err != nil {
+		return 0, 0, err
+	}
+	if maxAttenuation == 0 {
+		maxAttenuation = minAttenuation
+	}
+	return minAttenuation, maxAttenuation, nil
+}
+
+// formatTemperatureRange formats a temperature range (e.g. 18-20). If there is no end temperature, only the start is returned
+func formatTemperatureRange(start, end float32) string {
+	if start == 0 {
+		return ""
+	}
+	s := strconv.FormatFloat(float64(start), 'f', -1, 32)
+	if end == 0 || end == start {
+		return s
+	}
+	return s + "-" + strconv.FormatFloat(float64(end), 'f', -1, 32)
+}
+
+// miscToIngredient converts a BeerJSON misc to an additional ingredient
+// Volumes are converted to grams assuming the density of water. Amounts in units (e.g. "each") are kept as they are
+func miscToIngredient(m *BeerJSONMisc) (*recipe.AdditionalIngredient, error) {
+	var amount float64
+	var err error
+	switch {
+	case m.Amount == nil:
+	case m.Amount.isMass():
+		amount, err = m.Amount.toGrams()
+	case m.Amount.isVolume():
+		amount, err = m.Amount.toLiters()
+		amount *= 1000
+	default:
+		amount = m.Amount.Value
+	}
+	if err != nil {
+		return nil, err
+	}
+	duration, err := timingMinutes(m.Timing)
+	if err != nil {
+		return nil, err
+	}
+	return &recipe.AdditionalIngredient{
+		Name:     strings.TrimSpace(m.Name),
+		Amount:   tools.RoundTo(float32(amount), 1),
+		Duration: tools.RoundTo(float32(duration), 1),
+	}, nil
+}
+
+// timingUse returns the use of a timing in lowercase. A missing timing is treated as a boil addition
+func timingUse(t *BeerJSONTiming) string {
+	if t == nil || t.Use == "" {
+		return "add_to_boil"
+	}
+	return strings.ToLower(t.Use)
+}
+
+// whirlpoolTemperature returns the temperature in °C of the boil step an addition is made in
+// It also returns whether the step is a whirlpool (or hop stand), that is below boiling
+func whirlpoolTemperature(boil *BeerJSONBoil, t *BeerJSONTiming) (float64, bool, error) {
+	if boil == nil || t == nil || t.Step < 1 || t.Step > len(boil.Steps) {
+		return 0, false, nil
+	}
+	temperature, err := boil.Steps[t.Step-1].StartTemperature.toCelsius()
+	if err != nil {
+		return 0, false, err
+	}
+	return temperature, temperature > 0 && temperature < 100, nil
+}
+
+// timingMinutes returns the time of an addition in minutes
+// Tools differ in which field they use for boil additions, so time is preferred and duration used as fallback
+func timingMinutes(t *BeerJSONTiming) (float64, error) {
+	if t == nil {
+		return 0, nil
+	}
+	if t.Time != nil {
+		return t.Time.toMinutes()
+	}
+	return t.Duration.toMinutes()
+}
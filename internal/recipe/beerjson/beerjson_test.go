@@ -0,0 +1,252 @@
+package beerjson
+
+import (
+	"brewday/internal/recipe"
+	"brewday/internal/recipe/mmum"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func readBeerJSONRecipe(require *require.Assertions, fileName string) *BeerJSONRecipe {
+	file, err := os.ReadFile("../../../test/recipe/beerjson/" + fileName)
+	require.NoError(err)
+	var doc BeerJSONDocument
+	require.NoError(json.Unmarshal(file, &doc))
+	require.NotEmpty(doc.BeerJSON.Recipes)
+	return &doc.BeerJSON.Recipes[0]
+}
+
+func TestParse(t *testing.T) {
+	require := require.New(t)
+	file, err := os.ReadFile("../../../test/recipe/beerjson/Cascade_Pale_Ale.json")
+	require.NoError(err)
+	p := &BeerJSONParser{}
+	actual, err := p.Parse(string(file))
+	require.NoError(err)
+	require.Equal("Cascade Pale Ale", actual.Name)
+	require.Equal("American Pale Ale", actual.Style)
+	require.Equal(float32(20.82), actual.BatchSize)
+	require.Equal(float32(1.048), actual.InitialSG)
+	require.Equal(float32(27.3), actual.Bitterness)
+	require.Equal(float32(11.8), actual.ColorEBC)
+}
+
+func TestParseInvalid(t *testing.T) {
+	require := require.New(t)
+	type testCase struct {
+		Name   string
+		Recipe string
+	}
+	testCases := []testCase{
+		{Name: "Not JSON", Recipe: "not json"},
+		{Name: "No recipes", Recipe: `{"beerjson": {"version": 1.0, "recipes": []}}`},
+		{Name: "Invalid unit", Recipe: `{"beerjson": {"version": 1.0, "recipes": [{"name": "a", "batch_size": {"unit": "bucket", "value": 1}}]}}`},
+	}
+	p := &BeerJSONParser{}
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			_, err := p.Parse(tc.Recipe)
+			require.Error(err)
+		})
+	}
+}
+
+func TestGetMashInstructions(t *testing.T) {
+	require := require.New(t)
+	expected := recipe.MashInstructions{
+		Malts: []recipe.Malt{
+			{Name: "Pale 2-Row", Amount: 4082.3, Color: 3.3},
+			{Name: "Crystal 40", Amount: 340.2, Color: 105.2},
+			{Name: "Corn Sugar", Amount: 226.8},
+		},
+		MainWaterVolume:    15.14,
+		MashTemperature:    73.3,
+		Nachguss:           17.03,
+		MashOutTemperature: 75.6,
+		MashType:           recipe.MashTypeInfusion,
+		Rasts: []recipe.Rast{
+			{Temperature: 66.7, Duration: 60},
+		},
+	}
+	actual, err := getMashInstructions(readBeerJSONRecipe(require, "Cascade_Pale_Ale.json"))
+	require.NoError(err)
+	require.Equal(expected, *actual)
+}
+
+func TestGetHopInstructions(t *testing.T) {
+	require := require.New(t)
+	expected := recipe.HopInstructions{
+		TotalCookingTime: 60,
+		Hops: []recipe.Hops{
+			{Name: "Magnum", Alpha: 12, Amount: 14.2, Duration: 60},
+			{Name: "Cascade", Alpha: 7, Amount: 28.3, Duration: 10},
+			{Name: "Cascade", Amount: 56.7, DryHop: true},
+		},
+		AdditionalIngredients: []recipe.AdditionalIngredient{
+			{Name: "Whirlfloc", Amount: 1, Duration: 5},
+		},
+	}
+	actual, err := getHopInstructions(readBeerJSONRecipe(require, "Cascade_Pale_Ale.json"))
+	require.NoError(err)
+	require.Equal(expected, *actual)
+}
+
+func TestGetFermentationInstructions(t *testing.T) {
+	require := require.New(t)
+	expected := recipe.FermentationInstructions{
+		Yeast: recipe.Yeast{
+			Name:           "Safale US-05",
+			Amount:         11.5,
+			Lab:            "Fermentis",
+			MinAttenuation: 78,
+			MaxAttenuation: 82,
+		},
+		Temperature: "17.8-20",
+		AdditionalIngredients: []recipe.AdditionalIngredient{
+			{Name: "Grapefruit Zest", Amount: 28.3, Duration: 4320},
+		},
+		Carbonation: 4.7,
+	}
+	actual, err := getFermentationInstructions(readBeerJSONRecipe(require, "Cascade_Pale_Ale.json"))
+	require.NoError(err)
+	require.Equal(expected, *actual)
+}
+
+func TestExportRoundTrip(t *testing.T) {
+	require := require.New(t)
+	basePath := "../../../test/recipe/mmum/"
+	type testCase struct {
+		Name     string
+		FileName string
+	}
+	testCases := []testCase{
+		{Name: "Hula Hula IPA", FileName: "Hula_Hula_IPA.json"},
+		{Name: "Callippo Mango", FileName: "Callippo_Mango.json"},
+		{Name: "4S Saison", FileName: "4S_Saison.json"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			file, err := os.ReadFile(basePath + tc.FileName)
+			require.NoError(err)
+			original, err := (&mmum.MMUMParser{}).Parse(string(file))
+			require.NoError(err)
+			exported, err := (&BeerJSONExporter{}).Export(original)
+			require.NoError(err)
+			actual, err := (&BeerJSONParser{}).Parse(exported)
+			require.NoError(err)
+			require.Equal(original.Name, actual.Name)
+			require.Equal(original.Style, actual.Style)
+			require.Equal(original.BatchSize, actual.BatchSize)
+			require.Equal(original.InitialSG, actual.InitialSG)
+			require.Equal(original.ColorEBC, actual.ColorEBC)
+			require.Equal(original.Mashing, actual.Mashing)
+			require.Equal(original.Hopping, actual.Hopping)
+			require.Equal(original.Fermentation, actual.Fermentation)
+		})
+	}
+}
+
+func TestExportRoundTripYeast(t *testing.T) {
+	require := require.New(t)
+	file, err := os.ReadFile("../../../test/recipe/beerjson/Cascade_Pale_Ale.json")
+	require.NoError(err)
+	original, err := (&BeerJSONParser{}).Parse(string(file))
+	require.NoError(err)
+	exported, err := (&BeerJSONExporter{}).Export(original)
+	require.NoError(err)
+	actual, err := (&BeerJSONParser{}).Parse(exported)
+	require.NoError(err)
+	require.Equal(original.Fermentation.Yeast, actual.Fermentation.Yeast)
+	// A single attenuation is read as a range of one value
+	culture := BeerJSONCulture{Attenuation: &Quantity{Unit: "%", Value: 75}}
+	minAttenuation, maxAttenuation, err := culture.attenuation()
+	require.NoError(err)
+	require.Equal([]float64{75, 75}, []float64{minAttenuation, maxAttenuation})
+}
+
+func TestExportRoundTripDecoction(t *testing.T) {
+	require := require.New(t)
+	file, err := os.ReadFile("../../../test/recipe/mmum/Dunkel_Dekoktion.json")
+	require.NoError(err)
+	original, err := (&mmum.MMUMParser{}).Parse(string(file))
+	require.NoError(err)
+	exported, err := (&BeerJSONExporter{}).Export(original)
+	require.NoError(err)
+	actual, err := (&BeerJSONParser{}).Parse(exported)
+	require.NoError(err)
+	require.Equal(original.Mashing.MainWaterVolume, actual.Mashing.MainWaterVolume)
+	require.Equal(original.Mashing.Rasts, actual.Mashing.Rasts)
+	require.Equal(recipe.MashTypeDecoction, actual.Mashing.MashType)
+	// BeerJSON has no rest and boil durations for the decoctions, only the volume is kept
+	require.Equal([]recipe.Decoction{{Rast: 1, Volume: 7.5}, {Rast: 2, Volume: 6}}, actual.Mashing.Decoctions)
+}
+
+func TestGetFermentationSchedule(t *testing.T) {
+	require := require.New(t)
+	steps := []BeerJSONFermentationStep{
+		{Name: "Primary", StartTemperature: &Quantity{Unit: "C", Value: 10}, StepTime: &Quantity{Unit: "day", Value: 5}},
+		{Name: "Diacetyl rest", StartTemperature: &Quantity{Unit: "C", Value: 10}, EndTemperature: &Quantity{Unit: "C", Value: 14}, StepTime: &Quantity{Unit: "hr", Value: 60}},
+		{Name: "Cold crash", StartTemperature: &Quantity{Unit: "F", Value: 57.2}, EndTemperature: &Quantity{Unit: "F", Value: 35.6}},
+	}
+	expected := []recipe.FermentationStep{
+		{Name: "Primary", Temperature: 10, Days: 5},
+		{Name: "Diacetyl rest", Temperature: 14, Days: 2.5, FreeRise: true},
+		{Name: "Cold crash", Temperature: 2},
+	}
+	actual, err := getFermentationSchedule(steps)
+	require.NoError(err)
+	require.Equal(expected, actual)
+
+	_, err = getFermentationSchedule([]BeerJSONFermentationStep{{StepTime: &Quantity{Unit: "fortnight", Value: 1}}})
+	require.Error(err)
+}
+
+func TestExportRoundTripFermentationSchedule(t *testing.T) {
+	require := require.New(t)
+	file, err := os.ReadFile("../../../test/recipe/mmum/Hula_Hula_IPA.json")
+	require.NoError(err)
+	original, err := (&mmum.MMUMParser{}).Parse(string(file))
+	require.NoError(err)
+	original.Fermentation.Temperature = "10"
+	original.Fermentation.Schedule = []recipe.FermentationStep{
+		{Name: "Primary", Temperature: 10, Days: 5},
+		{Name: "Diacetyl rest", Temperature: 14, Days: 2, FreeRise: true},
+		{Name: "Cold crash", Temperature: 2},
+	}
+	exported, err := (&BeerJSONExporter{}).Export(original)
+	require.NoError(err)
+	actual, err := (&BeerJSONParser{}).Parse(exported)
+	require.NoError(err)
+	require.Equal(original.Fermentation, actual.Fermentation)
+}
+
+func TestExportRoundTripWhirlpool(t *testing.T) {
+	require := require.New(t)
+	file, err := os.ReadFile("../../../test/recipe/mmum/Hula_Hula_IPA.json")
+	require.NoError(err)
+	original, err := (&mmum.MMUMParser{}).Parse(string(file))
+	require.NoError(err)
+	original.Hopping.Hops = append(original.Hopping.Hops,
+		recipe.Hops{Name: "Citra", Alpha: 12, Amount: 50, Whirlpool: true, StandTemperature: 80, StandTime: 20},
+		recipe.Hops{Name: "Mosaic", Alpha: 11.5, Amount: 40, Whirlpool: true, StandTemperature: 70, StandTime: 30},
+		recipe.Hops{Name: "Amarillo", Alpha: 9, Amount: 30, Whirlpool: true, StandTemperature: 80, StandTime: 10},
+	)
+	exported, err := (&BeerJSONExporter{}).Export(original)
+	require.NoError(err)
+	actual, err := (&BeerJSONParser{}).Parse(exported)
+	require.NoError(err)
+	require.Equal(original.Hopping, actual.Hopping)
+	require.Equal(original.Hopping.HopStandTime(), actual.Hopping.HopStandTime())
+
+	// The whirlpool hops without temperature and steep time are exported with the defaults
+	original.Hopping.Hops = []recipe.Hops{{Name: "Citra", Alpha: 12, Amount: 50, Whirlpool: true}}
+	exported, err = (&BeerJSONExporter{}).Export(original)
+	require.NoError(err)
+	actual, err = (&BeerJSONParser{}).Parse(exported)
+	require.NoError(err)
+	expected := recipe.Hops{Name: "Citra", Alpha: 12, Amount: 50, Whirlpool: true, StandTemperature: recipe.DefaultStandTemperature, StandTime: recipe.DefaultStandTime}
+	require.Equal([]recipe.Hops{expected}, actual.Hopping.Hops)
+}
@@ -0,0 +1,321 @@
+package beerjson
+
+import (
+	"brewday/internal/recipe"
+	"brewday/internal/tools"
+	"encoding/json"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// defaultEfficiency is the brewhouse efficiency in % used when exporting, as BrewDay recipes do not store it
+const defaultEfficiency = 75
+
+// defaultGrainTemperature is the grain temperature in °C used when exporting, as BrewDay recipes do not store it
+const defaultGrainTemperature = 20
+
+// temperatureRegex matches the numbers in a fermentation temperature (e.g. 18-20 or 18,5 °C)
+var temperatureRegex = regexp.MustCompile(`\d+(?:[.,]\d+)?`)
+
+// BeerJSONExporter is a RecipeExporter implementation that writes recipes in BeerJSON 1.0 format
+// Values are exported in the units used in BrewDay (g, l, °C, min, SG, EBC)
+type BeerJSONExporter struct{}
+
+// Export returns the recipe as a BeerJSON document
+func (e *BeerJSONExporter) Export(r *recipe.Recipe) (string, error) {
+	doc := BeerJSONDocument{
+		BeerJSON: BeerJSON{
+			Version: 1.0,
+			Recipes: []BeerJSONRecipe{*recipeToBeerJSONRecipe(r)},
+		},
+	}
+	b, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// recipeToBeerJSONRecipe converts a recipe.Recipe to a BeerJSONRecipe
+func recipeToBeerJSONRecipe(r *recipe.Recipe) *BeerJSONRecipe {
+	bj := &BeerJSONRecipe{
+		Name:      r.Name,
+		Type:      "all grain",
+		Author:    "BrewDay",
+		BatchSize: &Quantity{Unit: "l", Value: toFloat64(r.BatchSize)},
+		Efficiency: BeerJSONEfficiency{
+			Brewhouse: &Quantity{Unit: "%", Value: defaultEfficiency},
+		},
+		Ingredients: BeerJSONIngredients{
+			Fermentables: exportFermentables(&r.Mashing),
+			Hops:         exportHops(&r.Hopping),
+			Miscs:        exportMiscs(r),
+			Cultures:     exportCultures(&r.Fermentation),
+		},
+		Mash:            exportMash(&r.Mashing),
+		OriginalGravity: &Quantity{Unit: "sg", Value: toFloat64(r.InitialSG)},
+		ColorEstimate:   &Quantity{Unit: "EBC", Value: toFloat64(r.ColorEBC)},
+		Carbonation:     float64(tools.RoundTo(tools.GramsPerLiterToCO2Volumes(r.Fermentation.Carbonation), 2)),
+		Fermentation:    exportFermentation(&r.Fermentation),
+		Boil:            exportBoil(&r.Hopping),
+	}
+	if r.Style != "" {
+		bj.Style = &BeerJSONStyle{Name: r.Style, Category: r.Style, Type: "beer"}
+	}
+	return bj
+}
+
+// exportFermentables returns the malts as fermentable additions
+// BrewDay does not store the type of a malt, so all of them are exported as grain
+func exportFermentables(mash *recipe.MashInstructions) []BeerJSONFermentable {
+	fermentables := []BeerJSONFermentable{}
+	for _, m := range mash.Malts {
+		f := BeerJSONFermentable{
+			Name:   m.Name,
+			Type:   "grain",
+			Amount: &Quantity{Unit: "g", Value: toFloat64(m.Amount)},
+		}
+		if m.Color > 0 {
+			f.Color = &Quantity{Unit: "EBC", Value: toFloat64(m.Color)}
+		}
+		fermentables = append(fermentables, f)
+	}
+	return fermentables
+}
+
+// exportHops returns the hops as hop additions
+// Vorderwürze hops are marked with a suffix in the name, so they can be recognized when importing them again
+// Whirlpool hops are added at the end of the boil in the whirlpool step of their temperature, for their steep time
+func exportHops(hopping *recipe.HopInstructions) []BeerJSONHop {
+	temperatures := whirlpoolTemperatures(hopping)
+	var hops []BeerJSONHop
+	for _, h := range hopping.Hops {
+		hop := BeerJSONHop{
+			Name:      h.Name,
+			AlphaAcid: &Quantity{Unit: "%", Value: toFloat64(h.Alpha)},
+			Amount:    &Quantity{Unit: "g", Value: toFloat64(h.Amount)},
+			Timing: &BeerJSONTiming{
+				Use:  "add_to_boil",
+				Time: &Quantity{Unit: "min", Value: toFloat64(h.Duration)},
+			},
+		}
+		if h.DryHop {
+			hop.Timing = &BeerJSONTiming{Use: "add_to_fermentation"}
+		} else if h.Whirlpool {
+			// The default temperature and steep time apply to the hops that do not give them
+			stand := recipe.HopInstructions{Hops: []recipe.Hops{h}}.WhirlpoolHops()[0]
+			hop.Timing = &BeerJSONTiming{
+				Use:      "add_to_boil",
+				Time:     &Quantity{Unit: "min", Value: 0},
+				Duration: &Quantity{Unit: "min", Value: toFloat64(stand.StandTime)},
+				// The first step is the boil itself
+				Step: slices.Index(temperatures, stand.StandTemperature) + 2,
+			}
+		}
+		if h.Vorderwuerze && !strings.HasSuffix(h.Name, vorderwuerzeSuffix) {
+			hop.Name = h.Name + vorderwuerzeSuffix
+		}
+		hops = append(hops, hop)
+	}
+	return hops
+}
+
+// exportBoil returns the boil procedure
+// With whirlpool hops, the boil is the first step, followed by a whirlpool step for every temperature of the hops
+// from the hottest, which lasts as long as the longest steep time at that temperature
+func exportBoil(hopping *recipe.HopInstructions) *BeerJSONBoil {
+	boil := &BeerJSONBoil{
+		BoilTime: &Quantity{Unit: "min", Value: toFloat64(hopping.TotalCookingTime)},
+	}
+	temperatures := whirlpoolTemperatures(hopping)
+	if len(temperatures) == 0 {
+		return boil
+	}
+	boil.Steps = append(boil.Steps, BeerJSONBoilStep{
+		Name:             "Boil",
+		StartTemperature: &Quantity{Unit: "C", Value: 100},
+		StepTime:         boil.BoilTime,
+	})
+	hops := hopping.WhirlpoolHops()
+	for _, temperature := range temperatures {
+		var steep float32
+		for _, h := range hops {
+			if h.StandTemperature == temperature {
+				steep = max(steep, h.StandTime)
+			}
+		}
+		boil.Steps = append(boil.Steps, BeerJSONBoilStep{
+			Name:             "Whirlpool",
+			StartTemperature: &Quantity{Unit: "C", Value: toFloat64(temperature)},
+			StepTime:         &Quantity{Unit: "min", Value: toFloat64(steep)},
+		})
+	}
+	return boil
+}
+
+// whirlpoolTemperatures returns the temperatures of the whirlpool hops, from the hottest, without duplicates
+func whirlpoolTemperatures(hopping *recipe.HopInstructions) []float32 {
+	var temperatures []float32
+	for _, h := range hopping.WhirlpoolHops() {
+		if !slices.Contains(temperatures, h.StandTemperature) {
+			temperatures = append(temperatures, h.StandTemperature)
+		}
+	}
+	return temperatures
+}
+
+// exportMiscs returns the additional ingredients of the boil and the fermentation as miscellaneous additions
+func exportMiscs(r *recipe.Recipe) []BeerJSONMisc {
+	var miscs []BeerJSONMisc
+	for _, a := range r.Hopping.AdditionalIngredients {
+		miscs = append(miscs, ingredientToMisc(&a, "add_to_boil"))
+	}
+	for _, a := range r.Fermentation.AdditionalIngredients {
+		miscs = append(miscs, ingredientToMisc(&a, "add_to_fermentation"))
+	}
+	return miscs
+}
+
+// ingredientToMisc converts an additional ingredient to a miscellaneous addition with the given use
+func ingredientToMisc(a *recipe.AdditionalIngredient, use string) BeerJSONMisc {
+	return BeerJSONMisc{
+		Name:   a.Name,
+		Type:   "other",
+		Amount: &Quantity{Unit: "g", Value: toFloat64(a.Amount)},
+		Timing: &BeerJSONTiming{
+			Use:  use,
+			Time: &Quantity{Unit: "min", Value: toFloat64(a.Duration)},
+		},
+	}
+}
+
+// exportCultures returns the yeast as culture addition
+// A yeast with an amount in grams is considered dry yeast
+func exportCultures(fermentation *recipe.FermentationInstructions) []BeerJSONCulture {
+	if fermentation.Yeast.Name == "" {
+		return nil
+	}
+	culture := BeerJSONCulture{
+		Name:     fermentation.Yeast.Name,
+		Type:     "other",
+		Form:     "liquid",
+		Producer: fermentation.Yeast.Lab,
+	}
+	if fermentation.Yeast.KnownAttenuation() {
+		culture.AttenuationRange = &BeerJSONPercentRange{
+			Minimum: &Quantity{Unit: "%", Value: toFloat64(fermentation.Yeast.MinAttenuation)},
+			Maximum: &Quantity{Unit: "%", Value: toFloat64(fermentation.Yeast.MaxAttenuation)},
+		}
+	}
+	if fermentation.Yeast.Amount > 0 {
+		culture.Form = "dry"
+		culture.Amount = &Quantity{Unit: "g", Value: toFloat64(fermentation.Yeast.Amount)}
+	}
+	return []BeerJSONCulture{culture}
+}
+
+// exportMash returns the mash procedure
+// The first rast is the infusion of the main water at the mash temperature, followed by the rest of the rasts, the mash out and the sparge
+// Rasts reached by a decoction are exported as decoction steps with the volume of the decoction as amount
+func exportMash(mash *recipe.MashInstructions) *BeerJSONMash {
+	m := &BeerJSONMash{
+		Name:             "BrewDay",
+		GrainTemperature: &Quantity{Unit: "C", Value: defaultGrainTemperature},
+		Steps:            []BeerJSONMashStep{},
+	}
+	for i, r := range mash.Rasts {
+		step := BeerJSONMashStep{
+			Name:            "Rast " + strconv.Itoa(i+1),
+			Type:            "temperature",
+			StepTemperature: &Quantity{Unit: "C", Value: toFloat64(r.Temperature)},
+			StepTime:        &Quantity{Unit: "min", Value: toFloat64(r.Duration)},
+		}
+		if i == 0 {
+			step.Type = "infusion"
+			step.Amount = &Quantity{Unit: "l", Value: toFloat64(mash.MainWaterVolume)}
+			step.InfuseTemperature = &Quantity{Unit: "C", Value: toFloat64(mash.MashTemperature)}
+		} else if d := mash.DecoctionForRast(i); d >= 0 {
+			step.Type = "decoction"
+			step.Amount = &Quantity{Unit: "l", Value: toFloat64(mash.Decoctions[d].Volume)}
+		}
+		m.Steps = append(m.Steps, step)
+	}
+	if mash.MashOutTemperature > 0 {
+		m.Steps = append(m.Steps, BeerJSONMashStep{
+			Name:            "Mash Out",
+			Type:            "temperature",
+			StepTemperature: &Quantity{Unit: "C", Value: toFloat64(mash.MashOutTemperature)},
+			StepTime:        &Quantity{Unit: "min", Value: 0},
+		})
+	}
+	if mash.Nachguss > 0 {
+		m.Steps = append(m.Steps, BeerJSONMashStep{
+			Name:            "Sparge",
+			Type:            "sparge",
+			Amount:          &Quantity{Unit: "l", Value: toFloat64(mash.Nachguss)},
+			StepTemperature: &Quantity{Unit: "C", Value: toFloat64(mash.MashOutTemperature)},
+			StepTime:        &Quantity{Unit: "min", Value: 0},
+		})
+	}
+	return m
+}
+
+// exportFermentation returns the fermentation procedure with a single step
+// The fermentation temperature can be a range (e.g. 18-20), in which case it is exported as start and end temperature
+// If the recipe has a temperature schedule, each step of the schedule is exported instead
+func exportFermentation(fermentation *recipe.FermentationInstructions) *BeerJSONFermentation {
+	if len(fermentation.Schedule) > 0 {
+		return exportFermentationSchedule(fermentation.Schedule)
+	}
+	temperatures := temperatureRegex.FindAllString(fermentation.Temperature, 2)
+	if len(temperatures) == 0 {
+		return nil
+	}
+	step := BeerJSONFermentationStep{Name: "Primary"}
+	for i, t := range temperatures {
+		value, err := strconv.ParseFloat(strings.ReplaceAll(t, ",", "."), 64)
+		if err != nil {
+			return nil
+		}
+		q := &Quantity{Unit: "C", Value: value}
+		if i == 0 {
+			step.StartTemperature = q
+		} else {
+			step.EndTemperature = q
+		}
+	}
+	return &BeerJSONFermentation{
+		Name:  "Primary",
+		Steps: []BeerJSONFermentationStep{step},
+	}
+}
+
+// exportFermentationSchedule returns the fermentation procedure with a step for each step of the schedule
+// A free rise starts at the temperature of the previous step
+func exportFermentationSchedule(schedule []recipe.FermentationStep) *BeerJSONFermentation {
+	steps := []BeerJSONFermentationStep{}
+	for i, s := range schedule {
+		start := s.Temperature
+		if s.FreeRise && i > 0 {
+			start = schedule[i-1].Temperature
+		}
+		steps = append(steps, BeerJSONFermentationStep{
+			Name:             s.Name,
+			StartTemperature: &Quantity{Unit: "C", Value: toFloat64(start)},
+			EndTemperature:   &Quantity{Unit: "C", Value: toFloat64(s.Temperature)},
+			StepTime:         &Quantity{Unit: "day", Value: toFloat64(s.Days)},
+		})
+	}
+	return &BeerJSONFermentation{
+		Name:  "Schedule",
+		Steps: steps,
+	}
+}
+
+// toFloat64 converts a float32 to float64 keeping its shortest decimal representation (e.g. 1.052 instead of 1.0520000457763672)
+func toFloat64(f float32) float64 {
+	v, _ := strconv.ParseFloat(strconv.FormatFloat(float64(f), 'f', -1, 32), 64)
+	return v
+}
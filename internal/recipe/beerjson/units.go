@@ -0,0 +1,150 @@
+package beerjson
+
+import (
+	"brewday/internal/tools"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Quantity is a value with a unit as used by all BeerJSON measurable types (mass, volume, temperature, ...)
+type Quantity struct {
+	Unit  string  `json:"unit"`
+	Value float64 `json:"value"`
+}
+
+// massFactors are the factors to convert BeerJSON mass units to grams
+var massFactors = map[string]float64{
+	"mg": 0.001,
+	"g":  1,
+	"kg": 1000,
+	"lb": 453.59237,
+	"oz": 28.349523125,
+}
+
+// volumeFactors are the factors to convert BeerJSON volume units to liters
+var volumeFactors = map[string]float64{
+	"ml":    0.001,
+	"l":     1,
+	"tsp":   0.00492892,
+	"tbsp":  0.0147868,
+	"floz":  0.0295735,
+	"cup":   0.236588,
+	"pt":    0.473176,
+	"qt":    0.946353,
+	"gal":   3.78541,
+	"bbl":   117.348,
+	"ifloz": 0.0284131,
+	"ipt":   0.568261,
+	"iqt":   1.13652,
+	"igal":  4.54609,
+	"ibbl":  163.659,
+}
+
+// timeFactors are the factors to convert BeerJSON time units to minutes
+var timeFactors = map[string]float64{
+	"sec":  1.0 / 60,
+	"min":  1,
+	"hr":   60,
+	"day":  1440,
+	"week": 10080,
+}
+
+// isMass returns whether the quantity is expressed in a mass unit
+func (q *Quantity) isMass() bool {
+	_, ok := massFactors[strings.ToLower(q.Unit)]
+	return ok
+}
+
+// isVolume returns whether the quantity is expressed in a volume unit
+func (q *Quantity) isVolume() bool {
+	_, ok := volumeFactors[strings.ToLower(q.Unit)]
+	return ok
+}
+
+// toGrams returns the quantity in grams. A nil quantity returns 0
+func (q *Quantity) toGrams() (float64, error) {
+	return q.convert(massFactors, "mass")
+}
+
+// toLiters returns the quantity in liters. A nil quantity returns 0
+func (q *Quantity) toLiters() (float64, error) {
+	return q.convert(volumeFactors, "volume")
+}
+
+// toMinutes returns the quantity in minutes. A nil quantity returns 0
+func (q *Quantity) toMinutes() (float64, error) {
+	return q.convert(timeFactors, "time")
+}
+
+// convert applies the factor for the unit of the quantity
+func (q *Quantity) convert(factors map[string]float64, kind string) (float64, error) {
+	if q == nil {
+		return 0, nil
+	}
+	factor, ok := factors[strings.ToLower(q.Unit)]
+	if !ok {
+		return 0, fmt.Errorf("invalid %s unit %s", kind, q.Unit)
+	}
+	return q.Value * factor, nil
+}
+
+// toCelsius returns the temperature in °C. A nil quantity returns 0
+func (q *Quantity) toCelsius() (float64, error) {
+	if q == nil {
+		return 0, nil
+	}
+	switch strings.ToUpper(q.Unit) {
+	case "C":
+		return q.Value, nil
+	case "F":
+		return float64(tools.FahrenheitToCelsius(float32(q.Value))), nil
+	default:
+		return 0, fmt.Errorf("invalid temperature unit %s", q.Unit)
+	}
+}
+
+// toSG returns the gravity in SG. Brix is treated as plato, as they are equivalent for wort. A nil quantity returns 0
+func (q *Quantity) toSG() (float64, error) {
+	if q == nil {
+		return 0, nil
+	}
+	switch strings.ToLower(q.Unit) {
+	case "sg":
+		return q.Value, nil
+	case "plato", "brix":
+		return float64(tools.RoundTo(tools.PlatoToSG(float32(q.Value)), 3)), nil
+	default:
+		return 0, fmt.Errorf("invalid gravity unit %s", q.Unit)
+	}
+}
+
+// toPercent returns the value of a percentage. A nil quantity returns 0
+func (q *Quantity) toPercent() (float64, error) {
+	if q == nil {
+		return 0, nil
+	}
+	if q.Unit != "%" {
+		return 0, fmt.Errorf("invalid percent unit %s", q.Unit)
+	}
+	return q.Value, nil
+}
+
+// toEBC returns the color in EBC. A nil quantity returns 0
+func (q *Quantity) toEBC() (float64, error) {
+	if q == nil {
+		return 0, nil
+	}
+	switch strings.ToLower(q.Unit) {
+	case "ebc":
+		return q.Value, nil
+	case "srm":
+		return float64(tools.SRMtoEBC(float32(q.Value))), nil
+	case "lovi":
+		// The conversion is not valid for very light colors (e.g. sugars)
+		srm := math.Max(1.3546*q.Value-0.76, 0)
+		return float64(tools.SRMtoEBC(float32(srm))), nil
+	default:
+		return 0, fmt.Errorf("invalid color unit %s", q.Unit)
+	}
+}
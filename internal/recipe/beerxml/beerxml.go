@@ -0,0 +1,388 @@
+package beerxml
+
+import (
+	"brewday/internal/recipe"
+	"brewday/internal/tools"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// grainAbsorption is the amount of water in liters retained by one kilogram of grain after lautering
+const grainAbsorption = 1.0
+
+// BeerXMLParser is a RecipeParser implementation that parses recipes in BeerXML 1.0 format
+// This is the format exported by BeerSmith, Brewfather, Brewer's Friend and most other brewing software
+type BeerXMLParser struct{}
+
+// BeerXMLRecipes is the root element of a BeerXML document
+type BeerXMLRecipes struct {
+	XMLName xml.Name        `xml:"RECIPES"`
+	Recipes []BeerXMLRecipe `xml:"RECIPE"`
+}
+
+// BeerXMLRecipe represents a recipe in BeerXML format
+// All units are metric as defined in the standard: kg, liters, minutes and °C
+type BeerXMLRecipe struct {
+	Name          string               `xml:"NAME"`
+	Type          string               `xml:"TYPE"`
+	Style         BeerXMLStyle         `xml:"STYLE"`
+	BatchSize     float64              `xml:"BATCH_SIZE"`
+	BoilSize      float64              `xml:"BOIL_SIZE"`
+	BoilTime      float64              `xml:"BOIL_TIME"`
+	Efficiency    float64              `xml:"EFFICIENCY"`
+	Hops          []BeerXMLHop         `xml:"HOPS>HOP"`
+	Fermentables  []BeerXMLFermentable `xml:"FERMENTABLES>FERMENTABLE"`
+	Miscs         []BeerXMLMisc        `xml:"MISCS>MISC"`
+	Yeasts        []BeerXMLYeast       `xml:"YEASTS>YEAST"`
+	Mash          BeerXMLMash          `xml:"MASH"`
+	OG            float64              `xml:"OG"`
+	FG            float64              `xml:"FG"`
+	Stages        int                  `xml:"FERMENTATION_STAGES"`
+	PrimaryAge    float64              `xml:"PRIMARY_AGE"`
+	PrimaryTemp   float64              `xml:"PRIMARY_TEMP"`
+	SecondaryAge  float64              `xml:"SECONDARY_AGE"`
+	SecondaryTemp float64              `xml:"SECONDARY_TEMP"`
+	TertiaryAge   float64              `xml:"TERTIARY_AGE"`
+	TertiaryTemp  float64              `xml:"TERTIARY_TEMP"`
+	Carbonation   float64              `xml:"CARBONATION"`
+	IBU           float64              `xml:"IBU"`
+	EstColor      string               `xml:"EST_COLOR"`
+}
+
+// BeerXMLStyle represents the style of a BeerXML recipe
+type BeerXMLStyle struct {
+	Name     string `xml:"NAME"`
+	Category string `xml:"CATEGORY"`
+}
+
+// BeerXMLHop represents a hop addition in BeerXML format
+type BeerXMLHop struct {
+	Name   string  `xml:"NAME"`
+	Alpha  float64 `xml:"ALPHA"`
+	Amount float64 `xml:"AMOUNT"`
+	Use    string  `xml:"USE"`
+	Time   float64 `xml:"TIME"`
+}
+
+// BeerXMLFermentable represents a fermentable in BeerXML format
+type BeerXMLFermentable struct {
+	Name   string  `xml:"NAME"`
+	Type   string  `xml:"TYPE"`
+	Amount float64 `xml:"AMOUNT"`
+	Yield  float64 `xml:"YIELD"`
+	Color  float64 `xml:"COLOR"`
+}
+
+// BeerXMLMisc represents a miscellaneous ingredient in BeerXML format
+type BeerXMLMisc struct {
+	Name           string  `xml:"NAME"`
+	Type           string  `xml:"TYPE"`
+	Use            string  `xml:"USE"`
+	Time           float64 `xml:"TIME"`
+	Amount         float64 `xml:"AMOUNT"`
+	AmountIsWeight string  `xml:"AMOUNT_IS_WEIGHT"`
+}
+
+// BeerXMLYeast represents a yeast in BeerXML format
+type BeerXMLYeast struct {
+	Name           string  `xml:"NAME"`
+	Type           string  `xml:"TYPE"`
+	Form           string  `xml:"FORM"`
+	Amount         float64 `xml:"AMOUNT"`
+	AmountIsWeight string  `xml:"AMOUNT_IS_WEIGHT"`
+	Laboratory     string  `xml:"LABORATORY"`
+	Attenuation    float64 `xml:"ATTENUATION"`
+}
+
+// BeerXMLMash represents the mash profile in BeerXML format
+type BeerXMLMash struct {
+	Name       string            `xml:"NAME"`
+	GrainTemp  float64           `xml:"GRAIN_TEMP"`
+	SpargeTemp float64           `xml:"SPARGE_TEMP"`
+	Steps      []BeerXMLMashStep `xml:"MASH_STEPS>MASH_STEP"`
+}
+
+// BeerXMLMashStep represents a mash step in BeerXML format
+type BeerXMLMashStep struct {
+	Name         string  `xml:"NAME"`
+	Type         string  `xml:"TYPE"`
+	InfuseAmount float64 `xml:"INFUSE_AMOUNT"`
+	StepTime     float64 `xml:"STEP_TIME"`
+	StepTemp     float64 `xml:"STEP_TEMP"`
+}
+
+// Parse parses a recipe from a string
+// If the document contains more than one recipe, only the first one is returned
+func (p *BeerXMLParser) Parse(recipe string) (*recipe.Recipe, error) {
+	recipes, err := decode(recipe)
+	if err != nil {
+		return nil, err
+	}
+	return beerXMLRecipeToRecipe(&recipes.Recipes[0])
+}
+
+// ParseAll parses all the recipes contained in a BeerXML document
+// The returned errors are aligned with the recipes, so one invalid recipe does not prevent reading the others
+func (p *BeerXMLParser) ParseAll(doc string) ([]*recipe.Recipe, []error, error) {
+	recipes, err := decode(doc)
+	if err != nil {
+		return nil, nil, err
+	}
+	result := make([]*recipe.Recipe, len(recipes.Recipes))
+	errs := make([]error, len(recipes.Recipes))
+	for i := range recipes.Recipes {
+		result[i], errs[i] = beerXMLRecipeToRecipe(&recipes.Recipes[i])
+		if errs[i] != nil {
+			errs[i] = fmt.Errorf("recipe %q: %w", recipes.Recipes[i].Name, errs[i])
+		}
+	}
+	return result, errs, nil
+}
+
+// decode reads a BeerXML document and returns its recipes
+// BeerSmith writes documents in ISO-8859-1, so latin-1 is supported in addition to UTF-8
+func decode(doc string) (*BeerXMLRecipes, error) {
+	var recipes BeerXMLRecipes
+	d := xml.NewDecoder(strings.NewReader(doc))
+	d.CharsetReader = charsetReader
+	err := d.Decode(&recipes)
+	if err != nil {
+		return nil, err
+	}
+	if len(recipes.Recipes) == 0 {
+		return nil, errors.New("no recipe found in beerxml document")
+	}
+	return &recipes, nil
+}
+
+// charsetReader converts the supported non UTF-8 charsets to UTF-8
+func charsetReader(charset string, input io.Reader) (io.Reader, error) {
+	switch strings.ToLower(charset) {
+	case "iso-8859-1", "latin1", "windows-1252":
+		raw, err := io.ReadAll(input)
+		if err != nil {
+			return nil, err
+		}
+		var buf bytes.Buffer
+		for _, b := range raw {
+			buf.WriteRune(rune(b))
+		}
+		return &buf, nil
+	default:
+		return nil, fmt.Errorf("unsupported charset %s", charset)
+	}
+}
+
+// beerXMLRecipeToRecipe converts a BeerXMLRecipe to a recipe.Recipe
+func beerXMLRecipeToRecipe(r *BeerXMLRecipe) (*recipe.Recipe, error) {
+	if !utf8.ValidString(r.Name) {
+		return nil, errors.New("invalid recipe name")
+	}
+	color, err := parseColor(r.EstColor)
+	if err != nil {
+		return nil, err
+	}
+	return &recipe.Recipe{
+		Name:         strings.TrimSpace(r.Name),
+		Style:        strings.TrimSpace(r.Style.Name),
+		BatchSize:    float32(r.BatchSize),
+		InitialSG:    float32(r.OG),
+		Bitterness:   float32(r.IBU),
+		ColorEBC:     color,
+		Mashing:      *getMashInstructions(r),
+		Hopping:      *getHopInstructions(r),
+		Fermentation: *getFermentationInstructions(r),
+	}, nil
+}
+
+// lovibondToEBC converts the color of a fermentable (in Lovibond) to EBC
+func lovibondToEBC(lovibond float64) float32 {
+	srm := 1.3546*lovibond - 0.76
+	if srm <= 0 {
+		return 0
+	}
+	return tools.RoundTo(tools.SRMtoEBC(float32(srm)), 1)
+}
+
+// parseColor parses the estimated color of a recipe (e.g. "9.5 SRM") and returns it in EBC
+// BeerXML defines colors in SRM. An empty color returns 0
+func parseColor(estColor string) (float32, error) {
+	fields := strings.Fields(estColor)
+	if len(fields) == 0 {
+		return 0, nil
+	}
+	value, err := strconv.ParseFloat(fields[0], 32)
+	if err != nil {
+		return 0, err
+	}
+	if len(fields) > 1 && strings.EqualFold(fields[1], "EBC") {
+		return float32(value), nil
+	}
+	return tools.RoundTo(tools.SRMtoEBC(float32(value)), 1), nil
+}
+
+// getMashInstructions returns the mash instructions for a BeerXMLRecipe
+// The first step defines the mash temperature and main water, the last one the mash out temperature
+// The nachguss is estimated from the boil size, the water used in the mash and the grain absorption
+func getMashInstructions(r *BeerXMLRecipe) *recipe.MashInstructions {
+	var malts []recipe.Malt
+	var grainKg float64
+	for _, f := range r.Fermentables {
+		malts = append(malts, recipe.Malt{
+			Name:   strings.TrimSpace(f.Name),
+			Amount: float32(f.Amount * 1000),
+			Color:  lovibondToEBC(f.Color),
+		})
+		if isGrain(f.Type) {
+			grainKg += f.Amount
+		}
+	}
+	var rasts []recipe.Rast
+	var infused float64
+	for _, s := range r.Mash.Steps {
+		rasts = append(rasts, recipe.Rast{
+			Temperature: float32(s.StepTemp),
+			Duration:    float32(s.StepTime),
+		})
+		infused += s.InfuseAmount
+	}
+	mash := &recipe.MashInstructions{
+		Malts: malts,
+		Rasts: rasts,
+	}
+	if len(r.Mash.Steps) > 0 {
+		first := r.Mash.Steps[0]
+		last := r.Mash.Steps[len(r.Mash.Steps)-1]
+		mash.MainWaterVolume = float32(first.InfuseAmount)
+		mash.MashTemperature = float32(first.StepTemp)
+		mash.MashOutTemperature = float32(last.StepTemp)
+	}
+	nachguss := r.BoilSize - infused + grainKg*grainAbsorption
+	if nachguss > 0 {
+		mash.Nachguss = tools.RoundTo(float32(nachguss), 1)
+	}
+	return mash
+}
+
+// isGrain returns whether a fermentable type is mashed (and therefore absorbs water)
+func isGrain(fermentableType string) bool {
+	switch strings.ToLower(fermentableType) {
+	case "grain", "adjunct":
+		return true
+	default:
+		return false
+	}
+}
+
+// getHopInstructions returns the hop instructions for a BeerXMLRecipe
+// Hops with use "First Wort" are marked as vorderwuerze, "Dry Hop" as dry hops and "Aroma" (or "Whirlpool") as whirlpool
+// hops, which steep for their time at the default temperature. Every other use is treated as a boil addition
+// Miscs with use "Boil" are added as additional ingredients
+func getHopInstructions(r *BeerXMLRecipe) *recipe.HopInstructions {
+	var hops []recipe.Hops
+	for _, h := range r.Hops {
+		hop := recipe.Hops{
+			Name:   strings.TrimSpace(h.Name),
+			Alpha:  float32(h.Alpha),
+			Amount: float32(h.Amount * 1000),
+		}
+		switch strings.ToLower(strings.TrimSpace(h.Use)) {
+		case "dry hop":
+			hop.DryHop = true
+			hop.Alpha = 0
+		case "first wort":
+			hop.Vorderwuerze = true
+			hop.Name = hop.Name + " (VW)"
+			hop.Duration = float32(r.BoilTime)
+		case "aroma", "whirlpool":
+			hop.Whirlpool = true
+			hop.StandTime = float32(h.Time)
+		default:
+			hop.Duration = float32(h.Time)
+		}
+		hops = append(hops, hop)
+	}
+	var additions []recipe.AdditionalIngredient
+	for _, m := range r.Miscs {
+		if strings.EqualFold(m.Use, "boil") {
+			additions = append(additions, miscToIngredient(&m))
+		}
+	}
+	return &recipe.HopInstructions{
+		TotalCookingTime:      float32(r.BoilTime),
+		Hops:                  hops,
+		AdditionalIngredients: additions,
+	}
+}
+
+// getFermentationInstructions returns the fermentation instructions for a BeerXMLRecipe
+// Only the first yeast is used. Its amount is only set for dry yeast (weight), as liquid yeast is measured in liters
+// BeerXML has a single average attenuation, it is used as both ends of the attenuation range
+func getFermentationInstructions(r *BeerXMLRecipe) *recipe.FermentationInstructions {
+	var yeast recipe.Yeast
+	if len(r.Yeasts) > 0 {
+		y := r.Yeasts[0]
+		yeast.Name = strings.TrimSpace(y.Name)
+		if isTrue(y.AmountIsWeight) {
+			yeast.Amount = float32(y.Amount * 1000)
+		}
+		yeast.Lab = strings.TrimSpace(y.Laboratory)
+		if y.Attenuation > 0 {
+			yeast.MinAttenuation = tools.RoundTo(float32(y.Attenuation), 1)
+			yeast.MaxAttenuation = yeast.MinAttenuation
+		}
+	}
+	var additions []recipe.AdditionalIngredient
+	for _, m := range r.Miscs {
+		switch strings.ToLower(m.Use) {
+		case "primary", "secondary", "bottling":
+			additions = append(additions, miscToIngredient(&m))
+		}
+	}
+	var temperature string
+	if r.PrimaryTemp != 0 {
+		temperature = strconv.FormatFloat(r.PrimaryTemp, 'f', -1, 32)
+	}
+	return &recipe.FermentationInstructions{
+		Yeast:                 yeast,
+		Temperature:           temperature,
+		AdditionalIngredients: additions,
+		Carbonation:           tools.RoundTo(tools.CO2VolumesToGramsPerLiter(float32(r.Carbonation)), 1),
+		Schedule:              getFermentationSchedule(r),
+	}
+}
+
+// getFermentationSchedule returns the temperature schedule from the fermentation stages (primary, secondary and tertiary)
+// A single stage is only the fermentation temperature, so there is only a schedule for two or more stages
+func getFermentationSchedule(r *BeerXMLRecipe) []recipe.FermentationStep {
+	if r.Stages < 2 {
+		return nil
+	}
+	stages := []recipe.FermentationStep{
+		{Name: "Primary", Temperature: float32(r.PrimaryTemp), Days: float32(r.PrimaryAge)},
+		{Name: "Secondary", Temperature: float32(r.SecondaryTemp), Days: float32(r.SecondaryAge)},
+		{Name: "Tertiary", Temperature: float32(r.TertiaryTemp), Days: float32(r.TertiaryAge)},
+	}
+	return stages[:min(r.Stages, len(stages))]
+}
+
+// miscToIngredient converts a BeerXML misc to an additional ingredient in grams
+// Volumes (liters) are converted assuming the density of water
+func miscToIngredient(m *BeerXMLMisc) recipe.AdditionalIngredient {
+	return recipe.AdditionalIngredient{
+		Name:     strings.TrimSpace(m.Name),
+		Amount:   float32(m.Amount * 1000),
+		Duration: float32(m.Time),
+	}
+}
+
+// isTrue parses a BeerXML boolean (TRUE or FALSE)
+func isTrue(s string) bool {
+	return strings.EqualFold(strings.TrimSpace(s), "true")
+}
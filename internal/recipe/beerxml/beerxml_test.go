@@ -0,0 +1,264 @@
+package beerxml
+
+import (
+	"brewday/internal/recipe"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	require := require.New(t)
+	basePath := "../../../test/recipe/beerxml/"
+	type testCase struct {
+		Name               string
+		FileName           string
+		ExpectedName       string
+		ExpectedStyle      string
+		ExpectedBatchSize  float32
+		ExpectedInitialSG  float32
+		ExpectedBitterness float32
+		ExpectedColorEBC   float32
+	}
+	testCases := []testCase{
+		{
+			Name:               "Burton Pale Ale",
+			FileName:           "Burton_Pale_Ale.xml",
+			ExpectedName:       "Burton Pale Ale",
+			ExpectedStyle:      "English IPA",
+			ExpectedBatchSize:  20,
+			ExpectedInitialSG:  1.052,
+			ExpectedBitterness: 38.5,
+			ExpectedColorEBC:   18.7,
+		},
+		{
+			Name:               "Hefeweizen",
+			FileName:           "Hefeweizen.xml",
+			ExpectedName:       "Sommer Hefeweizen",
+			ExpectedStyle:      "Weissbier",
+			ExpectedBatchSize:  23,
+			ExpectedInitialSG:  1.05,
+			ExpectedBitterness: 14,
+			ExpectedColorEBC:   0,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			file, err := os.ReadFile(basePath + tc.FileName)
+			require.NoError(err)
+			p := &BeerXMLParser{}
+			actual, err := p.Parse(string(file))
+			require.NoError(err)
+			require.Equal(tc.ExpectedName, actual.Name)
+			require.Equal(tc.ExpectedStyle, actual.Style)
+			require.Equal(tc.ExpectedBatchSize, actual.BatchSize)
+			require.Equal(tc.ExpectedInitialSG, actual.InitialSG)
+			require.Equal(tc.ExpectedBitterness, actual.Bitterness)
+			require.Equal(tc.ExpectedColorEBC, actual.ColorEBC)
+		})
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	require := require.New(t)
+	p := &BeerXMLParser{}
+	_, err := p.Parse("<RECIPES></RECIPES>")
+	require.Error(err)
+	_, err = p.Parse("not xml")
+	require.Error(err)
+}
+
+func TestGetMashInstructions(t *testing.T) {
+	require := require.New(t)
+	basePath := "../../../test/recipe/beerxml/"
+	type testCase struct {
+		Name     string
+		FileName string
+		Expected recipe.MashInstructions
+	}
+	testCases := []testCase{
+		{
+			Name:     "Burton Pale Ale",
+			FileName: "Burton_Pale_Ale.xml",
+			Expected: recipe.MashInstructions{
+				Malts: []recipe.Malt{
+					{Name: "Maris Otter", Amount: 4500, Color: 6.5},
+					{Name: "Crystal 60", Amount: 300, Color: 158.6},
+					{Name: "Invert Sugar", Amount: 250},
+				},
+				MainWaterVolume:    15,
+				MashTemperature:    66,
+				Nachguss:           14.8,
+				MashOutTemperature: 76,
+				Rasts: []recipe.Rast{
+					{Temperature: 66, Duration: 60},
+					{Temperature: 76, Duration: 10},
+				},
+			},
+		},
+		{
+			Name:     "Hefeweizen",
+			FileName: "Hefeweizen.xml",
+			Expected: recipe.MashInstructions{
+				Malts: []recipe.Malt{
+					{Name: "Weizenmalz hell", Amount: 2800, Color: 3.8},
+					{Name: "Pilsner Malz", Amount: 2200, Color: 3.3},
+				},
+				MainWaterVolume:    17,
+				MashTemperature:    45,
+				Nachguss:           16.5,
+				MashOutTemperature: 78,
+				Rasts: []recipe.Rast{
+					{Temperature: 45, Duration: 15},
+					{Temperature: 63, Duration: 40},
+					{Temperature: 72, Duration: 20},
+					{Temperature: 78, Duration: 5},
+				},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			file, err := os.ReadFile(basePath + tc.FileName)
+			require.NoError(err)
+			recipes, err := decode(string(file))
+			require.NoError(err)
+			actual := getMashInstructions(&recipes.Recipes[0])
+			require.Equal(tc.Expected, *actual)
+		})
+	}
+}
+
+func TestGetHopInstructions(t *testing.T) {
+	require := require.New(t)
+	basePath := "../../../test/recipe/beerxml/"
+	type testCase struct {
+		Name     string
+		FileName string
+		Expected recipe.HopInstructions
+	}
+	testCases := []testCase{
+		{
+			Name:     "Burton Pale Ale",
+			FileName: "Burton_Pale_Ale.xml",
+			Expected: recipe.HopInstructions{
+				TotalCookingTime: 60,
+				Hops: []recipe.Hops{
+					{Name: "East Kent Goldings", Alpha: 5, Amount: 30, Duration: 60},
+					{Name: "Fuggles", Alpha: 4.5, Amount: 20, Duration: 15},
+					{Name: "East Kent Goldings", Amount: 25, DryHop: true},
+				},
+				AdditionalIngredients: []recipe.AdditionalIngredient{
+					{Name: "Irish Moss", Amount: 5, Duration: 10},
+				},
+			},
+		},
+		{
+			Name:     "Hefeweizen",
+			FileName: "Hefeweizen.xml",
+			Expected: recipe.HopInstructions{
+				TotalCookingTime: 90,
+				Hops: []recipe.Hops{
+					{Name: "Hallertauer Mittelfrueh (VW)", Alpha: 4, Amount: 15, Duration: 90, Vorderwuerze: true},
+					{Name: "Hallertauer Mittelfrueh", Alpha: 4, Amount: 10, Whirlpool: true, StandTime: 5},
+				},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			file, err := os.ReadFile(basePath + tc.FileName)
+			require.NoError(err)
+			recipes, err := decode(string(file))
+			require.NoError(err)
+			actual := getHopInstructions(&recipes.Recipes[0])
+			require.Equal(tc.Expected, *actual)
+		})
+	}
+}
+
+func TestGetFermentationInstructions(t *testing.T) {
+	require := require.New(t)
+	basePath := "../../../test/recipe/beerxml/"
+	type testCase struct {
+		Name     string
+		FileName string
+		Expected recipe.FermentationInstructions
+	}
+	testCases := []testCase{
+		{
+			Name:     "Burton Pale Ale",
+			FileName: "Burton_Pale_Ale.xml",
+			Expected: recipe.FermentationInstructions{
+				Yeast: recipe.Yeast{
+					Name:           "Safale S-04",
+					Amount:         11.5,
+					Lab:            "Fermentis",
+					MinAttenuation: 75,
+					MaxAttenuation: 75,
+				},
+				Temperature: "19",
+				Carbonation: 4.5,
+			},
+		},
+		{
+			Name:     "Hefeweizen",
+			FileName: "Hefeweizen.xml",
+			Expected: recipe.FermentationInstructions{
+				Yeast: recipe.Yeast{
+					Name: "WLP300 Hefeweizen Ale",
+				},
+				Temperature: "18",
+				AdditionalIngredients: []recipe.AdditionalIngredient{
+					{Name: "Orange Peel", Amount: 20, Duration: 4320},
+				},
+				Carbonation: 5.9,
+			},
+		},
+		{
+			Name:     "Helles with fermentation stages",
+			FileName: "Helles_Lager.xml",
+			Expected: recipe.FermentationInstructions{
+				Yeast: recipe.Yeast{
+					Name:   "Saflager W-34/70",
+					Amount: 23,
+				},
+				Temperature: "10",
+				Carbonation: 4.9,
+				Schedule: []recipe.FermentationStep{
+					{Name: "Primary", Temperature: 10, Days: 8},
+					{Name: "Secondary", Temperature: 14, Days: 3},
+					{Name: "Tertiary", Temperature: 2, Days: 5},
+				},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			file, err := os.ReadFile(basePath + tc.FileName)
+			require.NoError(err)
+			recipes, err := decode(string(file))
+			require.NoError(err)
+			actual := getFermentationInstructions(&recipes.Recipes[0])
+			require.Equal(tc.Expected, *actual)
+		})
+	}
+}
+
+func TestParseAll(t *testing.T) {
+	require := require.New(t)
+	file, err := os.ReadFile("../../../test/recipe/beerxml/Multiple.xml")
+	require.NoError(err)
+	p := &BeerXMLParser{}
+	recipes, errs, err := p.ParseAll(string(file))
+	require.NoError(err)
+	require.Len(recipes, 3)
+	require.Len(errs, 3)
+	require.NoError(errs[0])
+	require.Equal("Burton Pale Ale", recipes[0].Name)
+	require.Error(errs[1])
+	require.Nil(recipes[1])
+	require.NoError(errs[2])
+	require.Equal("Sommer Hefeweizen", recipes[2].Name)
+}
@@ -63,7 +63,29 @@ type BraurekaJSONRecipe struct {
 	MashRast6Time       string  `json:"Infusion_Rastzeit6"`
 	MashRast7Temp       string  `json:"Infusion_Rasttemperatur7"`
 	MashRast7Time       string  `json:"Infusion_Rastzeit7"`
+	DecoctionMainWater  string  `json:"Dekoktion_0_Volumen"`
+	DecoctionMashTemp   string  `json:"Dekoktion_0_Temperatur_resultierend"`
+	DecoctionMashTime   string  `json:"Dekoktion_0_Rastzeit"`
+	Decoction1Volume    string  `json:"Dekoktion_1_Volumen"`
+	Decoction1RestTemp  string  `json:"Dekoktion_1_Teilmaische_Temperatur"`
+	Decoction1RestTime  string  `json:"Dekoktion_1_Teilmaische_Rastzeit"`
+	Decoction1BoilTime  string  `json:"Dekoktion_1_Teilmaische_Kochzeit"`
+	Decoction1Temp      string  `json:"Dekoktion_1_Temperatur_resultierend"`
+	Decoction1Time      string  `json:"Dekoktion_1_Rastzeit"`
+	Decoction2Volume    string  `json:"Dekoktion_2_Volumen"`
+	Decoction2RestTemp  string  `json:"Dekoktion_2_Teilmaische_Temperatur"`
+	Decoction2RestTime  string  `json:"Dekoktion_2_Teilmaische_Rastzeit"`
+	Decoction2BoilTime  string  `json:"Dekoktion_2_Teilmaische_Kochzeit"`
+	Decoction2Temp      string  `json:"Dekoktion_2_Temperatur_resultierend"`
+	Decoction2Time      string  `json:"Dekoktion_2_Rastzeit"`
+	Decoction3Volume    string  `json:"Dekoktion_3_Volumen"`
+	Decoction3RestTemp  string  `json:"Dekoktion_3_Teilmaische_Temperatur"`
+	Decoction3RestTime  string  `json:"Dekoktion_3_Teilmaische_Rastzeit"`
+	Decoction3BoilTime  string  `json:"Dekoktion_3_Teilmaische_Kochzeit"`
+	Decoction3Temp      string  `json:"Dekoktion_3_Temperatur_resultierend"`
+	Decoction3Time      string  `json:"Dekoktion_3_Rastzeit"`
 	CookingTime         string  `json:"Kochzeit_Wuerze"`
+	HopStandTime        string  `json:"Nachisomerisierungszeit"`
 	HopBefore1Name      string  `json:"Hopfen_VWH_1_Sorte"`
 	HopBefore1Amount    string  `json:"Hopfen_VWH_1_Menge"`
 	HopBefore1Alpha     string  `json:"Hopfen_VWH_1_alpha"`
@@ -263,14 +285,72 @@ func getMashInstructions(r *BraurekaJSONRecipe) (*recipe.MashInstructions, error
 	if err != nil {
 		return nil, err
 	}
-	return &recipe.MashInstructions{
+	mash := &recipe.MashInstructions{
 		Malts:              malts,
 		MainWaterVolume:    mainWater,
 		Nachguss:           extraWater,
 		MashTemperature:    mashTemp,
 		MashOutTemperature: float32(mashOutValue),
 		Rasts:              rasts,
-	}, nil
+		MashType:           recipe.ParseMashType(r.MashType),
+	}
+	if mash.IsDecoction() {
+		if err := getDecoctions(r, mash); err != nil {
+			return nil, err
+		}
+	}
+	return mash, nil
+}
+
+// getDecoctions replaces the mash in and the rasts of the mash instructions with the ones of a BraurekaJSONRecipe mashed by decoction
+// The first rast is the one after mashing in, every other rast is reached by returning a boiled decoction
+// If the recipe has no decoction data, the mash instructions are not modified
+func getDecoctions(r *BraurekaJSONRecipe, mash *recipe.MashInstructions) error {
+	if r.DecoctionMashTemp == "" {
+		return nil
+	}
+	mainWater, err := stringToFloat(r.DecoctionMainWater)
+	if err != nil {
+		return err
+	}
+	mashTemp, err := stringToFloat(r.DecoctionMashTemp)
+	if err != nil {
+		return err
+	}
+	mashTime, err := stringToFloat(r.DecoctionMashTime)
+	if err != nil {
+		return err
+	}
+	rasts := []recipe.Rast{{Temperature: mashTemp, Duration: mashTime}}
+	var decoctions []recipe.Decoction
+	v := reflect.ValueOf(r).Elem()
+	for i := 1; i <= 3; i++ {
+		if v.FieldByName(fmt.Sprintf("Decoction%dTemp", i)).String() == "" {
+			continue
+		}
+		values := map[string]float32{}
+		for _, name := range []string{"Temp", "Time", "Volume", "RestTemp", "RestTime", "BoilTime"} {
+			values[name], err = stringToFloat(v.FieldByName(fmt.Sprintf("Decoction%d%s", i, name)).String())
+			if err != nil {
+				return err
+			}
+		}
+		rasts = append(rasts, recipe.Rast{Temperature: values["Temp"], Duration: values["Time"]})
+		decoctions = append(decoctions, recipe.Decoction{
+			Rast:            len(rasts) - 1,
+			Volume:          values["Volume"],
+			RestTemperature: values["RestTemp"],
+			RestDuration:    values["RestTime"],
+			BoilDuration:    values["BoilTime"],
+		})
+	}
+	if mainWater > 0 {
+		mash.MainWaterVolume = mainWater
+	}
+	mash.MashTemperature = mashTemp
+	mash.Rasts = rasts
+	mash.Decoctions = decoctions
+	return nil
 }
 
 // getHopInstructions returns the hop instructions for a BraurekaJSONRecipe
@@ -333,7 +413,13 @@ func getHopInstructions(r *BraurekaJSONRecipe) (*recipe.HopInstructions, error)
 			}
 			h.Duration = durationValueFloat
 		} else {
+			standTime, err := stringToFloat(r.HopStandTime)
+			if err != nil {
+				return nil, err
+			}
 			h.Duration = 0
+			h.Whirlpool = true
+			h.StandTime = standTime
 		}
 		h.Amount = amountValueFloat
 		h.Alpha = alphaValueFloat
@@ -149,7 +149,7 @@ func TestBJsonRecipeToRecipe(t *testing.T) {
 						{Name: "Huell Melon (VW)", Alpha: 7.2, Amount: 10, Duration: 90, DryHop: false, Vorderwuerze: true},
 						{Name: "Huell Melon", Alpha: 7.2, Amount: 3, Duration: 60, DryHop: false, Vorderwuerze: false},
 						{Name: "Huell Melon", Alpha: 7.2, Amount: 2, Duration: 10, DryHop: false, Vorderwuerze: false},
-						{Name: "Huell Melon", Alpha: 7.2, Amount: 10, Duration: 0, DryHop: false, Vorderwuerze: false},
+						{Name: "Huell Melon", Alpha: 7.2, Amount: 10, Duration: 0, DryHop: false, Vorderwuerze: false, Whirlpool: true, StandTime: 15},
 						{Name: "Huell Melon", Amount: 35, Duration: 0, DryHop: true, Vorderwuerze: false},
 					},
 					AdditionalIngredients: nil,
@@ -178,3 +178,35 @@ func TestBJsonRecipeToRecipe(t *testing.T) {
 		})
 	}
 }
+
+func TestGetMashInstructionsDecoction(t *testing.T) {
+	require := require.New(t)
+	bytes, err := os.ReadFile("../../../test/recipe/braureka_json/Dunkel_Dekoktion_min.json")
+	require.NoError(err)
+	var r BraurekaJSONRecipe
+	err = json.Unmarshal(bytes, &r)
+	require.NoError(err)
+	actual, err := getMashInstructions(&r)
+	require.NoError(err)
+	expected := recipe.MashInstructions{
+		Malts: []recipe.Malt{
+			{Name: "Münchner Malz", Amount: 4000},
+			{Name: "Pilsner", Amount: 800},
+		},
+		MainWaterVolume:    17,
+		Nachguss:           12,
+		MashTemperature:    52,
+		MashOutTemperature: 78,
+		Rasts: []recipe.Rast{
+			{Temperature: 52, Duration: 1},
+			{Temperature: 64, Duration: 1},
+			{Temperature: 72, Duration: 1},
+		},
+		MashType: recipe.MashTypeDecoction,
+		Decoctions: []recipe.Decoction{
+			{Rast: 1, Volume: 7.5, RestTemperature: 72, RestDuration: 1, BoilDuration: 1},
+			{Rast: 2, BoilDuration: 1},
+		},
+	}
+	require.Equal(expected, *actual)
+}
@@ -0,0 +1,40 @@
+package recipe
+
+// Clone returns a copy of the definition of the recipe (name, style, ingredients and instructions)
+// The copy has no identifier, status or results, so it can be stored as a new brew
+// Lists are copied, so modifying the copy does not change the original recipe
+func (r *Recipe) Clone() *Recipe {
+	c := &Recipe{
+		Name:         r.Name,
+		Style:        r.Style,
+		BatchSize:    r.BatchSize,
+		InitialSG:    r.InitialSG,
+		Bitterness:   r.Bitterness,
+		ColorEBC:     r.ColorEBC,
+		Mashing:      r.Mashing,
+		Hopping:      r.Hopping,
+		Fermentation: r.Fermentation,
+	}
+	c.Mashing.Malts = append([]Malt(nil), r.Mashing.Malts...)
+	c.Mashing.Rasts = append([]Rast(nil), r.Mashing.Rasts...)
+	c.Mashing.Decoctions = append([]Decoction(nil), r.Mashing.Decoctions...)
+	c.Hopping.Hops = append([]Hops(nil), r.Hopping.Hops...)
+	c.Hopping.AdditionalIngredients = append([]AdditionalIngredient(nil), r.Hopping.AdditionalIngredients...)
+	c.Fermentation.AdditionalIngredients = append([]AdditionalIngredient(nil), r.Fermentation.AdditionalIngredients...)
+	c.Fermentation.Schedule = append([]FermentationStep(nil), r.Fermentation.Schedule...)
+	return c
+}
+
+// WithDefinition returns a copy of the definition (name, style, ingredients and instructions) of another recipe, with the
+// identifier, status and results of this recipe
+// Stores replace the recipe with the copy, so the recipe is never changed while it is being read
+func (r *Recipe) WithDefinition(d *Recipe) *Recipe {
+	c := d.Clone()
+	c.ID = r.ID
+	status, params := r.GetStatus()
+	c.SetStatus(status, params...)
+	c.results = r.GetResults()
+	c.mainFermSGs = append([]*SGMeasurement(nil), r.GetSGMeasurements()...)
+	c.primingSugarResults = append([]*PrimingSugarResult(nil), r.GetPrimingSugarResults()...)
+	return c
+}
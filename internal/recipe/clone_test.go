@@ -0,0 +1,67 @@
+package recipe
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClone(t *testing.T) {
+	require := require.New(t)
+	original := validRecipe()
+	original.ID = "1"
+	original.Fermentation.AdditionalIngredients = []AdditionalIngredient{{Name: "Vanilla", Amount: 5, Duration: 7}}
+	original.Fermentation.Schedule = []FermentationStep{{Name: "Primary", Temperature: 10, Days: 5}}
+	original.SetStatus(RecipeStatusBoiling, "2")
+	original.InitResults()
+	original.SetOriginalGravity(1.052)
+
+	clone := original.Clone()
+	require.Empty(clone.ID)
+	status, params := clone.GetStatus()
+	require.Equal(RecipeStatusUnknown, status)
+	require.Empty(params)
+	require.Zero(clone.GetResults().OriginalGravity)
+	require.Equal(original.Name, clone.Name)
+	require.Equal(original.Mashing, clone.Mashing)
+	require.Equal(original.Hopping, clone.Hopping)
+	require.Equal(original.Fermentation, clone.Fermentation)
+
+	// Modifying the clone does not change the original
+	clone.Mashing.Malts[0].Amount = 1000
+	clone.Hopping.Hops[0].Name = "Perle"
+	clone.Fermentation.AdditionalIngredients[0].Amount = 10
+	clone.Fermentation.Schedule[0].Temperature = 12
+	require.Equal(float32(4000), original.Mashing.Malts[0].Amount)
+	require.Equal("Magnum", original.Hopping.Hops[0].Name)
+	require.Equal(float32(5), original.Fermentation.AdditionalIngredients[0].Amount)
+	require.Equal(float32(10), original.Fermentation.Schedule[0].Temperature)
+}
+
+func TestWithDefinition(t *testing.T) {
+	require := require.New(t)
+	stored := validRecipe()
+	stored.ID = "1"
+	stored.SetStatus(RecipeStatusFermenting, "main")
+	stored.InitResults()
+	stored.SetOriginalGravity(1.052)
+	stored.SetSGMeasurement(&SGMeasurement{Value: 1.030})
+	definition := validRecipe()
+	definition.Name = "Edited"
+	definition.Hopping.Hops[0].Amount = 30
+
+	edited := stored.WithDefinition(definition)
+	require.Equal("1", edited.ID)
+	require.Equal("Edited", edited.Name)
+	require.Equal(definition.Hopping, edited.Hopping)
+	status, params := edited.GetStatus()
+	require.Equal(RecipeStatusFermenting, status)
+	require.Equal([]string{"main"}, params)
+	require.Equal(stored.GetResults(), edited.GetResults())
+	require.Equal(stored.GetSGMeasurements(), edited.GetSGMeasurements())
+
+	// Neither the stored recipe nor the definition are changed
+	require.NotEqual("Edited", stored.Name)
+	edited.Hopping.Hops[0].Amount = 40
+	require.Equal(float32(30), definition.Hopping.Hops[0].Amount)
+}
@@ -0,0 +1,183 @@
+package recipe
+
+import (
+	"brewday/internal/tools"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// sgDateFormats are the formats of the date of a measurement: measurements entered by hand have the day,
+// readings of a wireless hydrometer the day and time
+var sgDateFormats = []string{"2006-01-02 15:04", "2006-01-02"}
+
+// Time returns the date of the measurement
+func (m *SGMeasurement) Time() (time.Time, error) {
+	for _, layout := range sgDateFormats {
+		t, err := time.ParseInLocation(layout, m.Date, time.Local)
+		if err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid measurement date %s", m.Date)
+}
+
+// CompletionCriteria are the conditions for the gravity to be stable at the end of the main fermentation
+type CompletionCriteria struct {
+	// Readings is the minimum number of readings that are compared
+	Readings int
+	// Days is how long the gravity has to be stable. All the readings taken in this time are compared
+	Days float32
+	// Tolerance is the maximum difference between the compared gravities
+	Tolerance float32
+	// MaxSlope is the maximum change of the gravity per day, from a least squares regression of the compared readings
+	// The fermentation is also complete when the slope is below it, which suits noisy readings of wireless hydrometers
+	// Zero disables the check
+	MaxSlope float32
+	// MinAttenuation is the minimum apparent attenuation in % the gravity must reach, so that a fermentation that has not
+	// started yet is not reported as complete. Zero disables the check
+	MinAttenuation float32
+}
+
+// FermentationCompletion is the result of checking whether the main fermentation is complete
+type FermentationCompletion struct {
+	// Complete is whether the gravity is stable
+	Complete bool
+	// FinalGravity is the proposed final gravity, the average of the compared readings. Only set when the fermentation is complete
+	FinalGravity float32
+	// Readings is the number of compared readings
+	Readings int
+	// Days is the time covered by the compared readings
+	Days float32
+	// Spread is the difference between the highest and the lowest compared gravity
+	Spread float32
+	// Slope is the change of the gravity per day
+	Slope float32
+	// Attenuation is the apparent attenuation of the compared readings in %
+	Attenuation float32
+	// Reasons explain the result to the brewer
+	Reasons []string
+}
+
+// timedMeasurement is a measurement with its parsed date
+type timedMeasurement struct {
+	date  time.Time
+	value float32
+}
+
+// sgSeries returns the measurements with a valid date and gravity, sorted by date
+func sgSeries(measurements []*SGMeasurement) []timedMeasurement {
+	series := make([]timedMeasurement, 0, len(measurements))
+	for _, m := range measurements {
+		t, err := m.Time()
+		if err != nil || m.Value <= 0 {
+			continue
+		}
+		series = append(series, timedMeasurement{date: t, value: m.Value})
+	}
+	sort.SliceStable(series, func(i, j int) bool {
+		return series[i].date.Before(series[j].date)
+	})
+	return series
+}
+
+// DetectFermentationCompletion checks whether the gravity of the main fermentation is stable
+// The last readings (at least criteria.Readings, and all those taken in the last criteria.Days) are compared: the fermentation
+// is complete when they cover criteria.Days and their gravities are within the tolerance or their slope is below the maximum
+// The gravity must also have dropped by criteria.MinAttenuation from the original gravity, or from the first reading if it is not known
+// Measurements with an invalid date are ignored
+func DetectFermentationCompletion(measurements []*SGMeasurement, originalGravity float32, criteria CompletionCriteria) *FermentationCompletion {
+	series := sgSeries(measurements)
+	needed := max(criteria.Readings, 2)
+	c := &FermentationCompletion{}
+	if len(series) < needed {
+		c.Readings = len(series)
+		c.Reasons = append(c.Reasons, fmt.Sprintf("There are %d readings, at least %d are needed to tell whether the gravity is stable", len(series), needed))
+		return c
+	}
+	last := series[len(series)-1].date
+	windowStart := last.Add(-time.Duration(float64(criteria.Days) * float64(24*time.Hour)))
+	first := len(series) - needed
+	for first > 0 && !series[first-1].date.Before(windowStart) {
+		first--
+	}
+	window := series[first:]
+	c.Readings = len(window)
+	c.Days = float32(last.Sub(window[0].date).Hours() / 24)
+	low, high := window[0].value, window[0].value
+	var sum float32
+	for _, m := range window {
+		low = min(low, m.value)
+		high = max(high, m.value)
+		sum += m.value
+	}
+	// Rounded so that float errors do not push a spread of exactly the tolerance over it
+	c.Spread = tools.RoundTo(high-low, 4)
+	c.Slope = tools.RoundTo(slopePerDay(window), 5)
+	c.Reasons = append(c.Reasons, fmt.Sprintf("Compared %d readings from %s to %s (%.1f days)",
+		c.Readings, window[0].date.Format(sgDateFormats[0]), last.Format(sgDateFormats[0]), c.Days))
+	covered := c.Days >= criteria.Days
+	if !covered {
+		c.Reasons = append(c.Reasons, fmt.Sprintf("The readings cover %.1f days, the gravity has to be stable for %.1f days", c.Days, criteria.Days))
+	}
+	stable := c.Spread <= criteria.Tolerance
+	if stable {
+		c.Reasons = append(c.Reasons, fmt.Sprintf("The gravity changed %.4f, within the tolerance of %.4f", c.Spread, criteria.Tolerance))
+	} else {
+		c.Reasons = append(c.Reasons, fmt.Sprintf("The gravity changed %.4f, more than the tolerance of %.4f", c.Spread, criteria.Tolerance))
+	}
+	if criteria.MaxSlope > 0 {
+		flat := float32(math.Abs(float64(c.Slope))) <= criteria.MaxSlope
+		if flat {
+			c.Reasons = append(c.Reasons, fmt.Sprintf("The gravity changes %.4f per day, below the limit of %.4f", c.Slope, criteria.MaxSlope))
+		} else {
+			c.Reasons = append(c.Reasons, fmt.Sprintf("The gravity changes %.4f per day, more than the limit of %.4f", c.Slope, criteria.MaxSlope))
+		}
+		stable = stable || flat
+	}
+	average := sum / float32(len(window))
+	attenuated := true
+	if criteria.MinAttenuation > 0 {
+		reference := "original gravity"
+		if originalGravity <= 1 {
+			originalGravity = series[0].value
+			reference = "first reading"
+		}
+		c.Attenuation = tools.RoundTo(tools.ApparentAttenuation(originalGravity, average), 1)
+		attenuated = c.Attenuation >= criteria.MinAttenuation
+		if attenuated {
+			c.Reasons = append(c.Reasons, fmt.Sprintf("The apparent attenuation from the %s %.3f is %.1f%%, at least %.1f%%", reference, originalGravity, c.Attenuation, criteria.MinAttenuation))
+		} else {
+			c.Reasons = append(c.Reasons, fmt.Sprintf("The apparent attenuation from the %s %.3f is %.1f%%, the fermentation has to reach %.1f%%", reference, originalGravity, c.Attenuation, criteria.MinAttenuation))
+		}
+	}
+	c.Complete = covered && stable && attenuated
+	if c.Complete {
+		c.FinalGravity = tools.RoundTo(average, 3)
+		c.Reasons = append(c.Reasons, fmt.Sprintf("The fermentation is complete, the final gravity is %.3f", c.FinalGravity))
+	}
+	return c
+}
+
+// slopePerDay returns the change of the gravity per day from a least squares regression of the measurements
+func slopePerDay(series []timedMeasurement) float32 {
+	n := float64(len(series))
+	var meanX, meanY float64
+	for _, m := range series {
+		meanX += m.date.Sub(series[0].date).Hours() / 24
+		meanY += float64(m.value)
+	}
+	meanX /= n
+	meanY /= n
+	var cov, varX float64
+	for _, m := range series {
+		dx := m.date.Sub(series[0].date).Hours()/24 - meanX
+		cov += dx * (float64(m.value) - meanY)
+		varX += dx * dx
+	}
+	if varX == 0 {
+		return 0
+	}
+	return float32(cov / varX)
+}
@@ -0,0 +1,157 @@
+package recipe
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSGMeasurementTime(t *testing.T) {
+	require := require.New(t)
+	day, err := (&SGMeasurement{Date: "2026-10-17"}).Time()
+	require.NoError(err)
+	withTime, err := (&SGMeasurement{Date: "2026-10-17 06:30"}).Time()
+	require.NoError(err)
+	require.Equal(6.5, withTime.Sub(day).Hours())
+	_, err = (&SGMeasurement{Date: "17.10.2026"}).Time()
+	require.Error(err)
+}
+
+func TestDetectFermentationCompletion(t *testing.T) {
+	criteria := CompletionCriteria{Readings: 3, Days: 2, Tolerance: 0.001, MaxSlope: 0.0002, MinAttenuation: 50}
+	type testCase struct {
+		Name            string
+		Measurements    []*SGMeasurement
+		OriginalGravity float32
+		Criteria        CompletionCriteria
+		Complete        bool
+		FinalGravity    float32
+		Readings        int
+	}
+	testCases := []testCase{
+		{
+			Name:         "No measurements",
+			Measurements: nil,
+			Criteria:     criteria,
+			Readings:     0,
+		},
+		{
+			Name: "Too few readings",
+			Measurements: []*SGMeasurement{
+				{Value: 1.012, Date: "2026-10-01"},
+				{Value: 1.012, Date: "2026-10-05"},
+			},
+			Criteria: criteria,
+			Readings: 2,
+		},
+		{
+			Name: "Stable by hand",
+			Measurements: []*SGMeasurement{
+				{Value: 1.020, Date: "2026-10-01"},
+				{Value: 1.012, Date: "2026-10-03"},
+				{Value: 1.011, Date: "2026-10-04"},
+				{Value: 1.011, Date: "2026-10-05"},
+			},
+			OriginalGravity: 1.048,
+			Criteria:        criteria,
+			Complete:        true,
+			FinalGravity:    1.011,
+			Readings:        3,
+		},
+		{
+			Name: "Still dropping",
+			Measurements: []*SGMeasurement{
+				{Value: 1.020, Date: "2026-10-01"},
+				{Value: 1.015, Date: "2026-10-02"},
+				{Value: 1.012, Date: "2026-10-03"},
+			},
+			Criteria: criteria,
+			Readings: 3,
+		},
+		{
+			Name: "Readings in a short time are not enough",
+			Measurements: []*SGMeasurement{
+				{Value: 1.011, Date: "2026-10-05 08:00"},
+				{Value: 1.011, Date: "2026-10-05 08:15"},
+				{Value: 1.011, Date: "2026-10-05 08:30"},
+			},
+			Criteria: criteria,
+			Readings: 3,
+		},
+		{
+			Name: "Noisy hydrometer with a flat slope, unordered and invalid dates ignored",
+			Measurements: []*SGMeasurement{
+				{Value: 1.0100, Date: "2026-10-05 00:00"},
+				{Value: 1.0130, Date: "2026-10-03 00:00"},
+				{Value: 1.0110, Date: "2026-10-03 12:00"},
+				{Value: 1.0100, Date: "2026-10-04 00:00"},
+				{Value: 1.0125, Date: "2026-10-04 12:00"},
+				{Value: 1.0110, Date: "yesterday"},
+				{Value: 1.0110, Date: "2026-10-05 12:00"},
+				{Value: 1.0500, Date: "2026-09-28 12:00"},
+			},
+			OriginalGravity: 1.048,
+			Criteria:        criteria,
+			Complete:        true,
+			FinalGravity:    1.011,
+			Readings:        5,
+		},
+		{
+			Name: "Noisy hydrometer without slope check",
+			Measurements: []*SGMeasurement{
+				{Value: 1.0130, Date: "2026-10-03 00:00"},
+				{Value: 1.0110, Date: "2026-10-03 12:00"},
+				{Value: 1.0100, Date: "2026-10-04 00:00"},
+				{Value: 1.0125, Date: "2026-10-04 12:00"},
+				{Value: 1.0100, Date: "2026-10-05 00:00"},
+				{Value: 1.0110, Date: "2026-10-05 12:00"},
+			},
+			Criteria: CompletionCriteria{Readings: 3, Days: 2, Tolerance: 0.001},
+			Readings: 5,
+		},
+		{
+			Name: "Stable before the fermentation started",
+			Measurements: []*SGMeasurement{
+				{Value: 1.047, Date: "2026-10-01"},
+				{Value: 1.047, Date: "2026-10-02"},
+				{Value: 1.047, Date: "2026-10-03"},
+			},
+			OriginalGravity: 1.048,
+			Criteria:        criteria,
+			Readings:        3,
+		},
+		{
+			Name: "Drop from the first reading without original gravity",
+			Measurements: []*SGMeasurement{
+				{Value: 1.050, Date: "2026-09-28"},
+				{Value: 1.012, Date: "2026-10-03"},
+				{Value: 1.012, Date: "2026-10-04"},
+				{Value: 1.012, Date: "2026-10-05"},
+			},
+			Criteria:     criteria,
+			Complete:     true,
+			FinalGravity: 1.012,
+			Readings:     3,
+		},
+		{
+			Name: "No drop from the first reading without original gravity",
+			Measurements: []*SGMeasurement{
+				{Value: 1.050, Date: "2026-10-01"},
+				{Value: 1.050, Date: "2026-10-02"},
+				{Value: 1.050, Date: "2026-10-03"},
+			},
+			Criteria: criteria,
+			Readings: 3,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			require := require.New(t)
+			actual := DetectFermentationCompletion(tc.Measurements, tc.OriginalGravity, tc.Criteria)
+			require.Equal(tc.Complete, actual.Complete, actual.Reasons)
+			require.Equal(tc.FinalGravity, actual.FinalGravity)
+			require.Equal(tc.Readings, actual.Readings)
+			require.NotEmpty(actual.Reasons)
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package recipe
+
+import (
+	"brewday/internal/tools"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// IBUMethod is the formula used to estimate the bitterness of a recipe
+type IBUMethod string
+
+const (
+	// IBUTinseth estimates the bitterness using Tinseth's formula
+	IBUTinseth IBUMethod = "tinseth"
+	// IBURager estimates the bitterness using Rager's formula
+	IBURager IBUMethod = "rager"
+)
+
+// DeviationThreshold is the difference (in %) between a given and an estimated value from which it is considered significant
+const DeviationThreshold float32 = 10
+
+// Estimation contains the values of a recipe calculated from its ingredients
+type Estimation struct {
+	// InitialSG is the expected initial specific gravity (in SG)
+	InitialSG float32
+	// Bitterness is the expected bitterness in IBU
+	Bitterness float32
+	// ColorEBC is the expected color in EBC. It is zero if the color of the malts is unknown
+	ColorEBC float32
+}
+
+// Deviation is the difference between a value given in a recipe and the one calculated from its ingredients
+type Deviation struct {
+	// Field is the name of the field in the recipe (e.g. InitialSG)
+	Field string
+	// Given is the value in the recipe
+	Given float32
+	// Estimated is the calculated value
+	Estimated float32
+	// Percentage is the relative difference of the estimated value to the given one in %
+	// For gravities, it is calculated on the gravity points (e.g. 50 for 1.050)
+	Percentage float32
+}
+
+// Significant returns whether the deviation is bigger than the DeviationThreshold
+func (d Deviation) Significant() bool {
+	return float32(math.Abs(float64(d.Percentage))) > DeviationThreshold
+}
+
+// Estimate calculates the initial gravity, bitterness and color of the recipe from its ingredients
+// - The gravity is calculated from the malt bill, correcting the brewhouse efficiency (in %) with the extract potential of each malt
+// - The bitterness is calculated from the hops with the given method, using the gravity of the recipe (or the estimated one if it is not set)
+// - The color is calculated with Morey's formula, using the color of each malt or a typical one based on its name
+func (r *Recipe) Estimate(efficiency float32, method IBUMethod) (*Estimation, error) {
+	if efficiency <= 0 || efficiency > 100 {
+		return nil, errors.New("invalid efficiency for estimation, it must be between 0 and 100")
+	}
+	var ibuFormula func(alpha, grams, minutes, gravity, volume float32) float32
+	switch method {
+	case IBUTinseth:
+		ibuFormula = tools.TinsethIBU
+	case IBURager:
+		ibuFormula = tools.RagerIBU
+	default:
+		return nil, fmt.Errorf("unknown bitterness method %s", method)
+	}
+	var extractMalt, mcu float32
+	colorKnown := false
+	for _, m := range r.Mashing.Malts {
+		properties, known := tools.LookupMalt(m.Name)
+		extractMalt += m.Amount * properties.Potential / tools.ReferenceMaltPotential
+		color := m.Color
+		if color == 0 && known {
+			color = properties.Color
+		}
+		if m.Color > 0 || known {
+			colorKnown = true
+		}
+		mcu += tools.MaltColorUnits(m.Amount, color, r.BatchSize)
+	}
+	og := tools.EstimateOriginalGravity(extractMalt, r.BatchSize, efficiency)
+	gravity := r.InitialSG
+	if gravity <= 1 {
+		gravity = og
+	}
+	var ibu float32
+	for _, h := range r.Hopping.Hops {
+		if h.DryHop || h.Whirlpool {
+			continue
+		}
+		duration := h.Duration
+		if h.Vorderwuerze && duration == 0 {
+			duration = r.Hopping.TotalCookingTime
+		}
+		ibu += ibuFormula(h.Alpha, h.Amount, duration, gravity, r.BatchSize)
+	}
+	// Whirlpool hops isomerize slower below boiling, they count as a shorter boil
+	for _, h := range r.Hopping.WhirlpoolHops() {
+		ibu += ibuFormula(h.Alpha, h.Amount, tools.HopStandMinutes(h.StandTime, h.StandTemperature), gravity, r.BatchSize)
+	}
+	e := &Estimation{
+		InitialSG:  tools.RoundTo(og, 3),
+		Bitterness: tools.RoundTo(ibu, 1),
+	}
+	if colorKnown {
+		e.ColorEBC = tools.RoundTo(tools.MoreyEBC(mcu), 1)
+	}
+	return e, nil
+}
+
+// Deviations compares the estimated values with the ones given in the recipe
+// Values that are not set in the recipe or could not be estimated are not compared
+func (e *Estimation) Deviations(r *Recipe) []Deviation {
+	var deviations []Deviation
+	if r.InitialSG > 1 && e.InitialSG > 1 {
+		deviations = append(deviations, newDeviation("InitialSG", r.InitialSG, e.InitialSG, (r.InitialSG-1)*1000, (e.InitialSG-1)*1000))
+	}
+	if r.Bitterness > 0 && e.Bitterness > 0 {
+		deviations = append(deviations, newDeviation("Bitterness", r.Bitterness, e.Bitterness, r.Bitterness, e.Bitterness))
+	}
+	if r.ColorEBC > 0 && e.ColorEBC > 0 {
+		deviations = append(deviations, newDeviation("ColorEBC", r.ColorEBC, e.ColorEBC, r.ColorEBC, e.ColorEBC))
+	}
+	return deviations
+}
+
+// newDeviation creates a deviation, calculating the percentage on the given base values
+func newDeviation(field string, given, estimated, givenBase, estimatedBase float32) Deviation {
+	return Deviation{
+		Field:      field,
+		Given:      given,
+		Estimated:  estimated,
+		Percentage: tools.RoundTo((estimatedBase-givenBase)*100/givenBase, 1),
+	}
+}
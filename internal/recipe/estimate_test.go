@@ -0,0 +1,150 @@
+package recipe
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEstimate(t *testing.T) {
+	require := require.New(t)
+	type testCase struct {
+		Name       string
+		Modify     func(r *Recipe)
+		Efficiency float32
+		Method     IBUMethod
+		Expected   *Estimation
+		Error      bool
+	}
+	testCases := []testCase{
+		{
+			Name:       "Tinseth",
+			Modify:     func(r *Recipe) {},
+			Efficiency: 65,
+			Method:     IBUTinseth,
+			Expected:   &Estimation{InitialSG: 1.053, Bitterness: 27.7, ColorEBC: 6.4},
+		},
+		{
+			Name:       "Rager",
+			Modify:     func(r *Recipe) {},
+			Efficiency: 65,
+			Method:     IBURager,
+			Expected:   &Estimation{InitialSG: 1.053, Bitterness: 37, ColorEBC: 6.4},
+		},
+		{
+			Name:       "Higher efficiency",
+			Modify:     func(r *Recipe) {},
+			Efficiency: 75,
+			Method:     IBUTinseth,
+			Expected:   &Estimation{InitialSG: 1.061, Bitterness: 27.7, ColorEBC: 6.4},
+		},
+		{
+			Name: "Malt color overrides the typical one",
+			Modify: func(r *Recipe) {
+				r.Mashing.Malts = append(r.Mashing.Malts, Malt{Name: "Crystal 60", Amount: 300, Color: 160})
+			},
+			Efficiency: 65,
+			Method:     IBUTinseth,
+			Expected:   &Estimation{InitialSG: 1.056, Bitterness: 27.7, ColorEBC: 14.9},
+		},
+		{
+			Name: "Unknown malt without color",
+			Modify: func(r *Recipe) {
+				r.Mashing.Malts = []Malt{{Name: "Mystery", Amount: 4000}}
+			},
+			Efficiency: 65,
+			Method:     IBUTinseth,
+			Expected:   &Estimation{InitialSG: 1.052, Bitterness: 27.7, ColorEBC: 0},
+		},
+		{
+			Name: "Vorderwuerze without duration boils the whole time",
+			Modify: func(r *Recipe) {
+				r.Hopping.Hops[0].Duration = 0
+				r.Hopping.Hops[0].Vorderwuerze = true
+			},
+			Efficiency: 65,
+			Method:     IBUTinseth,
+			Expected:   &Estimation{InitialSG: 1.053, Bitterness: 27.7, ColorEBC: 6.4},
+		},
+		{
+			Name: "Whirlpool hop counts as a shorter boil",
+			Modify: func(r *Recipe) {
+				r.Hopping.Hops = append(r.Hopping.Hops, Hops{Name: "Citra", Alpha: 12, Amount: 50, Whirlpool: true, StandTemperature: 80, StandTime: 30})
+			},
+			Efficiency: 65,
+			Method:     IBUTinseth,
+			Expected:   &Estimation{InitialSG: 1.053, Bitterness: 41.4, ColorEBC: 6.4},
+		},
+		{Name: "Invalid efficiency", Modify: func(r *Recipe) {}, Efficiency: 0, Method: IBUTinseth, Error: true},
+		{Name: "Invalid method", Modify: func(r *Recipe) {}, Efficiency: 65, Method: "garetz", Error: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			r := validRecipe()
+			tc.Modify(r)
+			actual, err := r.Estimate(tc.Efficiency, tc.Method)
+			if tc.Error {
+				require.Error(err)
+				return
+			}
+			require.NoError(err)
+			require.Equal(tc.Expected, actual)
+		})
+	}
+}
+
+func TestDeviations(t *testing.T) {
+	require := require.New(t)
+	type testCase struct {
+		Name        string
+		Modify      func(r *Recipe)
+		Estimation  *Estimation
+		Expected    []Deviation
+		Significant []bool
+	}
+	testCases := []testCase{
+		{
+			Name:       "All values",
+			Modify:     func(r *Recipe) {},
+			Estimation: &Estimation{InitialSG: 1.053, Bitterness: 27.7, ColorEBC: 6.4},
+			Expected: []Deviation{
+				{Field: "InitialSG", Given: 1.05, Estimated: 1.053, Percentage: 6},
+				{Field: "Bitterness", Given: 30, Estimated: 27.7, Percentage: -7.7},
+				{Field: "ColorEBC", Given: 12, Estimated: 6.4, Percentage: -46.7},
+			},
+			Significant: []bool{false, false, true},
+		},
+		{
+			Name:       "Unknown color is not compared",
+			Modify:     func(r *Recipe) {},
+			Estimation: &Estimation{InitialSG: 1.061, Bitterness: 27.7},
+			Expected: []Deviation{
+				{Field: "InitialSG", Given: 1.05, Estimated: 1.061, Percentage: 22},
+				{Field: "Bitterness", Given: 30, Estimated: 27.7, Percentage: -7.7},
+			},
+			Significant: []bool{true, false},
+		},
+		{
+			Name: "Values not set in the recipe are not compared",
+			Modify: func(r *Recipe) {
+				r.InitialSG = 0
+				r.Bitterness = 0
+				r.ColorEBC = 0
+			},
+			Estimation:  &Estimation{InitialSG: 1.053, Bitterness: 27.7, ColorEBC: 6.4},
+			Expected:    nil,
+			Significant: nil,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			r := validRecipe()
+			tc.Modify(r)
+			actual := tc.Estimation.Deviations(r)
+			require.Equal(tc.Expected, actual)
+			for i, d := range actual {
+				require.Equal(tc.Significant[i], d.Significant())
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package recipe
+
+import (
+	"math"
+	"time"
+)
+
+// DefaultApparentAttenuation is the apparent attenuation in percent expected when the yeast does not tell it
+const DefaultApparentAttenuation float32 = 75
+
+const (
+	// forecastStep is the resolution of the asymptote searched when fitting the decay curve
+	forecastStep = 0.0001
+	// forecastRange is how far below the lowest reading the asymptote is searched
+	forecastRange = 0.03
+	// forecastMaxDays is how far after the last reading the curve is followed to reach the target
+	forecastMaxDays = 60
+)
+
+// FermentationForecast is a decay curve fitted to the gravity readings of the main fermentation:
+// the gravity drops exponentially from the first reading towards an asymptote
+type FermentationForecast struct {
+	// Fitted is whether a decaying curve could be fitted, it needs 3 readings on different dates
+	Fitted bool
+	// Start is the date of the first reading, the origin of the curve
+	Start time.Time
+	// Asymptote is the gravity the curve tends to
+	Asymptote float32
+	// Amplitude is the gravity above the asymptote at the start
+	Amplitude float32
+	// Rate is the decay rate per day
+	Rate float32
+	// Target is the expected final gravity
+	Target float32
+	// Reached is whether the last reading is at or below the target
+	Reached bool
+	// TargetDate is when the curve reaches the target. It is zero if the curve stays above it (the fermentation may stall)
+	TargetDate time.Time
+}
+
+// Gravity returns the gravity of the fitted curve at the given date
+func (f *FermentationForecast) Gravity(date time.Time) float32 {
+	days := date.Sub(f.Start).Hours() / 24
+	return f.Asymptote + f.Amplitude*float32(math.Exp(-float64(f.Rate)*days))
+}
+
+// ForecastFermentation fits a decay curve to the gravity readings and forecasts when they reach the target gravity
+// For each asymptote below the lowest reading, the logarithm of the gravity above it is fitted by least squares,
+// and the asymptote whose curve is closest to the readings is kept
+func ForecastFermentation(measurements []*SGMeasurement, target float32) *FermentationForecast {
+	series := sgSeries(measurements)
+	f := &FermentationForecast{Target: target}
+	if len(series) == 0 {
+		return f
+	}
+	f.Start = series[0].date
+	last := series[len(series)-1]
+	f.Reached = last.value <= target
+	if len(series) < 3 || !last.date.After(f.Start) {
+		return f
+	}
+	lowest := series[0].value
+	for _, m := range series {
+		lowest = min(lowest, m.value)
+	}
+	days := make([]float64, len(series))
+	for i, m := range series {
+		days[i] = m.date.Sub(f.Start).Hours() / 24
+	}
+	bestError := math.Inf(1)
+	for step := 1; float64(step)*forecastStep <= forecastRange; step++ {
+		asymptote := float64(lowest) - float64(step)*forecastStep
+		amplitude, rate := fitDecay(series, days, asymptote)
+		if rate <= 0 {
+			continue
+		}
+		var sse float64
+		for i, m := range series {
+			d := asymptote + amplitude*math.Exp(-rate*days[i]) - float64(m.value)
+			sse += d * d
+		}
+		if sse < bestError {
+			bestError = sse
+			f.Fitted = true
+			f.Asymptote = float32(asymptote)
+			f.Amplitude = float32(amplitude)
+			f.Rate = float32(rate)
+		}
+	}
+	if !f.Fitted || target <= f.Asymptote {
+		return f
+	}
+	// asymptote + amplitude * e^(-rate * t) = target
+	targetDays := math.Log(float64(f.Amplitude)/float64(target-f.Asymptote)) / float64(f.Rate)
+	if targetDays > days[len(days)-1]+forecastMaxDays {
+		return f
+	}
+	f.TargetDate = f.Start.Add(time.Duration(max(targetDays, 0) * float64(24*time.Hour)))
+	return f
+}
+
+// fitDecay fits ln(gravity - asymptote) = ln(amplitude) - rate * days by least squares
+func fitDecay(series []timedMeasurement, days []float64, asymptote float64) (amplitude, rate float64) {
+	n := float64(len(series))
+	var meanX, meanY float64
+	ys := make([]float64, len(series))
+	for i, m := range series {
+		ys[i] = math.Log(float64(m.value) - asymptote)
+		meanX += days[i]
+		meanY += ys[i]
+	}
+	meanX /= n
+	meanY /= n
+	var cov, varX float64
+	for i := range series {
+		dx := days[i] - meanX
+		cov += dx * (ys[i] - meanY)
+		varX += dx * dx
+	}
+	if varX == 0 {
+		return 0, 0
+	}
+	slope := cov / varX
+	return math.Exp(meanY - slope*meanX), -slope
+}
@@ -0,0 +1,93 @@
+package recipe
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestForecastFermentation(t *testing.T) {
+	start := time.Date(2026, 10, 1, 0, 0, 0, 0, time.Local)
+	// decay returns readings every 12 hours of a gravity dropping from 1.050 towards 1.010 at a rate of 0.5 per day
+	decay := func(readings int) []*SGMeasurement {
+		measurements := make([]*SGMeasurement, 0, readings)
+		for i := range readings {
+			days := float64(i) / 2
+			measurements = append(measurements, &SGMeasurement{
+				Value: float32(1.010 + 0.040*math.Exp(-0.5*days)),
+				Date:  start.Add(time.Duration(days * float64(24*time.Hour))).Format("2006-01-02 15:04"),
+			})
+		}
+		return measurements
+	}
+	type testCase struct {
+		Name       string
+		Readings   []*SGMeasurement
+		Target     float32
+		Fitted     bool
+		Reached    bool
+		TargetDays float64
+	}
+	testCases := []testCase{
+		{
+			Name:     "No readings",
+			Readings: nil,
+			Target:   1.012,
+		},
+		{
+			Name:     "Too few readings",
+			Readings: decay(2),
+			Target:   1.012,
+		},
+		{
+			Name:     "Readings on the same date",
+			Readings: []*SGMeasurement{{Value: 1.050, Date: "2026-10-01"}, {Value: 1.040, Date: "2026-10-01"}, {Value: 1.030, Date: "2026-10-01"}},
+			Target:   1.012,
+		},
+		{
+			Name:       "Decaying gravity",
+			Readings:   decay(9),
+			Target:     1.012,
+			Fitted:     true,
+			TargetDays: math.Log(20) / 0.5,
+		},
+		{
+			Name:     "Target below the asymptote",
+			Readings: decay(9),
+			Target:   1.008,
+			Fitted:   true,
+		},
+		{
+			Name:       "Target reached",
+			Readings:   decay(15),
+			Target:     1.016,
+			Fitted:     true,
+			Reached:    true,
+			TargetDays: math.Log(40.0/6) / 0.5,
+		},
+		{
+			Name:     "Rising gravity",
+			Readings: []*SGMeasurement{{Value: 1.040, Date: "2026-10-01"}, {Value: 1.045, Date: "2026-10-02"}, {Value: 1.050, Date: "2026-10-03"}},
+			Target:   1.012,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			require := require.New(t)
+			actual := ForecastFermentation(tc.Readings, tc.Target)
+			require.Equal(tc.Fitted, actual.Fitted)
+			require.Equal(tc.Reached, actual.Reached)
+			require.Equal(tc.Target, actual.Target)
+			if tc.TargetDays == 0 {
+				require.True(actual.TargetDate.IsZero())
+				return
+			}
+			require.InDelta(1.010, actual.Asymptote, 0.0002)
+			require.InDelta(0.5, actual.Rate, 0.05)
+			require.InDelta(tc.TargetDays, actual.TargetDate.Sub(start).Hours()/24, 0.25)
+			require.InDelta(tc.Target, actual.Gravity(actual.TargetDate), 0.0001)
+		})
+	}
+}
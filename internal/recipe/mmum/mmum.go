@@ -62,7 +62,29 @@ type MMUMRecipe struct {
 	MashRast6Time       string  `json:"Infusion_Rastzeit6"`
 	MashRast7Temp       string  `json:"Infusion_Rasttemperatur7"`
 	MashRast7Time       string  `json:"Infusion_Rastzeit7"`
+	DecoctionMainWater  string  `json:"Dekoktion_0_Volumen"`
+	DecoctionMashTemp   string  `json:"Dekoktion_0_Temperatur_resultierend"`
+	DecoctionMashTime   string  `json:"Dekoktion_0_Rastzeit"`
+	Decoction1Volume    string  `json:"Dekoktion_1_Volumen"`
+	Decoction1RestTemp  string  `json:"Dekoktion_1_Teilmaische_Temperatur"`
+	Decoction1RestTime  string  `json:"Dekoktion_1_Teilmaische_Rastzeit"`
+	Decoction1BoilTime  string  `json:"Dekoktion_1_Teilmaische_Kochzeit"`
+	Decoction1Temp      string  `json:"Dekoktion_1_Temperatur_resultierend"`
+	Decoction1Time      string  `json:"Dekoktion_1_Rastzeit"`
+	Decoction2Volume    string  `json:"Dekoktion_2_Volumen"`
+	Decoction2RestTemp  string  `json:"Dekoktion_2_Teilmaische_Temperatur"`
+	Decoction2RestTime  string  `json:"Dekoktion_2_Teilmaische_Rastzeit"`
+	Decoction2BoilTime  string  `json:"Dekoktion_2_Teilmaische_Kochzeit"`
+	Decoction2Temp      string  `json:"Dekoktion_2_Temperatur_resultierend"`
+	Decoction2Time      string  `json:"Dekoktion_2_Rastzeit"`
+	Decoction3Volume    string  `json:"Dekoktion_3_Volumen"`
+	Decoction3RestTemp  string  `json:"Dekoktion_3_Teilmaische_Temperatur"`
+	Decoction3RestTime  string  `json:"Dekoktion_3_Teilmaische_Rastzeit"`
+	Decoction3BoilTime  string  `json:"Dekoktion_3_Teilmaische_Kochzeit"`
+	Decoction3Temp      string  `json:"Dekoktion_3_Temperatur_resultierend"`
+	Decoction3Time      string  `json:"Dekoktion_3_Rastzeit"`
 	CookingTime         float32 `json:"Kochzeit_Wuerze"`
+	HopStandTime        float32 `json:"Nachisomerisierungszeit"`
 	HopBefore1Name      string  `json:"Hopfen_VWH_1_Sorte"`
 	HopBefore1Amount    float32 `json:"Hopfen_VWH_1_Menge"`
 	HopBefore1Alpha     float32 `json:"Hopfen_VWH_1_alpha"`
@@ -75,31 +97,31 @@ type MMUMRecipe struct {
 	Hop1Name            string  `json:"Hopfen_1_Sorte"`
 	Hop1Amount          float32 `json:"Hopfen_1_Menge"`
 	Hop1Alpha           float32 `json:"Hopfen_1_alpha"`
-	Hop1Time            float32 `json:"Hopfen_1_Kochzeit"`
+	Hop1Time            hopTime `json:"Hopfen_1_Kochzeit"`
 	Hop2Name            string  `json:"Hopfen_2_Sorte"`
 	Hop2Amount          float32 `json:"Hopfen_2_Menge"`
 	Hop2Alpha           float32 `json:"Hopfen_2_alpha"`
-	Hop2Time            float32 `json:"Hopfen_2_Kochzeit"`
+	Hop2Time            hopTime `json:"Hopfen_2_Kochzeit"`
 	Hop3Name            string  `json:"Hopfen_3_Sorte"`
 	Hop3Amount          float32 `json:"Hopfen_3_Menge"`
 	Hop3Alpha           float32 `json:"Hopfen_3_alpha"`
-	Hop3Time            float32 `json:"Hopfen_3_Kochzeit"`
+	Hop3Time            hopTime `json:"Hopfen_3_Kochzeit"`
 	Hop4Name            string  `json:"Hopfen_4_Sorte"`
 	Hop4Amount          float32 `json:"Hopfen_4_Menge"`
 	Hop4Alpha           float32 `json:"Hopfen_4_alpha"`
-	Hop4Time            float32 `json:"Hopfen_4_Kochzeit"`
+	Hop4Time            hopTime `json:"Hopfen_4_Kochzeit"`
 	Hop5Name            string  `json:"Hopfen_5_Sorte"`
 	Hop5Amount          float32 `json:"Hopfen_5_Menge"`
 	Hop5Alpha           float32 `json:"Hopfen_5_alpha"`
-	Hop5Time            float32 `json:"Hopfen_5_Kochzeit"`
+	Hop5Time            hopTime `json:"Hopfen_5_Kochzeit"`
 	Hop6Name            string  `json:"Hopfen_6_Sorte"`
 	Hop6Amount          float32 `json:"Hopfen_6_Menge"`
 	Hop6Alpha           float32 `json:"Hopfen_6_alpha"`
-	Hop6Time            float32 `json:"Hopfen_6_Kochzeit"`
+	Hop6Time            hopTime `json:"Hopfen_6_Kochzeit"`
 	Hop7Name            string  `json:"Hopfen_7_Sorte"`
 	Hop7Amount          float32 `json:"Hopfen_7_Menge"`
 	Hop7Alpha           float32 `json:"Hopfen_7_alpha"`
-	Hop7Time            float32 `json:"Hopfen_7_Kochzeit"`
+	Hop7Time            hopTime `json:"Hopfen_7_Kochzeit"`
 	OtherSpice1Name     string  `json:"WeitereZutat_Wuerze_1_Name"`
 	OtherSpice1Amount   float32 `json:"WeitereZutat_Wuerze_1_Menge"`
 	OtherSpice1Unit     string  `json:"WeitereZutat_Wuerze_1_Einheit"`
@@ -141,6 +163,30 @@ type MMUMRecipe struct {
 	Notes               string  `json:"Anmerkung_Autor"`
 }
 
+// hopTime is the cooking time of a hop in minutes, or "Whirlpool" for hops added after the boil
+type hopTime struct {
+	Minutes   float32
+	Whirlpool bool
+}
+
+// UnmarshalJSON parses the cooking time from a number or a string
+func (t *hopTime) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return json.Unmarshal(data, &t.Minutes)
+	}
+	if strings.EqualFold(strings.TrimSpace(s), "Whirlpool") {
+		t.Whirlpool = true
+		return nil
+	}
+	minutes, err := stringToFloat(strings.TrimSpace(s))
+	if err != nil {
+		return err
+	}
+	t.Minutes = minutes
+	return nil
+}
+
 // Parse parses a recipe from a string
 func (p *MMUMParser) Parse(recipe string) (*recipe.Recipe, error) {
 	var r MMUMRecipe
@@ -226,14 +272,84 @@ func getMashInstructions(r *MMUMRecipe) (*recipe.MashInstructions, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &recipe.MashInstructions{
+	mash := &recipe.MashInstructions{
 		Malts:              malts,
 		MainWaterVolume:    r.MainWater,
 		Nachguss:           r.ExtraWater,
 		MashTemperature:    r.MashTemp,
 		MashOutTemperature: float32(mashOutValue),
 		Rasts:              rasts,
-	}, nil
+		MashType:           recipe.ParseMashType(r.MashType),
+	}
+	if mash.IsDecoction() {
+		if err := getDecoctions(r, mash); err != nil {
+			return nil, err
+		}
+	}
+	return mash, nil
+}
+
+// stringToFloat parses a float from a string, an empty string is zero
+func stringToFloat(s string) (float32, error) {
+	if s == "" {
+		return 0, nil
+	}
+	f, err := strconv.ParseFloat(s, 32)
+	if err != nil {
+		return 0, err
+	}
+	return float32(f), nil
+}
+
+// getDecoctions replaces the mash in and the rasts of the mash instructions with the ones of a MMUMRecipe mashed by decoction
+// The first rast is the one after mashing in, every other rast is reached by returning a boiled decoction
+// If the recipe has no decoction data, the mash instructions are not modified
+func getDecoctions(r *MMUMRecipe, mash *recipe.MashInstructions) error {
+	if r.DecoctionMashTemp == "" {
+		return nil
+	}
+	mainWater, err := stringToFloat(r.DecoctionMainWater)
+	if err != nil {
+		return err
+	}
+	mashTemp, err := stringToFloat(r.DecoctionMashTemp)
+	if err != nil {
+		return err
+	}
+	mashTime, err := stringToFloat(r.DecoctionMashTime)
+	if err != nil {
+		return err
+	}
+	rasts := []recipe.Rast{{Temperature: mashTemp, Duration: mashTime}}
+	var decoctions []recipe.Decoction
+	v := reflect.ValueOf(r).Elem()
+	for i := 1; i <= 3; i++ {
+		if v.FieldByName(fmt.Sprintf("Decoction%dTemp", i)).String() == "" {
+			continue
+		}
+		values := map[string]float32{}
+		for _, name := range []string{"Temp", "Time", "Volume", "RestTemp", "RestTime", "BoilTime"} {
+			values[name], err = stringToFloat(v.FieldByName(fmt.Sprintf("Decoction%d%s", i, name)).String())
+			if err != nil {
+				return err
+			}
+		}
+		rasts = append(rasts, recipe.Rast{Temperature: values["Temp"], Duration: values["Time"]})
+		decoctions = append(decoctions, recipe.Decoction{
+			Rast:            len(rasts) - 1,
+			Volume:          values["Volume"],
+			RestTemperature: values["RestTemp"],
+			RestDuration:    values["RestTime"],
+			BoilDuration:    values["BoilTime"],
+		})
+	}
+	if mainWater > 0 {
+		mash.MainWaterVolume = mainWater
+	}
+	mash.MashTemperature = mashTemp
+	mash.Rasts = rasts
+	mash.Decoctions = decoctions
+	return nil
 }
 
 // getHopInstructions returns the hop instructions for a MMUMRecipe
@@ -268,10 +384,14 @@ func getHopInstructions(r *MMUMRecipe) (*recipe.HopInstructions, error) {
 		h.Name = strings.TrimSpace(nameValue)
 		amountValue := v.FieldByName(fmt.Sprintf("Hop%dAmount", i)).Float()
 		alphaValue := v.FieldByName(fmt.Sprintf("Hop%dAlpha", i)).Float()
-		durationValue := v.FieldByName(fmt.Sprintf("Hop%dTime", i)).Float()
+		durationValue := v.FieldByName(fmt.Sprintf("Hop%dTime", i)).Interface().(hopTime)
 		h.Amount = float32(amountValue)
 		h.Alpha = float32(alphaValue)
-		h.Duration = float32(durationValue)
+		h.Duration = durationValue.Minutes
+		if durationValue.Whirlpool {
+			h.Whirlpool = true
+			h.StandTime = r.HopStandTime
+		}
 		hops = append(hops, h)
 	}
 	for i := 1; i <= 3; i++ {
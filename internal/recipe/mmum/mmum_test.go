@@ -39,6 +39,7 @@ func TestGetMashInstructions(t *testing.T) {
 					{Temperature: 67.5, Duration: 45},
 					{Temperature: 72, Duration: 15},
 				},
+				MashType: recipe.MashTypeInfusion,
 			},
 		},
 		{
@@ -62,6 +63,7 @@ func TestGetMashInstructions(t *testing.T) {
 					{Temperature: 65, Duration: 40},
 					{Temperature: 72, Duration: 20},
 				},
+				MashType: recipe.MashTypeInfusion,
 			},
 		},
 		{
@@ -81,6 +83,32 @@ func TestGetMashInstructions(t *testing.T) {
 				Rasts: []recipe.Rast{
 					{Temperature: 67, Duration: 60},
 				},
+				MashType: recipe.MashTypeInfusion,
+			},
+		},
+		{
+			Name:     "Dunkel Dekoktion",
+			FileName: "Dunkel_Dekoktion.json",
+			Expected: recipe.MashInstructions{
+				Malts: []recipe.Malt{
+					{Name: "Münchner Malz", Amount: 4000},
+					{Name: "Pilsner", Amount: 800},
+					{Name: "Carafa Spezial II", Amount: 100},
+				},
+				MainWaterVolume:    17,
+				MashTemperature:    52,
+				Nachguss:           12,
+				MashOutTemperature: 78,
+				Rasts: []recipe.Rast{
+					{Temperature: 52, Duration: 10},
+					{Temperature: 64, Duration: 30},
+					{Temperature: 72, Duration: 20},
+				},
+				MashType: recipe.MashTypeDecoction,
+				Decoctions: []recipe.Decoction{
+					{Rast: 1, Volume: 7.5, RestTemperature: 72, RestDuration: 15, BoilDuration: 20},
+					{Rast: 2, Volume: 6, BoilDuration: 15},
+				},
 			},
 		},
 	}
@@ -151,6 +179,20 @@ func TestGetHopInstructions(t *testing.T) {
 				},
 			},
 		},
+		{
+			Name:     "Whirlpool IPA",
+			FileName: "Whirlpool_IPA_min.json",
+			Expected: recipe.HopInstructions{
+				TotalCookingTime: 60,
+				Hops: []recipe.Hops{
+					{Name: "Magnum", Amount: 15, Alpha: 13, Duration: 60, DryHop: false},
+					{Name: "Citra", Amount: 25, Alpha: 12, Duration: 10, DryHop: false},
+					{Name: "Citra", Amount: 60, Alpha: 12, Duration: 0, DryHop: false, Whirlpool: true, StandTime: 30},
+					{Name: "Mosaic", Amount: 80, Alpha: 0, Duration: 0, DryHop: true},
+				},
+				AdditionalIngredients: nil,
+			},
+		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.Name, func(t *testing.T) {
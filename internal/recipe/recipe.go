@@ -1,6 +1,13 @@
 package recipe
 
-import "sync"
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
 
 type RecipeStatus int
 type ResultType int
@@ -23,11 +30,17 @@ const (
 	ResultAlcohol
 	ResultMainFermentationVolume
 	ResultVolumeBeforeBoil
+	// ResultExtraBoilTime is the time in minutes the boil is extended before the first hop addition
+	ResultExtraBoilTime
 )
 
 type SGMeasurement struct {
 	Value float32 `json:"value,omitempty"`
 	Date  string  `json:"date,omitempty"`
+	// Temperature is the temperature of the beer in °C, only known for readings of a wireless hydrometer
+	Temperature float32 `json:"temperature,omitempty"`
+	// Device is the name of the wireless hydrometer that sent the reading, empty for measurements entered by hand
+	Device string `json:"device,omitempty"`
 }
 
 // PrimingSugarResult is the result of calculating the amount of sugar to when bottling
@@ -47,6 +60,8 @@ type RecipeResults struct {
 	FinalGravity           float32
 	Alcohol                float32
 	MainFermentationVolume float32
+	// ExtraBoilTime is the time in minutes the boil is extended before the first hop addition
+	ExtraBoilTime float32
 }
 
 // Recipe is the main struct for a recipe
@@ -108,6 +123,53 @@ type MashInstructions struct {
 	MashOutTemperature float32 `json:"MashOutTemperature"`
 	// Rasts is the list of rasts to perform
 	Rasts []Rast `json:"Rasts"`
+	// MashType is the mashing method. If it is empty, the recipe is mashed by infusion
+	MashType MashType `json:"MashType,omitempty"`
+	// Decoctions is the list of decoctions to perform, only for decoction mashing
+	Decoctions []Decoction `json:"Decoctions,omitempty"`
+}
+
+// MashType is the mashing method of a recipe
+type MashType string
+
+const (
+	// MashTypeInfusion heats the mash from one rast to the next one directly or by adding water
+	MashTypeInfusion MashType = "infusion"
+	// MashTypeDecoction heats the mash by pulling part of it, boiling it and returning it
+	MashTypeDecoction MashType = "decoction"
+)
+
+// ParseMashType returns the mash type from the name used in the recipe formats (e.g. "Dekoktion" in MMUM)
+// Unknown names return an empty mash type
+func ParseMashType(name string) MashType {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "infusion":
+		return MashTypeInfusion
+	case "decoction", "dekoktion":
+		return MashTypeDecoction
+	default:
+		return ""
+	}
+}
+
+// Decoction is the struct for a decoction
+// Part of the mash is pulled, optionally rested, boiled and returned to the mash to reach the temperature of a rast
+type Decoction struct {
+	// Rast is the index of the rast that is reached when returning the decoction
+	Rast int `json:"Rast"`
+	// Volume is the volume of mash to pull in liters. If it is zero, it is calculated from the temperatures
+	Volume float32 `json:"Volume"`
+	// RestTemperature is the temperature in °C of the rest of the pulled mash before boiling, zero if there is no rest
+	RestTemperature float32 `json:"RestTemperature"`
+	// RestDuration is the duration of the rest of the pulled mash in minutes
+	RestDuration float32 `json:"RestDuration"`
+	// BoilDuration is the duration of the boil of the pulled mash in minutes
+	BoilDuration float32 `json:"BoilDuration"`
+}
+
+// RastNumber returns the number of the rast reached by the decoction, counting from 1 as it is shown to the brewer
+func (d Decoction) RastNumber() int {
+	return d.Rast + 1
 }
 
 // Rast is the struct for a rast
@@ -120,12 +182,14 @@ type Rast struct {
 }
 
 // Malt is the struct for a malt
-// It contains the name and the amount in grams
+// It contains the name, the amount in grams and the color in EBC (if known)
 type Malt struct {
 	// Name of the malt
 	Name string `json:"Name"`
 	// Amount in grams
 	Amount float32 `json:"Amount"`
+	// Color of the malt in EBC. It is zero if unknown
+	Color float32 `json:"Color,omitempty"`
 }
 
 // HopInstructions is the struct for the hopping instructions
@@ -154,6 +218,53 @@ type Hops struct {
 	DryHop bool `json:"DryHop"`
 	// Vorderwürze is true if this hop is for vorderwürze hopping
 	Vorderwuerze bool `json:"Vorderwuerze"`
+	// Whirlpool is true if this hop is added after the boil, in the whirlpool or hop stand. Duration is not used then
+	Whirlpool bool `json:"Whirlpool,omitempty"`
+	// StandTemperature is the temperature of the wort in °C when a whirlpool hop is added
+	StandTemperature float32 `json:"StandTemperature,omitempty"`
+	// StandTime is the time in minutes a whirlpool hop steeps in the hot wort before cooling
+	StandTime float32 `json:"StandTime,omitempty"`
+}
+
+const (
+	// DefaultStandTemperature is the temperature in °C of the whirlpool when the recipe does not give one
+	DefaultStandTemperature float32 = 80
+	// DefaultStandTime is the steep time in minutes of the whirlpool hops when the recipe does not give one
+	DefaultStandTime float32 = 20
+)
+
+// WhirlpoolHops returns the hops added after the boil, with the default temperature and steep time if they are not set
+// They are sorted from the hottest addition to the coldest, and then from the longest steep time to the shortest
+func (h HopInstructions) WhirlpoolHops() []Hops {
+	var hops []Hops
+	for _, hop := range h.Hops {
+		if !hop.Whirlpool || hop.DryHop {
+			continue
+		}
+		if hop.StandTemperature <= 0 {
+			hop.StandTemperature = DefaultStandTemperature
+		}
+		if hop.StandTime <= 0 {
+			hop.StandTime = DefaultStandTime
+		}
+		hops = append(hops, hop)
+	}
+	sort.SliceStable(hops, func(i, j int) bool {
+		if hops[i].StandTemperature != hops[j].StandTemperature {
+			return hops[i].StandTemperature > hops[j].StandTemperature
+		}
+		return hops[i].StandTime > hops[j].StandTime
+	})
+	return hops
+}
+
+// HopStandTime returns the time in minutes of the hop stand, the longest steep time of the whirlpool hops
+func (h HopInstructions) HopStandTime() float32 {
+	var longest float32
+	for _, hop := range h.WhirlpoolHops() {
+		longest = max(longest, hop.StandTime)
+	}
+	return longest
 }
 
 // AdditionalIngredient is the struct for an additional ingredient
@@ -181,15 +292,80 @@ type FermentationInstructions struct {
 	AdditionalIngredients []AdditionalIngredient `json:"FermentationAdditionalIngredients"`
 	// Carbonation is the carbonation in g/l
 	Carbonation float32 `json:"Carbonation"`
+	// Schedule is the list of temperature steps of the fermentation, starting when the yeast is pitched
+	// If it is empty, the fermentation is held at Temperature
+	Schedule []FermentationStep `json:"Schedule,omitempty"`
+}
+
+// FermentationStep is a step of the fermentation temperature schedule (e.g. 10 °C for 5 days)
+type FermentationStep struct {
+	// Name of the step (e.g. "Diacetyl rest")
+	Name string `json:"Name"`
+	// Temperature is the temperature of the step in °C
+	Temperature float32 `json:"Temperature"`
+	// Days is the duration of the step. The last step can have no duration, it lasts until the end of the fermentation
+	Days float32 `json:"Days"`
+	// FreeRise lets the beer warm up by itself to the temperature instead of setting it (e.g. for a diacetyl rest)
+	FreeRise bool `json:"FreeRise,omitempty"`
+}
+
+// Instruction returns what has to be done at the start of the step
+func (s FermentationStep) Instruction() string {
+	temperature := strconv.FormatFloat(float64(s.Temperature), 'f', -1, 32)
+	action := "Set the temperature to " + temperature + " °C"
+	if s.FreeRise {
+		action = "Let the temperature rise freely to " + temperature + " °C"
+	}
+	if s.Name == "" {
+		return action
+	}
+	return s.Name + ": " + action
 }
 
 // Yeast is the struct for a yeast
-// It contains the name and the amount in grams
+// It contains the name and the amount in grams, and the values of the strain from the yeast database if its name matches one
 type Yeast struct {
 	// Name of the yeast
 	Name string `json:"Name"`
 	// Amount in grams
 	Amount float32 `json:"Amount"`
+	// Lab is the laboratory that produces the strain
+	Lab string `json:"Lab,omitempty"`
+	// MinAttenuation is the lowest apparent attenuation of the strain in %, 0 if it is not known
+	MinAttenuation float32 `json:"MinAttenuation,omitempty"`
+	// MaxAttenuation is the highest apparent attenuation of the strain in %, 0 if it is not known
+	MaxAttenuation float32 `json:"MaxAttenuation,omitempty"`
+	// MinTemperature is the lowest recommended fermentation temperature in °C
+	MinTemperature float32 `json:"MinTemperature,omitempty"`
+	// MaxTemperature is the highest recommended fermentation temperature in °C
+	MaxTemperature float32 `json:"MaxTemperature,omitempty"`
+	// Flocculation is low, medium or high
+	Flocculation string `json:"Flocculation,omitempty"`
+}
+
+// StepStarts returns the start of each step of the schedule for a fermentation started at the given time
+// The durations are rounded to the minute
+func (f FermentationInstructions) StepStarts(start time.Time) []time.Time {
+	starts := make([]time.Time, 0, len(f.Schedule))
+	for _, step := range f.Schedule {
+		starts = append(starts, start)
+		start = start.Add(time.Duration(math.Round(float64(step.Days)*24*60)) * time.Minute)
+	}
+	return starts
+}
+
+// CurrentStep returns the index of the step of the schedule running at the given time, or -1 if there is none
+// The starts of the steps are the ones returned by StepStarts, or the ones recorded when the fermentation started
+// The last step lasts until the end of the fermentation, whatever its duration
+func (f FermentationInstructions) CurrentStep(starts []time.Time, now time.Time) int {
+	current := -1
+	for i, s := range starts {
+		if i >= len(f.Schedule) || now.Before(s) {
+			break
+		}
+		current = i
+	}
+	return current
 }
 
 // GetTotalMaltWeight returns the total weight of the malts in g
@@ -201,6 +377,24 @@ func (mash MashInstructions) GetTotalMaltWeight() float32 {
 	return total
 }
 
+// IsDecoction returns whether the recipe is mashed by decoction
+func (mash MashInstructions) IsDecoction() bool {
+	return mash.MashType == MashTypeDecoction
+}
+
+// DecoctionForRast returns the index of the decoction that reaches the given rast, or -1 if the rast is reached without a decoction
+func (mash MashInstructions) DecoctionForRast(rast int) int {
+	if !mash.IsDecoction() {
+		return -1
+	}
+	for i, d := range mash.Decoctions {
+		if d.Rast == rast {
+			return i
+		}
+	}
+	return -1
+}
+
 // GetStatus returns the status of the recipe
 func (r *Recipe) GetStatus() (RecipeStatus, []string) {
 	r.statusLock.Lock()
@@ -216,6 +410,13 @@ func (r *Recipe) SetStatus(status RecipeStatus, params ...string) {
 	r.statusParams = params
 }
 
+// Editable returns whether the definition of the recipe can still be changed, that is its brew has not started
+// Once it has, the status parameters and the brew steps refer to the rasts and hops of the recipe by their position
+func (r *Recipe) Editable() bool {
+	status, _ := r.GetStatus()
+	return status <= RecipeStatusCreated
+}
+
 // GetStatusString returns the status of the recipe as a string
 func (r *Recipe) GetStatusString() string {
 	status, _ := r.GetStatus()
@@ -301,6 +502,13 @@ func (r *Recipe) SetVolumeBeforeBoil(volume float32) {
 	r.results.VolumeBeforeBoil = volume
 }
 
+// SetExtraBoilTime sets the time in minutes the boil is extended before the first hop addition
+func (r *Recipe) SetExtraBoilTime(minutes float32) {
+	r.resultsLock.Lock()
+	defer r.resultsLock.Unlock()
+	r.results.ExtraBoilTime = minutes
+}
+
 // SetSGMeasurement adds an sg measurement to the results of the recipe
 func (r *Recipe) SetSGMeasurement(measurement *SGMeasurement) {
 	r.mainFermSGsLock.Lock()
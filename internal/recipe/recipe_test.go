@@ -2,6 +2,7 @@ package recipe
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -50,6 +51,85 @@ func TestGetTotalMaltWeight(t *testing.T) {
 
 }
 
+func TestParseMashType(t *testing.T) {
+	require := require.New(t)
+	require.Equal(MashTypeInfusion, ParseMashType("infusion"))
+	require.Equal(MashTypeDecoction, ParseMashType("Dekoktion"))
+	require.Equal(MashTypeDecoction, ParseMashType(" decoction "))
+	require.Equal(MashType(""), ParseMashType(""))
+	require.Equal(MashType(""), ParseMashType("unknown"))
+}
+
+func TestWhirlpoolHops(t *testing.T) {
+	require := require.New(t)
+	hopping := HopInstructions{
+		TotalCookingTime: 60,
+		Hops: []Hops{
+			{Name: "Magnum", Amount: 20, Duration: 60},
+			{Name: "Citra", Amount: 50, Whirlpool: true, StandTemperature: 75, StandTime: 30},
+			{Name: "Mosaic", Amount: 40, Whirlpool: true},
+			{Name: "Simcoe", Amount: 30, Whirlpool: true, StandTemperature: 80, StandTime: 10},
+			{Name: "Galaxy", Amount: 60, DryHop: true, Whirlpool: true},
+		},
+	}
+	require.Equal([]Hops{
+		{Name: "Mosaic", Amount: 40, Whirlpool: true, StandTemperature: DefaultStandTemperature, StandTime: DefaultStandTime},
+		{Name: "Simcoe", Amount: 30, Whirlpool: true, StandTemperature: 80, StandTime: 10},
+		{Name: "Citra", Amount: 50, Whirlpool: true, StandTemperature: 75, StandTime: 30},
+	}, hopping.WhirlpoolHops())
+	require.Equal(float32(30), hopping.HopStandTime())
+	require.Equal(float32(0), HopInstructions{}.HopStandTime())
+}
+
+func TestDecoctionForRast(t *testing.T) {
+	require := require.New(t)
+	mash := MashInstructions{
+		MashType: MashTypeDecoction,
+		Rasts: []Rast{
+			{Temperature: 50, Duration: 15},
+			{Temperature: 64, Duration: 30},
+			{Temperature: 72, Duration: 20},
+		},
+		Decoctions: []Decoction{
+			{Rast: 1, Volume: 8, BoilDuration: 15},
+			{Rast: 2, Volume: 7, BoilDuration: 10},
+		},
+	}
+	require.Equal(-1, mash.DecoctionForRast(0))
+	require.Equal(0, mash.DecoctionForRast(1))
+	require.Equal(1, mash.DecoctionForRast(2))
+	mash.MashType = MashTypeInfusion
+	require.Equal(-1, mash.DecoctionForRast(1))
+}
+
+func TestFermentationSchedule(t *testing.T) {
+	require := require.New(t)
+	fermentation := FermentationInstructions{
+		Schedule: []FermentationStep{
+			{Name: "Primary", Temperature: 10, Days: 5},
+			{Name: "Diacetyl rest", Temperature: 14, Days: 2.5, FreeRise: true},
+			{Name: "Cold crash", Temperature: 2},
+		},
+	}
+	start := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	starts := fermentation.StepStarts(start)
+	require.Equal([]time.Time{
+		start,
+		time.Date(2026, 10, 6, 12, 0, 0, 0, time.UTC),
+		time.Date(2026, 10, 9, 0, 0, 0, 0, time.UTC),
+	}, starts)
+	require.Equal(-1, fermentation.CurrentStep(starts, start.Add(-time.Hour)))
+	require.Equal(0, fermentation.CurrentStep(starts, start))
+	require.Equal(1, fermentation.CurrentStep(starts, starts[1].Add(time.Hour)))
+	require.Equal(2, fermentation.CurrentStep(starts, start.AddDate(0, 1, 0)))
+	require.Equal(-1, fermentation.CurrentStep(nil, start))
+	require.Equal(-1, FermentationInstructions{}.CurrentStep(starts, start))
+
+	require.Equal("Primary: Set the temperature to 10 °C", fermentation.Schedule[0].Instruction())
+	require.Equal("Diacetyl rest: Let the temperature rise freely to 14 °C", fermentation.Schedule[1].Instruction())
+	require.Equal("Set the temperature to 2.5 °C", FermentationStep{Temperature: 2.5}.Instruction())
+}
+
 func TestGetStatus(t *testing.T) {
 	require := require.New(t)
 	type testCase struct {
@@ -0,0 +1,80 @@
+package recipe
+
+import (
+	"brewday/internal/tools"
+	"errors"
+)
+
+// Scale returns a copy of the recipe adapted to a different batch size and brewhouse efficiency
+// efficiency is the efficiency (in %) the recipe was designed for, and targetEfficiency the one of the system it will be brewed on
+// - Malts are scaled with the volume and corrected by the efficiency ratio, so the initial gravity stays the same
+// - Hops are scaled with the volume only. As the gravity does not change, this keeps the bitterness (Tinseth IBU depend on the alpha acid concentration)
+// - Water volumes, decoction volumes, additional ingredients and yeast are scaled with the volume
+// Temperatures, durations, gravity, bitterness and color are not modified
+func (r *Recipe) Scale(batchSize, efficiency, targetEfficiency float32) (*Recipe, error) {
+	if r.BatchSize <= 0 {
+		return nil, errors.New("can not scale a recipe without batch size")
+	}
+	if batchSize <= 0 {
+		return nil, errors.New("invalid batch size for scaling")
+	}
+	if efficiency <= 0 || efficiency > 100 || targetEfficiency <= 0 || targetEfficiency > 100 {
+		return nil, errors.New("invalid efficiency for scaling, it must be between 0 and 100")
+	}
+	volumeFactor := batchSize / r.BatchSize
+	maltFactor := volumeFactor * efficiency / targetEfficiency
+	scaled := &Recipe{
+		Name:       r.Name,
+		Style:      r.Style,
+		BatchSize:  batchSize,
+		InitialSG:  r.InitialSG,
+		Bitterness: r.Bitterness,
+		ColorEBC:   r.ColorEBC,
+		Mashing: MashInstructions{
+			MainWaterVolume:    tools.RoundTo(r.Mashing.MainWaterVolume*volumeFactor, 1),
+			Nachguss:           tools.RoundTo(r.Mashing.Nachguss*volumeFactor, 1),
+			MashTemperature:    r.Mashing.MashTemperature,
+			MashOutTemperature: r.Mashing.MashOutTemperature,
+			Rasts:              append([]Rast(nil), r.Mashing.Rasts...),
+			MashType:           r.Mashing.MashType,
+		},
+		Hopping: HopInstructions{
+			TotalCookingTime:      r.Hopping.TotalCookingTime,
+			AdditionalIngredients: scaleIngredients(r.Hopping.AdditionalIngredients, volumeFactor),
+		},
+		Fermentation: FermentationInstructions{
+			Yeast:                 r.Fermentation.Yeast,
+			Temperature:           r.Fermentation.Temperature,
+			AdditionalIngredients: scaleIngredients(r.Fermentation.AdditionalIngredients, volumeFactor),
+			Carbonation:           r.Fermentation.Carbonation,
+			Schedule:              append([]FermentationStep(nil), r.Fermentation.Schedule...),
+		},
+	}
+	scaled.Fermentation.Yeast.Amount = tools.RoundTo(r.Fermentation.Yeast.Amount*volumeFactor, 1)
+	for _, m := range r.Mashing.Malts {
+		scaled.Mashing.Malts = append(scaled.Mashing.Malts, Malt{
+			Name:   m.Name,
+			Amount: tools.RoundTo(m.Amount*maltFactor, 0),
+			Color:  m.Color,
+		})
+	}
+	for _, d := range r.Mashing.Decoctions {
+		d.Volume = tools.RoundTo(d.Volume*volumeFactor, 1)
+		scaled.Mashing.Decoctions = append(scaled.Mashing.Decoctions, d)
+	}
+	for _, h := range r.Hopping.Hops {
+		h.Amount = tools.RoundTo(h.Amount*volumeFactor, 1)
+		scaled.Hopping.Hops = append(scaled.Hopping.Hops, h)
+	}
+	return scaled, nil
+}
+
+// scaleIngredients returns a copy of the additional ingredients with the amounts multiplied by a factor
+func scaleIngredients(ingredients []AdditionalIngredient, factor float32) []AdditionalIngredient {
+	var scaled []AdditionalIngredient
+	for _, i := range ingredients {
+		i.Amount = tools.RoundTo(i.Amount*factor, 1)
+		scaled = append(scaled, i)
+	}
+	return scaled
+}
@@ -0,0 +1,142 @@
+package recipe
+
+import (
+	"brewday/internal/tools"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScale(t *testing.T) {
+	require := require.New(t)
+	type testCase struct {
+		Name               string
+		BatchSize          float32
+		Efficiency         float32
+		TargetEfficiency   float32
+		ExpectedMalt       float32
+		ExpectedHop        float32
+		ExpectedDryHop     float32
+		ExpectedMainWater  float32
+		ExpectedNachguss   float32
+		ExpectedYeast      float32
+		ExpectedBitterness float32
+	}
+	testCases := []testCase{
+		{
+			Name:               "Same batch and efficiency",
+			BatchSize:          20,
+			Efficiency:         75,
+			TargetEfficiency:   75,
+			ExpectedMalt:       4000,
+			ExpectedHop:        20,
+			ExpectedDryHop:     50,
+			ExpectedMainWater:  16,
+			ExpectedNachguss:   14,
+			ExpectedYeast:      11.5,
+			ExpectedBitterness: 30,
+		},
+		{
+			Name:               "Half batch size",
+			BatchSize:          10,
+			Efficiency:         75,
+			TargetEfficiency:   75,
+			ExpectedMalt:       2000,
+			ExpectedHop:        10,
+			ExpectedDryHop:     25,
+			ExpectedMainWater:  8,
+			ExpectedNachguss:   7,
+			ExpectedYeast:      5.8,
+			ExpectedBitterness: 30,
+		},
+		{
+			Name:               "Lower efficiency",
+			BatchSize:          20,
+			Efficiency:         75,
+			TargetEfficiency:   60,
+			ExpectedMalt:       5000,
+			ExpectedHop:        20,
+			ExpectedDryHop:     50,
+			ExpectedMainWater:  16,
+			ExpectedNachguss:   14,
+			ExpectedYeast:      11.5,
+			ExpectedBitterness: 30,
+		},
+		{
+			Name:               "Bigger batch and higher efficiency",
+			BatchSize:          50,
+			Efficiency:         65,
+			TargetEfficiency:   78,
+			ExpectedMalt:       8333,
+			ExpectedHop:        50,
+			ExpectedDryHop:     125,
+			ExpectedMainWater:  40,
+			ExpectedNachguss:   35,
+			ExpectedYeast:      28.8,
+			ExpectedBitterness: 30,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			original := validRecipe()
+			scaled, err := original.Scale(tc.BatchSize, tc.Efficiency, tc.TargetEfficiency)
+			require.NoError(err)
+			require.Equal(tc.BatchSize, scaled.BatchSize)
+			require.Equal(original.InitialSG, scaled.InitialSG)
+			require.Equal(tc.ExpectedBitterness, scaled.Bitterness)
+			require.Equal(tc.ExpectedMalt, scaled.Mashing.Malts[0].Amount)
+			require.Equal(tc.ExpectedHop, scaled.Hopping.Hops[0].Amount)
+			require.Equal(tc.ExpectedDryHop, scaled.Hopping.Hops[1].Amount)
+			require.Equal(tc.ExpectedMainWater, scaled.Mashing.MainWaterVolume)
+			require.Equal(tc.ExpectedNachguss, scaled.Mashing.Nachguss)
+			require.Equal(tc.ExpectedYeast, scaled.Fermentation.Yeast.Amount)
+			require.Equal(original.Mashing.Rasts, scaled.Mashing.Rasts)
+			// The bitterness of the scaled hops is the same as the original one
+			hop, scaledHop := original.Hopping.Hops[0], scaled.Hopping.Hops[0]
+			originalIBU := tools.TinsethIBU(hop.Alpha, hop.Amount, hop.Duration, original.InitialSG, original.BatchSize)
+			scaledIBU := tools.TinsethIBU(scaledHop.Alpha, scaledHop.Amount, scaledHop.Duration, scaled.InitialSG, scaled.BatchSize)
+			require.InDelta(originalIBU, scaledIBU, 0.1)
+			// The original recipe is not modified
+			require.Equal(validRecipe().Mashing, original.Mashing)
+			require.Equal(validRecipe().Hopping, original.Hopping)
+		})
+	}
+}
+
+func TestScaleDecoction(t *testing.T) {
+	require := require.New(t)
+	original := validRecipe()
+	original.Mashing.MashType = MashTypeDecoction
+	original.Mashing.Rasts = append(original.Mashing.Rasts, Rast{Temperature: 72, Duration: 20})
+	original.Mashing.Decoctions = []Decoction{{Rast: 1, Volume: 7.5, RestTemperature: 72, RestDuration: 10, BoilDuration: 15}}
+	scaled, err := original.Scale(10, 75, 75)
+	require.NoError(err)
+	require.Equal(MashTypeDecoction, scaled.Mashing.MashType)
+	require.Equal([]Decoction{{Rast: 1, Volume: 3.8, RestTemperature: 72, RestDuration: 10, BoilDuration: 15}}, scaled.Mashing.Decoctions)
+	require.Equal(float32(7.5), original.Mashing.Decoctions[0].Volume)
+}
+
+func TestScaleInvalid(t *testing.T) {
+	require := require.New(t)
+	type testCase struct {
+		Name             string
+		RecipeBatchSize  float32
+		BatchSize        float32
+		Efficiency       float32
+		TargetEfficiency float32
+	}
+	testCases := []testCase{
+		{Name: "Recipe without batch size", RecipeBatchSize: 0, BatchSize: 20, Efficiency: 75, TargetEfficiency: 75},
+		{Name: "Zero batch size", RecipeBatchSize: 20, BatchSize: 0, Efficiency: 75, TargetEfficiency: 75},
+		{Name: "Zero efficiency", RecipeBatchSize: 20, BatchSize: 20, Efficiency: 0, TargetEfficiency: 75},
+		{Name: "Efficiency over 100", RecipeBatchSize: 20, BatchSize: 20, Efficiency: 75, TargetEfficiency: 120},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			r := validRecipe()
+			r.BatchSize = tc.RecipeBatchSize
+			_, err := r.Scale(tc.BatchSize, tc.Efficiency, tc.TargetEfficiency)
+			require.Error(err)
+		})
+	}
+}
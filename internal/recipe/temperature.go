@@ -0,0 +1,81 @@
+package recipe
+
+import (
+	"brewday/internal/tools"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// TemperatureRange is the range of temperature in °C a fermentation should be kept in
+type TemperatureRange struct {
+	// Min is the lowest temperature
+	Min float32
+	// Max is the highest temperature
+	Max float32
+	// Source tells where the range comes from (e.g. the step of the schedule or the yeast)
+	Source string
+}
+
+// Contains returns whether a temperature is in the range, bounds included
+func (t *TemperatureRange) Contains(temperature float32) bool {
+	return temperature >= t.Min && temperature <= t.Max
+}
+
+var temperatureNumber = regexp.MustCompile(`-?\d+(?:[.,]\d+)?`)
+
+// ParseTemperatureRange parses a fermentation temperature written in a recipe, either a single temperature (e.g. 20 °C)
+// or a range (e.g. 18-22°C). A decimal comma is accepted (e.g. 18,5)
+func ParseTemperatureRange(s string) (*TemperatureRange, error) {
+	// A dash between two numbers is a range, not a negative sign
+	numbers := temperatureNumber.FindAllString(strings.ReplaceAll(s, "-", " -"), -1)
+	values := []float32{}
+	for i, n := range numbers {
+		if i > 0 {
+			n = strings.TrimPrefix(n, "-")
+		}
+		value, err := strconv.ParseFloat(strings.Replace(n, ",", ".", 1), 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid temperature %q: %w", s, err)
+		}
+		values = append(values, float32(value))
+	}
+	switch len(values) {
+	case 1:
+		return &TemperatureRange{Min: values[0], Max: values[0]}, nil
+	case 2:
+		return &TemperatureRange{Min: min(values[0], values[1]), Max: max(values[0], values[1])}, nil
+	default:
+		return nil, fmt.Errorf("invalid temperature %q", s)
+	}
+}
+
+// TargetTemperature returns the range of temperature the fermentation should be kept in during the given step of the
+// schedule (-1 if there is none), widened by the tolerance in °C. It comes from the first that is set of:
+//   - the step of the schedule. During a free rise the temperature only has to stay between the previous step and it
+//   - the fermentation temperature of the recipe
+//   - the recommended temperature range of the yeast
+//
+// It returns nil if none is set
+func (f FermentationInstructions) TargetTemperature(step int, tolerance float32) *TemperatureRange {
+	if step >= 0 && step < len(f.Schedule) {
+		s := f.Schedule[step]
+		low := s.Temperature
+		if s.FreeRise && step > 0 {
+			low = min(low, f.Schedule[step-1].Temperature)
+		}
+		source := fmt.Sprintf("step %d", step+1)
+		if s.Name != "" {
+			source += ", " + s.Name
+		}
+		return &TemperatureRange{Min: tools.RoundTo(low-tolerance, 1), Max: tools.RoundTo(s.Temperature+tolerance, 1), Source: source}
+	}
+	if t, err := ParseTemperatureRange(f.Temperature); err == nil {
+		return &TemperatureRange{Min: tools.RoundTo(t.Min-tolerance, 1), Max: tools.RoundTo(t.Max+tolerance, 1), Source: "recipe"}
+	}
+	if f.Yeast.MaxTemperature > 0 {
+		return &TemperatureRange{Min: f.Yeast.MinTemperature, Max: f.Yeast.MaxTemperature, Source: "yeast"}
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package recipe
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTemperatureRange(t *testing.T) {
+	type testCase struct {
+		Input    string
+		Expected *TemperatureRange
+		Error    bool
+	}
+	testCases := []testCase{
+		{Input: "20", Expected: &TemperatureRange{Min: 20, Max: 20}},
+		{Input: "18-22", Expected: &TemperatureRange{Min: 18, Max: 22}},
+		{Input: "18 - 22 °C", Expected: &TemperatureRange{Min: 18, Max: 22}},
+		{Input: "22-18°C", Expected: &TemperatureRange{Min: 18, Max: 22}},
+		{Input: "18,5", Expected: &TemperatureRange{Min: 18.5, Max: 18.5}},
+		{Input: "9.5 °C", Expected: &TemperatureRange{Min: 9.5, Max: 9.5}},
+		{Input: "-1", Expected: &TemperatureRange{Min: -1, Max: -1}},
+		{Input: "", Error: true},
+		{Input: "cool", Error: true},
+		{Input: "10 then 12 then 14", Error: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Input, func(t *testing.T) {
+			require := require.New(t)
+			actual, err := ParseTemperatureRange(tc.Input)
+			if tc.Error {
+				require.Error(err)
+				return
+			}
+			require.NoError(err)
+			require.Equal(tc.Expected, actual)
+		})
+	}
+}
+
+func TestTargetTemperature(t *testing.T) {
+	schedule := []FermentationStep{
+		{Name: "Primary", Temperature: 10, Days: 7},
+		{Name: "Diacetyl rest", Temperature: 16, Days: 2, FreeRise: true},
+		{Temperature: 2},
+	}
+	yeast := Yeast{Name: "W-34/70", MinTemperature: 9, MaxTemperature: 22}
+	type testCase struct {
+		Name         string
+		Instructions FermentationInstructions
+		Step         int
+		Expected     *TemperatureRange
+	}
+	testCases := []testCase{
+		{
+			Name:         "Schedule step",
+			Instructions: FermentationInstructions{Schedule: schedule, Temperature: "9-12", Yeast: yeast},
+			Step:         0,
+			Expected:     &TemperatureRange{Min: 9, Max: 11, Source: "step 1, Primary"},
+		},
+		{
+			Name:         "Free rise",
+			Instructions: FermentationInstructions{Schedule: schedule},
+			Step:         1,
+			Expected:     &TemperatureRange{Min: 9, Max: 17, Source: "step 2, Diacetyl rest"},
+		},
+		{
+			Name:         "Step without name",
+			Instructions: FermentationInstructions{Schedule: schedule},
+			Step:         2,
+			Expected:     &TemperatureRange{Min: 1, Max: 3, Source: "step 3"},
+		},
+		{
+			Name:         "Before the schedule",
+			Instructions: FermentationInstructions{Schedule: schedule, Temperature: "9-12", Yeast: yeast},
+			Step:         -1,
+			Expected:     &TemperatureRange{Min: 8, Max: 13, Source: "recipe"},
+		},
+		{
+			Name:         "Recipe temperature",
+			Instructions: FermentationInstructions{Temperature: "19 °C", Yeast: yeast},
+			Step:         -1,
+			Expected:     &TemperatureRange{Min: 18, Max: 20, Source: "recipe"},
+		},
+		{
+			Name:         "Yeast",
+			Instructions: FermentationInstructions{Yeast: yeast},
+			Step:         -1,
+			Expected:     &TemperatureRange{Min: 9, Max: 22, Source: "yeast"},
+		},
+		{
+			Name:         "Unknown",
+			Instructions: FermentationInstructions{Yeast: Yeast{Name: "House yeast"}},
+			Step:         -1,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			require.Equal(t, tc.Expected, tc.Instructions.TargetTemperature(tc.Step, 1))
+		})
+	}
+}
+
+func TestTemperatureRangeContains(t *testing.T) {
+	require := require.New(t)
+	r := &TemperatureRange{Min: 18, Max: 20}
+	require.True(r.Contains(18))
+	require.True(r.Contains(20))
+	require.False(r.Contains(17.9))
+	require.False(r.Contains(20.1))
+}
@@ -0,0 +1,238 @@
+package recipe
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidationSeverity represents how serious a problem found in a recipe is
+type ValidationSeverity int
+
+const (
+	// ValidationWarning is a problem that does not prevent brewing the recipe, but might produce unexpected results
+	ValidationWarning ValidationSeverity = iota
+	// ValidationError is a problem that prevents brewing the recipe
+	ValidationError
+)
+
+// String returns the string representation of the severity
+func (s ValidationSeverity) String() string {
+	switch s {
+	case ValidationWarning:
+		return "warning"
+	case ValidationError:
+		return "error"
+	default:
+		return "unknown"
+	}
+}
+
+// ValidationIssue is a problem found in a field of a recipe
+type ValidationIssue struct {
+	// Field is the path of the field in the recipe (e.g. Mashing.Rasts[0].Duration)
+	Field string
+	// Message describes the problem
+	Message string
+	// Severity is how serious the problem is
+	Severity ValidationSeverity
+}
+
+// String returns the string representation of the issue
+func (i ValidationIssue) String() string {
+	return fmt.Sprintf("%s: %s", i.Field, i.Message)
+}
+
+// ValidationResult contains all the problems found when validating a recipe
+type ValidationResult struct {
+	Issues []ValidationIssue
+}
+
+// HasErrors returns whether the result contains any fatal problem
+func (v *ValidationResult) HasErrors() bool {
+	return len(v.Errors()) > 0
+}
+
+// Errors returns the fatal problems found
+func (v *ValidationResult) Errors() []ValidationIssue {
+	return v.filter(ValidationError)
+}
+
+// Warnings returns the non fatal problems found
+func (v *ValidationResult) Warnings() []ValidationIssue {
+	return v.filter(ValidationWarning)
+}
+
+// Err returns an error containing all the fatal problems, or nil if there are none
+func (v *ValidationResult) Err() error {
+	errs := v.Errors()
+	if len(errs) == 0 {
+		return nil
+	}
+	messages := make([]string, len(errs))
+	for i, e := range errs {
+		messages[i] = e.String()
+	}
+	return fmt.Errorf("invalid recipe: %s", strings.Join(messages, "; "))
+}
+
+// filter returns the issues with the given severity
+func (v *ValidationResult) filter(severity ValidationSeverity) []ValidationIssue {
+	var issues []ValidationIssue
+	if v == nil {
+		return issues
+	}
+	for _, i := range v.Issues {
+		if i.Severity == severity {
+			issues = append(issues, i)
+		}
+	}
+	return issues
+}
+
+// add adds an issue to the result
+func (v *ValidationResult) add(severity ValidationSeverity, field, message string) {
+	v.Issues = append(v.Issues, ValidationIssue{Field: field, Message: message, Severity: severity})
+}
+
+// Validate checks the recipe for problems that would prevent brewing it (errors) or produce unexpected results (warnings)
+func (r *Recipe) Validate() *ValidationResult {
+	v := &ValidationResult{}
+	if strings.TrimSpace(r.Name) == "" {
+		v.add(ValidationError, "Name", "recipe has no name")
+	}
+	if r.BatchSize <= 0 {
+		v.add(ValidationError, "BatchSize", "batch size must be greater than zero")
+	}
+	if r.InitialSG <= 1 {
+		v.add(ValidationError, "InitialSG", "initial gravity must be greater than 1.000")
+	} else if r.InitialSG > 1.2 {
+		v.add(ValidationWarning, "InitialSG", "initial gravity is unusually high, check it is given in SG")
+	}
+	if r.Bitterness <= 0 {
+		v.add(ValidationWarning, "Bitterness", "bitterness is not set")
+	}
+	if r.ColorEBC <= 0 {
+		v.add(ValidationWarning, "ColorEBC", "color is not set")
+	}
+	r.Mashing.validate(v)
+	r.Hopping.validate(v)
+	r.Fermentation.validate(v)
+	return v
+}
+
+// validate checks the mashing instructions for problems
+func (m *MashInstructions) validate(v *ValidationResult) {
+	if len(m.Malts) == 0 {
+		v.add(ValidationError, "Mashing.Malts", "recipe has no malts")
+	}
+	for i, malt := range m.Malts {
+		if malt.Amount <= 0 {
+			v.add(ValidationError, fmt.Sprintf("Mashing.Malts[%d].Amount", i), fmt.Sprintf("amount of %s must be greater than zero", malt.Name))
+		}
+	}
+	if m.MainWaterVolume <= 0 {
+		v.add(ValidationError, "Mashing.MainWaterVolume", "main water volume must be greater than zero")
+	}
+	if m.Nachguss < 0 {
+		v.add(ValidationError, "Mashing.Nachguss", "nachguss can not be negative")
+	} else if m.Nachguss == 0 {
+		v.add(ValidationWarning, "Mashing.Nachguss", "nachguss is not set")
+	}
+	if m.MashTemperature <= 0 || m.MashTemperature >= 100 {
+		v.add(ValidationWarning, "Mashing.MashTemperature", "mash temperature should be between 0 and 100 °C")
+	}
+	if m.MashOutTemperature <= 0 || m.MashOutTemperature >= 100 {
+		v.add(ValidationWarning, "Mashing.MashOutTemperature", "mash out temperature should be between 0 and 100 °C")
+	}
+	if len(m.Rasts) == 0 {
+		v.add(ValidationError, "Mashing.Rasts", "recipe has no rasts")
+	}
+	for i, rast := range m.Rasts {
+		if rast.Temperature <= 0 || rast.Temperature >= 100 {
+			v.add(ValidationError, fmt.Sprintf("Mashing.Rasts[%d].Temperature", i), "rast temperature must be between 0 and 100 °C")
+		}
+		if rast.Duration < 0 {
+			v.add(ValidationError, fmt.Sprintf("Mashing.Rasts[%d].Duration", i), "rast duration can not be negative")
+		}
+	}
+	if m.IsDecoction() && len(m.Decoctions) == 0 {
+		v.add(ValidationWarning, "Mashing.Decoctions", "decoction recipe has no decoctions, it will be mashed by infusion")
+	}
+	for i, d := range m.Decoctions {
+		field := fmt.Sprintf("Mashing.Decoctions[%d]", i)
+		if d.Rast <= 0 || d.Rast >= len(m.Rasts) {
+			v.add(ValidationError, field+".Rast", "decoction must reach a rast after the first one")
+		}
+		if d.Volume < 0 {
+			v.add(ValidationError, field+".Volume", "decoction volume can not be negative")
+		}
+		if d.RestDuration < 0 || d.BoilDuration < 0 {
+			v.add(ValidationError, field, "decoction durations can not be negative")
+		}
+	}
+}
+
+// validate checks the hopping instructions for problems
+func (h *HopInstructions) validate(v *ValidationResult) {
+	if h.TotalCookingTime <= 0 {
+		v.add(ValidationError, "Hopping.TotalCookingTime", "cooking time must be greater than zero")
+	}
+	if len(h.Hops) == 0 {
+		v.add(ValidationWarning, "Hopping.Hops", "recipe has no hops")
+	}
+	for i, hop := range h.Hops {
+		field := fmt.Sprintf("Hopping.Hops[%d]", i)
+		if hop.Amount <= 0 {
+			v.add(ValidationError, field+".Amount", fmt.Sprintf("amount of %s must be greater than zero", hop.Name))
+		}
+		if hop.DryHop {
+			continue
+		}
+		if hop.Whirlpool {
+			if hop.StandTemperature > 100 {
+				v.add(ValidationError, field+".StandTemperature", fmt.Sprintf("whirlpool temperature of %s can not be above boiling", hop.Name))
+			}
+			if hop.StandTime < 0 {
+				v.add(ValidationError, field+".StandTime", fmt.Sprintf("steep time of %s can not be negative", hop.Name))
+			}
+		} else if hop.Duration < 0 {
+			v.add(ValidationError, field+".Duration", fmt.Sprintf("duration of %s can not be negative", hop.Name))
+		} else if h.TotalCookingTime > 0 && hop.Duration > h.TotalCookingTime {
+			v.add(ValidationWarning, field+".Duration", fmt.Sprintf("%s is cooked longer than the total cooking time", hop.Name))
+		}
+		if hop.Alpha <= 0 {
+			v.add(ValidationWarning, field+".Alpha", fmt.Sprintf("alpha acid of %s is not set", hop.Name))
+		}
+	}
+	for i, a := range h.AdditionalIngredients {
+		if a.Duration < 0 {
+			v.add(ValidationError, fmt.Sprintf("Hopping.AdditionalIngredients[%d].Duration", i), fmt.Sprintf("duration of %s can not be negative", a.Name))
+		}
+	}
+}
+
+// validate checks the fermentation instructions for problems
+func (f *FermentationInstructions) validate(v *ValidationResult) {
+	if strings.TrimSpace(f.Yeast.Name) == "" {
+		v.add(ValidationWarning, "Fermentation.Yeast.Name", "recipe has no yeast")
+	}
+	if strings.TrimSpace(f.Temperature) == "" {
+		v.add(ValidationWarning, "Fermentation.Temperature", "fermentation temperature is not set")
+	}
+	if f.Carbonation < 0 {
+		v.add(ValidationError, "Fermentation.Carbonation", "carbonation can not be negative")
+	} else if f.Carbonation == 0 {
+		v.add(ValidationWarning, "Fermentation.Carbonation", "carbonation is not set")
+	}
+	for i, step := range f.Schedule {
+		field := fmt.Sprintf("Fermentation.Schedule[%d]", i)
+		if step.Temperature < -5 || step.Temperature > 40 {
+			v.add(ValidationError, field+".Temperature", fmt.Sprintf("fermentation temperature %.1f °C is out of range", step.Temperature))
+		}
+		if step.Days < 0 {
+			v.add(ValidationError, field+".Days", "fermentation step duration can not be negative")
+		} else if step.Days == 0 && i < len(f.Schedule)-1 {
+			v.add(ValidationWarning, field+".Days", "fermentation step has no duration, it will be skipped")
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package recipe
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func validRecipe() *Recipe {
+	return &Recipe{
+		Name:       "Test",
+		Style:      "Pale Ale",
+		BatchSize:  20,
+		InitialSG:  1.050,
+		Bitterness: 30,
+		ColorEBC:   12,
+		Mashing: MashInstructions{
+			Malts:              []Malt{{Name: "Pilsner", Amount: 4000}},
+			MainWaterVolume:    16,
+			Nachguss:           14,
+			MashTemperature:    67,
+			MashOutTemperature: 77,
+			Rasts:              []Rast{{Temperature: 66, Duration: 60}},
+		},
+		Hopping: HopInstructions{
+			TotalCookingTime: 60,
+			Hops: []Hops{
+				{Name: "Magnum", Alpha: 12, Amount: 20, Duration: 60},
+				{Name: "Cascade", Amount: 50, DryHop: true},
+			},
+		},
+		Fermentation: FermentationInstructions{
+			Yeast:       Yeast{Name: "US-05", Amount: 11.5},
+			Temperature: "18",
+			Carbonation: 5,
+		},
+	}
+}
+
+func fields(issues []ValidationIssue) []string {
+	result := []string{}
+	for _, i := range issues {
+		result = append(result, i.Field)
+	}
+	return result
+}
+
+func TestValidate(t *testing.T) {
+	require := require.New(t)
+	type testCase struct {
+		Name             string
+		Modify           func(r *Recipe)
+		ExpectedErrors   []string
+		ExpectedWarnings []string
+	}
+	testCases := []testCase{
+		{
+			Name:             "Valid recipe",
+			Modify:           func(r *Recipe) {},
+			ExpectedErrors:   []string{},
+			ExpectedWarnings: []string{},
+		},
+		{
+			Name: "No rasts",
+			Modify: func(r *Recipe) {
+				r.Mashing.Rasts = nil
+			},
+			ExpectedErrors:   []string{"Mashing.Rasts"},
+			ExpectedWarnings: []string{},
+		},
+		{
+			Name: "Zero batch size and gravity",
+			Modify: func(r *Recipe) {
+				r.BatchSize = 0
+				r.InitialSG = 0
+			},
+			ExpectedErrors:   []string{"BatchSize", "InitialSG"},
+			ExpectedWarnings: []string{},
+		},
+		{
+			Name: "Gravity in plato",
+			Modify: func(r *Recipe) {
+				r.InitialSG = 12.5
+			},
+			ExpectedErrors:   []string{},
+			ExpectedWarnings: []string{"InitialSG"},
+		},
+		{
+			Name: "Invalid ingredients",
+			Modify: func(r *Recipe) {
+				r.Mashing.Malts[0].Amount = 0
+				r.Mashing.Rasts[0].Temperature = 150
+				r.Hopping.Hops[0].Duration = 90
+				r.Hopping.Hops[1].Amount = 0
+			},
+			ExpectedErrors:   []string{"Mashing.Malts[0].Amount", "Mashing.Rasts[0].Temperature", "Hopping.Hops[1].Amount"},
+			ExpectedWarnings: []string{"Hopping.Hops[0].Duration"},
+		},
+		{
+			Name: "Whirlpool hop above boiling",
+			Modify: func(r *Recipe) {
+				r.Hopping.Hops[1].DryHop = false
+				r.Hopping.Hops[1].Whirlpool = true
+				r.Hopping.Hops[1].Alpha = 12
+				r.Hopping.Hops[1].Duration = -1
+				r.Hopping.Hops[1].StandTemperature = 105
+			},
+			ExpectedErrors:   []string{"Hopping.Hops[1].StandTemperature"},
+			ExpectedWarnings: []string{},
+		},
+		{
+			Name: "Fermentation schedule",
+			Modify: func(r *Recipe) {
+				r.Fermentation.Schedule = []FermentationStep{
+					{Temperature: 10, Days: 5},
+					{Temperature: 14},
+					{Temperature: 2, Days: -1},
+					{Temperature: 45},
+				}
+			},
+			ExpectedErrors:   []string{"Fermentation.Schedule[2].Days", "Fermentation.Schedule[3].Temperature"},
+			ExpectedWarnings: []string{"Fermentation.Schedule[1].Days"},
+		},
+		{
+			Name: "Missing optional values",
+			Modify: func(r *Recipe) {
+				r.Bitterness = 0
+				r.Mashing.Nachguss = 0
+				r.Fermentation = FermentationInstructions{}
+			},
+			ExpectedErrors:   []string{},
+			ExpectedWarnings: []string{"Bitterness", "Mashing.Nachguss", "Fermentation.Yeast.Name", "Fermentation.Temperature", "Fermentation.Carbonation"},
+		},
+		{
+			Name: "Decoction without decoctions",
+			Modify: func(r *Recipe) {
+				r.Mashing.MashType = MashTypeDecoction
+			},
+			ExpectedErrors:   []string{},
+			ExpectedWarnings: []string{"Mashing.Decoctions"},
+		},
+		{
+			Name: "Invalid decoctions",
+			Modify: func(r *Recipe) {
+				r.Mashing.MashType = MashTypeDecoction
+				r.Mashing.Rasts = append(r.Mashing.Rasts, Rast{Temperature: 72, Duration: 20})
+				r.Mashing.Decoctions = []Decoction{
+					{Rast: 1, Volume: 5, BoilDuration: 15},
+					{Rast: 0, Volume: -1, BoilDuration: 15},
+					{Rast: 2, BoilDuration: -5},
+				}
+			},
+			ExpectedErrors:   []string{"Mashing.Decoctions[1].Rast", "Mashing.Decoctions[1].Volume", "Mashing.Decoctions[2].Rast", "Mashing.Decoctions[2]"},
+			ExpectedWarnings: []string{},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			r := validRecipe()
+			tc.Modify(r)
+			result := r.Validate()
+			require.Equal(tc.ExpectedErrors, fields(result.Errors()))
+			require.Equal(tc.ExpectedWarnings, fields(result.Warnings()))
+			require.Equal(len(tc.ExpectedErrors) > 0, result.HasErrors())
+			if result.HasErrors() {
+				require.Error(result.Err())
+			} else {
+				require.NoError(result.Err())
+			}
+		})
+	}
+}
@@ -0,0 +1,14 @@
+package recipe
+
+import "brewday/internal/tools"
+
+// WaterAdjustment calculates the water treatment of the recipe for the given source water
+// The target water is chosen from the style of the recipe, and the mash pH is estimated from its malt bill
+func (r *Recipe) WaterAdjustment(source tools.WaterProfile) tools.WaterAdjustment {
+	target, _ := tools.LookupWaterTarget(r.Style)
+	grist := make([]tools.GristMalt, 0, len(r.Mashing.Malts))
+	for _, m := range r.Mashing.Malts {
+		grist = append(grist, tools.GristMalt{Name: m.Name, Amount: m.Amount, Color: m.Color})
+	}
+	return tools.AdjustWater(source, target, r.Mashing.MainWaterVolume, r.Mashing.Nachguss, grist)
+}
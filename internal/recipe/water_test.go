@@ -0,0 +1,21 @@
+package recipe
+
+import (
+	"brewday/internal/tools"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWaterAdjustment(t *testing.T) {
+	require := require.New(t)
+	r := validRecipe()
+	adj := r.WaterAdjustment(tools.WaterProfile{})
+	require.Equal("Hoppy", adj.Target.Name)
+	// All the water (16 + 14 l) is treated with the salts
+	require.InDelta(10.3, adj.Gypsum, 0.001)
+	require.InDelta(3.7, adj.CalciumChloride, 0.001)
+	require.InDelta(4.6, adj.Epsom, 0.001)
+	require.Greater(adj.LacticAcid, float32(0))
+	require.InDelta(5.3, adj.AdjustedMashPH, 0.011)
+}
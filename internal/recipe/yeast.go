@@ -0,0 +1,82 @@
+package recipe
+
+import (
+	"brewday/internal/tools"
+	"brewday/internal/yeast"
+)
+
+// ExpectedFermentation is the range of final gravity and alcohol that the yeast reaches from an original gravity
+// The lowest final gravity and the highest alcohol come with the highest attenuation
+type ExpectedFermentation struct {
+	// Known is whether the attenuation of the yeast is known. Otherwise DefaultApparentAttenuation is assumed
+	Known bool
+	// OriginalGravity is the gravity the ranges are calculated from
+	OriginalGravity float32
+	// MinAttenuation is the lowest apparent attenuation in %
+	MinAttenuation float32
+	// MaxAttenuation is the highest apparent attenuation in %
+	MaxAttenuation float32
+	// MinFinalGravity is the final gravity reached with the highest attenuation
+	MinFinalGravity float32
+	// MaxFinalGravity is the final gravity reached with the lowest attenuation
+	MaxFinalGravity float32
+	// MinAlcohol is the alcohol in %vol reached with the lowest attenuation
+	MinAlcohol float32
+	// MaxAlcohol is the alcohol in %vol reached with the highest attenuation
+	MaxAlcohol float32
+}
+
+// MatchStrain sets the lab, attenuation, temperature and flocculation of the yeast from the strain of the yeast
+// database that matches its name. If no strain matches, they are kept (e.g. as read from a BeerXML file) and it
+// returns false
+func (y *Yeast) MatchStrain() bool {
+	strain, ok := yeast.Lookup(y.Name)
+	if !ok {
+		return false
+	}
+	y.Lab = strain.Lab
+	y.MinAttenuation = strain.MinAttenuation
+	y.MaxAttenuation = strain.MaxAttenuation
+	y.MinTemperature = strain.MinTemperature
+	y.MaxTemperature = strain.MaxTemperature
+	y.Flocculation = strain.Flocculation
+	return true
+}
+
+// KnownAttenuation returns whether the attenuation range of the yeast is known
+func (y Yeast) KnownAttenuation() bool {
+	return y.MaxAttenuation > 0
+}
+
+// AttenuationRange returns the lowest and highest apparent attenuation of the yeast in %
+// If it is not known, both are DefaultApparentAttenuation
+func (y Yeast) AttenuationRange() (float32, float32) {
+	if !y.KnownAttenuation() {
+		return DefaultApparentAttenuation, DefaultApparentAttenuation
+	}
+	return y.MinAttenuation, y.MaxAttenuation
+}
+
+// ExpectedAttenuation returns the apparent attenuation in % expected from the yeast, the middle of its range
+func (y Yeast) ExpectedAttenuation() float32 {
+	minAttenuation, maxAttenuation := y.AttenuationRange()
+	return (minAttenuation + maxAttenuation) / 2
+}
+
+// ExpectedFermentation returns the final gravity and alcohol ranges expected from the attenuation of the yeast
+// The original gravity is in SG. The gravities are rounded to 3 decimals and the alcohol to 1
+func (y Yeast) ExpectedFermentation(originalGravity float32) *ExpectedFermentation {
+	minAttenuation, maxAttenuation := y.AttenuationRange()
+	minFG := tools.RoundTo(tools.FinalGravityForAttenuation(originalGravity, maxAttenuation), 3)
+	maxFG := tools.RoundTo(tools.FinalGravityForAttenuation(originalGravity, minAttenuation), 3)
+	return &ExpectedFermentation{
+		Known:           y.KnownAttenuation(),
+		OriginalGravity: originalGravity,
+		MinAttenuation:  minAttenuation,
+		MaxAttenuation:  maxAttenuation,
+		MinFinalGravity: minFG,
+		MaxFinalGravity: maxFG,
+		MinAlcohol:      tools.RoundTo(tools.CalculateAlcohol(originalGravity, maxFG), 1),
+		MaxAlcohol:      tools.RoundTo(tools.CalculateAlcohol(originalGravity, minFG), 1),
+	}
+}
@@ -0,0 +1,76 @@
+package recipe
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestYeastMatchStrain(t *testing.T) {
+	require := require.New(t)
+	y := Yeast{Name: "Fermentis Safale US-05", Amount: 11.5}
+	require.True(y.MatchStrain())
+	require.Equal(Yeast{
+		Name:           "Fermentis Safale US-05",
+		Amount:         11.5,
+		Lab:            "Fermentis",
+		MinAttenuation: 78,
+		MaxAttenuation: 82,
+		MinTemperature: 18,
+		MaxTemperature: 26,
+		Flocculation:   "medium",
+	}, y)
+	// the values of a yeast that matches no strain (e.g. read from a recipe file) are kept
+	y = Yeast{Name: "Hefe vom Nachbarn", Amount: 11.5, Lab: "Nachbar", MinAttenuation: 75, MaxAttenuation: 75}
+	require.False(y.MatchStrain())
+	require.Equal(Yeast{Name: "Hefe vom Nachbarn", Amount: 11.5, Lab: "Nachbar", MinAttenuation: 75, MaxAttenuation: 75}, y)
+}
+
+func TestYeastExpectedFermentation(t *testing.T) {
+	type testCase struct {
+		Name     string
+		Yeast    Yeast
+		OG       float32
+		Expected *ExpectedFermentation
+	}
+	testCases := []testCase{
+		{
+			Name:  "Known attenuation",
+			Yeast: Yeast{Name: "US-05", MinAttenuation: 78, MaxAttenuation: 82},
+			OG:    1.050,
+			Expected: &ExpectedFermentation{
+				Known:           true,
+				OriginalGravity: 1.050,
+				MinAttenuation:  78,
+				MaxAttenuation:  82,
+				MinFinalGravity: 1.009,
+				MaxFinalGravity: 1.011,
+				MinAlcohol:      5.1,
+				MaxAlcohol:      5.4,
+			},
+		},
+		{
+			Name:  "Unknown attenuation",
+			Yeast: Yeast{Name: "Hefe vom Nachbarn"},
+			OG:    1.048,
+			Expected: &ExpectedFermentation{
+				Known:           false,
+				OriginalGravity: 1.048,
+				MinAttenuation:  DefaultApparentAttenuation,
+				MaxAttenuation:  DefaultApparentAttenuation,
+				MinFinalGravity: 1.012,
+				MaxFinalGravity: 1.012,
+				MinAlcohol:      4.7,
+				MaxAlcohol:      4.7,
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			require := require.New(t)
+			actual := tc.Yeast.ExpectedFermentation(tc.OG)
+			require.Equal(tc.Expected, actual)
+			require.Equal((tc.Expected.MinAttenuation+tc.Expected.MaxAttenuation)/2, tc.Yeast.ExpectedAttenuation())
+		})
+	}
+}
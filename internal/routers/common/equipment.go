@@ -0,0 +1,27 @@
+package common
+
+import (
+	"brewday/internal/equipment"
+
+	"github.com/rs/zerolog/log"
+)
+
+// BrewProfileStore represents a component that retrieves the equipment profile used to brew a recipe
+type BrewProfileStore interface {
+	// RetrieveBrewProfile retrieves the equipment profile used to brew a recipe, nil if none was selected
+	RetrieveBrewProfile(recipeID string) (*equipment.Profile, error)
+}
+
+// BrewProfile returns the equipment profile used to brew a recipe, nil if none was selected or it could not be retrieved
+// Pages work without a profile, so errors are only logged
+func BrewProfile(store BrewProfileStore, id string) *equipment.Profile {
+	if store == nil {
+		return nil
+	}
+	p, err := store.RetrieveBrewProfile(id)
+	if err != nil {
+		log.Error().Str("id", id).Err(err).Msg("could not retrieve equipment profile")
+		return nil
+	}
+	return p
+}
@@ -1,6 +1,10 @@
 package common
 
-import "github.com/labstack/echo/v4"
+import (
+	"context"
+
+	"github.com/labstack/echo/v4"
+)
 
 // Router represents a component that adds routes to the web server
 // In the context of the application it encapsulates the different pages or functionalities
@@ -20,3 +24,10 @@ type WatcherRouter interface {
 	// This method helps notifications be persistent in case of restarts.
 	CheckWatchers(id string) error
 }
+
+// A BackgroundRouter is a router with work that runs in the background while the application runs (e.g. reading sensors)
+type BackgroundRouter interface {
+	Router
+	// Start starts the background work, it stops when the context is cancelled
+	Start(ctx context.Context)
+}
@@ -1,6 +1,7 @@
 package cooling
 
 import (
+	"brewday/internal/equipment"
 	"brewday/internal/recipe"
 	"brewday/internal/routers/common"
 	"net/http"
@@ -15,6 +16,7 @@ type CoolingRouter struct {
 	TLStore      TimelineStore
 	SummaryStore SummaryStore
 	Timer        Timer
+	Equipment    EquipmentStore
 }
 
 // addSummaryCooling adds a cooling to the summary and notes related to it
@@ -49,12 +51,29 @@ func (r *CoolingRouter) getCoolingHandler(c echo.Context) error {
 	if err != nil {
 		return err
 	}
-	return c.Render(http.StatusOK, "cooling.html", map[string]interface{}{
+	data := map[string]interface{}{
 		"Title":            "Cooling",
 		"RecipeID":         id,
 		"StartClickedOnce": started,
 		"Stopped":          stopped,
-	})
+	}
+	if p := common.BrewProfile(r.Equipment, id); p != nil {
+		re, err := r.Store.Retrieve(id)
+		if err != nil {
+			return err
+		}
+		results, err := r.Store.RetrieveResults(id)
+		if err != nil {
+			return err
+		}
+		data["Profile"] = p
+		data["PostBoil"] = equipment.Expectation{
+			Expected: p.RecipePostBoilVolume(re, results.VolumeBeforeBoil, results.ExtraBoilTime),
+			Measured: results.HotWortVolume,
+		}
+		data["Fermenter"] = equipment.Expectation{Expected: p.FermenterVolume(results.HotWortVolume)}
+	}
+	return c.Render(http.StatusOK, "cooling.html", data)
 }
 
 // postCoolingHandler handles the post request for the cooling page
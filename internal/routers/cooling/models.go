@@ -1,6 +1,7 @@
 package cooling
 
 import (
+	"brewday/internal/equipment"
 	"brewday/internal/recipe"
 	"time"
 
@@ -13,6 +14,12 @@ type TimelineStore interface {
 	AddEvent(id, message string) error
 }
 
+// EquipmentStore represents a component that stores the equipment profiles
+type EquipmentStore interface {
+	// RetrieveBrewProfile retrieves the equipment profile used to brew a recipe, nil if none was selected
+	RetrieveBrewProfile(recipeID string) (*equipment.Profile, error)
+}
+
 // SummaryStore represents a component that stores summaries
 type SummaryStore interface {
 	// AddCooling adds a cooling to the summary and notes related to it
@@ -21,6 +28,10 @@ type SummaryStore interface {
 
 // RecipeStore represents a component that stores recipes
 type RecipeStore interface {
+	// Retrieve retrieves a recipe based on an identifier
+	Retrieve(id string) (*recipe.Recipe, error)
+	// RetrieveResults gets the results from a certain recipe
+	RetrieveResults(id string) (*recipe.RecipeResults, error)
 	// UpdateStatus updates the status of a recipe in the store
 	UpdateStatus(id string, status recipe.RecipeStatus, statusParams ...string) error
 }
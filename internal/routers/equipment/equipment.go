@@ -0,0 +1,164 @@
+package equipment
+
+import (
+	"brewday/internal/equipment"
+	"brewday/internal/routers/common"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+type EquipmentRouter struct {
+	Store EquipmentStore
+}
+
+// RegisterRoutes registers the routes for the equipment router
+func (r *EquipmentRouter) RegisterRoutes(root *echo.Echo, parent *echo.Group) {
+	eq := parent.Group("/equipment")
+	eq.GET("", r.getEquipmentHandler).Name = "getEquipment"
+	eq.POST("", r.postProfileHandler).Name = "postEquipmentProfile"
+	eq.POST("/update/:profile_id", r.postUpdateProfileHandler).Name = "postEquipmentUpdate"
+	eq.GET("/delete/:profile_id", r.deleteProfileHandler).Name = "deleteEquipmentProfile"
+	eq.GET("/activate/:profile_id", r.activateProfileHandler).Name = "activateEquipmentProfile"
+	eq.POST("/brew/:recipe_id", r.postBrewProfileHandler).Name = "postBrewEquipment"
+}
+
+// toProfile builds an equipment profile from the values sent in the form
+func (req *ReqPostProfile) toProfile() (*equipment.Profile, error) {
+	p := &equipment.Profile{
+		Name:            strings.TrimSpace(req.Name),
+		KettleSize:      req.KettleSize,
+		DeadSpace:       req.DeadSpace,
+		TrubLoss:        req.TrubLoss,
+		EvaporationRate: req.EvaporationRate,
+		HopAbsorption:   req.HopAbsorption,
+		ChillerLoss:     req.ChillerLoss,
+		TunThermalMass:  req.TunThermalMass,
+		Efficiency:      req.Efficiency,
+	}
+	err := p.Validate()
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// getEquipmentHandler is the handler for the equipment page
+func (r *EquipmentRouter) getEquipmentHandler(c echo.Context) error {
+	if r.Store == nil {
+		return errors.New("equipment store not configured")
+	}
+	profiles, err := r.Store.ListProfiles()
+	if err != nil {
+		return err
+	}
+	return c.Render(http.StatusOK, "equipment.html", map[string]any{
+		"Title":    "Equipment",
+		"Subtitle": "Equipment profiles",
+		"Profiles": profiles,
+		// NewProfile fills the form to add a profile, an empty profile has no losses
+		"NewProfile": &equipment.Profile{},
+	})
+}
+
+// postProfileHandler is the handler for adding an equipment profile
+func (r *EquipmentRouter) postProfileHandler(c echo.Context) error {
+	if r.Store == nil {
+		return errors.New("equipment store not configured")
+	}
+	var req ReqPostProfile
+	err := c.Bind(&req)
+	if err != nil {
+		return err
+	}
+	p, err := req.toProfile()
+	if err != nil {
+		return err
+	}
+	_, err = r.Store.AddProfile(p)
+	if err != nil {
+		return err
+	}
+	return c.Redirect(http.StatusFound, c.Echo().Reverse("getEquipment"))
+}
+
+// postUpdateProfileHandler is the handler for changing an equipment profile
+func (r *EquipmentRouter) postUpdateProfileHandler(c echo.Context) error {
+	if r.Store == nil {
+		return errors.New("equipment store not configured")
+	}
+	id := c.Param("profile_id")
+	if id == "" {
+		return errors.New("no equipment profile id provided")
+	}
+	var req ReqPostProfile
+	err := c.Bind(&req)
+	if err != nil {
+		return err
+	}
+	p, err := req.toProfile()
+	if err != nil {
+		return err
+	}
+	p.ID = id
+	err = r.Store.UpdateProfile(p)
+	if err != nil {
+		return err
+	}
+	return c.Redirect(http.StatusFound, c.Echo().Reverse("getEquipment"))
+}
+
+// deleteProfileHandler is the handler for deleting an equipment profile
+func (r *EquipmentRouter) deleteProfileHandler(c echo.Context) error {
+	if r.Store == nil {
+		return errors.New("equipment store not configured")
+	}
+	id := c.Param("profile_id")
+	if id == "" {
+		return errors.New("no equipment profile id provided")
+	}
+	err := r.Store.DeleteProfile(id)
+	if err != nil {
+		return err
+	}
+	return c.Redirect(http.StatusFound, c.Echo().Reverse("getEquipment"))
+}
+
+// activateProfileHandler is the handler for making an equipment profile the one used by default
+func (r *EquipmentRouter) activateProfileHandler(c echo.Context) error {
+	if r.Store == nil {
+		return errors.New("equipment store not configured")
+	}
+	id := c.Param("profile_id")
+	if id == "" {
+		return errors.New("no equipment profile id provided")
+	}
+	err := r.Store.SetActiveProfile(id)
+	if err != nil {
+		return err
+	}
+	return c.Redirect(http.StatusFound, c.Echo().Reverse("getEquipment"))
+}
+
+// postBrewProfileHandler selects the equipment profile of a brew and starts mashing
+func (r *EquipmentRouter) postBrewProfileHandler(c echo.Context) error {
+	if r.Store == nil {
+		return errors.New("equipment store not configured")
+	}
+	id := c.Param("recipe_id")
+	if id == "" {
+		return common.ErrNoRecipeIDProvided
+	}
+	var req ReqPostBrewProfile
+	err := c.Bind(&req)
+	if err != nil {
+		return err
+	}
+	err = r.Store.SetBrewProfile(id, req.ProfileID)
+	if err != nil {
+		return err
+	}
+	return c.Redirect(http.StatusFound, c.Echo().Reverse("getMashStart", id))
+}
@@ -0,0 +1,37 @@
+package equipment
+
+import "brewday/internal/equipment"
+
+// EquipmentStore represents a component that stores the equipment profiles and the profile used in each brew
+type EquipmentStore interface {
+	// AddProfile adds an equipment profile and returns its identifier
+	AddProfile(p *equipment.Profile) (string, error)
+	// UpdateProfile replaces an equipment profile, the identifier of the profile is used to find it
+	UpdateProfile(p *equipment.Profile) error
+	// ListProfiles lists all the equipment profiles
+	ListProfiles() ([]*equipment.Profile, error)
+	// DeleteProfile deletes an equipment profile
+	DeleteProfile(id string) error
+	// SetActiveProfile makes a profile the one used by default. An empty id leaves no profile active
+	SetActiveProfile(id string) error
+	// SetBrewProfile selects the equipment profile used to brew a recipe. An empty profile id removes the selection
+	SetBrewProfile(recipeID, profileID string) error
+}
+
+// ReqPostProfile represents the request for adding or updating an equipment profile
+type ReqPostProfile struct {
+	Name            string  `json:"name" form:"name"`
+	KettleSize      float32 `json:"kettle_size" form:"kettle_size"`
+	DeadSpace       float32 `json:"dead_space" form:"dead_space"`
+	TrubLoss        float32 `json:"trub_loss" form:"trub_loss"`
+	EvaporationRate float32 `json:"evaporation_rate" form:"evaporation_rate"`
+	HopAbsorption   float32 `json:"hop_absorption" form:"hop_absorption"`
+	ChillerLoss     float32 `json:"chiller_loss" form:"chiller_loss"`
+	TunThermalMass  float32 `json:"tun_thermal_mass" form:"tun_thermal_mass"`
+	Efficiency      float32 `json:"efficiency" form:"efficiency"`
+}
+
+// ReqPostBrewProfile represents the request for selecting the equipment profile of a brew
+type ReqPostBrewProfile struct {
+	ProfileID string `json:"profile_id" form:"profile_id"`
+}
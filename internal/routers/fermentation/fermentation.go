@@ -1,14 +1,17 @@
 package fermentation
 
 import (
+	"brewday/internal/equipment"
 	"brewday/internal/recipe"
 	"brewday/internal/routers/common"
+	"brewday/internal/summary"
 	"brewday/internal/tools"
 	"brewday/internal/watcher"
 	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/labstack/echo/v4"
@@ -17,13 +20,42 @@ import (
 
 const notificationNamePattern = "main_ferm_notification_"
 
+// stepNamePattern is the name of the stored start dates of the steps of the temperature schedule
+const stepNamePattern = "ferm_step_"
+
+// completionFlag is the name of the flag stored once the main fermentation is detected as complete
+const completionFlag = "main_ferm_complete"
+
+// PitchRate is a pitch rate in million cells per ml and °P that can be chosen in the yeast page
+type PitchRate struct {
+	Name  string
+	Value float32
+}
+
+// pitchRates are the pitch rates offered in the yeast page
+var pitchRates = []PitchRate{
+	{Name: "Ale", Value: tools.PitchRateAle},
+	{Name: "Strong ale", Value: tools.PitchRateStrongAle},
+	{Name: "Lager", Value: tools.PitchRateLager},
+	{Name: "Strong lager", Value: tools.PitchRateStrongLager},
+}
+
 type FermentationRouter struct {
 	TLStore          TimelineStore
 	SummaryStore     SummaryStore
 	Store            RecipeStore
 	Notifier         Notifier
 	RefractometerWCF float32
-	watchersSet      map[string]bool // This keeps track if watches are set. In case of restart, it will go back to nil and force reconfig of watchers
+	Equipment        EquipmentStore
+	// Completion are the conditions for the gravity readings to be stable at the end of the main fermentation
+	Completion recipe.CompletionCriteria
+	// Temperatures are the readings of the temperature sensors, shown if they are configured
+	Temperatures TemperatureStore
+	// TemperatureTolerance is how many °C the fermentation temperature can differ from its target
+	TemperatureTolerance float32
+	watchersSet          map[string]bool // This keeps track if watches are set. In case of restart, it will go back to nil and force reconfig of watchers
+	// completionLock makes sure that a complete fermentation is only notified once when readings arrive at the same time
+	completionLock sync.Mutex
 }
 
 // CheckWatchers will check it watchers were set for a given recipe.
@@ -72,11 +104,135 @@ func (r *FermentationRouter) CheckWatchers(id string) error {
 				return nil
 			}).Start()
 		}
+		err = r.restoreStepWatchers(id, re)
+		if err != nil {
+			return err
+		}
 		r.addWatchersSet(id)
 	}
 	return nil
 }
 
+// restoreStepWatchers sets up again the notifications of the steps of the temperature schedule that did not start yet
+// The steps that already started were notified and recorded in the timeline when they started
+func (r *FermentationRouter) restoreStepWatchers(id string, re *recipe.Recipe) error {
+	dates, err := r.Store.RetrieveDates(id, stepNamePattern)
+	if err != nil {
+		return err
+	}
+	for i, date := range dates {
+		if i >= len(re.Fermentation.Schedule) || time.Until(*date) < 0 {
+			continue
+		}
+		r.startStepWatcher(id, re, i, *date)
+	}
+	return nil
+}
+
+// startStepWatcher notifies the start of a step of the temperature schedule and records it in the timeline
+func (r *FermentationRouter) startStepWatcher(id string, re *recipe.Recipe, step int, date time.Time) {
+	instruction := re.Fermentation.Schedule[step].Instruction()
+	watcher.NewWatcher(date, func() error {
+		log.Info().Str("id", id).Int("step", step).Msg("fermentation step")
+		err := r.addTimelineEvent(id, fmt.Sprintf("Fermentation Step %d: %s", step+1, instruction))
+		if err != nil {
+			log.Error().Str("id", id).Err(err).Msg("could not add timeline event")
+		}
+		return r.sendNotification(instruction, "Fermentation Schedule "+re.Name, nil)
+	}).Start()
+}
+
+// scheduleSteps stores the start of each step of the temperature schedule and sets up their notifications
+// With the seconds time unit (for testing) each day of the schedule lasts a second
+func (r *FermentationRouter) scheduleSteps(id string, re *recipe.Recipe, start time.Time, timeUnit string) error {
+	dates := re.Fermentation.StepStarts(start)
+	if timeUnit == "seconds" {
+		date := start
+		for i, step := range re.Fermentation.Schedule {
+			dates[i] = date
+			date = date.Add(time.Duration(step.Days * float32(time.Second)))
+		}
+	}
+	for i, date := range dates {
+		err := r.Store.AddDate(id, &date, fmt.Sprintf(stepNamePattern+"%d", i))
+		if err != nil {
+			return err
+		}
+		r.startStepWatcher(id, re, i, date)
+	}
+	return nil
+}
+
+// scheduledSteps returns the steps of the temperature schedule with their start dates, marking the running one
+func (r *FermentationRouter) scheduledSteps(id string, re *recipe.Recipe, now time.Time) ([]ScheduledStep, error) {
+	dates, err := r.Store.RetrieveDates(id, stepNamePattern)
+	if err != nil {
+		return nil, err
+	}
+	starts := make([]time.Time, 0, len(dates))
+	for _, date := range dates {
+		starts = append(starts, *date)
+	}
+	current := re.Fermentation.CurrentStep(starts, now)
+	steps := []ScheduledStep{}
+	for i, start := range starts {
+		if i >= len(re.Fermentation.Schedule) {
+			break
+		}
+		steps = append(steps, ScheduledStep{
+			FermentationStep: re.Fermentation.Schedule[i],
+			Start:            start,
+			Done:             i < current,
+			Current:          i == current,
+		})
+	}
+	return steps, nil
+}
+
+// CheckCompletion checks whether the main fermentation of a recipe is complete from its gravity readings
+// The first time it is, a notification with the proposed final gravity is sent and it is recorded in the timeline
+func (r *FermentationRouter) CheckCompletion(id string) (*recipe.FermentationCompletion, error) {
+	measurements, err := r.Store.RetrieveMainFermSGs(id)
+	if err != nil {
+		return nil, err
+	}
+	re, err := r.Store.Retrieve(id)
+	if err != nil {
+		return nil, err
+	}
+	results, err := r.Store.RetrieveResults(id)
+	if err != nil {
+		return nil, err
+	}
+	completion := recipe.DetectFermentationCompletion(measurements, originalGravity(re, results), r.Completion)
+	if !completion.Complete {
+		return completion, nil
+	}
+	r.completionLock.Lock()
+	defer r.completionLock.Unlock()
+	notified, err := r.Store.RetrieveBoolFlag(id, completionFlag)
+	if err != nil {
+		return nil, err
+	}
+	if notified {
+		return completion, nil
+	}
+	err = r.Store.AddBoolFlag(id, completionFlag, true)
+	if err != nil {
+		return nil, err
+	}
+	message := fmt.Sprintf("Fermentation complete, the gravity is stable at %.3f", completion.FinalGravity)
+	err = r.addTimelineEvent(id, message)
+	if err != nil {
+		log.Error().Str("id", id).Err(err).Msg("could not add timeline event")
+	}
+	err = r.sendNotification(message, "Main Fermentation "+re.Name, nil)
+	if err != nil {
+		log.Error().Str("id", id).Err(err).Msg("could not send notification")
+	}
+	return completion, nil
+}
+
 func (r *FermentationRouter) addWatchersSet(id string) {
 	if r.watchersSet == nil {
 		r.watchersSet = make(map[string]bool)
@@ -124,6 +280,33 @@ func (r *FermentationRouter) addSummaryYeastStart(id string, temperature, notes
 	return nil
 }
 
+// addSummaryYeastPitch adds the pitch rate calculation to the summary
+func (r *FermentationRouter) addSummaryYeastPitch(id string, pitch *summary.YeastPitch) error {
+	if r.SummaryStore != nil {
+		return r.SummaryStore.AddYeastPitch(id, pitch)
+	}
+	return nil
+}
+
+// addSummaryYeastExpectation adds the final gravity and alcohol expected from the yeast to the summary
+func (r *FermentationRouter) addSummaryYeastExpectation(id string, y recipe.Yeast, expected *recipe.ExpectedFermentation) error {
+	if r.SummaryStore != nil {
+		return r.SummaryStore.AddYeastExpectation(id, &summary.YeastExpectation{
+			Yeast:           y.Name,
+			Lab:             y.Lab,
+			Known:           expected.Known,
+			OriginalGravity: expected.OriginalGravity,
+			MinAttenuation:  expected.MinAttenuation,
+			MaxAttenuation:  expected.MaxAttenuation,
+			MinFinalGravity: expected.MinFinalGravity,
+			MaxFinalGravity: expected.MaxFinalGravity,
+			MinAlcohol:      expected.MinAlcohol,
+			MaxAlcohol:      expected.MaxAlcohol,
+		})
+	}
+	return nil
+}
+
 // addSummarySGMeasurement adds a SG measurement to the summary
 func (r *FermentationRouter) addSummarySGMeasurement(id string, sg float32, date string, final bool, notes string) error {
 	if r.SummaryStore != nil {
@@ -154,6 +337,7 @@ func (r *FermentationRouter) RegisterRoutes(root *echo.Echo, parent *echo.Group)
 	fermentation.GET("/main/:recipe_id", r.getMainFermentationHandler).Name = "getMainFermentation"
 	fermentation.POST("/main/:recipe_id", r.postMainFermentationHandler).Name = "postMainFermentation"
 	fermentation.POST("/main/correct_sg/:recipe_id", r.postCorrectSGHandler).Name = "postCorrectSG"
+	fermentation.GET("/main/chart/:recipe_id", r.getMainFermentationChartHandler).Name = "getMainFermentationChart"
 }
 
 // getPreFermentationHandler returns the handler for the pre fermentation page
@@ -170,10 +354,19 @@ func (r *FermentationRouter) getPreFermentationHandler(c echo.Context) error {
 	if err != nil {
 		return err
 	}
-	return c.Render(http.StatusOK, "fermentation_pre.html", map[string]interface{}{
+	data := map[string]interface{}{
 		"Title":    "Pre Fermentation",
 		"RecipeID": id,
-	})
+	}
+	if p := common.BrewProfile(r.Equipment, id); p != nil {
+		results, err := r.Store.RetrieveResults(id)
+		if err != nil {
+			return err
+		}
+		data["Profile"] = p
+		data["Fermenter"] = equipment.Expectation{Expected: p.FermenterVolume(results.HotWortVolume)}
+	}
+	return c.Render(http.StatusOK, "fermentation_pre.html", data)
 }
 
 // postPreFermentationHandler handles the post request for the pre fermentation page
@@ -263,7 +456,7 @@ func (r *FermentationRouter) getPreFermentationWaterHandler(c echo.Context) erro
 	if err != nil {
 		return err
 	}
-	return c.Render(http.StatusOK, "fermentation_pre_water.html", map[string]interface{}{
+	data := map[string]interface{}{
 		"Title":         "Pre Fermentation Water",
 		"RecipeID":      id,
 		"RecipeVolume":  re.BatchSize,
@@ -271,7 +464,16 @@ func (r *FermentationRouter) getPreFermentationWaterHandler(c echo.Context) erro
 		"CurrentSG":     currentSG,
 		"CurrentVolume": currentVol,
 		"Options":       options,
-	})
+	}
+	if p := common.BrewProfile(r.Equipment, id); p != nil {
+		results, err := r.Store.RetrieveResults(id)
+		if err != nil {
+			return err
+		}
+		data["Profile"] = p
+		data["Fermenter"] = equipment.Expectation{Expected: p.FermenterVolume(results.HotWortVolume), Measured: currentVol}
+	}
+	return c.Render(http.StatusOK, "fermentation_pre_water.html", data)
 }
 
 // postPreFermentationWaterHandler handles the post request for the pre fermentation water page
@@ -308,7 +510,141 @@ func (r *FermentationRouter) postPreFermentationWaterHandler(c echo.Context) err
 	return c.Redirect(http.StatusFound, c.Echo().Reverse("getFermentationYeast", id))
 }
 
+// yeastPitch calculates the cells needed for the wort in the fermenter and the starter to grow them
+// The values missing in the request are taken from the recipe: dry yeast if it has an amount, an ale pitch rate
+// (a higher one for strong beers) and no starter
+func (r *FermentationRouter) yeastPitch(re *recipe.Recipe, results *recipe.RecipeResults, req *ReqPostFermentationYeast, now time.Time) (*summary.YeastPitch, error) {
+	gravity := results.OriginalGravity
+	if gravity <= 0 {
+		gravity = re.InitialSG
+	}
+	volume := results.MainFermentationVolume
+	if volume <= 0 {
+		volume = re.BatchSize
+	}
+	plato := tools.SGToPlato(gravity)
+	if req.PitchRate <= 0 {
+		req.PitchRate = tools.PitchRateAle
+		if plato > 15 {
+			req.PitchRate = tools.PitchRateStrongAle
+		}
+	}
+	form := tools.YeastForm(req.Form)
+	if form != tools.YeastFormDry && form != tools.YeastFormLiquid {
+		form = tools.YeastFormLiquid
+		if re.Fermentation.Yeast.Amount > 0 {
+			form = tools.YeastFormDry
+		}
+	}
+	if req.Amount <= 0 {
+		req.Amount = 1
+		if form == tools.YeastFormDry && re.Fermentation.Yeast.Amount > 0 {
+			req.Amount = re.Fermentation.Yeast.Amount
+		}
+	}
+	model := tools.StarterModel(req.StarterModel)
+	if model != tools.StarterSimple && model != tools.StarterStirPlate {
+		model = tools.StarterNone
+	}
+	var production time.Time
+	if req.ProductionDate != "" {
+		var err error
+		production, err = time.Parse("2006-01-02", req.ProductionDate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid production date: %w", err)
+		}
+	}
+	viability := tools.YeastViability(form, production, now)
+	needed := tools.CellsNeeded(req.PitchRate, plato, volume)
+	available := tools.YeastCells(form, req.Amount, viability)
+	plan := tools.PlanStarter(model, available, needed)
+	return &summary.YeastPitch{
+		PitchRate:      req.PitchRate,
+		Form:           string(form),
+		Amount:         req.Amount,
+		ProductionDate: req.ProductionDate,
+		Viability:      viability * 100,
+		CellsNeeded:    needed,
+		CellsAvailable: available,
+		StarterModel:   string(model),
+		StarterVolume:  plan.Volume,
+		StarterDME:     plan.DME,
+		CellsPitched:   plan.Cells,
+	}, nil
+}
+
+// originalGravity returns the measured original gravity, or the one of the recipe before it is measured
+func originalGravity(re *recipe.Recipe, results *recipe.RecipeResults) float32 {
+	if results.OriginalGravity > 0 {
+		return results.OriginalGravity
+	}
+	return re.InitialSG
+}
+
+// fermentationYeast returns the yeast of the recipe with the values of its strain
+// Recipes stored before the yeast database existed are matched against it here
+func fermentationYeast(re *recipe.Recipe) recipe.Yeast {
+	y := re.Fermentation.Yeast
+	if !y.KnownAttenuation() {
+		y.MatchStrain()
+	}
+	return y
+}
+
+// expectedFermentation returns the final gravity and alcohol ranges expected from the attenuation of the yeast
+func (r *FermentationRouter) expectedFermentation(id string, re *recipe.Recipe) (*recipe.ExpectedFermentation, error) {
+	results, err := r.Store.RetrieveResults(id)
+	if err != nil {
+		return nil, err
+	}
+	return fermentationYeast(re).ExpectedFermentation(originalGravity(re, results)), nil
+}
+
+// targetTemperature returns the target temperature range of the fermentation at the given date, nil if it is not known
+func (r *FermentationRouter) targetTemperature(re *recipe.Recipe, steps []ScheduledStep, date time.Time) *recipe.TemperatureRange {
+	f := re.Fermentation
+	f.Yeast = fermentationYeast(re)
+	starts := make([]time.Time, 0, len(steps))
+	for _, s := range steps {
+		starts = append(starts, s.Start)
+	}
+	return f.TargetTemperature(f.CurrentStep(starts, date), r.TemperatureTolerance)
+}
+
+// TemperatureRange returns the target temperature range of the fermentation of a recipe at the given time, nil if it
+// is not known. The temperature sensors are checked against it
+func (r *FermentationRouter) TemperatureRange(id string, now time.Time) (*recipe.TemperatureRange, error) {
+	re, err := r.Store.Retrieve(id)
+	if err != nil {
+		return nil, err
+	}
+	steps, err := r.scheduledSteps(id, re, now)
+	if err != nil {
+		return nil, err
+	}
+	return r.targetTemperature(re, steps, now), nil
+}
+
+// chamberStatus returns the last reading of the temperature sensors of the fermentation and its target range
+// It returns nil if the temperature sensors are not configured
+func (r *FermentationRouter) chamberStatus(id string, re *recipe.Recipe, steps []ScheduledStep) (*ChamberStatus, error) {
+	if r.Temperatures == nil {
+		return nil, nil
+	}
+	last, err := r.Temperatures.RetrieveLastReading(id)
+	if err != nil {
+		return nil, err
+	}
+	status := &ChamberStatus{Target: r.targetTemperature(re, steps, time.Now())}
+	if last != nil {
+		status.Reading = last
+		status.OutOfRange = status.Target != nil && !status.Target.Contains(status.Reading.Temperature)
+	}
+	return status, nil
+}
+
 // getFermentationYeastHandler returns the handler for the start fermentation (yeast) page
+// The pitch rate is recalculated with the values sent as query parameters
 func (r *FermentationRouter) getFermentationYeastHandler(c echo.Context) error {
 	id := c.Param("recipe_id")
 	if id == "" {
@@ -318,20 +654,42 @@ func (r *FermentationRouter) getFermentationYeastHandler(c echo.Context) error {
 	if err != nil {
 		return err
 	}
-	err = r.addTimelineEvent(id, "Started Fermentation")
+	results, err := r.Store.RetrieveResults(id)
 	if err != nil {
-		log.Error().Str("id", id).Err(err).Msg("could not add timeline event")
+		return err
+	}
+	var req ReqPostFermentationYeast
+	err = c.Bind(&req)
+	if err != nil {
+		return err
+	}
+	if len(c.QueryParams()) == 0 {
+		err = r.addTimelineEvent(id, "Started Fermentation")
+		if err != nil {
+			log.Error().Str("id", id).Err(err).Msg("could not add timeline event")
+		}
+		req.Temperature = re.Fermentation.Temperature
 	}
 	err = r.Store.UpdateStatus(id, recipe.RecipeStatusFermenting, "yeast")
 	if err != nil {
 		return err
 	}
+	pitch, err := r.yeastPitch(re, results, &req, time.Now())
+	if err != nil {
+		return err
+	}
+	y := fermentationYeast(re)
 	return c.Render(http.StatusOK, "fermentation_yeast.html", map[string]interface{}{
-		"Title":       "Fermentation",
-		"Subtitle":    "Start Fermentation",
-		"RecipeID":    id,
-		"Yeast":       re.Fermentation.Yeast,
-		"Temperature": re.Fermentation.Temperature,
+		"Title":         "Fermentation",
+		"Subtitle":      "Start Fermentation",
+		"RecipeID":      id,
+		"Yeast":         y,
+		"Expected":      y.ExpectedFermentation(originalGravity(re, results)),
+		"Temperature":   req.Temperature,
+		"Notes":         req.Notes,
+		"Pitch":         pitch,
+		"PitchRates":    pitchRates,
+		"StarterModels": tools.StarterModels,
 	})
 }
 
@@ -354,6 +712,27 @@ func (r *FermentationRouter) postFermentationYeastHandler(c echo.Context) error
 	if err != nil {
 		log.Error().Str("id", id).Err(err).Msg("could not add yeast start to summary")
 	}
+	re, err := r.Store.Retrieve(id)
+	if err != nil {
+		return err
+	}
+	results, err := r.Store.RetrieveResults(id)
+	if err != nil {
+		return err
+	}
+	pitch, err := r.yeastPitch(re, results, &req, time.Now())
+	if err != nil {
+		return err
+	}
+	err = r.addSummaryYeastPitch(id, pitch)
+	if err != nil {
+		log.Error().Str("id", id).Err(err).Msg("could not add yeast pitch to summary")
+	}
+	y := fermentationYeast(re)
+	err = r.addSummaryYeastExpectation(id, y, y.ExpectedFermentation(originalGravity(re, results)))
+	if err != nil {
+		log.Error().Str("id", id).Err(err).Msg("could not add yeast expectation to summary")
+	}
 	return c.Redirect(http.StatusFound, c.Echo().Reverse("getMainFermentationStart", id))
 }
 
@@ -363,7 +742,19 @@ func (r *FermentationRouter) getMainFermentationStartHandler(c echo.Context) err
 	if id == "" {
 		return common.ErrNoRecipeIDProvided
 	}
-	err := r.Store.UpdateStatus(id, recipe.RecipeStatusFermenting, "start")
+	re, err := r.Store.Retrieve(id)
+	if err != nil {
+		return err
+	}
+	err = r.Store.UpdateStatus(id, recipe.RecipeStatusFermenting, "start")
+	if err != nil {
+		return err
+	}
+	schedule := []ScheduledStep{}
+	for _, step := range re.Fermentation.Schedule {
+		schedule = append(schedule, ScheduledStep{FermentationStep: step})
+	}
+	expected, err := r.expectedFermentation(id, re)
 	if err != nil {
 		return err
 	}
@@ -373,6 +764,8 @@ func (r *FermentationRouter) getMainFermentationStartHandler(c echo.Context) err
 		"RecipeID":           id,
 		"RecommendedMinDays": 8,
 		"RecommendedDays":    10,
+		"Schedule":           schedule,
+		"Expected":           expected,
 	})
 }
 
@@ -422,6 +815,10 @@ func (r *FermentationRouter) postMainFermentationStartHandler(c echo.Context) er
 		}).Start()
 		r.addWatchersSet(id)
 	}
+	err = r.scheduleSteps(id, re, now, req.TimeUnit)
+	if err != nil {
+		return err
+	}
 	return c.Redirect(http.StatusFound, c.Echo().Reverse("getMainFermentation", id))
 }
 
@@ -435,6 +832,22 @@ func (r *FermentationRouter) getMainFermentationHandler(c echo.Context) error {
 	if err != nil {
 		return err
 	}
+	re, err := r.Store.Retrieve(id)
+	if err != nil {
+		return err
+	}
+	schedule, err := r.scheduledSteps(id, re, time.Now())
+	if err != nil {
+		return err
+	}
+	expected, err := r.expectedFermentation(id, re)
+	if err != nil {
+		return err
+	}
+	chamber, err := r.chamberStatus(id, re, schedule)
+	if err != nil {
+		return err
+	}
 	missing := time.Until(*minDate[0])
 	if missing > 0 {
 		err = r.Store.UpdateStatus(id, recipe.RecipeStatusFermenting, "wait")
@@ -446,6 +859,9 @@ func (r *FermentationRouter) getMainFermentationHandler(c echo.Context) error {
 			"Subtitle":    "Main Fermentation",
 			"RecipeID":    id,
 			"MissingTime": missing.String(),
+			"Schedule":    schedule,
+			"Expected":    expected,
+			"Chamber":     chamber,
 		})
 	} else {
 		// This should ask for the SGs and once user clicks on its stable for me lead to
@@ -458,11 +874,19 @@ func (r *FermentationRouter) getMainFermentationHandler(c echo.Context) error {
 		if err != nil {
 			return err
 		}
+		completion, err := r.CheckCompletion(id)
+		if err != nil {
+			return err
+		}
 		return c.Render(http.StatusOK, "fermentation_main.html", map[string]interface{}{
 			"Title":            "Fermentation",
 			"Subtitle":         "Main Fermentation",
 			"RecipeID":         id,
 			"PastMeasurements": measurements,
+			"Schedule":         schedule,
+			"Completion":       completion,
+			"Expected":         expected,
+			"Chamber":          chamber,
 		})
 	}
 }
@@ -547,3 +971,136 @@ func (r *FermentationRouter) postCorrectSGHandler(c echo.Context) error {
 	}
 	return c.JSON(http.StatusOK, response)
 }
+
+// forecastPoints is the number of points of the fitted curve sent for the chart
+const forecastPoints = 50
+
+// temperatureChartInterval is the interval the readings of the temperature sensors are averaged over for the chart
+const temperatureChartInterval = 15 * time.Minute
+
+// getMainFermentationChartHandler returns the gravity readings of the main fermentation with their plato, attenuation,
+// alcohol and temperature, the decay curve fitted to them until the expected final gravity, and the readings of the
+// temperature sensors with their target range
+func (r *FermentationRouter) getMainFermentationChartHandler(c echo.Context) error {
+	id := c.Param("recipe_id")
+	if id == "" {
+		return common.ErrNoRecipeIDProvided
+	}
+	re, err := r.Store.Retrieve(id)
+	if err != nil {
+		return err
+	}
+	results, err := r.Store.RetrieveResults(id)
+	if err != nil {
+		return err
+	}
+	measurements, err := r.Store.RetrieveMainFermSGs(id)
+	if err != nil {
+		return err
+	}
+	og := originalGravity(re, results)
+	resp := RespChart{
+		OriginalGravity:      og,
+		ExpectedFinalGravity: tools.FinalGravityForAttenuation(og, fermentationYeast(re).ExpectedAttenuation()),
+		Points:               []ChartPoint{},
+		Forecast:             []ForecastPoint{},
+		Temperatures:         []TemperaturePoint{},
+	}
+	var lastDate time.Time
+	for _, m := range measurements {
+		date, err := m.Time()
+		if err != nil {
+			log.Error().Str("id", id).Err(err).Msg("skipping sg measurement in chart")
+			continue
+		}
+		lastDate = maxTime(lastDate, date)
+		p := ChartPoint{
+			Date:        date.Format(time.RFC3339),
+			SG:          m.Value,
+			Plato:       tools.SGToPlato(m.Value),
+			Attenuation: tools.ApparentAttenuation(og, m.Value),
+			ABV:         tools.CalculateAlcohol(og, m.Value),
+		}
+		if m.Device != "" {
+			temperature := m.Temperature
+			p.Temperature = &temperature
+		}
+		resp.Points = append(resp.Points, p)
+	}
+	forecast := recipe.ForecastFermentation(measurements, resp.ExpectedFinalGravity)
+	resp.Reached = forecast.Reached
+	if forecast.Fitted {
+		end := lastDate
+		if !forecast.TargetDate.IsZero() {
+			resp.TargetDate = forecast.TargetDate.Format(time.RFC3339)
+			end = maxTime(end, forecast.TargetDate)
+		}
+		step := end.Sub(forecast.Start) / forecastPoints
+		for i := range forecastPoints + 1 {
+			date := forecast.Start.Add(time.Duration(i) * step)
+			resp.Forecast = append(resp.Forecast, ForecastPoint{
+				Date: date.Format(time.RFC3339),
+				SG:   forecast.Gravity(date),
+			})
+		}
+	}
+	resp.Temperatures, err = r.temperaturePoints(id, re)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// temperaturePoints returns the readings of the temperature sensors of the fermentation for the chart, averaged by
+// sensor over temperatureChartInterval, with the target range at their date
+func (r *FermentationRouter) temperaturePoints(id string, re *recipe.Recipe) ([]TemperaturePoint, error) {
+	points := []TemperaturePoint{}
+	if r.Temperatures == nil {
+		return points, nil
+	}
+	readings, err := r.Temperatures.RetrieveReadings(id)
+	if err != nil {
+		return nil, err
+	}
+	steps, err := r.scheduledSteps(id, re, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	type bucket struct {
+		sensor string
+		start  time.Time
+	}
+	var order []bucket
+	sums := make(map[bucket]float32)
+	counts := make(map[bucket]int)
+	for _, reading := range readings {
+		b := bucket{sensor: reading.Sensor, start: reading.Date.Truncate(temperatureChartInterval)}
+		if counts[b] == 0 {
+			order = append(order, b)
+		}
+		sums[b] += reading.Temperature
+		counts[b]++
+	}
+	for _, b := range order {
+		p := TemperaturePoint{
+			Date:        b.start.Format(time.RFC3339),
+			Sensor:      b.sensor,
+			Temperature: tools.RoundTo(sums[b]/float32(counts[b]), 2),
+		}
+		target := r.targetTemperature(re, steps, b.start)
+		if target != nil {
+			p.Min = &target.Min
+			p.Max = &target.Max
+		}
+		points = append(points, p)
+	}
+	return points, nil
+}
+
+// maxTime returns the latest of two dates
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
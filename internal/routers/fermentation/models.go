@@ -1,7 +1,10 @@
 package fermentation
 
 import (
+	"brewday/internal/equipment"
 	"brewday/internal/recipe"
+	"brewday/internal/summary"
+	"brewday/internal/temperature"
 	"time"
 )
 
@@ -11,10 +14,26 @@ type TimelineStore interface {
 	AddEvent(id, message string) error
 }
 
+// EquipmentStore represents a component that stores the equipment profiles
+type EquipmentStore interface {
+	// RetrieveBrewProfile retrieves the equipment profile used to brew a recipe, nil if none was selected
+	RetrieveBrewProfile(recipeID string) (*equipment.Profile, error)
+}
+
+// TemperatureStore represents a component that stores the readings of the temperature sensors
+type TemperatureStore interface {
+	// RetrieveReadings retrieves the temperature readings of a recipe, from the oldest to the newest
+	RetrieveReadings(recipeID string) ([]*temperature.Reading, error)
+	// RetrieveLastReading retrieves the newest temperature reading of a recipe, nil if there is none
+	RetrieveLastReading(recipeID string) (*temperature.Reading, error)
+}
+
 // SummaryStore represents a component that stores summaries
 type SummaryStore interface {
 	AddPreFermentationVolume(id string, volume float32, sg float32, notes string) error
 	AddYeastStart(id string, temperature, notes string) error
+	AddYeastPitch(id string, pitch *summary.YeastPitch) error
+	AddYeastExpectation(id string, expectation *summary.YeastExpectation) error
 	AddMainFermentationSGMeasurement(id string, date string, gravity float32, final bool, notes string) error
 	AddMainFermentationAlcohol(id string, alcohol float32) error
 	AddEfficiency(id string, efficiencyPercentage float32) error
@@ -38,7 +57,12 @@ type RecipeStore interface {
 	AddDate(id string, date *time.Time, name string) error
 	// RetrieveDates allows to retreive stored dates with its purpose (name).It can be used to store notification dates, or timers
 	// It supports pattern in the name to retrieve multiple values
+	// The dates are returned in the order they were added
 	RetrieveDates(id, namePattern string) ([]*time.Time, error)
+	// AddBoolFlag allows to store a given flag that can be true or false in the store with a unique name
+	AddBoolFlag(id, name string, flag bool) error
+	// RetrieveBoolFlag gets a bool flag from the store given its name
+	RetrieveBoolFlag(id, name string) (bool, error)
 }
 
 // Notifier is the interface that helps decouple the notifier from the application
@@ -70,9 +94,20 @@ type ReqPostPreFermentationWater struct {
 }
 
 // ReqPostFermentationYeast represents the request for the post yeast fermentation page
+// The same values are sent as query parameters to recalculate the pitch rate in the page
 type ReqPostFermentationYeast struct {
-	Temperature string `json:"temperature" form:"temperature"` // string because it can be a range
-	Notes       string `json:"notes" form:"notes"`
+	Temperature string `json:"temperature" form:"temperature" query:"temperature"` // string because it can be a range
+	Notes       string `json:"notes" form:"notes" query:"notes"`
+	// PitchRate is in million cells per ml and °P
+	PitchRate float32 `json:"pitch_rate" form:"pitch_rate" query:"pitch_rate"`
+	// Form is one of the tools.YeastForm values
+	Form string `json:"form" form:"form" query:"form"`
+	// Amount is in grams for dry yeast and in packs for liquid yeast
+	Amount float32 `json:"amount" form:"amount" query:"amount"`
+	// ProductionDate is in the format 2006-01-02, it can be empty
+	ProductionDate string `json:"production_date" form:"production_date" query:"production_date"`
+	// StarterModel is one of the tools.StarterModel values
+	StarterModel string `json:"starter_model" form:"starter_model" query:"starter_model"`
 }
 
 // ReqPostFermentationStart represents the request for the post fermentation start page
@@ -82,6 +117,16 @@ type ReqPostFermentationStart struct {
 	TimeUnit               string `json:"time_unit" form:"time_unit"`
 }
 
+// ScheduledStep is a step of the temperature schedule with the date it starts
+type ScheduledStep struct {
+	recipe.FermentationStep
+	Start time.Time
+	// Done is true for the steps that are already over
+	Done bool
+	// Current is true for the step that is running
+	Current bool
+}
+
 // ReqPostMainFermentation represents the request for the post main fermentation page
 type ReqPostMainFermentation struct {
 	SG    float32 `json:"sg" form:"sg"`
@@ -99,3 +144,61 @@ type RespCorrectSG struct {
 	ApparentSG float32 `json:"apparent_sg"`
 	RealSG     float32 `json:"real_sg"`
 }
+
+// ChartPoint is a gravity reading of the main fermentation with the values derived from it
+type ChartPoint struct {
+	// Date is the date of the reading in RFC 3339
+	Date  string  `json:"date"`
+	SG    float32 `json:"sg"`
+	Plato float32 `json:"plato"`
+	// Attenuation is the apparent attenuation in %
+	Attenuation float32 `json:"attenuation"`
+	// ABV is the alcohol so far in %vol
+	ABV float32 `json:"abv"`
+	// Temperature is the temperature in °C, only sent by wireless hydrometers
+	Temperature *float32 `json:"temperature,omitempty"`
+}
+
+// TemperaturePoint is a reading of the fermentation temperature sensors
+type TemperaturePoint struct {
+	// Date is the date of the reading in RFC 3339
+	Date   string `json:"date"`
+	Sensor string `json:"sensor"`
+	// Temperature is in °C
+	Temperature float32 `json:"temperature"`
+	// Min and Max are the target temperature range at the date, if it is known
+	Min *float32 `json:"min,omitempty"`
+	Max *float32 `json:"max,omitempty"`
+}
+
+// ForecastPoint is a point of the decay curve fitted to the gravity readings
+type ForecastPoint struct {
+	Date string  `json:"date"`
+	SG   float32 `json:"sg"`
+}
+
+// RespChart represents the data of the main fermentation chart
+type RespChart struct {
+	OriginalGravity float32 `json:"original_gravity"`
+	// ExpectedFinalGravity is the final gravity expected from the apparent attenuation of the yeast
+	ExpectedFinalGravity float32      `json:"expected_final_gravity"`
+	Points               []ChartPoint `json:"points"`
+	// Forecast is the fitted decay curve, empty if it could not be fitted
+	Forecast []ForecastPoint `json:"forecast"`
+	// TargetDate is when the curve reaches the expected final gravity, empty if it does not
+	TargetDate string `json:"target_date,omitempty"`
+	// Reached is whether the last reading is at or below the expected final gravity
+	Reached bool `json:"reached"`
+	// Temperatures are the readings of the temperature sensors, averaged by sensor over temperatureChartInterval
+	Temperatures []TemperaturePoint `json:"temperatures"`
+}
+
+// ChamberStatus is the last reading of the temperature sensors of a fermentation and its target range
+type ChamberStatus struct {
+	// Reading is the last reading, nil if there is none
+	Reading *temperature.Reading
+	// Target is the current target range, nil if it is not known
+	Target *recipe.TemperatureRange
+	// OutOfRange is whether the last reading is outside of the target range
+	OutOfRange bool
+}
@@ -1,8 +1,11 @@
 package hopping
 
 import (
+	"brewday/internal/equipment"
+	"brewday/internal/inventory"
 	"brewday/internal/recipe"
 	"brewday/internal/routers/common"
+	"brewday/internal/summary"
 	"brewday/internal/tools"
 	"errors"
 	"fmt"
@@ -19,6 +22,8 @@ type HoppingRouter struct {
 	TLStore         TimelineStore
 	SummaryStore    SummaryStore
 	Timer           Timer
+	Inventory       InventoryStore
+	Equipment       EquipmentStore
 	ingredientCache map[string]ingredientList
 }
 
@@ -46,6 +51,14 @@ func (r *HoppingRouter) addSummaryPreBoilVolume(id string, amount float32, notes
 	return nil
 }
 
+// addSummaryBoilAdjustment adds the correction decided for the boil to the summary
+func (r *HoppingRouter) addSummaryBoilAdjustment(id string, adjustment *summary.BoilAdjustment) error {
+	if r.SummaryStore != nil {
+		return r.SummaryStore.AddBoilAdjustment(id, adjustment)
+	}
+	return nil
+}
+
 // addSummaryAfterBoilVolume adds the measured volume after boil to the summary
 func (r *HoppingRouter) addSummaryAfterBoilVolume(id string, amount float32, notes string) error {
 	if r.SummaryStore != nil {
@@ -54,6 +67,88 @@ func (r *HoppingRouter) addSummaryAfterBoilVolume(id string, amount float32, not
 	return nil
 }
 
+// boilProfile returns the profile used to predict the boil, the one of the brew or an empty one
+// The efficiency and the evaporation rate missing in the profile are taken from the statistics of past brews
+func (r *HoppingRouter) boilProfile(id string) *equipment.Profile {
+	p := common.BrewProfile(r.Equipment, id)
+	if p == nil {
+		p = &equipment.Profile{}
+	}
+	if (p.Efficiency > 0 && p.EvaporationRate > 0) || r.SummaryStore == nil {
+		return p
+	}
+	stats, err := r.SummaryStore.GetAllStats()
+	if err != nil {
+		log.Error().Str("id", id).Err(err).Msg("could not retrieve statistics")
+		return p
+	}
+	tuning := equipment.Tune(stats)
+	if p.Efficiency <= 0 {
+		p.Efficiency = tuning.Efficiency.Average
+	}
+	if p.EvaporationRate <= 0 {
+		p.EvaporationRate = tuning.Evaporation.Average
+	}
+	return p
+}
+
+// extraBoilTime returns the minutes the boil was extended, zero if it was not
+func (r *HoppingRouter) extraBoilTime(id string) float32 {
+	extra, err := r.Store.RetrieveResult(id, recipe.ResultExtraBoilTime)
+	if err != nil {
+		log.Error().Str("id", id).Err(err).Msg("could not retrieve extra boil time")
+		return 0
+	}
+	return extra
+}
+
+// adjustBoil applies the correction decided for the boil and records it in the summary and the timeline
+func (r *HoppingRouter) adjustBoil(id string, preBoil float32, advice *equipment.BoilAdvice, req *ReqPostBoilAdjustment) error {
+	var extra float32
+	var event string
+	switch req.Decision {
+	case summary.BoilDecisionKeep:
+		req.ExtraBoilTime, req.TopUpWater = 0, 0
+		event = "Kept the planned boil"
+	case summary.BoilDecisionExtend:
+		if req.ExtraBoilTime <= 0 {
+			return errors.New("the extra boil time must be positive")
+		}
+		req.TopUpWater = 0
+		extra = req.ExtraBoilTime
+		event = fmt.Sprintf("Extended the boil by %.0f minutes", extra)
+	case summary.BoilDecisionTopUp:
+		if req.TopUpWater <= 0 {
+			return errors.New("the water to add must be positive")
+		}
+		req.ExtraBoilTime = 0
+		err := r.Store.UpdateResult(id, recipe.ResultVolumeBeforeBoil, preBoil+req.TopUpWater)
+		if err != nil {
+			return err
+		}
+		event = fmt.Sprintf("Added %.1f l of water before boiling", req.TopUpWater)
+	default:
+		return errors.New("invalid boil decision: " + req.Decision)
+	}
+	err := r.Store.UpdateResult(id, recipe.ResultExtraBoilTime, extra)
+	if err != nil {
+		return err
+	}
+	err = r.addTimelineEvent(id, event)
+	if err != nil {
+		log.Error().Str("id", id).Err(err).Msg("could not add timeline event")
+	}
+	return r.addSummaryBoilAdjustment(id, &summary.BoilAdjustment{
+		Decision:       req.Decision,
+		PreBoilSG:      advice.PreBoilSG,
+		PostBoilSG:     advice.PostBoilSG,
+		PostBoilVolume: advice.PostBoilVolume,
+		ExtraBoilTime:  req.ExtraBoilTime,
+		TopUpWater:     req.TopUpWater,
+		Notes:          req.Notes,
+	})
+}
+
 // addSummaryEvaporation adds an evaporation to the summary
 func (r *HoppingRouter) addSummaryEvaporation(id string, amount float32) error {
 	if r.SummaryStore != nil {
@@ -62,6 +157,52 @@ func (r *HoppingRouter) addSummaryEvaporation(id string, amount float32) error {
 	return nil
 }
 
+// consumeIngredient deducts an ingredient from the inventory, only once per flag name
+// A timeline event is added if there was not enough in stock
+func (r *HoppingRouter) consumeIngredient(id, flag string, t inventory.IngredientType, name string, amount float32) error {
+	if r.Inventory == nil || amount <= 0 {
+		return nil
+	}
+	done, err := r.Store.RetrieveBoolFlag(id, flag)
+	if err == nil && done {
+		return nil
+	}
+	missing, err := r.Inventory.Consume(t, name, amount)
+	if err != nil {
+		return err
+	}
+	if missing > 0 {
+		err = r.addTimelineEvent(id, fmt.Sprintf("Not enough %s in the inventory, missing %.1f %s", name, missing, r.ingredientUnit(t, name)))
+		if err != nil {
+			log.Error().Str("id", id).Err(err).Msg("could not add timeline event")
+		}
+	}
+	return r.Store.AddBoolFlag(id, flag, true)
+}
+
+// ingredientUnit returns the unit of an ingredient in the inventory, grams if it is not known
+func (r *HoppingRouter) ingredientUnit(t inventory.IngredientType, name string) string {
+	items, err := r.Inventory.ListItems()
+	if err != nil {
+		log.Error().Err(err).Msg("could not list inventory items")
+	}
+	return inventory.UnitOf(items, t, name)
+}
+
+// consumeVorderwuerze deducts the Vorderwürze hops from the inventory, they are already in the wort when the boil starts
+func (r *HoppingRouter) consumeVorderwuerze(id string, re *recipe.Recipe) error {
+	for i, h := range re.Hopping.Hops {
+		if !h.Vorderwuerze || h.DryHop {
+			continue
+		}
+		err := r.consumeIngredient(id, "inventory_vw_hop_"+strconv.Itoa(i), inventory.IngredientHop, h.Name, h.Amount)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // storeIngredients stores the ingredients in the router
 // It initializes the ingredients map if it is nil
 func (r *HoppingRouter) storeIngredients(id string, re *recipe.Recipe) {
@@ -87,6 +228,8 @@ func (r *HoppingRouter) RegisterRoutes(root *echo.Echo, parent *echo.Group) {
 	hopping := parent.Group("/hopping")
 	hopping.GET("/start/:recipe_id", r.getStartHoppingHandler).Name = "getStartHopping"
 	hopping.POST("/start/:recipe_id", r.postStartHoppingHandler).Name = "postStartHopping"
+	hopping.GET("/start/advice/:recipe_id", r.getBoilAdviceHandler).Name = "getBoilAdvice"
+	hopping.POST("/start/advice/:recipe_id", r.postBoilAdviceHandler).Name = "postBoilAdvice"
 	hopping.GET("/boil/:recipe_id", r.getBoilingHandler).Name = "getBoiling"
 	hopping.GET("/end/:recipe_id", r.getEndHoppingHandler).Name = "getEndHopping"
 	hopping.POST("/end/:recipe_id", r.postEndHoppingHandler).Name = "postEndHopping"
@@ -95,6 +238,11 @@ func (r *HoppingRouter) RegisterRoutes(root *echo.Echo, parent *echo.Group) {
 	hopping.GET("/hop/timer/:recipe_id/:ingr_num", r.getHopTimestamp).Name = "getHopTimestamp"
 	hopping.POST("/hop/timer/stop/:recipe_id/:ingr_num", r.postHoppingStopTimer).Name = "postHoppingStopTimer"
 	hopping.GET("/hop/timer/duration/:recipe_id/:ingr_num", r.getHopRealDuration).Name = "getHopRealDuration"
+	hopping.GET("/stand/:recipe_id", r.getHopStandHandler).Name = "getHopStand"
+	hopping.POST("/stand/:recipe_id", r.postHopStandHandler).Name = "postHopStand"
+	hopping.GET("/stand/timer/:recipe_id", r.getHopStandTimestamp).Name = "getHopStandTimestamp"
+	hopping.POST("/stand/timer/stop/:recipe_id", r.postHopStandStopTimer).Name = "postHopStandStopTimer"
+	hopping.GET("/stand/timer/duration/:recipe_id", r.getHopStandDuration).Name = "getHopStandDuration"
 }
 
 // getStartHoppingHandler returns the handler for the start hopping route
@@ -115,11 +263,18 @@ func (r *HoppingRouter) getStartHoppingHandler(c echo.Context) error {
 	if err != nil {
 		return err
 	}
-	return c.Render(http.StatusOK, "hopping_start.html", map[string]interface{}{
+	data := map[string]interface{}{
 		"Title":    "Hopping " + re.Name,
 		"Subtitle": "1. Measure volume before boiling",
 		"RecipeID": id,
-	})
+	}
+	if p := common.BrewProfile(r.Equipment, id); p != nil {
+		preBoil := p.RecipePreBoilVolume(re)
+		data["Profile"] = p
+		data["PreBoil"] = equipment.Expectation{Expected: preBoil}
+		data["Overflow"] = !p.Fits(preBoil)
+	}
+	return c.Render(http.StatusOK, "hopping_start.html", data)
 }
 
 // postStartHoppingHandler returns the handler for the start hopping route
@@ -145,6 +300,84 @@ func (r *HoppingRouter) postStartHoppingHandler(c echo.Context) error {
 	if err != nil {
 		return err
 	}
+	re, err := r.Store.Retrieve(id)
+	if err != nil {
+		return err
+	}
+	err = r.consumeVorderwuerze(id, re)
+	if err != nil {
+		log.Error().Str("id", id).Err(err).Msg("could not deduct hops from inventory")
+	}
+	if r.boilProfile(id).AdviseBoil(re, req.InitialVolume) != nil {
+		return c.Redirect(http.StatusFound, c.Echo().Reverse("getBoilAdvice", id))
+	}
+	return c.Redirect(http.StatusFound, c.Echo().Reverse("getBoiling", id))
+}
+
+// getBoilAdviceHandler returns the handler for the boil correction route
+// It shows the gravity and volume expected after the boil and proposes to extend it or to add water
+func (r *HoppingRouter) getBoilAdviceHandler(c echo.Context) error {
+	id := c.Param("recipe_id")
+	if id == "" {
+		return common.ErrNoRecipeIDProvided
+	}
+	re, err := r.Store.Retrieve(id)
+	if err != nil {
+		return err
+	}
+	preBoil, err := r.Store.RetrieveResult(id, recipe.ResultVolumeBeforeBoil)
+	if err != nil {
+		return err
+	}
+	p := r.boilProfile(id)
+	advice := p.AdviseBoil(re, preBoil)
+	if advice == nil {
+		return c.Redirect(http.StatusFound, c.Echo().Reverse("getBoiling", id))
+	}
+	err = r.Store.UpdateStatus(id, recipe.RecipeStatusBoiling, "boilAdvice")
+	if err != nil {
+		return err
+	}
+	return c.Render(http.StatusOK, "hopping_start.html", map[string]interface{}{
+		"Title":          "Hopping " + re.Name,
+		"Subtitle":       "1. Adjust the boil",
+		"RecipeID":       id,
+		"Advice":         advice,
+		"BoilProfile":    p,
+		"BoilTime":       re.Hopping.TotalCookingTime,
+		"KeepDecision":   summary.BoilDecisionKeep,
+		"ExtendDecision": summary.BoilDecisionExtend,
+		"TopUpDecision":  summary.BoilDecisionTopUp,
+	})
+}
+
+// postBoilAdviceHandler returns the handler for the boil correction route
+func (r *HoppingRouter) postBoilAdviceHandler(c echo.Context) error {
+	id := c.Param("recipe_id")
+	if id == "" {
+		return common.ErrNoRecipeIDProvided
+	}
+	var req ReqPostBoilAdjustment
+	err := c.Bind(&req)
+	if err != nil {
+		return err
+	}
+	re, err := r.Store.Retrieve(id)
+	if err != nil {
+		return err
+	}
+	preBoil, err := r.Store.RetrieveResult(id, recipe.ResultVolumeBeforeBoil)
+	if err != nil {
+		return err
+	}
+	advice := r.boilProfile(id).AdviseBoil(re, preBoil)
+	if advice == nil {
+		return errors.New("the boil can not be predicted without the efficiency")
+	}
+	err = r.adjustBoil(id, preBoil, advice, &req)
+	if err != nil {
+		return err
+	}
 	return c.Redirect(http.StatusFound, c.Echo().Reverse("getBoiling", id))
 }
 
@@ -221,7 +454,7 @@ func (r *HoppingRouter) getHoppingHandler(c echo.Context) error {
 		return err
 	}
 	if ingrNum == 0 {
-		cookingTime = re.Hopping.TotalCookingTime
+		cookingTime = re.Hopping.TotalCookingTime + r.extraBoilTime(id)
 	} else {
 		cookingTime = ings[ingrNum-1].Duration
 	}
@@ -279,6 +512,14 @@ func (r *HoppingRouter) postHoppingHandler(c echo.Context) error {
 			if err != nil {
 				log.Error().Str("id", id).Err(err).Msg("could not add hopping to summary")
 			}
+			t := inventory.IngredientOther
+			if ingredient.IsHop {
+				t = inventory.IngredientHop
+			}
+			err = r.consumeIngredient(id, "inventory_hop_"+ingrNumStr, t, ingredient.Name, req.RealAmount)
+			if err != nil {
+				log.Error().Str("id", id).Err(err).Msg("could not deduct ingredient from inventory")
+			}
 		}
 	}
 	return c.Redirect(http.StatusFound, c.Echo().Reverse("getHopping", id, ingrNum+1))
@@ -302,11 +543,21 @@ func (r *HoppingRouter) getEndHoppingHandler(c echo.Context) error {
 	if err != nil {
 		log.Error().Err(err).Str("id", id).Msg("could not add timeline event")
 	}
-	return c.Render(http.StatusOK, "hopping_end.html", map[string]interface{}{
+	data := map[string]interface{}{
 		"Title":    "Hopping " + re.Name,
 		"Subtitle": "4. Measure volume after boiling",
 		"RecipeID": id,
-	})
+	}
+	if p := common.BrewProfile(r.Equipment, id); p != nil {
+		preBoil, err := r.Store.RetrieveResult(id, recipe.ResultVolumeBeforeBoil)
+		if err != nil {
+			return err
+		}
+		data["Profile"] = p
+		data["PreBoil"] = equipment.Expectation{Expected: p.RecipePreBoilVolume(re), Measured: preBoil}
+		data["PostBoil"] = equipment.Expectation{Expected: p.RecipePostBoilVolume(re, preBoil, r.extraBoilTime(id))}
+	}
+	return c.Render(http.StatusOK, "hopping_end.html", data)
 }
 
 // postEndHoppingHandler returns the handler for the end hopping route
@@ -340,12 +591,15 @@ func (r *HoppingRouter) postEndHoppingHandler(c echo.Context) error {
 	if err != nil {
 		return err
 	}
-	evap := tools.CalculateEvaporation(initialVol, req.FinalVolume, re.Hopping.TotalCookingTime)
+	evap := tools.CalculateEvaporation(initialVol, req.FinalVolume, re.Hopping.TotalCookingTime+r.extraBoilTime(id))
 	log.Info().Float32("evaporation", evap).Str("recipe_id", id).Msg("Saving evaporation")
 	err = r.addSummaryEvaporation(id, evap)
 	if err != nil {
 		log.Error().Str("id", id).Err(err).Msg("could not add evaporation to summary")
 	}
+	if len(re.Hopping.WhirlpoolHops()) > 0 {
+		return c.Redirect(http.StatusFound, c.Echo().Reverse("getHopStand", id))
+	}
 	return c.Redirect(http.StatusFound, c.Echo().Reverse("getCooling", id))
 }
 
@@ -370,7 +624,7 @@ func (r *HoppingRouter) getHopTimestamp(c echo.Context) error {
 	var cookingTime float32
 	if ingrNum == 0 {
 		ing := ings[ingrNum]
-		cookingTime = re.Hopping.TotalCookingTime - ing.Duration
+		cookingTime = re.Hopping.TotalCookingTime + r.extraBoilTime(id) - ing.Duration
 	} else if ingrNum == len(ings) {
 		cookingTime = ings[ingrNum-1].Duration
 	} else {
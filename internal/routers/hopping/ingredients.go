@@ -31,10 +31,11 @@ func (l ingredientList) Swap(i, j int) {
 }
 
 // organizeIngredients organizes the ingredients by time of addition
+// Whirlpool hops are not part of the boil, they are added in the hop stand
 func organizeIngredients(re *recipe.Recipe) ingredientList {
 	var ings ingredientList
 	for _, h := range re.Hopping.Hops {
-		if !h.DryHop && !h.Vorderwuerze {
+		if !h.DryHop && !h.Vorderwuerze && !h.Whirlpool {
 			ings = append(ings, ingredient{
 				Name:     h.Name,
 				Amount:   h.Amount,
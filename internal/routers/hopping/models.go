@@ -1,7 +1,10 @@
 package hopping
 
 import (
+	"brewday/internal/equipment"
+	"brewday/internal/inventory"
 	"brewday/internal/recipe"
+	"brewday/internal/summary"
 	"time"
 
 	"github.com/labstack/echo/v4"
@@ -17,6 +20,10 @@ type RecipeStore interface {
 	UpdateResult(id string, resultType recipe.ResultType, value float32) error
 	// RetrieveResult gets a certain result value from a recipe
 	RetrieveResult(id string, resultType recipe.ResultType) (float32, error)
+	// AddBoolFlag allows to store a given flag that can be true or false in the store with a unique name
+	AddBoolFlag(id, name string, flag bool) error
+	// RetrieveBoolFlag gets a bool flag from the store given its name
+	RetrieveBoolFlag(id, name string) (bool, error)
 }
 
 // TimelineStore represents a component that stores timelines
@@ -25,12 +32,29 @@ type TimelineStore interface {
 	AddEvent(id, message string) error
 }
 
+// EquipmentStore represents a component that stores the equipment profiles
+type EquipmentStore interface {
+	// RetrieveBrewProfile retrieves the equipment profile used to brew a recipe, nil if none was selected
+	RetrieveBrewProfile(recipeID string) (*equipment.Profile, error)
+}
+
 // SummaryStore represents a component that stores summaries
 type SummaryStore interface {
 	AddHopping(id string, name string, amount float32, alpha float32, duration float32, notes string) error
 	AddVolumeBeforeBoil(id string, amount float32, notes string) error
+	AddBoilAdjustment(id string, adjustment *summary.BoilAdjustment) error
 	AddVolumeAfterBoil(id string, amount float32, notes string) error
 	AddEvaporation(id string, amount float32) error
+	// GetAllStats returns the statistics of all the brews, used when the equipment profile lacks the efficiency or the evaporation
+	GetAllStats() ([]*summary.Statistics, error)
+}
+
+// InventoryStore represents a component that stores the ingredients in stock
+type InventoryStore interface {
+	// Consume deducts the given amount (in grams) of an ingredient and returns the amount that was missing
+	Consume(t inventory.IngredientType, name string, amount float32) (float32, error)
+	// ListItems lists all the items of the inventory
+	ListItems() ([]*inventory.Item, error)
 }
 
 type Timer interface {
@@ -50,12 +74,27 @@ type ReqPostStartHopping struct {
 	Notes         string  `json:"notes" form:"notes"`
 }
 
+// ReqPostBoilAdjustment is the request for the boil correction route
+// Decision is one of the summary.BoilDecision values
+type ReqPostBoilAdjustment struct {
+	Decision      string  `json:"decision" form:"decision"`
+	ExtraBoilTime float32 `json:"extra_boil_time" form:"extra_boil_time"`
+	TopUpWater    float32 `json:"top_up_water" form:"top_up_water"`
+	Notes         string  `json:"notes" form:"notes"`
+}
+
 // ReqPostEndHopping is the request for the end hopping route
 type ReqPostEndHopping struct {
 	FinalVolume float32 `json:"final_volume" form:"final_volume"`
 	Notes       string  `json:"notes" form:"notes"`
 }
 
+// ReqPostHopStand is the request for the hop stand route
+type ReqPostHopStand struct {
+	RealDuration float32 `json:"real_duration" form:"real_duration"`
+	Notes        string  `json:"notes" form:"notes"`
+}
+
 // ReqPostHopping is the response for the hopping route
 type ReqPostHopping struct {
 	RealAmount   float32 `json:"real_amount" form:"real_amount"`
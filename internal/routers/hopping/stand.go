@@ -0,0 +1,114 @@
+package hopping
+
+import (
+	"brewday/internal/inventory"
+	"brewday/internal/recipe"
+	"brewday/internal/routers/common"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+// getHopStandHandler returns the handler for the hop stand route
+// The whirlpool hops steep in the hot wort after the boil, before cooling
+func (r *HoppingRouter) getHopStandHandler(c echo.Context) error {
+	id := c.Param("recipe_id")
+	if id == "" {
+		return common.ErrNoRecipeIDProvided
+	}
+	re, err := r.Store.Retrieve(id)
+	if err != nil {
+		return err
+	}
+	hops := re.Hopping.WhirlpoolHops()
+	if len(hops) == 0 {
+		return c.Redirect(http.StatusFound, c.Echo().Reverse("getCooling", id))
+	}
+	err = r.Store.UpdateStatus(id, recipe.RecipeStatusBoiling, "hopStand")
+	if err != nil {
+		return err
+	}
+	started, stopped, err := r.Timer.GetBoolFlags(id, "hopping_stand")
+	if err != nil {
+		return err
+	}
+	return c.Render(http.StatusOK, "hopping_stand.html", map[string]interface{}{
+		"Title":            "Hopping " + re.Name,
+		"Subtitle":         "5. Hop stand",
+		"RecipeID":         id,
+		"Hops":             hops,
+		"StandTime":        re.Hopping.HopStandTime(),
+		"StartClickedOnce": started,
+		"Stopped":          stopped,
+	})
+}
+
+// postHopStandHandler returns the handler for the hop stand route
+// It adds the whirlpool hops to the summary and deducts them from the inventory
+func (r *HoppingRouter) postHopStandHandler(c echo.Context) error {
+	id := c.Param("recipe_id")
+	if id == "" {
+		return common.ErrNoRecipeIDProvided
+	}
+	re, err := r.Store.Retrieve(id)
+	if err != nil {
+		return err
+	}
+	var req ReqPostHopStand
+	err = c.Bind(&req)
+	if err != nil {
+		return err
+	}
+	for i, h := range re.Hopping.WhirlpoolHops() {
+		duration := h.StandTime
+		if req.RealDuration > 0 {
+			duration = min(duration, req.RealDuration)
+		}
+		err = r.addSummaryHopping(id, h.Name+" (Whirlpool)", h.Amount, h.Alpha, duration, req.Notes)
+		if err != nil {
+			log.Error().Str("id", id).Err(err).Msg("could not add hopping to summary")
+		}
+		err = r.consumeIngredient(id, "inventory_stand_hop_"+strconv.Itoa(i), inventory.IngredientHop, h.Name, h.Amount)
+		if err != nil {
+			log.Error().Str("id", id).Err(err).Msg("could not deduct ingredient from inventory")
+		}
+	}
+	err = r.addTimelineEvent(id, "Finished hop stand")
+	if err != nil {
+		log.Error().Err(err).Str("id", id).Msg("could not add timeline event")
+	}
+	return c.Redirect(http.StatusFound, c.Echo().Reverse("getCooling", id))
+}
+
+func (r *HoppingRouter) getHopStandTimestamp(c echo.Context) error {
+	id := c.Param("recipe_id")
+	if id == "" {
+		return common.ErrNoRecipeIDProvided
+	}
+	re, err := r.Store.Retrieve(id)
+	if err != nil {
+		return err
+	}
+	dur := time.Duration(re.Hopping.HopStandTime() * float32(time.Minute))
+	return r.Timer.HandleStartTimer(c, id, dur, "hopping_stand")
+}
+
+func (r *HoppingRouter) postHopStandStopTimer(c echo.Context) error {
+	id := c.Param("recipe_id")
+	if id == "" {
+		return common.ErrNoRecipeIDProvided
+	}
+	tlEvent := "Finished hop stand steep"
+	return r.Timer.HandleStopTimer(c, id, tlEvent, "The hop stand is finished, start cooling", "Hop stand finished", "hopping_stand")
+}
+
+func (r *HoppingRouter) getHopStandDuration(c echo.Context) error {
+	id := c.Param("recipe_id")
+	if id == "" {
+		return common.ErrNoRecipeIDProvided
+	}
+	return r.Timer.HandleRealDuration(c, id, "hopping_stand")
+}
@@ -0,0 +1,247 @@
+package hydrometer
+
+import (
+	"brewday/internal/hydrometer"
+	"brewday/internal/recipe"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// sgDateFormat is the format of the date of the stored measurements, readings are sent several times per hour
+	sgDateFormat = "2006-01-02 15:04"
+	// storeInterval is the shortest time between two stored readings of a device, the others are only shown
+	storeInterval = time.Hour
+)
+
+type HydrometerRouter struct {
+	Store           RecipeStore
+	HydrometerStore HydrometerStore
+	// Devices are the configured hydrometers, readings of other devices are rejected
+	Devices []hydrometer.Device
+	// Completion checks whether the fermentation is complete after storing a reading, it is optional
+	Completion CompletionChecker
+	// lastReadings are the last readings received of each device, by configured name
+	lastReadings map[string]*hydrometer.Reading
+	// lastStored are the dates of the last readings stored, by recipe id and configured name of the device
+	lastStored map[string]time.Time
+	lock       sync.Mutex
+}
+
+// RegisterRoutes registers the routes for the hydrometer router
+func (r *HydrometerRouter) RegisterRoutes(root *echo.Echo, parent *echo.Group) {
+	h := parent.Group("/hydrometers")
+	h.GET("", r.getHydrometersHandler).Name = "getHydrometers"
+	h.POST("/assign/:device", r.postAssignHandler).Name = "postHydrometerAssign"
+	h.POST("/ispindel", r.postISpindelHandler).Name = "postISpindelReading"
+	h.POST("/tilt", r.postTiltHandler).Name = "postTiltReading"
+}
+
+// getHydrometersHandler is the handler for the hydrometers page
+func (r *HydrometerRouter) getHydrometersHandler(c echo.Context) error {
+	if r.HydrometerStore == nil || r.Store == nil {
+		return errors.New("hydrometer store not configured")
+	}
+	assignments, err := r.HydrometerStore.ListAssignments()
+	if err != nil {
+		return err
+	}
+	recipes, err := r.Store.List()
+	if err != nil {
+		return err
+	}
+	fermenting := make([]*recipe.Recipe, 0)
+	byID := make(map[string]*recipe.Recipe, len(recipes))
+	for _, re := range recipes {
+		byID[re.ID] = re
+		status, _ := re.GetStatus()
+		if status == recipe.RecipeStatusFermenting {
+			fermenting = append(fermenting, re)
+		}
+	}
+	r.lock.Lock()
+	devices := make([]*DeviceStatus, 0, len(r.Devices))
+	for _, d := range r.Devices {
+		s := &DeviceStatus{
+			Name:       d.Name,
+			Type:       string(d.Type),
+			RecipeID:   assignments[d.Name],
+			Calibrated: len(d.Calibration) > 0,
+		}
+		re, ok := byID[s.RecipeID]
+		if ok {
+			s.RecipeName = re.Name
+			status, _ := re.GetStatus()
+			s.Fermenting = status == recipe.RecipeStatusFermenting
+		}
+		last, ok := r.lastReadings[d.Name]
+		if ok {
+			s.LastSG = d.Calibrate(last)
+			s.LastTemperature = last.Temperature
+			s.LastBattery = last.Battery
+			s.LastDate = last.Date.Format(sgDateFormat)
+		}
+		devices = append(devices, s)
+	}
+	r.lock.Unlock()
+	sort.SliceStable(devices, func(i, j int) bool {
+		return devices[i].Name < devices[j].Name
+	})
+	return c.Render(http.StatusOK, "hydrometers.html", map[string]any{
+		"Title":       "Hydrometers",
+		"Subtitle":    "Wireless hydrometers",
+		"Devices":     devices,
+		"Fermenting":  fermenting,
+		"ISpindelURL": c.Echo().Reverse("postISpindelReading"),
+		"TiltURL":     c.Echo().Reverse("postTiltReading"),
+	})
+}
+
+// postAssignHandler assigns a hydrometer to the fermentation of a recipe
+func (r *HydrometerRouter) postAssignHandler(c echo.Context) error {
+	if r.HydrometerStore == nil {
+		return errors.New("hydrometer store not configured")
+	}
+	d := r.findDevice(c.Param("device"))
+	if d == nil {
+		return fmt.Errorf("hydrometer %s not found", c.Param("device"))
+	}
+	var req ReqPostAssign
+	err := c.Bind(&req)
+	if err != nil {
+		return err
+	}
+	err = r.HydrometerStore.AssignDevice(d.Name, req.RecipeID)
+	if err != nil {
+		return err
+	}
+	return c.Redirect(http.StatusFound, c.Echo().Reverse("getHydrometers"))
+}
+
+// postISpindelHandler receives a reading sent by an iSpindel with the generic HTTP JSON payload
+func (r *HydrometerRouter) postISpindelHandler(c echo.Context) error {
+	var req hydrometer.ISpindelPayload
+	err := c.Bind(&req)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, RespError{Error: err.Error()})
+	}
+	reading, err := req.ToReading(time.Now())
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, RespError{Error: err.Error()})
+	}
+	return r.receiveReading(c, hydrometer.DeviceTypeISpindel, reading)
+}
+
+// postTiltHandler receives a reading sent by a Tilt through TiltPi or the cloud logging of the Tilt app
+func (r *HydrometerRouter) postTiltHandler(c echo.Context) error {
+	var req hydrometer.TiltPayload
+	err := c.Bind(&req)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, RespError{Error: err.Error()})
+	}
+	reading, err := req.ToReading(time.Now())
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, RespError{Error: err.Error()})
+	}
+	return r.receiveReading(c, hydrometer.DeviceTypeTilt, reading)
+}
+
+// receiveReading calibrates a reading and stores it as a measurement of the recipe its device is assigned to
+// Readings are only stored during the main fermentation, at most one per storeInterval
+func (r *HydrometerRouter) receiveReading(c echo.Context, deviceType hydrometer.DeviceType, reading *hydrometer.Reading) error {
+	if r.HydrometerStore == nil || r.Store == nil {
+		return c.JSON(http.StatusInternalServerError, RespError{Error: "hydrometer store not configured"})
+	}
+	d := r.findDevice(reading.Device)
+	if d == nil || d.Type != deviceType {
+		return c.JSON(http.StatusNotFound, RespError{Error: fmt.Sprintf("%s %s is not configured", deviceType, reading.Device)})
+	}
+	r.lock.Lock()
+	if r.lastReadings == nil {
+		r.lastReadings = make(map[string]*hydrometer.Reading)
+	}
+	r.lastReadings[d.Name] = reading
+	r.lock.Unlock()
+	resp := RespReading{
+		Device:      d.Name,
+		SG:          d.Calibrate(reading),
+		Temperature: reading.Temperature,
+	}
+	id, err := r.HydrometerStore.RetrieveDeviceRecipe(d.Name)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, RespError{Error: err.Error()})
+	}
+	if id == "" {
+		return c.JSON(http.StatusOK, resp)
+	}
+	re, err := r.Store.Retrieve(id)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, RespError{Error: err.Error()})
+	}
+	if !mainFermentation(re) || !r.due(id, d.Name, reading.Date) {
+		return c.JSON(http.StatusOK, resp)
+	}
+	err = r.Store.AddMainFermSG(id, &recipe.SGMeasurement{
+		Value:       resp.SG,
+		Date:        reading.Date.Format(sgDateFormat),
+		Temperature: reading.Temperature,
+		Device:      d.Name,
+	})
+	if err != nil {
+		log.Error().Str("id", id).Str("device", d.Name).Err(err).Msg("could not store hydrometer reading")
+		return c.JSON(http.StatusInternalServerError, RespError{Error: err.Error()})
+	}
+	r.lock.Lock()
+	if r.lastStored == nil {
+		r.lastStored = make(map[string]time.Time)
+	}
+	r.lastStored[id+"/"+d.Name] = reading.Date
+	r.lock.Unlock()
+	resp.RecipeID = id
+	resp.Stored = true
+	if r.Completion != nil {
+		completion, err := r.Completion.CheckCompletion(id)
+		if err != nil {
+			log.Error().Str("id", id).Err(err).Msg("could not check whether the fermentation is complete")
+		} else {
+			resp.Complete = completion.Complete
+		}
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// findDevice returns the configured hydrometer with the given name, ignoring the case, or nil
+func (r *HydrometerRouter) findDevice(name string) *hydrometer.Device {
+	for i := range r.Devices {
+		if r.Devices[i].Matches(&hydrometer.Reading{Device: name}) {
+			return &r.Devices[i]
+		}
+	}
+	return nil
+}
+
+// mainFermentation returns whether the recipe is in its main fermentation, after the yeast is pitched and before the
+// dry hopping and the bottling. The gravity measurements are only taken then
+func mainFermentation(re *recipe.Recipe) bool {
+	status, params := re.GetStatus()
+	if status != recipe.RecipeStatusFermenting || len(params) == 0 {
+		return false
+	}
+	return params[0] == "main" || params[0] == "wait"
+}
+
+// due returns whether a reading of a device taken at the given date has to be stored in a recipe
+// It is the case if no reading of the device was stored in the recipe during the last storeInterval
+func (r *HydrometerRouter) due(id, device string, date time.Time) bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	last, ok := r.lastStored[id+"/"+device]
+	return !ok || date.Sub(last) >= storeInterval
+}
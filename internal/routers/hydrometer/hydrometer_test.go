@@ -0,0 +1,49 @@
+package hydrometer
+
+import (
+	"brewday/internal/recipe"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMainFermentation(t *testing.T) {
+	require := require.New(t)
+	type testCase struct {
+		Name     string
+		Status   recipe.RecipeStatus
+		Params   []string
+		Expected bool
+	}
+	testCases := []testCase{
+		{Name: "Main fermentation", Status: recipe.RecipeStatusFermenting, Params: []string{"main"}, Expected: true},
+		{Name: "Waiting for the main fermentation", Status: recipe.RecipeStatusFermenting, Params: []string{"wait"}, Expected: true},
+		{Name: "Pitching the yeast", Status: recipe.RecipeStatusFermenting, Params: []string{"yeast"}},
+		{Name: "Dry hopping", Status: recipe.RecipeStatusFermenting, Params: []string{"dry_hop"}},
+		{Name: "Before bottling", Status: recipe.RecipeStatusFermenting, Params: []string{"pre_bottle"}},
+		{Name: "Without parameters", Status: recipe.RecipeStatusFermenting},
+		{Name: "Bottled", Status: recipe.RecipeStatusBottled, Params: []string{"main"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			re := &recipe.Recipe{}
+			re.SetStatus(tc.Status, tc.Params...)
+			require.Equal(tc.Expected, mainFermentation(re))
+		})
+	}
+}
+
+func TestDue(t *testing.T) {
+	require := require.New(t)
+	r := &HydrometerRouter{}
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	require.True(r.due("1", "Red", now))
+	r.lastStored = map[string]time.Time{"1/Red": now}
+	require.False(r.due("1", "Red", now.Add(15*time.Minute)))
+	require.False(r.due("1", "Red", now.Add(59*time.Minute)))
+	require.True(r.due("1", "Red", now.Add(storeInterval)))
+	// Other devices and recipes are stored independently
+	require.True(r.due("1", "Blue", now.Add(15*time.Minute)))
+	require.True(r.due("2", "Red", now.Add(15*time.Minute)))
+}
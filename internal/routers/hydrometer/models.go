@@ -0,0 +1,75 @@
+package hydrometer
+
+import (
+	"brewday/internal/recipe"
+)
+
+// HydrometerStore represents a component that stores the recipe each wireless hydrometer is assigned to
+type HydrometerStore interface {
+	// AssignDevice assigns a hydrometer to the recipe whose fermentation it measures. An empty recipe id removes the assignment
+	AssignDevice(device, recipeID string) error
+	// RetrieveDeviceRecipe retrieves the id of the recipe a hydrometer is assigned to, empty if it is not assigned
+	RetrieveDeviceRecipe(device string) (string, error)
+	// ListAssignments lists the recipe ids of the assigned hydrometers, by device
+	ListAssignments() (map[string]string, error)
+}
+
+// RecipeStore represents a component that stores recipes
+type RecipeStore interface {
+	// Retrieve retrieves a recipe based on an identifier
+	Retrieve(id string) (*recipe.Recipe, error)
+	// List lists all the recipes
+	List() ([]*recipe.Recipe, error)
+	// AddMainFermSG adds a new specific gravity measurement to a given recipe
+	AddMainFermSG(id string, m *recipe.SGMeasurement) error
+}
+
+// CompletionChecker represents a component that checks whether the main fermentation of a recipe is complete
+type CompletionChecker interface {
+	// CheckCompletion checks whether the main fermentation is complete from its gravity readings, and notifies it the first time
+	CheckCompletion(id string) (*recipe.FermentationCompletion, error)
+}
+
+// ReqPostAssign represents the request for assigning a hydrometer to a recipe
+type ReqPostAssign struct {
+	RecipeID string `json:"recipe_id" form:"recipe_id"`
+}
+
+// RespReading represents the response to a reading sent by a hydrometer
+type RespReading struct {
+	// Device is the configured name of the hydrometer
+	Device string `json:"device"`
+	// RecipeID is the recipe the reading was stored in, empty if it was not stored
+	RecipeID string `json:"recipe_id,omitempty"`
+	// SG is the calibrated specific gravity
+	SG float32 `json:"sg"`
+	// Temperature is the temperature in °C
+	Temperature float32 `json:"temperature"`
+	// Stored is whether the reading was stored as a measurement of the recipe
+	Stored bool `json:"stored"`
+	// Complete is whether the gravity of the recipe is stable and the main fermentation complete
+	Complete bool `json:"complete,omitempty"`
+}
+
+// DeviceStatus is a configured hydrometer with its assignment and its last reading, to show it in the hydrometers page
+type DeviceStatus struct {
+	Name     string
+	Type     string
+	RecipeID string
+	// RecipeName is the name of the assigned recipe
+	RecipeName string
+	// Fermenting is whether the assigned recipe is fermenting, readings are only stored while it is
+	Fermenting bool
+	// Calibrated is whether the device has a calibration polynomial
+	Calibrated bool
+	// LastSG is the calibrated gravity of the last reading, zero if no reading was received since the app started
+	LastSG          float32
+	LastTemperature float32
+	LastBattery     float32
+	LastDate        string
+}
+
+// RespError represents the response to a reading that could not be processed
+type RespError struct {
+	Error string `json:"error"`
+}
@@ -0,0 +1,269 @@
+package import_recipe
+
+import (
+	"archive/zip"
+	"brewday/internal/recipe"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// zipMagic are the first bytes of every zip archive
+var zipMagic = []byte("PK\x03\x04")
+
+const (
+	// maxFileSize is the largest uploaded file, or file in a zip archive, that is read. Recipes are much smaller
+	maxFileSize = 10 << 20
+	// batchExpiry is how long the result of an upload is kept when its recipes are not imported
+	batchExpiry = time.Hour
+)
+
+// ImportBatch is the result of an upload, kept until its recipes are imported or it expires
+type ImportBatch struct {
+	// Entries are the recipes read from the upload
+	Entries []*ImportEntry
+	// Created is when the files were uploaded
+	Created time.Time
+}
+
+// ImportEntry is a recipe read from an uploaded file, together with the problems found while reading it
+type ImportEntry struct {
+	// Source is the file (and archive entry) the recipe was read from
+	Source string
+	// ID is the identifier of the recipe in the temporary cache. It is empty if the recipe could not be parsed
+	ID string
+	// Recipe is the parsed recipe. It is nil if the recipe could not be parsed
+	Recipe *recipe.Recipe
+	// Errors are the problems found while reading the recipe, which prevent it from being imported
+	Errors []string
+	// Validation is the result of validating the parsed recipe. It is nil if the recipe could not be parsed
+	Validation *recipe.ValidationResult
+}
+
+// Valid returns whether the entry can be imported
+func (e *ImportEntry) Valid() bool {
+	return e.Recipe != nil && len(e.Errors) == 0 && !e.Validation.HasErrors()
+}
+
+// storeBatch stores a list of entries in the temporary cache and returns its identifier
+// Batches that expired are removed from the cache, together with the recipes they hold
+func (r *ImportRouter) storeBatch(entries []*ImportEntry) string {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.TempBatches == nil {
+		r.TempBatches = make(map[string]*ImportBatch)
+	}
+	now := time.Now()
+	var expired []string
+	for id, batch := range r.TempBatches {
+		if now.Sub(batch.Created) > batchExpiry {
+			expired = append(expired, id)
+		}
+	}
+	for _, id := range expired {
+		r.removeBatch(id)
+	}
+	id := strconv.FormatInt(now.UnixNano(), 36)
+	r.TempBatches[id] = &ImportBatch{Entries: entries, Created: now}
+	return id
+}
+
+// getBatch retrieves a list of entries from the temporary cache, nil if there is none or it expired
+func (r *ImportRouter) getBatch(id string) []*ImportEntry {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.TempBatches == nil {
+		return nil
+	}
+	batch, ok := r.TempBatches[id]
+	if !ok || time.Since(batch.Created) > batchExpiry {
+		return nil
+	}
+	return batch.Entries
+}
+
+// deleteBatch removes a batch and the recipes it holds from the temporary cache
+func (r *ImportRouter) deleteBatch(id string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.removeBatch(id)
+}
+
+// removeBatch removes a batch and the recipes it holds from the temporary cache. The lock must be held
+// Recipes that were replaced in the cache (e.g. by another upload with the same name) are kept
+func (r *ImportRouter) removeBatch(id string) {
+	batch, ok := r.TempBatches[id]
+	if !ok {
+		return
+	}
+	for _, e := range batch.Entries {
+		if e.ID != "" && r.TempCache[e.ID] == e.Recipe {
+			delete(r.TempCache, e.ID)
+		}
+	}
+	delete(r.TempBatches, id)
+}
+
+// parseUpload parses an uploaded file with the given parser
+// Zip archives are supported, in which case every file in the archive is parsed
+func parseUpload(fileName string, content []byte, parser RecipeParser) []*ImportEntry {
+	if !bytes.HasPrefix(content, zipMagic) {
+		return parseDocument(fileName, content, parser)
+	}
+	archive, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return []*ImportEntry{{Source: fileName, Errors: []string{err.Error()}}}
+	}
+	var entries []*ImportEntry
+	for _, f := range archive.File {
+		base := path.Base(f.Name)
+		if f.FileInfo().IsDir() || strings.HasPrefix(base, ".") || strings.HasPrefix(f.Name, "__MACOSX/") {
+			continue
+		}
+		source := fileName + "/" + f.Name
+		fileContent, err := readZipFile(f)
+		if err != nil {
+			entries = append(entries, &ImportEntry{Source: source, Errors: []string{err.Error()}})
+			continue
+		}
+		entries = append(entries, parseDocument(source, fileContent, parser)...)
+	}
+	if len(entries) == 0 {
+		return []*ImportEntry{{Source: fileName, Errors: []string{"no recipes found in archive"}}}
+	}
+	return entries
+}
+
+// readZipFile returns the content of a file in a zip archive
+func readZipFile(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return readLimited(rc)
+}
+
+// readLimited reads a file, refusing it if it is larger than maxFileSize
+func readLimited(r io.Reader) ([]byte, error) {
+	content, err := io.ReadAll(io.LimitReader(r, maxFileSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(content) > maxFileSize {
+		return nil, fmt.Errorf("file is larger than %d MiB", maxFileSize>>20)
+	}
+	return content, nil
+}
+
+// parseDocument parses every recipe in a document
+// Parsers that support several recipes per document (like BeerXML) return all of them
+func parseDocument(source string, content []byte, parser RecipeParser) []*ImportEntry {
+	multi, ok := parser.(MultiRecipeParser)
+	if !ok {
+		re, err := parser.Parse(string(content))
+		return []*ImportEntry{newEntry(source, re, err)}
+	}
+	recipes, errs, err := multi.ParseAll(string(content))
+	if err != nil {
+		return []*ImportEntry{{Source: source, Errors: []string{err.Error()}}}
+	}
+	entries := make([]*ImportEntry, len(recipes))
+	for i := range recipes {
+		entries[i] = newEntry(source+" #"+strconv.Itoa(i+1), recipes[i], errs[i])
+	}
+	return entries
+}
+
+// newEntry creates an entry for a parsed recipe, matches its yeast against the yeast database and validates it
+// This is done here, so recipes from every parser go through it
+func newEntry(source string, re *recipe.Recipe, err error) *ImportEntry {
+	if err != nil {
+		return &ImportEntry{Source: source, Errors: []string{err.Error()}}
+	}
+	if re == nil {
+		return &ImportEntry{Source: source, Errors: []string{"no recipe found"}}
+	}
+	re.Fermentation.Yeast.MatchStrain()
+	return &ImportEntry{
+		Source:     source,
+		Recipe:     re,
+		Validation: re.Validate(),
+	}
+}
+
+// cacheEntries stores the parsed recipes of a batch in the temporary cache
+// As recipes are identified by name, only the first recipe with a given name is kept
+func (r *ImportRouter) cacheEntries(entries []*ImportEntry) {
+	seen := make(map[string]bool)
+	for _, e := range entries {
+		if e.Recipe == nil {
+			continue
+		}
+		id := idFromRecipe(e.Recipe.Name)
+		if seen[id] {
+			e.Errors = append(e.Errors, "duplicated recipe name in upload")
+			continue
+		}
+		seen[id] = true
+		e.ID = r.storeRecipe(e.Recipe)
+	}
+}
+
+// importSelected stores the selected entries of a batch
+// It returns an error if one of the selected entries cannot be imported, in which case none of them is stored
+func (r *ImportRouter) importSelected(entries []*ImportEntry, selected []string) error {
+	if len(selected) == 0 {
+		return errors.New("no recipes selected")
+	}
+	byID := make(map[string]*ImportEntry, len(entries))
+	for _, e := range entries {
+		if e.ID != "" {
+			byID[e.ID] = e
+		}
+	}
+	// Every selected recipe is checked, as it is in the cache (it may have been scaled), before any is stored
+	seen := make(map[string]bool, len(selected))
+	var ids []string
+	var recipes []*recipe.Recipe
+	for _, id := range selected {
+		e, ok := byID[id]
+		if !ok || !e.Valid() {
+			return errors.New("invalid recipe selected: " + id)
+		}
+		re := r.getRecipe(id)
+		if re == nil {
+			return errors.New("no recipe found: " + id)
+		}
+		validation := re.Validate()
+		if validation.HasErrors() {
+			return validation.Err()
+		}
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+			recipes = append(recipes, re)
+		}
+	}
+	// The recipes already stored are removed again if one fails, so the import can be retried as a whole
+	var stored []string
+	for _, re := range recipes {
+		storedID, err := r.storeImported(re)
+		if err != nil {
+			for _, s := range stored {
+				r.removeImported(s)
+			}
+			return err
+		}
+		stored = append(stored, storedID)
+	}
+	for _, id := range ids {
+		r.deleteRecipe(id)
+	}
+	return nil
+}
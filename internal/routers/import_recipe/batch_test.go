@@ -0,0 +1,179 @@
+package import_recipe
+
+import (
+	"archive/zip"
+	"brewday/internal/recipe"
+	"brewday/internal/recipe/beerxml"
+	"brewday/internal/recipe/mmum"
+	recipe_store_memory "brewday/internal/store/memory"
+	summary_memory "brewday/internal/summary/memory"
+	timeline_memory "brewday/internal/timeline/memory"
+	"bytes"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadLimited(t *testing.T) {
+	require := require.New(t)
+	content, err := readLimited(strings.NewReader("recipe"))
+	require.NoError(err)
+	require.Equal([]byte("recipe"), content)
+	_, err = readLimited(bytes.NewReader(make([]byte, maxFileSize)))
+	require.NoError(err)
+	_, err = readLimited(bytes.NewReader(make([]byte, maxFileSize+1)))
+	require.ErrorContains(err, "larger than")
+}
+
+func TestParseUploadLargeZipMember(t *testing.T) {
+	require := require.New(t)
+	// A small archive can hold a huge (well compressed) file
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	f, err := w.Create("huge.json")
+	require.NoError(err)
+	_, err = f.Write(make([]byte, maxFileSize+1))
+	require.NoError(err)
+	require.NoError(w.Close())
+	require.Less(buf.Len(), maxFileSize)
+	entries := parseUpload("recipes.zip", buf.Bytes(), &mmum.MMUMParser{})
+	require.Len(entries, 1)
+	require.Equal("recipes.zip/huge.json", entries[0].Source)
+	require.Len(entries[0].Errors, 1)
+	require.Contains(entries[0].Errors[0], "larger than")
+}
+
+func TestParseDocumentValidation(t *testing.T) {
+	require := require.New(t)
+	// The recipe is read, its problems are shown field by field instead of as a parse error
+	entries := parseDocument("empty.xml", []byte("<RECIPES><RECIPE><NAME>Empty</NAME></RECIPE></RECIPES>"), &beerxml.BeerXMLParser{})
+	require.Len(entries, 1)
+	require.Empty(entries[0].Errors)
+	require.NotNil(entries[0].Recipe)
+	require.True(entries[0].Validation.HasErrors())
+	require.False(entries[0].Valid())
+}
+
+func TestStoreBatchExpiry(t *testing.T) {
+	require := require.New(t)
+	r := &ImportRouter{}
+	old := &recipe.Recipe{Name: "Old"}
+	r.storeRecipe(old)
+	r.TempBatches = map[string]*ImportBatch{
+		"old": {Entries: []*ImportEntry{{ID: "Old", Recipe: old}}, Created: time.Now().Add(-2 * batchExpiry)},
+	}
+	require.Nil(r.getBatch("old"))
+	id := r.storeBatch([]*ImportEntry{{Source: "new.json"}})
+	require.NotNil(r.getBatch(id))
+	require.NotContains(r.TempBatches, "old")
+	require.Nil(r.getRecipe("Old"))
+}
+
+func TestImportSelectedValidatesFirst(t *testing.T) {
+	require := require.New(t)
+	store := recipe_store_memory.NewMemoryStore()
+	r := &ImportRouter{Store: store}
+	valid := &recipe.Recipe{
+		Name:      "Pale Ale",
+		BatchSize: 20,
+		InitialSG: 1.050,
+		Mashing: recipe.MashInstructions{
+			Malts:           []recipe.Malt{{Name: "Pale", Amount: 4000}},
+			MainWaterVolume: 16,
+			Rasts:           []recipe.Rast{{Temperature: 66, Duration: 60}},
+		},
+		Hopping:      recipe.HopInstructions{TotalCookingTime: 60},
+		Fermentation: recipe.FermentationInstructions{Yeast: recipe.Yeast{Name: "US-05"}},
+	}
+	require.NoError(valid.Validate().Err())
+	entries := []*ImportEntry{
+		{Source: "a.json", Recipe: valid, Validation: valid.Validate()},
+		{Source: "b.json", Recipe: valid, Validation: valid.Validate()},
+	}
+	entries[0].ID = r.storeRecipe(valid)
+	// The second recipe was replaced in the cache by an invalid one after the upload was checked
+	invalid := &recipe.Recipe{Name: "Stout"}
+	entries[1].ID = r.storeRecipe(invalid)
+	err := r.importSelected(entries, []string{entries[0].ID, entries[1].ID})
+	require.Error(err)
+	// Nothing was stored, not even the valid recipe
+	recipes, err := store.List()
+	require.NoError(err)
+	require.Empty(recipes)
+	require.NotNil(r.getRecipe(entries[0].ID))
+}
+
+// failingTimelineStore is a timeline store that fails to add the timeline of the second recipe
+type failingTimelineStore struct {
+	*timeline_memory.TimelineMemoryStore
+	added int
+}
+
+func (s *failingTimelineStore) AddTimeline(recipeID string) error {
+	s.added++
+	if s.added == 2 {
+		return errors.New("timeline store unavailable")
+	}
+	return s.TimelineMemoryStore.AddTimeline(recipeID)
+}
+
+func TestImportSelectedRollsBack(t *testing.T) {
+	require := require.New(t)
+	store := recipe_store_memory.NewMemoryStore()
+	timelines := &failingTimelineStore{TimelineMemoryStore: timeline_memory.NewTimelineMemoryStore()}
+	r := &ImportRouter{Store: store, SummaryRecorderStore: summary_memory.NewSummaryMemoryStore(), TLStore: timelines}
+	var entries []*ImportEntry
+	for _, name := range []string{"Pale Ale", "Amber Ale"} {
+		re := &recipe.Recipe{
+			Name:      name,
+			BatchSize: 20,
+			InitialSG: 1.050,
+			Mashing: recipe.MashInstructions{
+				Malts:           []recipe.Malt{{Name: "Pale", Amount: 4000}},
+				MainWaterVolume: 16,
+				Rasts:           []recipe.Rast{{Temperature: 66, Duration: 60}},
+			},
+			Hopping:      recipe.HopInstructions{TotalCookingTime: 60},
+			Fermentation: recipe.FermentationInstructions{Yeast: recipe.Yeast{Name: "US-05"}},
+		}
+		entries = append(entries, &ImportEntry{ID: r.storeRecipe(re), Recipe: re, Validation: re.Validate()})
+	}
+	err := r.importSelected(entries, []string{entries[0].ID, entries[1].ID})
+	require.ErrorContains(err, "timeline store unavailable")
+	// The first recipe was stored before the second failed, it is removed again together with its timeline
+	recipes, err := store.List()
+	require.NoError(err)
+	require.Empty(recipes)
+	require.Error(timelines.AddEvent(store.CreateID("Pale Ale"), "Brewed"))
+	// Both recipes are still in the cache, so the import can be retried
+	require.NotNil(r.getRecipe(entries[0].ID))
+	require.NotNil(r.getRecipe(entries[1].ID))
+
+	err = r.importSelected(entries, []string{entries[0].ID, entries[1].ID})
+	require.NoError(err)
+	recipes, err = store.List()
+	require.NoError(err)
+	require.Len(recipes, 2)
+	require.Nil(r.getRecipe(entries[0].ID))
+}
+
+func TestTempCacheConcurrency(t *testing.T) {
+	r := &ImportRouter{}
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			id := r.storeRecipe(&recipe.Recipe{Name: "Pale Ale"})
+			batchID := r.storeBatch([]*ImportEntry{{ID: id}})
+			r.getRecipe(id)
+			r.getBatch(batchID)
+			r.deleteBatch(batchID)
+		}()
+	}
+	wg.Wait()
+}
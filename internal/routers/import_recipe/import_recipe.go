@@ -2,19 +2,29 @@ package import_recipe
 
 import (
 	"brewday/internal/recipe"
+	"brewday/internal/recipe/beerjson"
+	"brewday/internal/recipe/beerxml"
 	"brewday/internal/recipe/braureka_json"
 	"brewday/internal/recipe/mmum"
 	"brewday/internal/routers/common"
 	"brewday/internal/tools"
 	"errors"
-	"io"
+	"fmt"
 	"net/http"
 	"net/url"
+	"strconv"
+	"sync"
 
 	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
 )
 
+// defaultEfficiency is the brewhouse efficiency (in %) used to check the recipe values when none is given
+const defaultEfficiency = 65
+
 var parsers = map[string]RecipeParser{
+	"beerjson":      &beerjson.BeerJSONParser{},
+	"beerxml":       &beerxml.BeerXMLParser{},
 	"braureka_json": &braureka_json.BraurekaJSONParser{},
 	"mmum":          &mmum.MMUMParser{},
 }
@@ -23,36 +33,69 @@ type ImportRouter struct {
 	Store                RecipeStore
 	SummaryRecorderStore SummaryStore
 	TLStore              TimelineStore
+	Equipment            EquipmentStore
 	TempCache            map[string]*recipe.Recipe
+	TempBatches          map[string]*ImportBatch
+	// lock protects the temporary cache and batches, which are used by concurrent requests
+	lock sync.Mutex
 }
 
 // storeRecipe stores a recipe in the temporary cache
 func (r *ImportRouter) storeRecipe(re *recipe.Recipe) string {
+	id := idFromRecipe(re.Name)
+	r.replaceRecipe(id, re)
+	return id
+}
+
+// replaceRecipe stores a recipe in the temporary cache with the given identifier
+func (r *ImportRouter) replaceRecipe(id string, re *recipe.Recipe) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
 	if r.TempCache == nil {
 		r.TempCache = make(map[string]*recipe.Recipe)
 	}
-	id := idFromRecipe(re.Name)
 	r.TempCache[id] = re
-	return id
 }
 
 // getRecipe retrieves a recipe from the temporary cache
 func (r *ImportRouter) getRecipe(id string) *recipe.Recipe {
+	r.lock.Lock()
+	defer r.lock.Unlock()
 	if r.TempCache == nil {
 		return nil
 	}
 	return r.TempCache[id]
 }
 
+// deleteRecipe removes a recipe from the temporary cache
+func (r *ImportRouter) deleteRecipe(id string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	delete(r.TempCache, id)
+}
+
 func (r *ImportRouter) RegisterRoutes(root *echo.Echo, parent *echo.Group) {
 	imp := parent.Group("/import")
 	imp.GET("", r.getImportHandler).Name = "getImport"
 	imp.POST("/preview", r.postImportPreviewHandler).Name = "postImportPreview"
+	imp.POST("/batch/:batch_id", r.postImportBatchHandler).Name = "postImportBatch"
+	imp.POST("/scale/:recipe_id", r.postImportScaleHandler).Name = "postImportScale"
 	imp.GET("/:recipe_id/:next_action", r.getImportNextHandler).Name = "getImportNext"
 }
 
 // getImportHandler is the handler for the import page
+// It shows either a single recipe (query parameter recipe) or the result of a batch import (query parameter batch)
 func (r *ImportRouter) getImportHandler(c echo.Context) error {
+	batchID := c.QueryParam("batch")
+	if batch := r.getBatch(batchID); batch != nil {
+		return c.Render(200, "import.html", map[string]interface{}{
+			"Title":       "Import Recipe",
+			"Recipe":      nil,
+			"Batch":       batch,
+			"BatchID":     batchID,
+			"SquareColor": "#000000",
+		})
+	}
 	id := c.QueryParam("recipe")
 	re := r.getRecipe(id)
 	if re == nil {
@@ -62,52 +105,125 @@ func (r *ImportRouter) getImportHandler(c echo.Context) error {
 			"SquareColor": "#000000",
 		})
 	}
+	efficiency, method, err := r.estimationParams(c)
+	if err != nil {
+		return err
+	}
+	estimation, err := re.Estimate(efficiency, method)
+	if err != nil {
+		return err
+	}
 	return c.Render(200, "import.html", map[string]interface{}{
 		"Title":       "Import Recipe",
 		"Recipe":      re,
 		"RecipeID":    id,
+		"Validation":  re.Validate(),
+		"Estimation":  estimation,
+		"Deviations":  estimation.Deviations(re),
+		"Efficiency":  efficiency,
+		"IBUMethod":   method,
 		"SquareColor": tools.EBCtoHex(re.ColorEBC),
+		// TargetEfficiency is the efficiency the recipe is scaled to by default
+		"TargetEfficiency": r.targetEfficiency(efficiency),
 	})
 }
 
-// postImportPreviewHandler is the handler for the import form preview
-func (r *ImportRouter) postImportPreviewHandler(c echo.Context) error {
-	file, err := c.FormFile("recipe_file")
-	if err != nil {
-		return err
+// targetEfficiency returns the efficiency of the active equipment profile, the one the recipes are scaled to
+// The given efficiency is returned if there is no active profile or it does not have one
+func (r *ImportRouter) targetEfficiency(efficiency float32) float32 {
+	if r.Equipment == nil {
+		return efficiency
 	}
-	src, err := file.Open()
+	p, err := r.Equipment.RetrieveActiveProfile()
 	if err != nil {
-		return err
+		log.Error().Err(err).Msg("could not retrieve active equipment profile")
+		return efficiency
+	}
+	if p == nil || p.Efficiency <= 0 {
+		return efficiency
 	}
-	defer src.Close()
-	bytes, err := io.ReadAll(src)
+	return p.Efficiency
+}
+
+// estimationParams returns the efficiency and bitterness method used to check the recipe values
+// They are given in the query parameters efficiency and ibu_method, and default to 65% and Tinseth
+func (r *ImportRouter) estimationParams(c echo.Context) (float32, recipe.IBUMethod, error) {
+	var efficiency float32 = defaultEfficiency
+	if raw := c.QueryParam("efficiency"); raw != "" {
+		parsed, err := strconv.ParseFloat(raw, 32)
+		if err != nil {
+			return 0, "", err
+		}
+		efficiency = float32(parsed)
+	}
+	method := recipe.IBUTinseth
+	if raw := c.QueryParam("ibu_method"); raw != "" {
+		method = recipe.IBUMethod(raw)
+	}
+	return efficiency, method, nil
+}
+
+// postImportPreviewHandler is the handler for the import form preview
+// One or more files can be uploaded. Each of them can contain several recipes or be a zip archive of recipe files
+// If a single valid recipe is found, it is shown directly. Otherwise, a table with all recipes and their problems is shown
+func (r *ImportRouter) postImportPreviewHandler(c echo.Context) error {
+	form, err := c.MultipartForm()
 	if err != nil {
 		return err
 	}
+	files := form.File["recipe_file"]
+	if len(files) == 0 {
+		return errors.New("no recipe file provided")
+	}
 	parserType := c.FormValue("parser_type")
 	parser, ok := parsers[parserType]
 	if !ok {
 		return errors.New("invalid parser type")
 	}
-	recipe, err := parser.Parse(string(bytes))
-	if err != nil {
-		return err
+	var entries []*ImportEntry
+	for _, file := range files {
+		src, err := file.Open()
+		if err != nil {
+			return err
+		}
+		bytes, err := readLimited(src)
+		src.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %w", file.Filename, err)
+		}
+		entries = append(entries, parseUpload(file.Filename, bytes, parser)...)
 	}
-	id := r.storeRecipe(recipe)
-	redirect := "getImport"
-	idEncoded := url.QueryEscape(id)
-	queryParams := "?recipe=" + idEncoded
-	return c.Redirect(http.StatusFound, c.Echo().Reverse(redirect)+queryParams)
+	r.cacheEntries(entries)
+	if len(entries) == 1 && entries[0].Valid() {
+		idEncoded := url.QueryEscape(entries[0].ID)
+		return c.Redirect(http.StatusFound, c.Echo().Reverse("getImport")+"?recipe="+idEncoded)
+	}
+	batchID := r.storeBatch(entries)
+	return c.Redirect(http.StatusFound, c.Echo().Reverse("getImport")+"?batch="+batchID)
 }
 
-// idFromRecipe returns the identifier of a recipe based on its name
-func idFromRecipe(name string) string {
-	return name
+// postImportBatchHandler is the handler for importing the selected recipes of a batch
+func (r *ImportRouter) postImportBatchHandler(c echo.Context) error {
+	batchID := c.Param("batch_id")
+	batch := r.getBatch(batchID)
+	if batch == nil {
+		return errors.New("no import batch found")
+	}
+	form, err := c.FormParams()
+	if err != nil {
+		return err
+	}
+	err = r.importSelected(batch, form["recipe"])
+	if err != nil {
+		return err
+	}
+	r.deleteBatch(batchID)
+	return c.Redirect(http.StatusFound, c.Echo().Reverse("getRecipes"))
 }
 
-// getImportNextHandler is the handler for importing and starting a recipe (or continuing)
-func (r *ImportRouter) getImportNextHandler(c echo.Context) error {
+// postImportScaleHandler is the handler for scaling a recipe before importing it
+// The scaled copy replaces the recipe in the temporary cache, so it is the one that gets stored
+func (r *ImportRouter) postImportScaleHandler(c echo.Context) error {
 	id := c.Param("recipe_id")
 	if id == "" {
 		return common.ErrNoRecipeIDProvided
@@ -116,29 +232,43 @@ func (r *ImportRouter) getImportNextHandler(c echo.Context) error {
 	if err != nil {
 		return err
 	}
-	nextAction := c.Param("next_action")
-	if nextAction == "" {
-		return errors.New("no next action provided")
-	}
 	re := r.getRecipe(decodedID)
 	if re == nil {
 		return errors.New("no recipe found")
 	}
-	id, err = r.Store.Store(re)
+	var req ReqPostScale
+	err = c.Bind(&req)
 	if err != nil {
 		return err
 	}
-	err = r.Store.UpdateStatus(id, recipe.RecipeStatusCreated)
+	scaled, err := re.Scale(req.BatchSize, req.Efficiency, req.TargetEfficiency)
 	if err != nil {
 		return err
 	}
-	// Once stored, we can delete it from the cache
-	delete(r.TempCache, decodedID)
-	err = r.SummaryRecorderStore.AddSummary(id, re.Name)
+	r.replaceRecipe(decodedID, scaled)
+	return c.Redirect(http.StatusFound, c.Echo().Reverse("getImport")+"?recipe="+url.QueryEscape(decodedID))
+}
+
+// idFromRecipe returns the identifier of a recipe based on its name
+func idFromRecipe(name string) string {
+	return name
+}
+
+// getImportNextHandler is the handler for importing and starting a recipe (or continuing)
+func (r *ImportRouter) getImportNextHandler(c echo.Context) error {
+	id := c.Param("recipe_id")
+	if id == "" {
+		return common.ErrNoRecipeIDProvided
+	}
+	decodedID, err := url.QueryUnescape(id)
 	if err != nil {
 		return err
 	}
-	err = r.TLStore.AddTimeline(id)
+	nextAction := c.Param("next_action")
+	if nextAction == "" {
+		return errors.New("no next action provided")
+	}
+	id, err = r.importRecipe(decodedID)
 	if err != nil {
 		return err
 	}
@@ -151,3 +281,61 @@ func (r *ImportRouter) getImportNextHandler(c echo.Context) error {
 		return errors.New("invalid next action")
 	}
 }
+
+// importRecipe stores a recipe from the temporary cache, together with its summary and timeline
+// Recipes with validation errors are rejected. It returns the identifier of the stored recipe
+func (r *ImportRouter) importRecipe(cacheID string) (string, error) {
+	re := r.getRecipe(cacheID)
+	if re == nil {
+		return "", errors.New("no recipe found")
+	}
+	validation := re.Validate()
+	if validation.HasErrors() {
+		return "", validation.Err()
+	}
+	id, err := r.storeImported(re)
+	if err != nil {
+		return "", err
+	}
+	// Once stored, we can delete it from the cache
+	r.deleteRecipe(cacheID)
+	return id, nil
+}
+
+// storeImported stores a recipe together with its summary and timeline, and returns its identifier
+// If one of them cannot be stored, what was already stored is removed again
+func (r *ImportRouter) storeImported(re *recipe.Recipe) (string, error) {
+	id, err := r.Store.Store(re)
+	if err != nil {
+		return "", err
+	}
+	err = r.Store.UpdateStatus(id, recipe.RecipeStatusCreated)
+	if err == nil {
+		err = r.SummaryRecorderStore.AddSummary(id, re.Name)
+	}
+	if err == nil {
+		err = r.TLStore.AddTimeline(id)
+	}
+	if err != nil {
+		r.removeImported(id)
+		return "", err
+	}
+	return id, nil
+}
+
+// removeImported removes a stored recipe together with its summary and timeline
+// It is used to undo an import that failed, so the errors are only logged
+func (r *ImportRouter) removeImported(id string) {
+	err := r.Store.Delete(id)
+	if err != nil {
+		log.Error().Err(err).Str("id", id).Msg("could not remove imported recipe")
+	}
+	err = r.SummaryRecorderStore.DeleteSummary(id)
+	if err != nil {
+		log.Error().Err(err).Str("id", id).Msg("could not remove summary of imported recipe")
+	}
+	err = r.TLStore.DeleteTimeline(id)
+	if err != nil {
+		log.Error().Err(err).Str("id", id).Msg("could not remove timeline of imported recipe")
+	}
+}
@@ -1,6 +1,9 @@
 package import_recipe
 
-import "brewday/internal/recipe"
+import (
+	"brewday/internal/equipment"
+	"brewday/internal/recipe"
+)
 
 // RecipeParser represents a component that parses recipes
 type RecipeParser interface {
@@ -8,22 +11,46 @@ type RecipeParser interface {
 	Parse(recipe string) (*recipe.Recipe, error)
 }
 
+// MultiRecipeParser represents a component that parses documents containing several recipes
+type MultiRecipeParser interface {
+	RecipeParser
+	// ParseAll parses all the recipes in a document
+	// The returned errors are aligned with the recipes, so one invalid recipe does not prevent reading the others
+	ParseAll(document string) ([]*recipe.Recipe, []error, error)
+}
+
 // RecipeStore represents a component that stores recipes
 type RecipeStore interface {
 	// Store stores a recipe and returns an identifier that can be used to retrieve it
 	Store(recipe *recipe.Recipe) (string, error)
 	// UpdateStatus updates the status of a recipe in the store
 	UpdateStatus(id string, status recipe.RecipeStatus, statusParams ...string) error
+	// Delete deletes a recipe based on an identifier
+	Delete(id string) error
+}
+
+// EquipmentStore represents a component that stores the equipment profiles
+type EquipmentStore interface {
+	// RetrieveActiveProfile retrieves the profile used by default, nil if no profile is active
+	RetrieveActiveProfile() (*equipment.Profile, error)
 }
 
 // SummaryStore represents a component that stores summaries
 // The recipe id is used as key
 type SummaryStore interface {
 	AddSummary(recipeID, title string) error
+	DeleteSummary(recipeID string) error
 }
 
 // TimelineStore represents a component that stores timelines
 // The recipe id is used as key
 type TimelineStore interface {
 	AddTimeline(recipeID string) error
+	DeleteTimeline(recipeID string) error
+}
+
+type ReqPostScale struct {
+	BatchSize        float32 `json:"batch_size" form:"batch_size"`
+	Efficiency       float32 `json:"efficiency" form:"efficiency"`
+	TargetEfficiency float32 `json:"target_efficiency" form:"target_efficiency"`
 }
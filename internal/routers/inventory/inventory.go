@@ -0,0 +1,142 @@
+package inventory
+
+import (
+	"brewday/internal/inventory"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// dateFormat is the format of the best before dates in the forms
+const dateFormat = "2006-01-02"
+
+type InventoryRouter struct {
+	Store InventoryStore
+}
+
+// RegisterRoutes registers the routes for the inventory router
+func (r *InventoryRouter) RegisterRoutes(root *echo.Echo, parent *echo.Group) {
+	inv := parent.Group("/inventory")
+	inv.GET("", r.getInventoryHandler).Name = "getInventory"
+	inv.POST("", r.postItemHandler).Name = "postInventoryItem"
+	inv.POST("/update/:item_id", r.postUpdateItemHandler).Name = "postInventoryUpdate"
+	inv.GET("/delete/:item_id", r.deleteItemHandler).Name = "deleteInventoryItem"
+}
+
+// toItem builds an item of the inventory from the values sent in the form
+func (req *ReqPostItem) toItem() (*inventory.Item, error) {
+	t := inventory.IngredientType(req.Type)
+	if !inventory.ValidIngredientType(t) {
+		return nil, fmt.Errorf("invalid ingredient type %s", req.Type)
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, errors.New("the name of the ingredient can not be empty")
+	}
+	if req.Amount < 0 {
+		return nil, errors.New("the amount of the ingredient can not be negative")
+	}
+	item := &inventory.Item{
+		Type:   t,
+		Name:   name,
+		Amount: req.Amount,
+		Unit:   strings.TrimSpace(req.Unit),
+		Lot:    strings.TrimSpace(req.Lot),
+	}
+	if t == inventory.IngredientHop {
+		item.Alpha = req.Alpha
+	}
+	if req.BestBefore != "" {
+		bestBefore, err := time.Parse(dateFormat, req.BestBefore)
+		if err != nil {
+			return nil, err
+		}
+		item.BestBefore = bestBefore
+	}
+	return item, nil
+}
+
+// getInventoryHandler is the handler for the inventory page
+func (r *InventoryRouter) getInventoryHandler(c echo.Context) error {
+	if r.Store == nil {
+		return errors.New("inventory store not configured")
+	}
+	items, err := r.Store.ListItems()
+	if err != nil {
+		return err
+	}
+	return c.Render(http.StatusOK, "inventory.html", map[string]any{
+		"Title":    "Inventory",
+		"Subtitle": "Ingredients in stock",
+		"Items":    items,
+		"Types":    inventory.IngredientTypes,
+		"Now":      time.Now(),
+	})
+}
+
+// postItemHandler is the handler for adding an item to the inventory
+func (r *InventoryRouter) postItemHandler(c echo.Context) error {
+	if r.Store == nil {
+		return errors.New("inventory store not configured")
+	}
+	var req ReqPostItem
+	err := c.Bind(&req)
+	if err != nil {
+		return err
+	}
+	item, err := req.toItem()
+	if err != nil {
+		return err
+	}
+	_, err = r.Store.AddItem(item)
+	if err != nil {
+		return err
+	}
+	return c.Redirect(http.StatusFound, c.Echo().Reverse("getInventory"))
+}
+
+// postUpdateItemHandler is the handler for changing an item of the inventory
+func (r *InventoryRouter) postUpdateItemHandler(c echo.Context) error {
+	if r.Store == nil {
+		return errors.New("inventory store not configured")
+	}
+	id := c.Param("item_id")
+	if id == "" {
+		return errors.New("no item id provided")
+	}
+	var req ReqPostItem
+	err := c.Bind(&req)
+	if err != nil {
+		return err
+	}
+	item, err := req.toItem()
+	if err != nil {
+		return err
+	}
+	item.ID = id
+	err = r.Store.UpdateItem(item)
+	if err != nil {
+		return err
+	}
+	return c.Redirect(http.StatusFound, c.Echo().Reverse("getInventory"))
+}
+
+// deleteItemHandler is the handler for deleting an item of the inventory
+func (r *InventoryRouter) deleteItemHandler(c echo.Context) error {
+	if r.Store == nil {
+		return errors.New("inventory store not configured")
+	}
+	id := c.Param("item_id")
+	if id == "" {
+		return errors.New("no item id provided")
+	}
+	err := r.Store.DeleteItem(id)
+	if err != nil {
+		return err
+	}
+	return c.Redirect(http.StatusFound, c.Echo().Reverse("getInventory"))
+}
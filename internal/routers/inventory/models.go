@@ -0,0 +1,26 @@
+package inventory
+
+import "brewday/internal/inventory"
+
+// InventoryStore represents a component that stores the ingredients in stock
+type InventoryStore interface {
+	// AddItem adds an item to the inventory and returns its identifier
+	AddItem(item *inventory.Item) (string, error)
+	// UpdateItem replaces an item of the inventory, the identifier of the item is used to find it
+	UpdateItem(item *inventory.Item) error
+	// ListItems lists all the items of the inventory
+	ListItems() ([]*inventory.Item, error)
+	// DeleteItem deletes an item from the inventory
+	DeleteItem(id string) error
+}
+
+// ReqPostItem represents the request for adding or updating an item of the inventory
+type ReqPostItem struct {
+	Type       string  `json:"type" form:"type"`
+	Name       string  `json:"name" form:"name"`
+	Amount     float32 `json:"amount" form:"amount"`
+	Unit       string  `json:"unit" form:"unit"`
+	Lot        string  `json:"lot" form:"lot"`
+	Alpha      float32 `json:"alpha" form:"alpha"`
+	BestBefore string  `json:"best_before" form:"best_before"`
+}
@@ -0,0 +1,212 @@
+package mash
+
+import (
+	"brewday/internal/recipe"
+	"brewday/internal/routers/common"
+	"brewday/internal/tools"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+// Phases of a decoction with a timer. The mash is pulled before the first one and returned after the boil
+const (
+	decoctionPhaseRest = "rest"
+	decoctionPhaseBoil = "boil"
+)
+
+// validDecoctionPhase returns whether the phase is one of the phases of a decoction with a timer
+func validDecoctionPhase(phase string) bool {
+	return phase == decoctionPhaseRest || phase == decoctionPhaseBoil
+}
+
+// decoctionTimerPrefix returns the prefix of the timer of a decoction phase
+func decoctionTimerPrefix(phase string) string {
+	return "mashing_decoction_" + phase
+}
+
+// firstDecoctionPhase returns the first phase of a decoction, the boil if the pulled mash does not rest
+func firstDecoctionPhase(d *recipe.Decoction) string {
+	if d.RestDuration > 0 {
+		return decoctionPhaseRest
+	}
+	return decoctionPhaseBoil
+}
+
+// decoctionPhaseDuration returns the duration in minutes of a phase of a decoction
+// If the recipe does not give the boil duration, the default one is used
+func decoctionPhaseDuration(d *recipe.Decoction, phase string) float32 {
+	if phase == decoctionPhaseRest {
+		return d.RestDuration
+	}
+	if d.BoilDuration > 0 {
+		return d.BoilDuration
+	}
+	return tools.DecoctionBoilDuration
+}
+
+// runsDecoction returns the index of the decoction that has to be done to reach a rast, or -1 if there is none
+// Decoctions are only done when the recipe is mashed by decoction and the brewer kept it when mashing in
+func (r *MashRouter) runsDecoction(id string, re *recipe.Recipe, rastNum int) (int, error) {
+	d := re.Mashing.DecoctionForRast(rastNum)
+	if d < 0 {
+		return -1, nil
+	}
+	heating, err := r.getHeating(id, re)
+	if err != nil {
+		return -1, err
+	}
+	if heating != tools.MashHeatingDecoction {
+		return -1, nil
+	}
+	return d, nil
+}
+
+// getDecoction returns the decoction with the given number and the phase from the request
+func getDecoction(c echo.Context, re *recipe.Recipe) (int, *recipe.Decoction, string, error) {
+	decNumStr := c.Param("decoction_num")
+	if decNumStr == "" {
+		return 0, nil, "", errors.New("no decoction number provided")
+	}
+	decNum, err := strconv.Atoi(decNumStr)
+	if err != nil {
+		return 0, nil, "", err
+	}
+	if decNum < 0 || decNum >= len(re.Mashing.Decoctions) {
+		return 0, nil, "", fmt.Errorf("recipe has no decoction number %d", decNum)
+	}
+	phase := c.Param("phase")
+	if !validDecoctionPhase(phase) {
+		return 0, nil, "", errors.New("invalid decoction phase " + phase)
+	}
+	return decNum, &re.Mashing.Decoctions[decNum], phase, nil
+}
+
+// getDecoctionHandler is the handler for the decoction page
+// The pulled mash rests (if the recipe says so) and boils with a timer each, before being returned to the main mash
+func (r *MashRouter) getDecoctionHandler(c echo.Context) error {
+	id := c.Param("recipe_id")
+	if id == "" {
+		return common.ErrNoRecipeIDProvided
+	}
+	re, err := r.Store.Retrieve(id)
+	if err != nil {
+		return err
+	}
+	decNum, decoction, phase, err := getDecoction(c, re)
+	if err != nil {
+		return err
+	}
+	rast, err := getRast(re, decoction.Rast)
+	if err != nil {
+		return err
+	}
+	decNumStr := strconv.Itoa(decNum)
+	err = r.Store.UpdateStatus(id, recipe.RecipeStatusMashing, "decoction", decNumStr, phase)
+	if err != nil {
+		return err
+	}
+	started, stopped, err := r.Timer.GetBoolFlags(id, decoctionTimerPrefix(phase), decNumStr)
+	if err != nil {
+		return err
+	}
+	step := r.planMashSteps(id, re, tools.MashHeatingDecoction)[decoction.Rast]
+	return c.Render(200, "mash_decoction.html", map[string]interface{}{
+		"Title":            "Mash " + re.Name,
+		"RecipeID":         id,
+		"DecoctionNumber":  decNum,
+		"Decoction":        decoction,
+		"Phase":            phase,
+		"Pull":             phase == firstDecoctionPhase(decoction),
+		"Volume":           step.Decoction,
+		"Duration":         decoctionPhaseDuration(decoction, phase),
+		"Rast":             rast,
+		"FromTemp":         step.FromTemp,
+		"StartClickedOnce": started,
+		"Stopped":          stopped,
+	})
+}
+
+// postDecoctionHandler is the handler to finish a phase of a decoction
+// After the rest the decoction is boiled, after the boil it is returned to the main mash and the rast starts
+func (r *MashRouter) postDecoctionHandler(c echo.Context) error {
+	id := c.Param("recipe_id")
+	if id == "" {
+		return common.ErrNoRecipeIDProvided
+	}
+	re, err := r.Store.Retrieve(id)
+	if err != nil {
+		return err
+	}
+	decNum, decoction, phase, err := getDecoction(c, re)
+	if err != nil {
+		return err
+	}
+	if phase == decoctionPhaseRest {
+		return c.Redirect(http.StatusFound, c.Echo().Reverse("getDecoction", id, decNum, decoctionPhaseBoil))
+	}
+	err = r.addTimelineEvent(id, fmt.Sprintf("Returned decoction %d", decNum))
+	if err != nil {
+		log.Error().Str("id", id).Err(err).Msg("could not add timeline event")
+	}
+	return c.Redirect(http.StatusFound, c.Echo().Reverse("getRasts", id, decoction.Rast))
+}
+
+// getDecoctionTimestamp returns the final timestamp for the frontend timers for a decoction phase
+func (r *MashRouter) getDecoctionTimestamp(c echo.Context) error {
+	id := c.Param("recipe_id")
+	if id == "" {
+		return common.ErrNoRecipeIDProvided
+	}
+	re, err := r.Store.Retrieve(id)
+	if err != nil {
+		return err
+	}
+	decNum, decoction, phase, err := getDecoction(c, re)
+	if err != nil {
+		return err
+	}
+	duration := time.Duration(decoctionPhaseDuration(decoction, phase) * float32(time.Minute))
+	return r.Timer.HandleStartTimer(c, id, duration, decoctionTimerPrefix(phase), strconv.Itoa(decNum))
+}
+
+// postDecoctionStopTimer handles the post request when the timer of a decoction phase stops
+func (r *MashRouter) postDecoctionStopTimer(c echo.Context) error {
+	id := c.Param("recipe_id")
+	if id == "" {
+		return common.ErrNoRecipeIDProvided
+	}
+	decNumStr := c.Param("decoction_num")
+	if decNumStr == "" {
+		return errors.New("no decoction number provided")
+	}
+	phase := c.Param("phase")
+	if !validDecoctionPhase(phase) {
+		return errors.New("invalid decoction phase " + phase)
+	}
+	tlEvent := fmt.Sprintf("Stopped decoction %s %s", decNumStr, phase)
+	notMessage := fmt.Sprintf("Finished decoction %s %s", decNumStr, phase)
+	return r.Timer.HandleStopTimer(c, id, tlEvent, notMessage, "Decoction Finished", decoctionTimerPrefix(phase), decNumStr)
+}
+
+// getDecoctionRealDuration handles the get request to send the real duration of a decoction phase
+func (r *MashRouter) getDecoctionRealDuration(c echo.Context) error {
+	id := c.Param("recipe_id")
+	if id == "" {
+		return common.ErrNoRecipeIDProvided
+	}
+	decNumStr := c.Param("decoction_num")
+	if decNumStr == "" {
+		return errors.New("no decoction number provided")
+	}
+	phase := c.Param("phase")
+	if !validDecoctionPhase(phase) {
+		return errors.New("invalid decoction phase " + phase)
+	}
+	return r.Timer.HandleRealDuration(c, id, decoctionTimerPrefix(phase), decNumStr)
+}
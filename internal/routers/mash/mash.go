@@ -1,10 +1,12 @@
 package mash
 
 import (
+	"brewday/internal/inventory"
 	"brewday/internal/recipe"
 	"brewday/internal/routers/common"
 	"brewday/internal/tools"
 	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
 	"time"
@@ -18,8 +20,22 @@ type MashRouter struct {
 	TLStore      TimelineStore
 	SummaryStore SummaryStore
 	Timer        Timer
+	Inventory    InventoryStore
+	Equipment    EquipmentStore
+	// SourceWater is the profile of the water used for brewing, it is used to calculate the water treatment
+	SourceWater tools.WaterProfile
+	// GrainTemperature is the default temperature of the grain and the mash tun in °C
+	GrainTemperature float32
+	// TunThermalMass is the default thermal mass of the mash tun in kg of water equivalent
+	// The one of the equipment profile of the brew is used instead when it is set
+	TunThermalMass float32
+	// MashHeating is the default way to heat the mash between rasts
+	MashHeating tools.MashHeating
 }
 
+// inventoryMaltsFlag marks that the malts of a recipe have already been deducted from the inventory
+const inventoryMaltsFlag = "inventory_malts_deducted"
+
 func (r *MashRouter) RegisterRoutes(root *echo.Echo, parent *echo.Group) {
 	mash := parent.Group("/mash")
 	mash.GET("/start/:recipe_id", r.getMashStartHandler).Name = "getMashStart"
@@ -28,6 +44,11 @@ func (r *MashRouter) RegisterRoutes(root *echo.Echo, parent *echo.Group) {
 	mash.GET("/rasts/timer/:recipe_id/:rast_num", r.getRastTimestamp).Name = "getMashRastTimestamp"
 	mash.POST("/rasts/timer/stop/:recipe_id/:rast_num", r.postRastStopTimer).Name = "postMashRastStopTimer"
 	mash.GET("/rasts/timer/duration/:recipe_id/:rast_num", r.getRastRealDuration).Name = "getMashRastDuration"
+	mash.GET("/decoction/:recipe_id/:decoction_num/:phase", r.getDecoctionHandler).Name = "getDecoction"
+	mash.POST("/decoction/:recipe_id/:decoction_num/:phase", r.postDecoctionHandler).Name = "postDecoction"
+	mash.GET("/decoction/timer/:recipe_id/:decoction_num/:phase", r.getDecoctionTimestamp).Name = "getDecoctionTimestamp"
+	mash.POST("/decoction/timer/stop/:recipe_id/:decoction_num/:phase", r.postDecoctionStopTimer).Name = "postDecoctionStopTimer"
+	mash.GET("/decoction/timer/duration/:recipe_id/:decoction_num/:phase", r.getDecoctionRealDuration).Name = "getDecoctionDuration"
 }
 
 // addTimelineEvent adds an event to the timeline
@@ -54,6 +75,115 @@ func (r *MashRouter) addSummaryRast(id string, temp float32, duration float32, n
 	return nil
 }
 
+// consumeMalts deducts the malts of the recipe from the inventory, only once per recipe
+func (r *MashRouter) consumeMalts(id string, re *recipe.Recipe) error {
+	if r.Inventory == nil {
+		return nil
+	}
+	done, err := r.Store.RetrieveBoolFlag(id, inventoryMaltsFlag)
+	if err == nil && done {
+		return nil
+	}
+	for _, malt := range re.Mashing.Malts {
+		missing, err := r.Inventory.Consume(inventory.IngredientMalt, malt.Name, malt.Amount)
+		if err != nil {
+			return err
+		}
+		if missing > 0 {
+			err = r.addTimelineEvent(id, fmt.Sprintf("Not enough %s in the inventory, missing %.1f g", malt.Name, missing))
+			if err != nil {
+				log.Error().Str("id", id).Err(err).Msg("could not add timeline event")
+			}
+		}
+	}
+	return r.Store.AddBoolFlag(id, inventoryMaltsFlag, true)
+}
+
+// defaultHeating returns the way to heat the mash of a recipe when the brewer did not choose one
+// Recipes mashed by decoction are heated by decoction, the rest with the configured heating (direct heating if it is not set)
+func (r *MashRouter) defaultHeating(re *recipe.Recipe) tools.MashHeating {
+	if re.Mashing.IsDecoction() {
+		return tools.MashHeatingDecoction
+	}
+	if tools.ValidMashHeating(r.MashHeating) {
+		return r.MashHeating
+	}
+	return tools.MashHeatingDirect
+}
+
+// storeHeating stores the way the mash of a recipe is heated
+func (r *MashRouter) storeHeating(id string, heating tools.MashHeating) error {
+	err := r.Store.AddBoolFlag(id, "mash_heating_infusion", heating == tools.MashHeatingInfusion)
+	if err != nil {
+		return err
+	}
+	return r.Store.AddBoolFlag(id, "mash_heating_decoction", heating == tools.MashHeatingDecoction)
+}
+
+// getHeating returns the way the mash of a recipe is heated
+// If the brewer did not choose one when mashing in, the default one is returned
+func (r *MashRouter) getHeating(id string, re *recipe.Recipe) (tools.MashHeating, error) {
+	infusion, err := r.Store.RetrieveBoolFlag(id, "mash_heating_infusion")
+	if err != nil {
+		return "", err
+	}
+	if infusion {
+		return tools.MashHeatingInfusion, nil
+	}
+	decoction, err := r.Store.RetrieveBoolFlag(id, "mash_heating_decoction")
+	if err != nil {
+		return "", err
+	}
+	if decoction {
+		return tools.MashHeatingDecoction, nil
+	}
+	return r.defaultHeating(re), nil
+}
+
+// tunThermalMass returns the thermal mass of the mash tun used for a brew
+// The equipment profile of the brew is preferred, the configured one is used if there is none
+func (r *MashRouter) tunThermalMass(id string) float32 {
+	if r.Equipment == nil {
+		return r.TunThermalMass
+	}
+	p, err := r.Equipment.RetrieveBrewProfile(id)
+	if err != nil {
+		log.Error().Str("id", id).Err(err).Msg("could not retrieve equipment profile")
+		return r.TunThermalMass
+	}
+	if p == nil || p.TunThermalMass <= 0 {
+		return r.TunThermalMass
+	}
+	return p.TunThermalMass
+}
+
+// planMashSteps returns the steps to heat the mash of the recipe through all its rasts
+// When heating by decoction, the volumes given by the recipe replace the calculated ones
+func (r *MashRouter) planMashSteps(id string, re *recipe.Recipe, heating tools.MashHeating) []tools.MashStep {
+	rastTemps := make([]float32, 0, len(re.Mashing.Rasts))
+	for _, rast := range re.Mashing.Rasts {
+		rastTemps = append(rastTemps, rast.Temperature)
+	}
+	grainKg := re.Mashing.GetTotalMaltWeight() / 1000
+	steps := tools.PlanMashSteps(heating, grainKg, re.Mashing.MainWaterVolume, re.Mashing.MashTemperature, rastTemps, r.tunThermalMass(id))
+	if heating == tools.MashHeatingDecoction && re.Mashing.IsDecoction() {
+		for _, d := range re.Mashing.Decoctions {
+			if d.Volume > 0 && d.Rast >= 0 && d.Rast < len(steps) {
+				steps[d.Rast].Decoction = d.Volume
+			}
+		}
+	}
+	return steps
+}
+
+// getRast returns the rast with the given number, or an error if the recipe does not have it
+func getRast(re *recipe.Recipe, rastNum int) (*recipe.Rast, error) {
+	if rastNum < 0 || rastNum >= len(re.Mashing.Rasts) {
+		return nil, fmt.Errorf("recipe has no rast number %d", rastNum)
+	}
+	return &re.Mashing.Rasts[rastNum], nil
+}
+
 // getMashStartHandler is the handler for the mash start page
 func (r *MashRouter) getMashStartHandler(c echo.Context) error {
 	id := c.Param("recipe_id")
@@ -64,6 +194,10 @@ func (r *MashRouter) getMashStartHandler(c echo.Context) error {
 	if err != nil {
 		return err
 	}
+	firstRast, err := getRast(re, 0)
+	if err != nil {
+		return err
+	}
 	err = r.Store.UpdateStatus(id, recipe.RecipeStatusMashing, "start")
 	if err != nil {
 		return err
@@ -72,12 +206,38 @@ func (r *MashRouter) getMashStartHandler(c echo.Context) error {
 	if err != nil {
 		log.Error().Str("id", id).Err(err).Msg("could not add timeline event")
 	}
+	// The grain temperature and the heating can be changed by the brewer to recalculate the page
+	grainTemp := r.GrainTemperature
+	if raw := c.QueryParam("grain_temp"); raw != "" {
+		parsed, err := strconv.ParseFloat(raw, 32)
+		if err != nil {
+			return errors.New("invalid grain temperature")
+		}
+		grainTemp = float32(parsed)
+	}
+	heating := r.defaultHeating(re)
+	if raw := c.QueryParam("heating"); raw != "" {
+		heating = tools.MashHeating(raw)
+		if !tools.ValidMashHeating(heating) {
+			return errors.New("invalid mash heating " + raw)
+		}
+	}
+	grainKg := re.Mashing.GetTotalMaltWeight() / 1000
 	return c.Render(200, "mash_start.html", map[string]interface{}{
 		"Title":        "Mash " + re.Name,
 		"MainWater":    re.Mashing.MainWaterVolume,
+		"Nachguss":     re.Mashing.Nachguss,
 		"MashTemp":     re.Mashing.MashTemperature,
-		"NextRastTemp": re.Mashing.Rasts[0].Temperature,
+		"NextRastTemp": firstRast.Temperature,
 		"RecipeID":     id,
+		"Water":        re.WaterAdjustment(r.SourceWater),
+		"SourceWater":  r.SourceWater,
+		"GrainTemp":    grainTemp,
+		"StrikeTemp":   tools.StrikeTemperature(grainKg, re.Mashing.MainWaterVolume, grainTemp, re.Mashing.MashTemperature, r.tunThermalMass(id)),
+		"Heating":      heating,
+		"Heatings":     tools.MashHeatings,
+		"Steps":        r.planMashSteps(id, re, heating),
+		"MashType":     re.Mashing.MashType,
 	})
 }
 
@@ -99,6 +259,23 @@ func (r *MashRouter) getRastsHandler(c echo.Context) error {
 	if err != nil {
 		return err
 	}
+	rast, err := getRast(re, rastNum)
+	if err != nil {
+		return err
+	}
+	heating, err := r.getHeating(id, re)
+	if err != nil {
+		return err
+	}
+	step := r.planMashSteps(id, re, heating)[rastNum]
+	decoction, err := r.runsDecoction(id, re, rastNum)
+	if err != nil {
+		return err
+	}
+	if decoction >= 0 {
+		// The decoction of the recipe has already been pulled, boiled and returned before the rast
+		step.Decoction = 0
+	}
 	missing := re.Mashing.Rasts[rastNum+1:]
 	missingDuration := float32(0.0)
 	if len(missing) > 0 {
@@ -117,7 +294,7 @@ func (r *MashRouter) getRastsHandler(c echo.Context) error {
 	}
 	return c.Render(200, "mash_rasts.html", map[string]interface{}{
 		"Title":                "Mash " + re.Name,
-		"Rast":                 re.Mashing.Rasts[rastNum],
+		"Rast":                 rast,
 		"RastNumber":           rastNum,
 		"NextRast":             nextRastNum,
 		"MissingRasts":         missing,
@@ -126,6 +303,8 @@ func (r *MashRouter) getRastsHandler(c echo.Context) error {
 		"RecipeID":             id,
 		"StartClickedOnce":     started,
 		"Stopped":              stopped,
+		"Heating":              heating,
+		"Step":                 step,
 	})
 }
 
@@ -161,6 +340,18 @@ func (r *MashRouter) postRastsHandler(c echo.Context) error {
 		if err != nil {
 			log.Error().Str("id", id).Err(err).Msg("could not add mash temp to summary")
 		}
+		heating := tools.MashHeating(req.Heating)
+		if !tools.ValidMashHeating(heating) {
+			heating = r.defaultHeating(re)
+		}
+		err = r.storeHeating(id, heating)
+		if err != nil {
+			return err
+		}
+		err = r.consumeMalts(id, re)
+		if err != nil {
+			log.Error().Str("id", id).Err(err).Msg("could not deduct malts from inventory")
+		}
 	} else {
 		var req ReqPostRasts
 		err := c.Bind(&req)
@@ -179,6 +370,14 @@ func (r *MashRouter) postRastsHandler(c echo.Context) error {
 			return c.Redirect(302, c.Echo().Reverse("getLautern", id))
 		}
 	}
+	decoction, err := r.runsDecoction(id, re, rastNum)
+	if err != nil {
+		return err
+	}
+	if decoction >= 0 {
+		phase := firstDecoctionPhase(&re.Mashing.Decoctions[decoction])
+		return c.Redirect(http.StatusFound, c.Echo().Reverse("getDecoction", id, decoction, phase))
+	}
 	return c.Redirect(http.StatusFound, c.Echo().Reverse("getRasts", id, rastNum))
 }
 
@@ -200,7 +399,10 @@ func (r *MashRouter) getRastTimestamp(c echo.Context) error {
 	if err != nil {
 		return err
 	}
-	rast := re.Mashing.Rasts[rastNum]
+	rast, err := getRast(re, rastNum)
+	if err != nil {
+		return err
+	}
 	duration := time.Duration(rast.Duration * float32(time.Minute))
 	return r.Timer.HandleStartTimer(c, id, duration, "mashing_rast", rastNumStr)
 }
@@ -1,6 +1,8 @@
 package mash
 
 import (
+	"brewday/internal/equipment"
+	"brewday/internal/inventory"
 	"brewday/internal/recipe"
 	"time"
 
@@ -30,6 +32,12 @@ type TimelineStore interface {
 	AddEvent(id, message string) error
 }
 
+// EquipmentStore represents a component that stores the equipment profiles
+type EquipmentStore interface {
+	// RetrieveBrewProfile retrieves the equipment profile used to brew a recipe, nil if none was selected
+	RetrieveBrewProfile(recipeID string) (*equipment.Profile, error)
+}
+
 // SummaryStore represents a component that stores summaries
 // The recipe id is used as key
 type SummaryStore interface {
@@ -37,6 +45,12 @@ type SummaryStore interface {
 	AddRast(id string, temp float32, duration float32, notes string) error
 }
 
+// InventoryStore represents a component that stores the ingredients in stock
+type InventoryStore interface {
+	// Consume deducts the given amount (in grams) of an ingredient and returns the amount that was missing
+	Consume(t inventory.IngredientType, name string, amount float32) (float32, error)
+}
+
 type Timer interface {
 	// GetBoolFlags returns whether the timer has started and has been stopped. Only the first suffix is used
 	GetBoolFlags(id string, prefix string, suffix ...string) (bool, bool, error)
@@ -58,4 +72,6 @@ type ReqPostRasts struct {
 type ReqPostFirstRast struct {
 	RealMashTemperature float32 `json:"real_mash_temperature" form:"real_mash_temp"`
 	Notes               string  `json:"notes" form:"notes"`
+	// Heating is the way the mash is heated between rasts (direct, infusion or decoction)
+	Heating string `json:"heating" form:"heating"`
 }
@@ -0,0 +1,343 @@
+package recipes
+
+import (
+	"brewday/internal/recipe"
+	"brewday/internal/routers/common"
+	"brewday/internal/tools"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Hop types used in the recipe form
+const (
+	hopTypeBoil         = "boil"
+	hopTypeVorderwuerze = "vw"
+	hopTypeDryHop       = "dry"
+	hopTypeWhirlpool    = "whirlpool"
+)
+
+// Fermentation step types used in the recipe form
+const (
+	fermStepTypeSet      = "set"
+	fermStepTypeFreeRise = "free_rise"
+)
+
+// errBrewStarted is returned when editing a recipe whose brew has started
+var errBrewStarted = errors.New("recipe cannot be edited once its brew has started")
+
+// ToRecipe builds a recipe from the values sent in the form and matches its yeast against the yeast database
+// It fails if the lists of a certain ingredient do not have the same length
+func (req *ReqPostRecipe) ToRecipe() (*recipe.Recipe, error) {
+	if len(req.MaltNames) != len(req.MaltAmounts) || len(req.MaltNames) != len(req.MaltColors) {
+		return nil, errors.New("malt names, amounts and colors do not match")
+	}
+	if len(req.RastTemperatures) != len(req.RastDurations) {
+		return nil, errors.New("rast temperatures and durations do not match")
+	}
+	if len(req.DecoctionRasts) != len(req.DecoctionVolumes) || len(req.DecoctionRasts) != len(req.DecoctionRestTemps) ||
+		len(req.DecoctionRasts) != len(req.DecoctionRestDurations) || len(req.DecoctionRasts) != len(req.DecoctionBoilDurations) {
+		return nil, errors.New("decoction rasts, volumes, rests and boils do not match")
+	}
+	if len(req.HopNames) != len(req.HopAlphas) || len(req.HopNames) != len(req.HopAmounts) ||
+		len(req.HopNames) != len(req.HopDurations) || len(req.HopNames) != len(req.HopTypes) ||
+		len(req.HopNames) != len(req.HopStandTemperatures) {
+		return nil, errors.New("hop names, alphas, amounts, durations, types and temperatures do not match")
+	}
+	if len(req.FermStepNames) != len(req.FermStepTemperatures) || len(req.FermStepNames) != len(req.FermStepDays) ||
+		len(req.FermStepNames) != len(req.FermStepTypes) {
+		return nil, errors.New("fermentation step names, temperatures, days and types do not match")
+	}
+	hopAdditionals, err := toAdditionalIngredients(req.HopAdditionalNames, req.HopAdditionalAmounts, req.HopAdditionalDuration)
+	if err != nil {
+		return nil, fmt.Errorf("invalid hopping additional ingredients: %w", err)
+	}
+	fermAdditionals, err := toAdditionalIngredients(req.FermAdditionalNames, req.FermAdditionalAmounts, req.FermAdditionalDuration)
+	if err != nil {
+		return nil, fmt.Errorf("invalid fermentation additional ingredients: %w", err)
+	}
+	r := &recipe.Recipe{
+		Name:       strings.TrimSpace(req.Name),
+		Style:      strings.TrimSpace(req.Style),
+		BatchSize:  req.BatchSize,
+		InitialSG:  req.InitialSG,
+		Bitterness: req.Bitterness,
+		ColorEBC:   req.ColorEBC,
+		Mashing: recipe.MashInstructions{
+			MainWaterVolume:    req.MainWaterVolume,
+			Nachguss:           req.Nachguss,
+			MashTemperature:    req.MashTemperature,
+			MashOutTemperature: req.MashOutTemperature,
+			MashType:           recipe.ParseMashType(req.MashType),
+		},
+		Hopping: recipe.HopInstructions{
+			TotalCookingTime:      req.TotalCookingTime,
+			AdditionalIngredients: hopAdditionals,
+		},
+		Fermentation: recipe.FermentationInstructions{
+			Yeast: recipe.Yeast{
+				Name:   strings.TrimSpace(req.YeastName),
+				Amount: req.YeastAmount,
+			},
+			Temperature:           strings.TrimSpace(req.FermentationTemp),
+			AdditionalIngredients: fermAdditionals,
+			Carbonation:           req.Carbonation,
+		},
+	}
+	for i, name := range req.MaltNames {
+		r.Mashing.Malts = append(r.Mashing.Malts, recipe.Malt{
+			Name:   strings.TrimSpace(name),
+			Amount: req.MaltAmounts[i],
+			Color:  req.MaltColors[i],
+		})
+	}
+	for i, temp := range req.RastTemperatures {
+		r.Mashing.Rasts = append(r.Mashing.Rasts, recipe.Rast{
+			Temperature: temp,
+			Duration:    req.RastDurations[i],
+		})
+	}
+	for i, rast := range req.DecoctionRasts {
+		r.Mashing.Decoctions = append(r.Mashing.Decoctions, recipe.Decoction{
+			Rast:            rast - 1,
+			Volume:          req.DecoctionVolumes[i],
+			RestTemperature: req.DecoctionRestTemps[i],
+			RestDuration:    req.DecoctionRestDurations[i],
+			BoilDuration:    req.DecoctionBoilDurations[i],
+		})
+	}
+	for i, name := range req.HopNames {
+		h := recipe.Hops{
+			Name:     strings.TrimSpace(name),
+			Alpha:    req.HopAlphas[i],
+			Amount:   req.HopAmounts[i],
+			Duration: req.HopDurations[i],
+		}
+		switch req.HopTypes[i] {
+		case hopTypeBoil:
+		case hopTypeVorderwuerze:
+			h.Vorderwuerze = true
+		case hopTypeDryHop:
+			h.DryHop = true
+			h.Duration = 0
+		case hopTypeWhirlpool:
+			// The duration of a whirlpool hop is its steep time
+			h.Whirlpool = true
+			h.StandTime = h.Duration
+			h.StandTemperature = req.HopStandTemperatures[i]
+			h.Duration = 0
+		default:
+			return nil, fmt.Errorf("invalid hop type %s for %s", req.HopTypes[i], name)
+		}
+		r.Hopping.Hops = append(r.Hopping.Hops, h)
+	}
+	for i, name := range req.FermStepNames {
+		step := recipe.FermentationStep{
+			Name:        strings.TrimSpace(name),
+			Temperature: req.FermStepTemperatures[i],
+			Days:        req.FermStepDays[i],
+		}
+		switch req.FermStepTypes[i] {
+		case fermStepTypeSet:
+		case fermStepTypeFreeRise:
+			step.FreeRise = true
+		default:
+			return nil, fmt.Errorf("invalid fermentation step type %s for %s", req.FermStepTypes[i], name)
+		}
+		r.Fermentation.Schedule = append(r.Fermentation.Schedule, step)
+	}
+	r.Fermentation.Yeast.MatchStrain()
+	return r, nil
+}
+
+// toAdditionalIngredients builds a list of additional ingredients from parallel lists of names, amounts and durations
+func toAdditionalIngredients(names []string, amounts, durations []float32) ([]recipe.AdditionalIngredient, error) {
+	if len(names) != len(amounts) || len(names) != len(durations) {
+		return nil, errors.New("names, amounts and durations do not match")
+	}
+	var ingredients []recipe.AdditionalIngredient
+	for i, name := range names {
+		ingredients = append(ingredients, recipe.AdditionalIngredient{
+			Name:     strings.TrimSpace(name),
+			Amount:   amounts[i],
+			Duration: durations[i],
+		})
+	}
+	return ingredients, nil
+}
+
+// keepYeastData keeps the lab and attenuation of the stored yeast (e.g. read from a BeerXML file) that are not part
+// of the form, as long as the yeast is the same and matched no strain of the yeast database
+func keepYeastData(y *recipe.Yeast, stored recipe.Yeast) {
+	if y.KnownAttenuation() || !strings.EqualFold(strings.TrimSpace(y.Name), strings.TrimSpace(stored.Name)) {
+		return
+	}
+	name, amount := y.Name, y.Amount
+	*y = stored
+	y.Name = name
+	y.Amount = amount
+}
+
+// addTimelineEvent adds an event to the timeline
+func (r *RecipesRouter) addTimelineEvent(id, message string) error {
+	if r.TLStore != nil {
+		return r.TLStore.AddEvent(id, message)
+	}
+	return nil
+}
+
+// renderRecipeForm renders the recipe form with the validation problems found (if any)
+// The form is sent to the given action URL
+func (r *RecipesRouter) renderRecipeForm(c echo.Context, title, action string, re *recipe.Recipe, validation *recipe.ValidationResult) error {
+	return c.Render(http.StatusOK, "recipe_edit.html", map[string]interface{}{
+		"Title":       title,
+		"Subtitle":    re.Name,
+		"Recipe":      re,
+		"Action":      action,
+		"Validation":  validation,
+		"Efficiency":  r.planningEfficiency(),
+		"SquareColor": tools.EBCtoHex(re.ColorEBC),
+	})
+}
+
+// getEditHandler is the handler for the recipe edit page
+// Only recipes whose brew has not started can be edited
+func (r *RecipesRouter) getEditHandler(c echo.Context) error {
+	id := c.Param("recipe_id")
+	if id == "" {
+		return common.ErrNoRecipeIDProvided
+	}
+	re, err := r.Store.Retrieve(id)
+	if err != nil {
+		return err
+	}
+	if !re.Editable() {
+		return errBrewStarted
+	}
+	return r.renderRecipeForm(c, "Edit Recipe", c.Echo().Reverse("postRecipeEdit", id), re, re.Validate())
+}
+
+// postEditHandler is the handler for saving the changes done to a recipe
+// The recipe is only stored if it has no validation errors, otherwise the form is shown again with the problems found
+// Once the brew has started, the steps refer to the rasts and hops of the recipe, so it cannot be edited anymore
+func (r *RecipesRouter) postEditHandler(c echo.Context) error {
+	id := c.Param("recipe_id")
+	if id == "" {
+		return common.ErrNoRecipeIDProvided
+	}
+	var req ReqPostRecipe
+	err := c.Bind(&req)
+	if err != nil {
+		return err
+	}
+	re, err := req.ToRecipe()
+	if err != nil {
+		return err
+	}
+	stored, err := r.Store.Retrieve(id)
+	if err != nil {
+		return err
+	}
+	if !stored.Editable() {
+		return errBrewStarted
+	}
+	keepYeastData(&re.Fermentation.Yeast, stored.Fermentation.Yeast)
+	validation := re.Validate()
+	if validation.HasErrors() {
+		return r.renderRecipeForm(c, "Edit Recipe", c.Echo().Reverse("postRecipeEdit", id), re, validation)
+	}
+	err = r.Store.Update(id, re)
+	if err != nil {
+		return err
+	}
+	err = r.addTimelineEvent(id, fmt.Sprintf("Edited recipe %s", re.Name))
+	if err != nil {
+		return err
+	}
+	return c.Redirect(http.StatusFound, c.Echo().Reverse("getRecipes"))
+}
+
+// newRecipe returns the recipe shown when creating a recipe from scratch
+// It contains sensible defaults and a row for each list so the form is easier to fill
+func newRecipe() *recipe.Recipe {
+	return &recipe.Recipe{
+		BatchSize: 20,
+		Mashing: recipe.MashInstructions{
+			Malts:              []recipe.Malt{{}},
+			MashTemperature:    67,
+			MashOutTemperature: 78,
+			Rasts:              []recipe.Rast{{Temperature: 66, Duration: 60}},
+		},
+		Hopping: recipe.HopInstructions{
+			TotalCookingTime: 60,
+			Hops:             []recipe.Hops{{Duration: 60}},
+		},
+		Fermentation: recipe.FermentationInstructions{
+			Carbonation: 5,
+		},
+	}
+}
+
+// getNewHandler is the handler for the page to create a recipe from scratch
+func (r *RecipesRouter) getNewHandler(c echo.Context) error {
+	return r.renderRecipeForm(c, "New Recipe", c.Echo().Reverse("postRecipeNew"), newRecipe(), nil)
+}
+
+// postNewHandler is the handler for storing a recipe created from scratch
+// Gravity, bitterness and color are estimated from the ingredients if they are not given
+// As with imported recipes, the recipe is only stored if it has no validation errors
+func (r *RecipesRouter) postNewHandler(c echo.Context) error {
+	var req ReqPostRecipe
+	err := c.Bind(&req)
+	if err != nil {
+		return err
+	}
+	re, err := req.ToRecipe()
+	if err != nil {
+		return err
+	}
+	estimate, err := r.estimateRecipe(re, req.Efficiency)
+	if err != nil {
+		return err
+	}
+	if re.InitialSG == 0 {
+		re.InitialSG = estimate.InitialSG
+	}
+	if re.Bitterness == 0 {
+		re.Bitterness = estimate.Bitterness
+	}
+	if re.ColorEBC == 0 {
+		re.ColorEBC = estimate.ColorEBC
+	}
+	validation := re.Validate()
+	if validation.HasErrors() {
+		return r.renderRecipeForm(c, "New Recipe", c.Echo().Reverse("postRecipeNew"), re, validation)
+	}
+	id, err := r.createBrew(re)
+	if err != nil {
+		return err
+	}
+	return c.Redirect(http.StatusFound, c.Echo().Reverse("getRecipeStart", id))
+}
+
+// postEstimateHandler returns the estimated gravity, bitterness and color of the recipe in the form
+// It is used to show the values live while the recipe is being written
+func (r *RecipesRouter) postEstimateHandler(c echo.Context) error {
+	var req ReqPostRecipe
+	err := c.Bind(&req)
+	if err != nil {
+		return err
+	}
+	re, err := req.ToRecipe()
+	if err != nil {
+		return err
+	}
+	estimate, err := r.estimateRecipe(re, req.Efficiency)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, estimate)
+}
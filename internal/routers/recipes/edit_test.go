@@ -0,0 +1,159 @@
+package recipes
+
+import (
+	"brewday/internal/recipe"
+	recipe_store_memory "brewday/internal/store/memory"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+// decoctionForm returns the values sent by the recipe form of a double decoction recipe
+func decoctionForm() url.Values {
+	return url.Values{
+		"name":                    {"Böhmisches Pils"},
+		"style":                   {"Pils"},
+		"batch_size":              {"20"},
+		"initial_sg":              {"1.048"},
+		"ibu":                     {"38"},
+		"ebc":                     {"8"},
+		"malt_name":               {"Pilsner Malz"},
+		"malt_amount":             {"4200"},
+		"malt_color":              {"3"},
+		"main_water":              {"18"},
+		"nachguss":                {"12"},
+		"mash_temp":               {"52"},
+		"mash_out_temp":           {"76"},
+		"rast_temp":               {"52", "64", "72"},
+		"rast_duration":           {"10", "40", "20"},
+		"mash_type":               {"decoction"},
+		"decoction_rast":          {"2", "3"},
+		"decoction_volume":        {"6.5", "0"},
+		"decoction_rest_temp":     {"72", "0"},
+		"decoction_rest_duration": {"15", "0"},
+		"decoction_boil_duration": {"20", "15"},
+		"cooking_time":            {"90"},
+		"hop_name":                {"Saazer"},
+		"hop_alpha":               {"3.5"},
+		"hop_amount":              {"80"},
+		"hop_duration":            {"90"},
+		"hop_type":                {"boil"},
+		"hop_stand_temperature":   {""},
+		"yeast_name":              {"Saflager W-34/70"},
+		"yeast_amount":            {"23"},
+		"ferm_temp":               {"10"},
+		"carbonation":             {"5"},
+	}
+}
+
+func TestPostEditDecoction(t *testing.T) {
+	require := require.New(t)
+	store := recipe_store_memory.NewMemoryStore()
+	id, err := store.Store(&recipe.Recipe{
+		Name: "Böhmisches Pils",
+		Mashing: recipe.MashInstructions{
+			Rasts:      []recipe.Rast{{Temperature: 52, Duration: 10}, {Temperature: 64, Duration: 40}, {Temperature: 72, Duration: 20}},
+			MashType:   recipe.MashTypeDecoction,
+			Decoctions: []recipe.Decoction{{Rast: 1, Volume: 6.5, RestTemperature: 72, RestDuration: 15, BoilDuration: 20}, {Rast: 2, BoilDuration: 15}},
+		},
+	})
+	require.NoError(err)
+	e := echo.New()
+	r := &RecipesRouter{Store: store}
+	r.RegisterRoutes(e, e.Group(""))
+
+	// The form is sent back with a longer first rast
+	form := decoctionForm()
+	form["rast_duration"] = []string{"15", "40", "20"}
+	req := httptest.NewRequest(http.MethodPost, e.Reverse("postRecipeEdit", id), strings.NewReader(form.Encode()))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	require.Equal(http.StatusFound, rec.Code, rec.Body.String())
+
+	re, err := store.Retrieve(id)
+	require.NoError(err)
+	require.Equal(float32(15), re.Mashing.Rasts[0].Duration)
+	require.Equal(recipe.MashTypeDecoction, re.Mashing.MashType)
+	require.Equal([]recipe.Decoction{
+		{Rast: 1, Volume: 6.5, RestTemperature: 72, RestDuration: 15, BoilDuration: 20},
+		{Rast: 2, BoilDuration: 15},
+	}, re.Mashing.Decoctions)
+}
+
+func TestPostEditKeepsYeastData(t *testing.T) {
+	require := require.New(t)
+	store := recipe_store_memory.NewMemoryStore()
+	// The lab and attenuation of a yeast that is not in the yeast database come from the imported file
+	yeast := recipe.Yeast{Name: "Hefe vom Nachbarn", Amount: 20, Lab: "Nachbar", MinAttenuation: 75, MaxAttenuation: 75}
+	id, err := store.Store(&recipe.Recipe{Name: "Böhmisches Pils", Fermentation: recipe.FermentationInstructions{Yeast: yeast}})
+	require.NoError(err)
+	e := echo.New()
+	r := &RecipesRouter{Store: store}
+	r.RegisterRoutes(e, e.Group(""))
+
+	post := func(yeastName string) recipe.Yeast {
+		form := decoctionForm()
+		form["yeast_name"] = []string{yeastName}
+		req := httptest.NewRequest(http.MethodPost, e.Reverse("postRecipeEdit", id), strings.NewReader(form.Encode()))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		require.Equal(http.StatusFound, rec.Code, rec.Body.String())
+		re, err := store.Retrieve(id)
+		require.NoError(err)
+		return re.Fermentation.Yeast
+	}
+	yeast.Amount = 23
+	require.Equal(yeast, post("Hefe vom Nachbarn"))
+	// Another yeast does not inherit them
+	require.Equal(recipe.Yeast{Name: "Hefe aus dem Keller", Amount: 23}, post("Hefe aus dem Keller"))
+}
+
+func TestEditBrewStarted(t *testing.T) {
+	require := require.New(t)
+	store := recipe_store_memory.NewMemoryStore()
+	id, err := store.Store(&recipe.Recipe{Name: "Böhmisches Pils"})
+	require.NoError(err)
+	err = store.UpdateStatus(id, recipe.RecipeStatusMashing, "rast", "1")
+	require.NoError(err)
+	e := echo.New()
+	r := &RecipesRouter{Store: store}
+	r.RegisterRoutes(e, e.Group(""))
+
+	req := httptest.NewRequest(http.MethodPost, e.Reverse("postRecipeEdit", id), strings.NewReader(decoctionForm().Encode()))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	require.Equal(http.StatusInternalServerError, rec.Code)
+	re, err := store.Retrieve(id)
+	require.NoError(err)
+	require.Empty(re.Mashing.Rasts)
+}
+
+func TestToRecipeDecoctions(t *testing.T) {
+	require := require.New(t)
+	req := ReqPostRecipe{
+		MashType:               "Dekoktion",
+		RastTemperatures:       []float32{52, 64},
+		RastDurations:          []float32{10, 40},
+		DecoctionRasts:         []int{2},
+		DecoctionVolumes:       []float32{0},
+		DecoctionRestTemps:     []float32{0},
+		DecoctionRestDurations: []float32{0},
+		DecoctionBoilDurations: []float32{15},
+	}
+	re, err := req.ToRecipe()
+	require.NoError(err)
+	require.True(re.Mashing.IsDecoction())
+	require.Equal([]recipe.Decoction{{Rast: 1, BoilDuration: 15}}, re.Mashing.Decoctions)
+
+	req.DecoctionBoilDurations = nil
+	_, err = req.ToRecipe()
+	require.Error(err)
+}
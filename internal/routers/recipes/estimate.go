@@ -0,0 +1,44 @@
+package recipes
+
+import (
+	"brewday/internal/recipe"
+
+	"github.com/rs/zerolog/log"
+)
+
+// defaultEfficiency is the brewhouse efficiency (in %) used when none is given and the active equipment profile has none
+const defaultEfficiency = 65
+
+// planningEfficiency returns the brewhouse efficiency (in %) used to plan recipes
+// It is the one of the active equipment profile, or the default one if there is no profile or it does not have it
+func (r *RecipesRouter) planningEfficiency() float32 {
+	if r.Equipment == nil {
+		return defaultEfficiency
+	}
+	p, err := r.Equipment.RetrieveActiveProfile()
+	if err != nil {
+		log.Error().Err(err).Msg("could not retrieve active equipment profile")
+		return defaultEfficiency
+	}
+	if p == nil || p.Efficiency <= 0 {
+		return defaultEfficiency
+	}
+	return p.Efficiency
+}
+
+// estimateRecipe estimates the initial gravity, bitterness and color of a recipe from its ingredients
+// The planning efficiency is used if none is given
+func (r *RecipesRouter) estimateRecipe(re *recipe.Recipe, efficiency float32) (*RespRecipeEstimate, error) {
+	if efficiency == 0 {
+		efficiency = r.planningEfficiency()
+	}
+	e, err := re.Estimate(efficiency, recipe.IBUTinseth)
+	if err != nil {
+		return nil, err
+	}
+	return &RespRecipeEstimate{
+		InitialSG:  e.InitialSG,
+		Bitterness: e.Bitterness,
+		ColorEBC:   e.ColorEBC,
+	}, nil
+}
@@ -0,0 +1,138 @@
+package recipes
+
+import (
+	"brewday/internal/recipe"
+	"brewday/internal/routers/common"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// errNoLibrary is returned when the recipe library is used but not configured
+var errNoLibrary = errors.New("recipe library not configured")
+
+// errNoLibraryIDProvided is returned when a recipe of the library is requested without identifier
+var errNoLibraryIDProvided = errors.New("no library recipe id provided")
+
+// createBrew stores the recipe as a new brew, with a fresh status, summary and timeline
+// It returns the identifier of the new brew
+func (r *RecipesRouter) createBrew(re *recipe.Recipe) (string, error) {
+	id, err := r.Store.Store(re)
+	if err != nil {
+		return "", err
+	}
+	err = r.Store.UpdateStatus(id, recipe.RecipeStatusCreated)
+	if err != nil {
+		return "", err
+	}
+	err = r.SummaryStore.AddSummary(id, re.Name)
+	if err != nil {
+		return "", err
+	}
+	err = r.TLStore.AddTimeline(id)
+	if err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// getCloneHandler is the handler for brewing a stored recipe again
+// The definition of the recipe is copied into a new brew, the original brew is not modified
+func (r *RecipesRouter) getCloneHandler(c echo.Context) error {
+	id := c.Param("recipe_id")
+	if id == "" {
+		return common.ErrNoRecipeIDProvided
+	}
+	re, err := r.Store.Retrieve(id)
+	if err != nil {
+		return err
+	}
+	newID, err := r.createBrew(re.Clone())
+	if err != nil {
+		return err
+	}
+	err = r.addTimelineEvent(newID, fmt.Sprintf("Brewing again recipe %s from brew %s", re.Name, id))
+	if err != nil {
+		return err
+	}
+	return c.Redirect(http.StatusFound, c.Echo().Reverse("getRecipeStart", newID))
+}
+
+// getLibraryHandler is the handler for the recipe library page
+func (r *RecipesRouter) getLibraryHandler(c echo.Context) error {
+	if r.Library == nil {
+		return errNoLibrary
+	}
+	recipes, err := r.Library.ListLibrary()
+	if err != nil {
+		return err
+	}
+	return c.Render(http.StatusOK, "library.html", map[string]interface{}{
+		"Title":    "Library",
+		"Subtitle": "Recipe Library",
+		"Recipes":  recipes,
+	})
+}
+
+// getAddToLibraryHandler is the handler for saving the definition of a stored recipe in the library
+func (r *RecipesRouter) getAddToLibraryHandler(c echo.Context) error {
+	if r.Library == nil {
+		return errNoLibrary
+	}
+	id := c.Param("recipe_id")
+	if id == "" {
+		return common.ErrNoRecipeIDProvided
+	}
+	re, err := r.Store.Retrieve(id)
+	if err != nil {
+		return err
+	}
+	_, err = r.Library.AddToLibrary(re)
+	if err != nil {
+		return err
+	}
+	return c.Redirect(http.StatusFound, c.Echo().Reverse("getLibrary"))
+}
+
+// getBrewFromLibraryHandler is the handler for starting a new brew from a recipe in the library
+func (r *RecipesRouter) getBrewFromLibraryHandler(c echo.Context) error {
+	if r.Library == nil {
+		return errNoLibrary
+	}
+	libraryID := c.Param("library_id")
+	if libraryID == "" {
+		return errNoLibraryIDProvided
+	}
+	re, err := r.Library.RetrieveFromLibrary(libraryID)
+	if err != nil {
+		return err
+	}
+	id, err := r.createBrew(re.Clone())
+	if err != nil {
+		return err
+	}
+	err = r.addTimelineEvent(id, fmt.Sprintf("Brewing recipe %s from the library", re.Name))
+	if err != nil {
+		return err
+	}
+	return c.Redirect(http.StatusFound, c.Echo().Reverse("getRecipeStart", id))
+}
+
+// deleteFromLibraryHandler is the handler for deleting a recipe from the library
+// Brews started from the recipe are not affected
+func (r *RecipesRouter) deleteFromLibraryHandler(c echo.Context) error {
+	if r.Library == nil {
+		return errNoLibrary
+	}
+	libraryID := c.Param("library_id")
+	if libraryID == "" {
+		return errNoLibraryIDProvided
+	}
+	err := r.Library.DeleteFromLibrary(libraryID)
+	if err != nil {
+		return err
+	}
+	return c.Redirect(http.StatusFound, c.Echo().Reverse("getLibrary"))
+}
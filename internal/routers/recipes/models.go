@@ -1,26 +1,132 @@
 package recipes
 
-import "brewday/internal/recipe"
+import (
+	"brewday/internal/equipment"
+	"brewday/internal/inventory"
+	"brewday/internal/recipe"
+)
 
 // RecipeStore represents a component that stores recipes
 type RecipeStore interface {
+	// Store stores a recipe and returns an identifier that can be used to retrieve it
+	Store(recipe *recipe.Recipe) (string, error)
+	// UpdateStatus updates the status of a recipe in the store
+	UpdateStatus(id string, status recipe.RecipeStatus, statusParams ...string) error
 	// List lists all the recipes
 	List() ([]*recipe.Recipe, error)
 	// Retrieve retrieves a recipe based on an identifier
 	Retrieve(id string) (*recipe.Recipe, error)
 	// Delete deletes a recipe based on an identifier
 	Delete(id string) error
+	// Update replaces the definition of a stored recipe, keeping its status and results
+	Update(id string, r *recipe.Recipe) error
+}
+
+// LibraryStore represents a component that stores the recipes that can be brewed again (the recipe library)
+// Recipes in the library are definitions only, they have no status or results and are not brewed directly
+type LibraryStore interface {
+	// AddToLibrary stores a copy of the recipe definition in the library and returns its identifier in the library
+	AddToLibrary(r *recipe.Recipe) (string, error)
+	// ListLibrary lists all the recipes in the library
+	ListLibrary() ([]*recipe.Recipe, error)
+	// RetrieveFromLibrary retrieves a recipe from the library based on its identifier
+	RetrieveFromLibrary(id string) (*recipe.Recipe, error)
+	// DeleteFromLibrary deletes a recipe from the library
+	DeleteFromLibrary(id string) error
+}
+
+// InventoryStore represents a component that stores the ingredients in stock
+type InventoryStore interface {
+	// ListItems lists all the items of the inventory
+	ListItems() ([]*inventory.Item, error)
+}
+
+// EquipmentStore represents a component that stores the equipment profiles
+type EquipmentStore interface {
+	// ListProfiles lists all the equipment profiles
+	ListProfiles() ([]*equipment.Profile, error)
+	// RetrieveBrewProfile retrieves the equipment profile used to brew a recipe, nil if none was selected
+	RetrieveBrewProfile(recipeID string) (*equipment.Profile, error)
+	// RetrieveActiveProfile retrieves the profile used by default, nil if no profile is active
+	RetrieveActiveProfile() (*equipment.Profile, error)
 }
 
 // SummaryStore represents a component that stores summaries
 // The recipe id is used as key
 type SummaryStore interface {
+	AddSummary(recipeID, title string) error
 	DeleteSummary(recipeID string) error
 }
 
 // TimelineStore represents a component that stores timelines
 // The recipe id is used as key
 type TimelineStore interface {
+	// AddEvent adds an event to the timeline
+	AddEvent(id, message string) error
+	// AddTimeline adds a timeline to the store
+	AddTimeline(recipeID string) error
 	// DeleteTimeline deletes the timeline for the given recipe id
 	DeleteTimeline(recipeID string) error
 }
+
+// RecipeExporter represents a component that outputs a recipe as a certain document (string)
+type RecipeExporter interface {
+	Export(r *recipe.Recipe) (string, error)
+}
+
+// ReqPostRecipe represents the request body for the postRecipeEdit handler
+// Lists of ingredients are sent as parallel lists, where the same index refers to the same ingredient
+// The rasts reached by the decoctions are numbered from 1, as they are shown in the form
+type ReqPostRecipe struct {
+	Name                   string    `json:"name" form:"name"`
+	Style                  string    `json:"style" form:"style"`
+	BatchSize              float32   `json:"batch_size" form:"batch_size"`
+	InitialSG              float32   `json:"initial_sg" form:"initial_sg"`
+	Bitterness             float32   `json:"ibu" form:"ibu"`
+	ColorEBC               float32   `json:"ebc" form:"ebc"`
+	MaltNames              []string  `json:"malt_name" form:"malt_name"`
+	MaltAmounts            []float32 `json:"malt_amount" form:"malt_amount"`
+	MaltColors             []float32 `json:"malt_color" form:"malt_color"`
+	MainWaterVolume        float32   `json:"main_water" form:"main_water"`
+	Nachguss               float32   `json:"nachguss" form:"nachguss"`
+	MashTemperature        float32   `json:"mash_temp" form:"mash_temp"`
+	MashOutTemperature     float32   `json:"mash_out_temp" form:"mash_out_temp"`
+	RastTemperatures       []float32 `json:"rast_temp" form:"rast_temp"`
+	RastDurations          []float32 `json:"rast_duration" form:"rast_duration"`
+	MashType               string    `json:"mash_type" form:"mash_type"`
+	DecoctionRasts         []int     `json:"decoction_rast" form:"decoction_rast"`
+	DecoctionVolumes       []float32 `json:"decoction_volume" form:"decoction_volume"`
+	DecoctionRestTemps     []float32 `json:"decoction_rest_temp" form:"decoction_rest_temp"`
+	DecoctionRestDurations []float32 `json:"decoction_rest_duration" form:"decoction_rest_duration"`
+	DecoctionBoilDurations []float32 `json:"decoction_boil_duration" form:"decoction_boil_duration"`
+	TotalCookingTime       float32   `json:"cooking_time" form:"cooking_time"`
+	HopNames               []string  `json:"hop_name" form:"hop_name"`
+	HopAlphas              []float32 `json:"hop_alpha" form:"hop_alpha"`
+	HopAmounts             []float32 `json:"hop_amount" form:"hop_amount"`
+	HopDurations           []float32 `json:"hop_duration" form:"hop_duration"`
+	HopTypes               []string  `json:"hop_type" form:"hop_type"`
+	HopStandTemperatures   []float32 `json:"hop_stand_temperature" form:"hop_stand_temperature"`
+	HopAdditionalNames     []string  `json:"hop_add_name" form:"hop_add_name"`
+	HopAdditionalAmounts   []float32 `json:"hop_add_amount" form:"hop_add_amount"`
+	HopAdditionalDuration  []float32 `json:"hop_add_duration" form:"hop_add_duration"`
+	YeastName              string    `json:"yeast_name" form:"yeast_name"`
+	YeastAmount            float32   `json:"yeast_amount" form:"yeast_amount"`
+	FermentationTemp       string    `json:"ferm_temp" form:"ferm_temp"`
+	FermAdditionalNames    []string  `json:"ferm_add_name" form:"ferm_add_name"`
+	FermAdditionalAmounts  []float32 `json:"ferm_add_amount" form:"ferm_add_amount"`
+	FermAdditionalDuration []float32 `json:"ferm_add_duration" form:"ferm_add_duration"`
+	FermStepNames          []string  `json:"ferm_step_name" form:"ferm_step_name"`
+	FermStepTemperatures   []float32 `json:"ferm_step_temp" form:"ferm_step_temp"`
+	FermStepDays           []float32 `json:"ferm_step_days" form:"ferm_step_days"`
+	FermStepTypes          []string  `json:"ferm_step_type" form:"ferm_step_type"`
+	Carbonation            float32   `json:"carbonation" form:"carbonation"`
+	// Efficiency is the brewhouse efficiency (in %) used to estimate the gravity. It is not stored
+	Efficiency float32 `json:"efficiency" form:"efficiency"`
+}
+
+// RespRecipeEstimate represents the values estimated from the ingredients of a recipe
+type RespRecipeEstimate struct {
+	InitialSG  float32 `json:"initial_sg"`
+	Bitterness float32 `json:"ibu"`
+	ColorEBC   float32 `json:"ebc"`
+}
@@ -1,20 +1,28 @@
 package recipes
 
 import (
+	"brewday/internal/equipment"
+	"brewday/internal/inventory"
 	"brewday/internal/recipe"
+	"brewday/internal/recipe/beerjson"
 	"brewday/internal/routers/common"
 	"brewday/internal/tools"
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/labstack/echo/v4"
 )
 
 type RecipesRouter struct {
 	Store        RecipeStore
+	Library      LibraryStore
 	TLStore      TimelineStore
 	SummaryStore SummaryStore
+	Inventory    InventoryStore
+	Equipment    EquipmentStore
 }
 
 func (r *RecipesRouter) RegisterRoutes(root *echo.Echo, parent *echo.Group) {
@@ -23,6 +31,18 @@ func (r *RecipesRouter) RegisterRoutes(root *echo.Echo, parent *echo.Group) {
 	recipes.GET("/continue/:recipe_id", r.getContinueHandler).Name = "getContinue"
 	recipes.GET("/start/:recipe_id", r.getStartHandler).Name = "getRecipeStart"
 	recipes.GET("/delete/:recipe_id", r.deleteRecipeHandler).Name = "deleteRecipe"
+	recipes.GET("/download/:recipe_id", r.getDownloadHandler).Name = "downloadRecipe"
+	recipes.GET("/edit/:recipe_id", r.getEditHandler).Name = "getRecipeEdit"
+	recipes.POST("/edit/:recipe_id", r.postEditHandler).Name = "postRecipeEdit"
+	recipes.GET("/new", r.getNewHandler).Name = "getRecipeNew"
+	recipes.POST("/new", r.postNewHandler).Name = "postRecipeNew"
+	recipes.POST("/estimate", r.postEstimateHandler).Name = "postRecipeEstimate"
+	recipes.GET("/clone/:recipe_id", r.getCloneHandler).Name = "cloneRecipe"
+	recipes.GET("/library", r.getLibraryHandler).Name = "getLibrary"
+	recipes.GET("/library/add/:recipe_id", r.getAddToLibraryHandler).Name = "addToLibrary"
+	recipes.GET("/library/brew/:library_id", r.getBrewFromLibraryHandler).Name = "brewFromLibrary"
+	recipes.GET("/library/delete/:library_id", r.deleteFromLibraryHandler).Name = "deleteFromLibrary"
+	recipes.GET("/shopping", r.getShoppingListHandler).Name = "getShoppingList"
 }
 
 // getRecipeList returns the list of recipes
@@ -56,14 +76,64 @@ func (r *RecipesRouter) getStartHandler(c echo.Context) error {
 	if err != nil {
 		return err
 	}
+	availability, err := r.checkInventory(re)
+	if err != nil {
+		return err
+	}
+	profiles, selected, err := r.getProfiles(id)
+	if err != nil {
+		return err
+	}
 	return c.Render(200, "recipe_start.html", map[string]interface{}{
-		"Title":       "Starting Recipe",
-		"Recipe":      re,
-		"RecipeID":    id,
-		"SquareColor": tools.EBCtoHex(re.ColorEBC),
+		"Title":           "Starting Recipe",
+		"Recipe":          re,
+		"RecipeID":        id,
+		"SquareColor":     tools.EBCtoHex(re.ColorEBC),
+		"Availability":    availability,
+		"CanBrew":         inventory.CanBrew(availability),
+		"Profiles":        profiles,
+		"SelectedProfile": selected,
 	})
 }
 
+// getProfiles returns the equipment profiles and the identifier of the one selected for the brew
+// The active profile is selected if the brew has none. It returns no profiles if there is no equipment store configured
+func (r *RecipesRouter) getProfiles(id string) ([]*equipment.Profile, string, error) {
+	if r.Equipment == nil {
+		return nil, "", nil
+	}
+	profiles, err := r.Equipment.ListProfiles()
+	if err != nil {
+		return nil, "", err
+	}
+	selected, err := r.Equipment.RetrieveBrewProfile(id)
+	if err != nil {
+		return nil, "", err
+	}
+	if selected != nil {
+		return profiles, selected.ID, nil
+	}
+	for _, p := range profiles {
+		if p.Active {
+			return profiles, p.ID, nil
+		}
+	}
+	return profiles, "", nil
+}
+
+// checkInventory returns the ingredients needed by the recipe with the amount in stock
+// It returns no requirements if there is no inventory configured
+func (r *RecipesRouter) checkInventory(re *recipe.Recipe) ([]inventory.Requirement, error) {
+	if r.Inventory == nil {
+		return nil, nil
+	}
+	items, err := r.Inventory.ListItems()
+	if err != nil {
+		return nil, err
+	}
+	return inventory.Check(re, items, time.Now()), nil
+}
+
 // getContinueHandler is the handler for the continue button on the recipes page
 func (r *RecipesRouter) getContinueHandler(c echo.Context) error {
 	id := c.Param("recipe_id")
@@ -102,6 +172,55 @@ func (r *RecipesRouter) deleteRecipeHandler(c echo.Context) error {
 	return c.Redirect(http.StatusFound, c.Echo().Reverse("getRecipes"))
 }
 
+// getExtension returns the file extension for an export format
+func (r *RecipesRouter) getExtension(format string) string {
+	switch format {
+	case "beerjson":
+		return "json"
+	default:
+		return "json"
+	}
+}
+
+// exportRecipe instanciates the correct exporter based on the format and exports the recipe as a string
+func (r *RecipesRouter) exportRecipe(format string, re *recipe.Recipe) (string, error) {
+	var e RecipeExporter
+	switch format {
+	case "beerjson":
+		e = &beerjson.BeerJSONExporter{}
+	default:
+		return "", errors.New("could not find suitable exporter for format " + format)
+	}
+	return e.Export(re)
+}
+
+// getDownloadHandler is the handler for downloading a recipe in a format that can be opened in other tools
+// The format is given in the query parameter format and defaults to beerjson
+func (r *RecipesRouter) getDownloadHandler(c echo.Context) error {
+	id := c.Param("recipe_id")
+	if id == "" {
+		return common.ErrNoRecipeIDProvided
+	}
+	re, err := r.Store.Retrieve(id)
+	if err != nil {
+		return err
+	}
+	format := c.QueryParam("format")
+	if format == "" {
+		format = "beerjson"
+	}
+	format = strings.ToLower(format)
+	content, err := r.exportRecipe(format, re)
+	if err != nil {
+		return err
+	}
+	fileName := id + "." + r.getExtension(format)
+	c.Response().Header().Set("Content-Type", "application/octet-stream")
+	c.Response().Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", fileName))
+	_, err = c.Response().Write([]byte(content))
+	return err
+}
+
 // statusRedirectURL returns the URL to redirect to based on the status of the recipe
 func (r *RecipesRouter) statusRedirectURL(c echo.Context, re *recipe.Recipe, id string) (string, error) {
 	status, params := re.GetStatus()
@@ -114,6 +233,8 @@ func (r *RecipesRouter) statusRedirectURL(c echo.Context, re *recipe.Recipe, id
 			return c.Echo().Reverse("getMashStart", id), nil
 		case "rast":
 			return c.Echo().Reverse("getRasts", id, params[1]), nil
+		case "decoction":
+			return c.Echo().Reverse("getDecoction", id, params[1], params[2]), nil
 		default:
 			return "", errors.New("invalid parameter for mashing status")
 		}
@@ -123,12 +244,16 @@ func (r *RecipesRouter) statusRedirectURL(c echo.Context, re *recipe.Recipe, id
 		switch params[0] {
 		case "initialVol":
 			return c.Echo().Reverse("getStartHopping", id), nil
+		case "boilAdvice":
+			return c.Echo().Reverse("getBoilAdvice", id), nil
 		case "beforeBoil":
 			return c.Echo().Reverse("getBoiling", id), nil
 		case "lastBoil", "hop":
 			return c.Echo().Reverse("getHopping", id, params[1]), nil
 		case "finalVol":
 			return c.Echo().Reverse("getEndHopping", id), nil
+		case "hopStand":
+			return c.Echo().Reverse("getHopStand", id), nil
 		default:
 			return "", errors.New("invalid parameter for boiling status")
 		}
@@ -0,0 +1,78 @@
+package recipes
+
+import (
+	"brewday/internal/inventory"
+	"brewday/internal/recipe"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// shoppingListFileName is the name (without extension) of the exported shopping lists
+const shoppingListFileName = "shopping_list"
+
+// getShoppingListHandler is the handler for the shopping list of several recipes
+// The recipes are given with the repeated query parameter recipe. If stock is true, the inventory is subtracted
+// The format query parameter can be html (default), markdown or csv
+func (r *RecipesRouter) getShoppingListHandler(c echo.Context) error {
+	ids := c.QueryParams()["recipe"]
+	if len(ids) == 0 {
+		return errors.New("no recipes selected for the shopping list")
+	}
+	recipes := make([]*recipe.Recipe, 0, len(ids))
+	for _, id := range ids {
+		re, err := r.Store.Retrieve(id)
+		if err != nil {
+			return err
+		}
+		recipes = append(recipes, re)
+	}
+	withStock := c.QueryParam("stock") == "true" && r.Inventory != nil
+	var stock []*inventory.Item
+	if withStock {
+		var err error
+		stock, err = r.Inventory.ListItems()
+		if err != nil {
+			return err
+		}
+	}
+	list := inventory.ShoppingList(recipes, stock, time.Now())
+	format := strings.ToLower(c.QueryParam("format"))
+	switch format {
+	case "", "html":
+		query := c.QueryParams()
+		query.Del("format")
+		base := c.Echo().Reverse("getShoppingList") + "?" + query.Encode()
+		return c.Render(http.StatusOK, "shopping_list.html", map[string]interface{}{
+			"Title":       "Shopping list",
+			"Subtitle":    "Shopping list",
+			"Recipes":     recipes,
+			"Items":       list,
+			"WithStock":   withStock,
+			"MarkdownURL": base + "&format=markdown",
+			"CSVURL":      base + "&format=csv",
+		})
+	case "markdown":
+		return sendShoppingList(c, inventory.ShoppingListMarkdown(list, withStock), "md", "text/markdown")
+	case "csv":
+		content, err := inventory.ShoppingListCSV(list, withStock)
+		if err != nil {
+			return err
+		}
+		return sendShoppingList(c, content, "csv", "text/csv")
+	default:
+		return errors.New("invalid shopping list format " + format)
+	}
+}
+
+// sendShoppingList sends an exported shopping list as a file to download
+func sendShoppingList(c echo.Context, content, extension, contentType string) error {
+	c.Response().Header().Set("Content-Type", contentType+"; charset=utf-8")
+	c.Response().Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s.%s", shoppingListFileName, extension))
+	_, err := c.Response().Write([]byte(content))
+	return err
+}
@@ -1,17 +1,33 @@
 package stats
 
-import "brewday/internal/summary"
+import (
+	"brewday/internal/equipment"
+	"brewday/internal/summary"
+)
 
 // StatsStore represents a component that stores summaries
 type StatsStore interface {
-	// GetAllStats returns all the statistics mapped with the b64 representation of the recipe title
-	GetAllStats() (map[string]*summary.Statistics, error)
+	// GetAllStats returns the statistics of all the brews, several brews can have the same recipe name
+	GetAllStats() ([]*summary.Statistics, error)
 	// AddStatsExternal adds statistics from recipes outside the app
 	AddStatsExternal(recipeName string, stats *summary.Statistics) error
 }
 
+// EquipmentStore represents a component that stores the equipment profiles
+type EquipmentStore interface {
+	// RetrieveActiveProfile retrieves the profile used by default, nil if no profile is active
+	RetrieveActiveProfile() (*equipment.Profile, error)
+	// UpdateProfile replaces an equipment profile, the identifier of the profile is used to find it
+	UpdateProfile(p *equipment.Profile) error
+}
+
+// StatEntry represents the statistics of a brew
 type StatEntry struct {
-	RecipeName         string
+	RecipeName string
+	// RecipeID identifies the brew, it is empty for brews done outside the app
+	RecipeID string
+	// BrewNumber is the position of the brew among the finished brews of the same recipe, starting at 1
+	BrewNumber         int
 	Evaporation        *float32
 	Efficiency         *float32
 	FinishedTimeString string
@@ -1,6 +1,7 @@
 package stats
 
 import (
+	"brewday/internal/equipment"
 	"brewday/internal/summary"
 	"errors"
 	"net/http"
@@ -12,6 +13,7 @@ import (
 
 type StatsRouter struct {
 	StatsStore StatsStore
+	Equipment  EquipmentStore
 }
 
 func (r *StatsRouter) getStats() ([]StatEntry, error) {
@@ -23,22 +25,69 @@ func (r *StatsRouter) getStats() ([]StatEntry, error) {
 		return nil, err
 	}
 	res := []StatEntry{}
-	for name, s := range rawStats {
+	for _, s := range rawStats {
 		res = append(res, StatEntry{
-			RecipeName:         name,
+			RecipeName:         s.RecipeName,
+			RecipeID:           s.RecipeID,
 			Evaporation:        nullIf0(s.Evaporation),
 			Efficiency:         nullIf0(s.Efficiency),
 			FinishedTimeEpoch:  s.FinishedTime.Unix(),
 			FinishedTimeString: s.FinishedTime.Format("2006-01-02"),
 		})
 	}
-	sort.Slice(res, func(i, j int) bool {
+	sort.SliceStable(res, func(i, j int) bool {
 		return res[i].FinishedTimeEpoch < res[j].FinishedTimeEpoch
 	})
+	// Brews of the same recipe are numbered in the order they were finished
+	brews := make(map[string]int)
+	for i := range res {
+		brews[res[i].RecipeName]++
+		res[i].BrewNumber = brews[res[i].RecipeName]
+	}
 	return res, nil
 
 }
 
+// getTuning derives the evaporation rate and the efficiency from the stats and returns the active equipment profile
+// The profile is nil if there is no equipment store or no active profile
+func (r *StatsRouter) getTuning() (equipment.Tuning, *equipment.Profile, error) {
+	if r.StatsStore == nil {
+		return equipment.Tuning{}, nil, errors.New("summary store not configured")
+	}
+	rawStats, err := r.StatsStore.GetAllStats()
+	if err != nil {
+		return equipment.Tuning{}, nil, err
+	}
+	tuning := equipment.Tune(rawStats)
+	if r.Equipment == nil {
+		return tuning, nil, nil
+	}
+	p, err := r.Equipment.RetrieveActiveProfile()
+	if err != nil {
+		return equipment.Tuning{}, nil, err
+	}
+	return tuning, p, nil
+}
+
+// tuneProfile updates the active equipment profile with the evaporation rate and the efficiency of the past brews
+func (r *StatsRouter) tuneProfile() error {
+	if r.Equipment == nil {
+		return errors.New("equipment store not configured")
+	}
+	tuning, p, err := r.getTuning()
+	if err != nil {
+		return err
+	}
+	if p == nil {
+		return errors.New("no active equipment profile")
+	}
+	changed, err := tuning.Apply(p)
+	if err != nil || !changed {
+		return err
+	}
+	return r.Equipment.UpdateProfile(p)
+}
+
 func (r *StatsRouter) addStats(req *ReqPostAddStat) error {
 	if r.StatsStore == nil {
 		return errors.New("summary store not configured")
@@ -60,6 +109,7 @@ func (r *StatsRouter) RegisterRoutes(root *echo.Echo, parent *echo.Group) {
 	stats := parent.Group("/stats")
 	stats.GET("", r.getStatsHandler).Name = "getStats"
 	stats.POST("/add", r.postAddExtStatHandler).Name = "postAddExtStat"
+	stats.POST("/tune", r.postTuneEquipmentHandler).Name = "postTuneEquipment"
 }
 
 func (r *StatsRouter) getStatsHandler(c echo.Context) error {
@@ -67,13 +117,27 @@ func (r *StatsRouter) getStatsHandler(c echo.Context) error {
 	if err != nil {
 		return err
 	}
+	tuning, profile, err := r.getTuning()
+	if err != nil {
+		return err
+	}
 	return c.Render(200, "stats.html", map[string]any{
 		"Title":    "Stats",
 		"Subtitle": "Historical stats from saved summaries",
 		"Stats":    s,
+		"Tuning":   tuning,
+		"Profile":  profile,
 	})
 }
 
+func (r *StatsRouter) postTuneEquipmentHandler(c echo.Context) error {
+	err := r.tuneProfile()
+	if err != nil {
+		return err
+	}
+	return c.Redirect(http.StatusFound, c.Echo().Reverse("getStats"))
+}
+
 func (r *StatsRouter) postAddExtStatHandler(c echo.Context) error {
 	var req ReqPostAddStat
 	err := c.Bind(&req)
@@ -1,6 +1,7 @@
 package stats
 
 import (
+	"brewday/internal/equipment"
 	"brewday/internal/summary"
 	"testing"
 	"time"
@@ -9,15 +10,36 @@ import (
 )
 
 type mockStore struct {
-	store map[string]*summary.Statistics
+	store []*summary.Statistics
 }
 
-func (s *mockStore) GetAllStats() (map[string]*summary.Statistics, error) {
+func (s *mockStore) GetAllStats() ([]*summary.Statistics, error) {
 	return s.store, nil
 }
 
 func (s *mockStore) AddStatsExternal(recipeName string, stats *summary.Statistics) error {
-	s.store[recipeName] = stats
+	stats.RecipeName = recipeName
+	s.store = append(s.store, stats)
+	return nil
+}
+
+type mockEquipmentStore struct {
+	active  *equipment.Profile
+	updates int
+}
+
+func (s *mockEquipmentStore) RetrieveActiveProfile() (*equipment.Profile, error) {
+	if s.active == nil {
+		return nil, nil
+	}
+	p := *s.active
+	return &p, nil
+}
+
+func (s *mockEquipmentStore) UpdateProfile(p *equipment.Profile) error {
+	stored := *p
+	s.active = &stored
+	s.updates++
 	return nil
 }
 
@@ -29,36 +51,48 @@ func TestGetStats(t *testing.T) {
 	require := require.New(t)
 	type testCase struct {
 		Name     string
-		Store    map[string]*summary.Statistics
+		Store    []*summary.Statistics
 		Expected []StatEntry
 		Error    bool
 	}
 	testCases := []testCase{
 		{
 			Name: "One summaries",
-			Store: map[string]*summary.Statistics{
-				"Test1": {Evaporation: 20.5, Efficiency: 62.3, FinishedTime: time.Unix(150, 0)},
+			Store: []*summary.Statistics{
+				{RecipeName: "Test1", Evaporation: 20.5, Efficiency: 62.3, FinishedTime: time.Unix(150, 0)},
 			},
 			Expected: []StatEntry{
-				{RecipeName: "Test1", Evaporation: ptrFloat32(20.5), Efficiency: ptrFloat32(62.3), FinishedTimeString: "1970-01-01", FinishedTimeEpoch: 150},
+				{RecipeName: "Test1", BrewNumber: 1, Evaporation: ptrFloat32(20.5), Efficiency: ptrFloat32(62.3), FinishedTimeString: "1970-01-01", FinishedTimeEpoch: 150},
 			},
 			Error: false,
 		},
 		{
 			Name: "Two summaries",
-			Store: map[string]*summary.Statistics{
-				"Test1": {Evaporation: 20.5, Efficiency: 62.3, FinishedTime: time.Unix(150, 0)},
-				"Test2": {Evaporation: 16.333, Efficiency: 72.84, FinishedTime: time.Unix(150000, 0)},
+			Store: []*summary.Statistics{
+				{RecipeName: "Test1", Evaporation: 20.5, Efficiency: 62.3, FinishedTime: time.Unix(150, 0)},
+				{RecipeName: "Test2", Evaporation: 16.333, Efficiency: 72.84, FinishedTime: time.Unix(150000, 0)},
+			},
+			Expected: []StatEntry{
+				{RecipeName: "Test1", BrewNumber: 1, Evaporation: ptrFloat32(20.5), Efficiency: ptrFloat32(62.3), FinishedTimeString: "1970-01-01", FinishedTimeEpoch: 150},
+				{RecipeName: "Test2", BrewNumber: 1, Evaporation: ptrFloat32(16.333), Efficiency: ptrFloat32(72.84), FinishedTimeString: "1970-01-02", FinishedTimeEpoch: 150000},
+			},
+			Error: false,
+		},
+		{
+			Name: "Two brews of the same recipe",
+			Store: []*summary.Statistics{
+				{RecipeName: "Test1", RecipeID: "2", Evaporation: 16.333, Efficiency: 72.84, FinishedTime: time.Unix(150000, 0)},
+				{RecipeName: "Test1", RecipeID: "1", Evaporation: 20.5, Efficiency: 62.3, FinishedTime: time.Unix(150, 0)},
 			},
 			Expected: []StatEntry{
-				{RecipeName: "Test1", Evaporation: ptrFloat32(20.5), Efficiency: ptrFloat32(62.3), FinishedTimeString: "1970-01-01", FinishedTimeEpoch: 150},
-				{RecipeName: "Test2", Evaporation: ptrFloat32(16.333), Efficiency: ptrFloat32(72.84), FinishedTimeString: "1970-01-02", FinishedTimeEpoch: 150000},
+				{RecipeName: "Test1", RecipeID: "1", BrewNumber: 1, Evaporation: ptrFloat32(20.5), Efficiency: ptrFloat32(62.3), FinishedTimeString: "1970-01-01", FinishedTimeEpoch: 150},
+				{RecipeName: "Test1", RecipeID: "2", BrewNumber: 2, Evaporation: ptrFloat32(16.333), Efficiency: ptrFloat32(72.84), FinishedTimeString: "1970-01-02", FinishedTimeEpoch: 150000},
 			},
 			Error: false,
 		},
 		{
 			Name:     "No Summaries",
-			Store:    map[string]*summary.Statistics{},
+			Store:    []*summary.Statistics{},
 			Expected: []StatEntry{},
 			Error:    false,
 		},
@@ -70,21 +104,21 @@ func TestGetStats(t *testing.T) {
 		},
 		{
 			Name: "Summary with Efficiency 0",
-			Store: map[string]*summary.Statistics{
-				"Test1": {Evaporation: 20.5, Efficiency: 0, FinishedTime: time.Unix(150, 0)},
+			Store: []*summary.Statistics{
+				{RecipeName: "Test1", Evaporation: 20.5, Efficiency: 0, FinishedTime: time.Unix(150, 0)},
 			},
 			Expected: []StatEntry{
-				{RecipeName: "Test1", Evaporation: ptrFloat32(20.5), Efficiency: nil, FinishedTimeString: "1970-01-01", FinishedTimeEpoch: 150},
+				{RecipeName: "Test1", BrewNumber: 1, Evaporation: ptrFloat32(20.5), Efficiency: nil, FinishedTimeString: "1970-01-01", FinishedTimeEpoch: 150},
 			},
 			Error: false,
 		},
 		{
 			Name: "Summary with Evaporation 0",
-			Store: map[string]*summary.Statistics{
-				"Test1": {Evaporation: 0, Efficiency: 62.3, FinishedTime: time.Unix(150, 0)},
+			Store: []*summary.Statistics{
+				{RecipeName: "Test1", Evaporation: 0, Efficiency: 62.3, FinishedTime: time.Unix(150, 0)},
 			},
 			Expected: []StatEntry{
-				{RecipeName: "Test1", Evaporation: nil, Efficiency: ptrFloat32(62.3), FinishedTimeString: "1970-01-01", FinishedTimeEpoch: 150},
+				{RecipeName: "Test1", BrewNumber: 1, Evaporation: nil, Efficiency: ptrFloat32(62.3), FinishedTimeString: "1970-01-01", FinishedTimeEpoch: 150},
 			},
 			Error: false,
 		},
@@ -108,23 +142,24 @@ func TestAddStats(t *testing.T) {
 	require := require.New(t)
 	type testCase struct {
 		Name     string
-		Store    map[string]*summary.Statistics
+		Store    []*summary.Statistics
 		ToAdd    *ReqPostAddStat
-		Expected map[string]*summary.Statistics
+		Expected []*summary.Statistics
 		Error    bool
 	}
 	testCases := []testCase{
 		{
 			Name:  "Add recipe, empty store",
-			Store: map[string]*summary.Statistics{},
+			Store: []*summary.Statistics{},
 			ToAdd: &ReqPostAddStat{
 				RecipeName:         "Test1",
 				Evaporation:        60.2,
 				Efficiency:         60.3,
 				FinishedTimeString: "2025-12-25",
 			},
-			Expected: map[string]*summary.Statistics{
-				"Test1": {
+			Expected: []*summary.Statistics{
+				{
+					RecipeName:   "Test1",
 					Evaporation:  60.2,
 					Efficiency:   60.3,
 					FinishedTime: time.Date(2025, time.December, 25, 0, 0, 0, 0, time.UTC),
@@ -134,8 +169,9 @@ func TestAddStats(t *testing.T) {
 		},
 		{
 			Name: "Add recipe, non-empty store",
-			Store: map[string]*summary.Statistics{
-				"Test1": {
+			Store: []*summary.Statistics{
+				{
+					RecipeName:   "Test1",
 					Evaporation:  60.2,
 					Efficiency:   60.3,
 					FinishedTime: time.Date(2025, time.December, 25, 0, 0, 0, 0, time.UTC),
@@ -147,13 +183,15 @@ func TestAddStats(t *testing.T) {
 				Efficiency:         60.3,
 				FinishedTimeString: "2025-12-25",
 			},
-			Expected: map[string]*summary.Statistics{
-				"Test1": {
+			Expected: []*summary.Statistics{
+				{
+					RecipeName:   "Test1",
 					Evaporation:  60.2,
 					Efficiency:   60.3,
 					FinishedTime: time.Date(2025, time.December, 25, 0, 0, 0, 0, time.UTC),
 				},
-				"Test2": {
+				{
+					RecipeName:   "Test2",
 					Evaporation:  60.2,
 					Efficiency:   60.3,
 					FinishedTime: time.Date(2025, time.December, 25, 0, 0, 0, 0, time.UTC),
@@ -176,3 +214,74 @@ func TestAddStats(t *testing.T) {
 		})
 	}
 }
+
+func TestTuneProfile(t *testing.T) {
+	type testCase struct {
+		Name        string
+		Store       []*summary.Statistics
+		Active      *equipment.Profile
+		Expected    *equipment.Profile
+		Updates     int
+		Error       bool
+		NoEquipment bool
+	}
+	testCases := []testCase{
+		{
+			Name: "Active profile updated",
+			Store: []*summary.Statistics{
+				{RecipeName: "Test1", Evaporation: 10, Efficiency: 70, FinishedTime: time.Unix(150, 0)},
+				{RecipeName: "Test2", Evaporation: 12, Efficiency: 0, FinishedTime: time.Unix(150000, 0)},
+			},
+			Active:   &equipment.Profile{ID: "1", Name: "Kettle", EvaporationRate: 8, Efficiency: 65},
+			Expected: &equipment.Profile{ID: "1", Name: "Kettle", EvaporationRate: 11, Efficiency: 70},
+			Updates:  1,
+		},
+		{
+			Name: "Invalid tuning refused",
+			Store: []*summary.Statistics{
+				{RecipeName: "Test1", Evaporation: 10, Efficiency: 700, FinishedTime: time.Unix(150, 0)},
+			},
+			Active:   &equipment.Profile{ID: "1", Name: "Kettle", EvaporationRate: 8, Efficiency: 65},
+			Expected: &equipment.Profile{ID: "1", Name: "Kettle", EvaporationRate: 8, Efficiency: 65},
+			Error:    true,
+		},
+		{
+			Name:     "No stats, profile unchanged",
+			Store:    []*summary.Statistics{},
+			Active:   &equipment.Profile{ID: "1", Name: "Kettle", EvaporationRate: 8, Efficiency: 65},
+			Expected: &equipment.Profile{ID: "1", Name: "Kettle", EvaporationRate: 8, Efficiency: 65},
+			Updates:  0,
+		},
+		{
+			Name:  "No active profile",
+			Store: []*summary.Statistics{},
+			Error: true,
+		},
+		{
+			Name:        "No equipment store",
+			Store:       []*summary.Statistics{},
+			Error:       true,
+			NoEquipment: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			require := require.New(t)
+			mockE := &mockEquipmentStore{active: tc.Active}
+			router := StatsRouter{StatsStore: &mockStore{store: tc.Store}, Equipment: mockE}
+			if tc.NoEquipment {
+				router.Equipment = nil
+			}
+			err := router.tuneProfile()
+			if tc.Error {
+				require.Error(err)
+				require.Equal(tc.Expected, mockE.active)
+				require.Zero(mockE.updates)
+				return
+			}
+			require.NoError(err)
+			require.Equal(tc.Expected, mockE.active)
+			require.Equal(tc.Updates, mockE.updates)
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package temperature
+
+import (
+	"brewday/internal/recipe"
+	"brewday/internal/temperature"
+	"time"
+)
+
+// TemperatureStore represents a component that stores the recipe each temperature sensor is assigned to and its readings
+type TemperatureStore interface {
+	// AssignSensor assigns a temperature sensor to the recipe whose fermentation it measures. An empty recipe id removes the assignment
+	AssignSensor(sensor, recipeID string) error
+	// RetrieveSensorRecipe retrieves the id of the recipe a temperature sensor is assigned to, empty if it is not assigned
+	RetrieveSensorRecipe(sensor string) (string, error)
+	// ListAssignments lists the recipe ids of the assigned temperature sensors, by sensor
+	ListAssignments() (map[string]string, error)
+	// AddReading adds a temperature reading to a recipe
+	AddReading(recipeID string, reading *temperature.Reading) error
+}
+
+// RecipeStore represents a component that stores recipes
+type RecipeStore interface {
+	// Retrieve retrieves a recipe based on an identifier
+	Retrieve(id string) (*recipe.Recipe, error)
+	// List lists all the recipes
+	List() ([]*recipe.Recipe, error)
+}
+
+// TargetProvider represents a component that knows the temperature the fermentation of a recipe should be kept at
+type TargetProvider interface {
+	// TemperatureRange returns the target temperature range of the fermentation at the given time, nil if it is not known
+	TemperatureRange(id string, now time.Time) (*recipe.TemperatureRange, error)
+}
+
+// ReqPostAssign represents the request for assigning a temperature sensor to a recipe
+type ReqPostAssign struct {
+	RecipeID string `json:"recipe_id" form:"recipe_id"`
+}
+
+// ReqPostReading represents a temperature pushed by a sensor or a fermentation chamber controller
+type ReqPostReading struct {
+	Temperature *float32 `json:"temperature" form:"temperature"`
+	// Unit is C (the default) or F
+	Unit string `json:"unit" form:"unit"`
+}
+
+// RespReading represents the response to a pushed temperature
+type RespReading struct {
+	// Sensor is the configured name of the sensor
+	Sensor string `json:"sensor"`
+	// RecipeID is the recipe the reading was stored in, empty if it was not stored
+	RecipeID string `json:"recipe_id,omitempty"`
+	// Temperature is the temperature in °C
+	Temperature float32 `json:"temperature"`
+	// Stored is whether the reading was stored for the recipe
+	Stored bool `json:"stored"`
+	// Min and Max are the target temperature range of the fermentation, if it is known
+	Min *float32 `json:"min,omitempty"`
+	Max *float32 `json:"max,omitempty"`
+	// OutOfRange is whether the temperature is outside of the target range
+	OutOfRange bool `json:"out_of_range,omitempty"`
+}
+
+// SensorStatus is a configured temperature sensor with its assignment and its last reading, to show it in the temperatures page
+type SensorStatus struct {
+	Name string
+	Type string
+	// Source is where the readings come from (the file of a 1-Wire sensor or the topic of an MQTT sensor)
+	Source   string
+	RecipeID string
+	// RecipeName is the name of the assigned recipe
+	RecipeName string
+	// Fermenting is whether the assigned recipe is fermenting, readings are only stored while it is
+	Fermenting bool
+	// LastTemperature is the last reading, its date is empty if no reading was received since the app started
+	LastTemperature float32
+	LastDate        string
+	// Target is the target temperature range of the assigned recipe when the last reading was received
+	Target     *recipe.TemperatureRange
+	OutOfRange bool
+}
+
+// RespError represents the response to a reading that could not be processed
+type RespError struct {
+	Error string `json:"error"`
+}
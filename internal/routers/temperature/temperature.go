@@ -0,0 +1,346 @@
+package temperature
+
+import (
+	"brewday/internal/recipe"
+	"brewday/internal/routers/common"
+	"brewday/internal/temperature"
+	"brewday/internal/tools"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+// dateFormat is the format of the date of the last reading of a sensor
+const dateFormat = "2006-01-02 15:04"
+
+type TemperatureRouter struct {
+	Store            RecipeStore
+	TemperatureStore TemperatureStore
+	// Sensors are the configured temperature sensors, readings of other sensors are rejected
+	Sensors []temperature.Sensor
+	// Targets gives the target temperature of the fermentations to raise alerts, it is optional
+	Targets  TargetProvider
+	TLStore  common.TimelineStore
+	Notifier common.Notifier
+	// pushSources are the running sources of the sensors whose readings are pushed over HTTP, by configured name
+	pushSources map[string]*temperature.PushSource
+	// states are the last reading of each sensor and whether it was out of the target range, by configured name
+	states map[string]*sensorState
+	lock   sync.Mutex
+}
+
+// sensorState is what is known of a sensor since the app started
+type sensorState struct {
+	reading  temperature.Reading
+	recipeID string
+	stored   bool
+	target   *recipe.TemperatureRange
+	// alerted is whether the temperature left the target range and it was notified
+	alerted bool
+}
+
+// RegisterRoutes registers the routes for the temperature router
+func (r *TemperatureRouter) RegisterRoutes(root *echo.Echo, parent *echo.Group) {
+	t := parent.Group("/temperatures")
+	t.GET("", r.getTemperaturesHandler).Name = "getTemperatures"
+	t.POST("/assign/:sensor", r.postAssignHandler).Name = "postTemperatureAssign"
+	t.POST("/:sensor", r.postReadingHandler).Name = "postTemperatureReading"
+}
+
+// Start starts reading the configured sensors until the context is cancelled
+func (r *TemperatureRouter) Start(ctx context.Context) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.pushSources = make(map[string]*temperature.PushSource)
+	for _, s := range r.Sensors {
+		source, err := temperature.NewSource(s)
+		if err != nil {
+			log.Error().Str("sensor", s.Name).Err(err).Msg("could not start temperature sensor")
+			continue
+		}
+		if p, ok := source.(*temperature.PushSource); ok {
+			r.pushSources[s.Name] = p
+		}
+		go func() {
+			err := source.Run(ctx, r.Record)
+			if err != nil {
+				log.Error().Str("sensor", s.Name).Err(err).Msg("temperature sensor stopped")
+			}
+		}()
+	}
+}
+
+// getTemperaturesHandler is the handler for the temperature sensors page
+func (r *TemperatureRouter) getTemperaturesHandler(c echo.Context) error {
+	if r.TemperatureStore == nil || r.Store == nil {
+		return errors.New("temperature store not configured")
+	}
+	assignments, err := r.TemperatureStore.ListAssignments()
+	if err != nil {
+		return err
+	}
+	recipes, err := r.Store.List()
+	if err != nil {
+		return err
+	}
+	fermenting := make([]*recipe.Recipe, 0)
+	byID := make(map[string]*recipe.Recipe, len(recipes))
+	for _, re := range recipes {
+		byID[re.ID] = re
+		status, _ := re.GetStatus()
+		if status == recipe.RecipeStatusFermenting {
+			fermenting = append(fermenting, re)
+		}
+	}
+	r.lock.Lock()
+	sensors := make([]*SensorStatus, 0, len(r.Sensors))
+	for _, s := range r.Sensors {
+		status := &SensorStatus{
+			Name:     s.Name,
+			Type:     string(s.Type),
+			RecipeID: assignments[s.Name],
+		}
+		switch s.Type {
+		case temperature.SourceTypeOneWire:
+			status.Source = s.Path
+		case temperature.SourceTypeMQTT:
+			status.Source = s.Topic
+		}
+		re, ok := byID[status.RecipeID]
+		if ok {
+			status.RecipeName = re.Name
+			st, _ := re.GetStatus()
+			status.Fermenting = st == recipe.RecipeStatusFermenting
+		}
+		state, ok := r.states[s.Name]
+		if ok {
+			status.LastTemperature = state.reading.Temperature
+			status.LastDate = state.reading.Date.Format(dateFormat)
+			if state.recipeID == status.RecipeID {
+				status.Target = state.target
+				status.OutOfRange = state.target != nil && !state.target.Contains(state.reading.Temperature)
+			}
+		}
+		sensors = append(sensors, status)
+	}
+	r.lock.Unlock()
+	sort.SliceStable(sensors, func(i, j int) bool {
+		return sensors[i].Name < sensors[j].Name
+	})
+	return c.Render(http.StatusOK, "temperatures.html", map[string]any{
+		"Title":      "Temperatures",
+		"Subtitle":   "Fermentation temperature sensors",
+		"Sensors":    sensors,
+		"Fermenting": fermenting,
+	})
+}
+
+// postAssignHandler assigns a temperature sensor to the fermentation of a recipe
+func (r *TemperatureRouter) postAssignHandler(c echo.Context) error {
+	if r.TemperatureStore == nil {
+		return errors.New("temperature store not configured")
+	}
+	s := r.findSensor(c.Param("sensor"))
+	if s == nil {
+		return fmt.Errorf("temperature sensor %s not found", c.Param("sensor"))
+	}
+	var req ReqPostAssign
+	err := c.Bind(&req)
+	if err != nil {
+		return err
+	}
+	err = r.TemperatureStore.AssignSensor(s.Name, req.RecipeID)
+	if err != nil {
+		return err
+	}
+	return c.Redirect(http.StatusFound, c.Echo().Reverse("getTemperatures"))
+}
+
+// postReadingHandler receives a temperature pushed by a sensor or a fermentation chamber controller
+func (r *TemperatureRouter) postReadingHandler(c echo.Context) error {
+	s := r.findSensor(c.Param("sensor"))
+	if s == nil || s.Type != temperature.SourceTypeHTTP {
+		return c.JSON(http.StatusNotFound, RespError{Error: fmt.Sprintf("http temperature sensor %s is not configured", c.Param("sensor"))})
+	}
+	var req ReqPostReading
+	err := c.Bind(&req)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, RespError{Error: err.Error()})
+	}
+	if req.Temperature == nil {
+		return c.JSON(http.StatusBadRequest, RespError{Error: "temperature is missing"})
+	}
+	value := *req.Temperature
+	switch strings.ToUpper(strings.TrimPrefix(strings.TrimSpace(req.Unit), "°")) {
+	case "", "C":
+	case "F":
+		value = tools.RoundTo(tools.FahrenheitToCelsius(value), 2)
+	default:
+		return c.JSON(http.StatusBadRequest, RespError{Error: fmt.Sprintf("invalid temperature unit %s", req.Unit)})
+	}
+	r.lock.Lock()
+	source := r.pushSources[s.Name]
+	r.lock.Unlock()
+	if source == nil {
+		return c.JSON(http.StatusServiceUnavailable, RespError{Error: temperature.ErrSourceNotRunning.Error()})
+	}
+	err = source.Push(value, time.Now())
+	if err != nil {
+		return c.JSON(http.StatusServiceUnavailable, RespError{Error: err.Error()})
+	}
+	return c.JSON(http.StatusOK, r.lastResult(s.Name))
+}
+
+// lastResult returns how the last reading of a sensor was processed
+func (r *TemperatureRouter) lastResult(sensor string) RespReading {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	resp := RespReading{Sensor: sensor}
+	state, ok := r.states[sensor]
+	if !ok {
+		return resp
+	}
+	resp.Temperature = state.reading.Temperature
+	resp.Stored = state.stored
+	if state.stored {
+		resp.RecipeID = state.recipeID
+	}
+	if state.target != nil {
+		resp.Min = &state.target.Min
+		resp.Max = &state.target.Max
+		resp.OutOfRange = !state.target.Contains(state.reading.Temperature)
+	}
+	return resp
+}
+
+// Record stores a reading for the recipe its sensor is assigned to and alerts when it leaves the target range
+// Readings are only stored while the recipe is fermenting
+func (r *TemperatureRouter) Record(reading temperature.Reading) {
+	s := r.findSensor(reading.Sensor)
+	if s == nil {
+		log.Error().Str("sensor", reading.Sensor).Msg("temperature sensor is not configured")
+		return
+	}
+	reading.Sensor = s.Name
+	state := &sensorState{reading: reading}
+	defer func() {
+		r.lock.Lock()
+		if r.states == nil {
+			r.states = make(map[string]*sensorState)
+		}
+		r.states[s.Name] = state
+		r.lock.Unlock()
+	}()
+	if r.TemperatureStore == nil || r.Store == nil {
+		log.Error().Str("sensor", s.Name).Msg("temperature store not configured")
+		return
+	}
+	id, err := r.TemperatureStore.RetrieveSensorRecipe(s.Name)
+	if err != nil {
+		log.Error().Str("sensor", s.Name).Err(err).Msg("could not retrieve the recipe of the temperature sensor")
+		return
+	}
+	state.recipeID = id
+	if id == "" {
+		return
+	}
+	re, err := r.Store.Retrieve(id)
+	if err != nil {
+		log.Error().Str("id", id).Str("sensor", s.Name).Err(err).Msg("could not retrieve recipe")
+		return
+	}
+	status, _ := re.GetStatus()
+	if status != recipe.RecipeStatusFermenting {
+		return
+	}
+	err = r.TemperatureStore.AddReading(id, &reading)
+	if err != nil {
+		log.Error().Str("id", id).Str("sensor", s.Name).Err(err).Msg("could not store temperature reading")
+		return
+	}
+	state.stored = true
+	if r.Targets == nil {
+		return
+	}
+	target, err := r.Targets.TemperatureRange(id, reading.Date)
+	if err != nil {
+		log.Error().Str("id", id).Err(err).Msg("could not get the target fermentation temperature")
+		return
+	}
+	state.target = target
+	r.lock.Lock()
+	previous, ok := r.states[s.Name]
+	alerted := ok && previous.alerted && previous.recipeID == id
+	r.lock.Unlock()
+	if target == nil {
+		return
+	}
+	state.alerted = alerted
+	temp := formatTemperature(reading.Temperature)
+	expected := formatTemperature(target.Min) + "-" + formatTemperature(target.Max) + " °C"
+	if target.Source != "" {
+		expected += " (" + target.Source + ")"
+	}
+	switch {
+	case !target.Contains(reading.Temperature) && !alerted:
+		state.alerted = true
+		message := fmt.Sprintf("Fermentation temperature %s °C of sensor %s is out of the target range %s", temp, s.Name, expected)
+		log.Info().Str("id", id).Str("sensor", s.Name).Msg(message)
+		err = r.addTimelineEvent(id, message)
+		if err != nil {
+			log.Error().Str("id", id).Err(err).Msg("could not add timeline event")
+		}
+		err = r.sendNotification(message, "Fermentation Temperature "+re.Name, nil)
+		if err != nil {
+			log.Error().Str("id", id).Err(err).Msg("could not send notification")
+		}
+	case target.Contains(reading.Temperature) && alerted:
+		state.alerted = false
+		message := fmt.Sprintf("Fermentation temperature %s °C of sensor %s is back in the target range %s", temp, s.Name, expected)
+		log.Info().Str("id", id).Str("sensor", s.Name).Msg(message)
+		err = r.sendNotification(message, "Fermentation Temperature "+re.Name, nil)
+		if err != nil {
+			log.Error().Str("id", id).Err(err).Msg("could not send notification")
+		}
+	}
+}
+
+// sendNotification sends a notification if the notifier is configured
+func (r *TemperatureRouter) sendNotification(message, title string, opts map[string]any) error {
+	if r.Notifier != nil {
+		return r.Notifier.Send(message, title, opts)
+	}
+	return nil
+}
+
+// addTimelineEvent adds an event to the timeline
+func (r *TemperatureRouter) addTimelineEvent(id, message string) error {
+	if r.TLStore != nil {
+		return r.TLStore.AddEvent(id, message)
+	}
+	return nil
+}
+
+// findSensor returns the configured temperature sensor with the given name, ignoring the case, or nil
+func (r *TemperatureRouter) findSensor(name string) *temperature.Sensor {
+	for i := range r.Sensors {
+		if r.Sensors[i].Matches(name) {
+			return &r.Sensors[i]
+		}
+	}
+	return nil
+}
+
+// formatTemperature formats a temperature with at most 2 decimals
+func formatTemperature(t float32) string {
+	return strconv.FormatFloat(float64(tools.RoundTo(t, 2)), 'f', -1, 32)
+}
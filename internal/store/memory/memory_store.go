@@ -4,6 +4,7 @@ import (
 	"brewday/internal/recipe"
 	"encoding/hex"
 	"errors"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -28,6 +29,9 @@ type MemoryStore struct {
 	dates         map[string][]*Date
 	boolFlagsLock sync.Mutex
 	boolFlags     map[string][]*boolFlag
+	libraryLock   sync.Mutex
+	library       map[string]*recipe.Recipe
+	libraryLastID int
 }
 
 // NewMemoryStore creates a new MemoryStore
@@ -46,10 +50,14 @@ func (s *MemoryStore) CreateID(recipeName string) string {
 }
 
 // Store stores a recipe and returns an identifier that can be used to retrieve it
+// If a recipe with the same name is already stored (e.g. it is brewed again), a counter is added to the identifier
 func (s *MemoryStore) Store(recipe *recipe.Recipe) (string, error) {
 	s.lock.Lock()
 	defer s.lock.Unlock()
 	id := s.CreateID(recipe.Name)
+	for i := 2; s.recipes[id] != nil; i++ {
+		id = s.CreateID(recipe.Name) + "-" + strconv.Itoa(i)
+	}
 	s.recipes[id] = recipe
 	recipe.ID = id
 	recipe.InitResults()
@@ -90,6 +98,20 @@ func (s *MemoryStore) Delete(id string) error {
 	return nil
 }
 
+// Update replaces the definition of a stored recipe (name, style, ingredients and instructions)
+// The identifier, status and results of the recipe are kept
+// The stored recipe is replaced by a new one, as the previous one may still be read by the handlers that retrieved it
+func (s *MemoryStore) Update(id string, r *recipe.Recipe) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	re, ok := s.recipes[id]
+	if !ok {
+		return errors.New("recipe not found")
+	}
+	s.recipes[id] = re.WithDefinition(r)
+	return nil
+}
+
 // UpdateStatus updates the status of a recipe in the store
 func (s *MemoryStore) UpdateStatus(id string, status recipe.RecipeStatus, statusParams ...string) error {
 	r, err := s.Retrieve(id)
@@ -119,6 +141,8 @@ func (s *MemoryStore) UpdateResult(id string, resultType recipe.ResultType, valu
 		r.SetMainFermentationVolume(value)
 	case recipe.ResultVolumeBeforeBoil:
 		r.SetVolumeBeforeBoil(value)
+	case recipe.ResultExtraBoilTime:
+		r.SetExtraBoilTime(value)
 	default:
 		return errors.New("invalid result not present in struct: " + strconv.Itoa(int(resultType)))
 	}
@@ -144,6 +168,8 @@ func (s *MemoryStore) RetrieveResult(id string, resultType recipe.ResultType) (f
 		return res.MainFermentationVolume, nil
 	case recipe.ResultVolumeBeforeBoil:
 		return res.VolumeBeforeBoil, nil
+	case recipe.ResultExtraBoilTime:
+		return res.ExtraBoilTime, nil
 	default:
 		return 0, errors.New("invalid result not present in struct: " + strconv.Itoa(int(resultType)))
 	}
@@ -199,6 +225,7 @@ func (s *MemoryStore) AddDate(id string, date *time.Time, name string) error {
 
 // RetrieveDates allows to retreive stored dates with its purpose (name).It can be used to store notification dates, or timers
 // It supports pattern in the name to retrieve multiple values
+// The dates are returned in the order they were added
 // The pattern is searched in the names with strings.Contains
 func (s *MemoryStore) RetrieveDates(id, namePattern string) ([]*time.Time, error) {
 	s.datesLock.Lock()
@@ -264,3 +291,59 @@ func (s *MemoryStore) RetrieveBoolFlag(id, name string) (bool, error) {
 	}
 	return false, nil
 }
+
+// AddToLibrary stores a copy of the definition of the recipe in the library, so it can be brewed again later
+// It returns the identifier of the recipe in the library
+func (s *MemoryStore) AddToLibrary(r *recipe.Recipe) (string, error) {
+	s.libraryLock.Lock()
+	defer s.libraryLock.Unlock()
+	if s.library == nil {
+		s.library = make(map[string]*recipe.Recipe)
+	}
+	s.libraryLastID++
+	id := strconv.Itoa(s.libraryLastID)
+	c := r.Clone()
+	c.ID = id
+	s.library[id] = c
+	return id, nil
+}
+
+// ListLibrary lists all the recipes in the library, sorted by name
+func (s *MemoryStore) ListLibrary() ([]*recipe.Recipe, error) {
+	s.libraryLock.Lock()
+	defer s.libraryLock.Unlock()
+	recipes := make([]*recipe.Recipe, 0, len(s.library))
+	for _, re := range s.library {
+		recipes = append(recipes, re)
+	}
+	sort.Slice(recipes, func(i, j int) bool {
+		if recipes[i].Name == recipes[j].Name {
+			return recipes[i].ID < recipes[j].ID
+		}
+		return recipes[i].Name < recipes[j].Name
+	})
+	return recipes, nil
+}
+
+// RetrieveFromLibrary retrieves a recipe from the library based on its identifier
+func (s *MemoryStore) RetrieveFromLibrary(id string) (*recipe.Recipe, error) {
+	s.libraryLock.Lock()
+	defer s.libraryLock.Unlock()
+	re, ok := s.library[id]
+	if !ok {
+		return nil, errors.New("recipe not found in library")
+	}
+	return re, nil
+}
+
+// DeleteFromLibrary deletes a recipe from the library
+func (s *MemoryStore) DeleteFromLibrary(id string) error {
+	s.libraryLock.Lock()
+	defer s.libraryLock.Unlock()
+	_, ok := s.library[id]
+	if !ok {
+		return errors.New("recipe not found in library")
+	}
+	delete(s.library, id)
+	return nil
+}
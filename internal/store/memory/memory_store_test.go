@@ -51,6 +51,10 @@ func TestStore(t *testing.T) {
 			Name:    "Multiple recipes",
 			Recipes: []*recipe.Recipe{{Name: "recipe1"}, {Name: "recipe2"}},
 		},
+		{
+			Name:    "Same recipe brewed twice",
+			Recipes: []*recipe.Recipe{{Name: "recipe1"}, {Name: "recipe1"}},
+		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.Name, func(t *testing.T) {
@@ -150,3 +154,94 @@ func TestList(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdate(t *testing.T) {
+	require := require.New(t)
+	store := NewMemoryStore()
+	id, err := store.Store(&recipe.Recipe{
+		Name:    "recipe1",
+		Hopping: recipe.HopInstructions{Hops: []recipe.Hops{{Name: "Cascade", Amount: 10}}},
+	})
+	require.NoError(err)
+	err = store.UpdateStatus(id, recipe.RecipeStatusLautering)
+	require.NoError(err)
+	err = store.UpdateResult(id, recipe.ResultVolumeBeforeBoil, 25)
+	require.NoError(err)
+	// A recipe retrieved before the update is not changed by it
+	previous, err := store.Retrieve(id)
+	require.NoError(err)
+	type testCase struct {
+		Name   string
+		ID     string
+		Recipe *recipe.Recipe
+		Error  bool
+	}
+	testCases := []testCase{
+		{
+			Name: "Existing recipe",
+			ID:   id,
+			Recipe: &recipe.Recipe{
+				Name:    "recipe1 edited",
+				Style:   "IPA",
+				Hopping: recipe.HopInstructions{Hops: []recipe.Hops{{Name: "Citra", Amount: 20}}},
+			},
+		},
+		{
+			Name:   "Non existent recipe",
+			ID:     "id2",
+			Recipe: &recipe.Recipe{Name: "recipe2"},
+			Error:  true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			err := store.Update(tc.ID, tc.Recipe)
+			if tc.Error {
+				require.Error(err)
+				return
+			}
+			require.NoError(err)
+			actual, err := store.Retrieve(tc.ID)
+			require.NoError(err)
+			require.Equal(tc.ID, actual.ID)
+			require.Equal(tc.Recipe.Name, actual.Name)
+			require.Equal(tc.Recipe.Style, actual.Style)
+			require.Equal(tc.Recipe.Hopping, actual.Hopping)
+			status, _ := actual.GetStatus()
+			require.Equal(recipe.RecipeStatusLautering, status)
+			require.Equal(float32(25), actual.GetResults().VolumeBeforeBoil)
+			require.Equal("recipe1", previous.Name)
+			require.Equal("Cascade", previous.Hopping.Hops[0].Name)
+		})
+	}
+}
+
+func TestLibrary(t *testing.T) {
+	require := require.New(t)
+	store := NewMemoryStore()
+	original := &recipe.Recipe{
+		ID:      "brew1",
+		Name:    "recipe2",
+		Hopping: recipe.HopInstructions{Hops: []recipe.Hops{{Name: "Cascade", Amount: 10}}},
+	}
+	id, err := store.AddToLibrary(original)
+	require.NoError(err)
+	_, err = store.AddToLibrary(&recipe.Recipe{Name: "recipe1"})
+	require.NoError(err)
+	// The library keeps a copy, changes in the brew are not stored in the library
+	original.Hopping.Hops[0].Amount = 20
+	stored, err := store.RetrieveFromLibrary(id)
+	require.NoError(err)
+	require.Equal(id, stored.ID)
+	require.Equal("recipe2", stored.Name)
+	require.Equal(float32(10), stored.Hopping.Hops[0].Amount)
+	list, err := store.ListLibrary()
+	require.NoError(err)
+	require.Len(list, 2)
+	require.Equal("recipe1", list[0].Name)
+	require.Equal("recipe2", list[1].Name)
+	require.NoError(store.DeleteFromLibrary(id))
+	_, err = store.RetrieveFromLibrary(id)
+	require.Error(err)
+	require.Error(store.DeleteFromLibrary(id))
+}
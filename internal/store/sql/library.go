@@ -0,0 +1,88 @@
+package sql
+
+import (
+	"brewday/internal/recipe"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+)
+
+// AddToLibrary stores a copy of the definition of the recipe in the library, so it can be brewed again later
+// It returns the identifier of the recipe in the library
+func (s *PersistentStore) AddToLibrary(r *recipe.Recipe) (string, error) {
+	marshalled, err := json.Marshal(r.Clone())
+	if err != nil {
+		return "", err
+	}
+	res, err := s.dbClient.Exec(`INSERT INTO library (name, style, recipe, added_epoch) VALUES (?, ?, ?, ?)`,
+		r.Name, r.Style, string(marshalled), time.Now().Unix())
+	if err != nil {
+		return "", err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatInt(id, 10), nil
+}
+
+// ListLibrary lists all the recipes in the library, sorted by name
+func (s *PersistentStore) ListLibrary() ([]*recipe.Recipe, error) {
+	rows, err := s.dbClient.Query(`SELECT id, recipe FROM library ORDER BY name, id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	recipes := make([]*recipe.Recipe, 0)
+	for rows.Next() {
+		var id, marshalled string
+		err = rows.Scan(&id, &marshalled)
+		if err != nil {
+			return nil, err
+		}
+		r, err := s.unmarshalLibraryRecipe(id, marshalled)
+		if err != nil {
+			return nil, err
+		}
+		recipes = append(recipes, r)
+	}
+	return recipes, rows.Err()
+}
+
+// RetrieveFromLibrary retrieves a recipe from the library based on its identifier
+func (s *PersistentStore) RetrieveFromLibrary(id string) (*recipe.Recipe, error) {
+	var marshalled string
+	err := s.dbClient.QueryRow(`SELECT recipe FROM library WHERE id == ?`, id).Scan(&marshalled)
+	if err != nil {
+		return nil, err
+	}
+	return s.unmarshalLibraryRecipe(id, marshalled)
+}
+
+// DeleteFromLibrary deletes a recipe from the library
+func (s *PersistentStore) DeleteFromLibrary(id string) error {
+	res, err := s.dbClient.Exec(`DELETE FROM library WHERE id == ?`, id)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return errors.New("recipe not found in library")
+	}
+	return nil
+}
+
+// unmarshalLibraryRecipe builds a recipe of the library from its json representation
+func (s *PersistentStore) unmarshalLibraryRecipe(id, marshalled string) (*recipe.Recipe, error) {
+	var r recipe.Recipe
+	err := json.Unmarshal([]byte(marshalled), &r)
+	if err != nil {
+		return nil, err
+	}
+	r.ID = id
+	return &r, nil
+}
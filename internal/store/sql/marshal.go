@@ -6,23 +6,27 @@ import (
 )
 
 type MarshalResult struct {
-	StatusParams string
-	MashingMalts string
-	MashingRasts string
-	HopHops      string
-	HopAdd       string
-	FermAdd      string
-	Yeast        string
+	StatusParams      string
+	MashingMalts      string
+	MashingRasts      string
+	MashingDecoctions string
+	HopHops           string
+	HopAdd            string
+	FermAdd           string
+	FermSchedule      string
+	Yeast             string
 }
 
 type UnmarshalResult struct {
-	StatusParams []string
-	MashingMalts []recipe.Malt
-	MashingRasts []recipe.Rast
-	HopHops      []recipe.Hops
-	HopAdd       []recipe.AdditionalIngredient
-	FermAdd      []recipe.AdditionalIngredient
-	Yeast        recipe.Yeast
+	StatusParams      []string
+	MashingMalts      []recipe.Malt
+	MashingRasts      []recipe.Rast
+	MashingDecoctions []recipe.Decoction
+	HopHops           []recipe.Hops
+	HopAdd            []recipe.AdditionalIngredient
+	FermAdd           []recipe.AdditionalIngredient
+	FermSchedule      []recipe.FermentationStep
+	Yeast             recipe.Yeast
 }
 
 func (s *PersistentStore) marshalStructs(r *recipe.Recipe) (*MarshalResult, error) {
@@ -39,6 +43,10 @@ func (s *PersistentStore) marshalStructs(r *recipe.Recipe) (*MarshalResult, erro
 	if err != nil {
 		return nil, err
 	}
+	decoctionBytes, err := json.Marshal(r.Mashing.Decoctions)
+	if err != nil {
+		return nil, err
+	}
 	hopBytes, err := json.Marshal(r.Hopping.Hops)
 	if err != nil {
 		return nil, err
@@ -51,18 +59,24 @@ func (s *PersistentStore) marshalStructs(r *recipe.Recipe) (*MarshalResult, erro
 	if err != nil {
 		return nil, err
 	}
+	fermScheduleBytes, err := json.Marshal(r.Fermentation.Schedule)
+	if err != nil {
+		return nil, err
+	}
 	yeastBytes, err := json.Marshal(r.Fermentation.Yeast)
 	if err != nil {
 		return nil, err
 	}
 	return &MarshalResult{
-		StatusParams: sP,
-		MashingMalts: string(maltBytes),
-		MashingRasts: string(rastBytes),
-		HopHops:      string(hopBytes),
-		HopAdd:       string(hopAddBytes),
-		FermAdd:      string(fermAddBytes),
-		Yeast:        string(yeastBytes),
+		StatusParams:      sP,
+		MashingMalts:      string(maltBytes),
+		MashingRasts:      string(rastBytes),
+		MashingDecoctions: string(decoctionBytes),
+		HopHops:           string(hopBytes),
+		HopAdd:            string(hopAddBytes),
+		FermAdd:           string(fermAddBytes),
+		FermSchedule:      string(fermScheduleBytes),
+		Yeast:             string(yeastBytes),
 	}, nil
 }
 
@@ -78,9 +92,11 @@ func (s *PersistentStore) unmarshalStructs(m *MarshalResult) (*UnmarshalResult,
 	var statusParams []string
 	var mashingMalts []recipe.Malt
 	var mashingRasts []recipe.Rast
+	var mashingDecoctions []recipe.Decoction
 	var hopHops []recipe.Hops
 	var hopAdd []recipe.AdditionalIngredient
 	var fermAdd []recipe.AdditionalIngredient
+	var fermSchedule []recipe.FermentationStep
 	var yeast recipe.Yeast
 	err := json.Unmarshal([]byte(m.StatusParams), &statusParams)
 	if err != nil {
@@ -94,6 +110,10 @@ func (s *PersistentStore) unmarshalStructs(m *MarshalResult) (*UnmarshalResult,
 	if err != nil {
 		return nil, err
 	}
+	err = json.Unmarshal([]byte(m.MashingDecoctions), &mashingDecoctions)
+	if err != nil {
+		return nil, err
+	}
 	err = json.Unmarshal([]byte(m.HopHops), &hopHops)
 	if err != nil {
 		return nil, err
@@ -106,17 +126,23 @@ func (s *PersistentStore) unmarshalStructs(m *MarshalResult) (*UnmarshalResult,
 	if err != nil {
 		return nil, err
 	}
+	err = json.Unmarshal([]byte(m.FermSchedule), &fermSchedule)
+	if err != nil {
+		return nil, err
+	}
 	err = json.Unmarshal([]byte(m.Yeast), &yeast)
 	if err != nil {
 		return nil, err
 	}
 	return &UnmarshalResult{
-		StatusParams: statusParams,
-		MashingMalts: mashingMalts,
-		MashingRasts: mashingRasts,
-		HopHops:      hopHops,
-		HopAdd:       hopAdd,
-		FermAdd:      fermAdd,
-		Yeast:        yeast,
+		StatusParams:      statusParams,
+		MashingMalts:      mashingMalts,
+		MashingRasts:      mashingRasts,
+		MashingDecoctions: mashingDecoctions,
+		HopHops:           hopHops,
+		HopAdd:            hopAdd,
+		FermAdd:           fermAdd,
+		FermSchedule:      fermSchedule,
+		Yeast:             yeast,
 	}, nil
 }
@@ -21,9 +21,9 @@ type PersistentStore struct {
 func NewPersistentStore(db *sql.DB) (*PersistentStore, error) {
 	rs, err := db.Prepare(`SELECT 
 		name, style, batch_size_l, initial_sg, ibu, ebc, status, status_args,
-		mash_malts, mash_main_water, mash_nachguss, mash_temp, mash_out_temp, mash_rasts,
+		mash_malts, mash_main_water, mash_nachguss, mash_temp, mash_out_temp, mash_rasts, mash_type, mash_decoctions,
 		hop_cooking_time, hop_hops, hop_additional,
-		ferm_yeast, ferm_temp, ferm_additional, ferm_carbonation
+		ferm_yeast, ferm_temp, ferm_additional, ferm_carbonation, ferm_schedule
 	FROM recipes WHERE id == ?`)
 	if err != nil {
 		return nil, err
@@ -62,15 +62,15 @@ func (s *PersistentStore) Store(r *recipe.Recipe) (string, error) {
 	res, err := s.dbClient.Exec(`INSERT INTO recipes 
 	(
 		name, style, batch_size_l, initial_sg, ibu, ebc, status, status_args,
-		mash_malts, mash_main_water, mash_nachguss, mash_temp, mash_out_temp, mash_rasts,
+		mash_malts, mash_main_water, mash_nachguss, mash_temp, mash_out_temp, mash_rasts, mash_type, mash_decoctions,
 		hop_cooking_time, hop_hops, hop_additional,
-		ferm_yeast, ferm_temp, ferm_additional, ferm_carbonation
+		ferm_yeast, ferm_temp, ferm_additional, ferm_carbonation, ferm_schedule
 	) 
-	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`, r.Name, r.Style, r.BatchSize, r.InitialSG, r.Bitterness, r.ColorEBC, status, marshalled.StatusParams,
-		marshalled.MashingMalts, r.Mashing.MainWaterVolume, r.Mashing.Nachguss, r.Mashing.MashTemperature, r.Mashing.MashOutTemperature, marshalled.MashingRasts,
+		marshalled.MashingMalts, r.Mashing.MainWaterVolume, r.Mashing.Nachguss, r.Mashing.MashTemperature, r.Mashing.MashOutTemperature, marshalled.MashingRasts, r.Mashing.MashType, marshalled.MashingDecoctions,
 		r.Hopping.TotalCookingTime, marshalled.HopHops, marshalled.HopAdd,
-		marshalled.Yeast, r.Fermentation.Temperature, marshalled.FermAdd, r.Fermentation.Carbonation,
+		marshalled.Yeast, r.Fermentation.Temperature, marshalled.FermAdd, r.Fermentation.Carbonation, marshalled.FermSchedule,
 	)
 	if err != nil {
 		return "", err
@@ -83,11 +83,12 @@ func (s *PersistentStore) Store(r *recipe.Recipe) (string, error) {
 	r.InitResults()
 	initialResults := r.GetResults()
 	_, err = s.dbClient.Exec(`INSERT INTO recipe_results 
-		(hot_wort_vol, original_sg, final_sg, alcohol, main_ferm_vol, vol_bb, recipe_id)
-		VALUES ( ?, ?, ?, ?, ?, ?, ?)
+		(hot_wort_vol, original_sg, final_sg, alcohol, main_ferm_vol, vol_bb, extra_boil_time, recipe_id)
+		VALUES ( ?, ?, ?, ?, ?, ?, ?, ?)
 	`, initialResults.HotWortVolume, initialResults.OriginalGravity,
 		initialResults.FinalGravity, initialResults.Alcohol,
-		initialResults.MainFermentationVolume, initialResults.VolumeBeforeBoil, idString)
+		initialResults.MainFermentationVolume, initialResults.VolumeBeforeBoil,
+		initialResults.ExtraBoilTime, idString)
 	if err != nil {
 		return "", err
 	}
@@ -99,11 +100,12 @@ func (s *PersistentStore) Retrieve(id string) (*recipe.Recipe, error) {
 	var name, style, fermTemp string
 	var batchSizeL, initialSg, ibu, ebc, mashMainWater, mashNachguss, mashTemp, mashOutTemp, hopCooking, fermCarbonation float32
 	var status recipe.RecipeStatus
+	var mashType recipe.MashType
 	toUnmarshall := &MarshalResult{}
 	err := s.retrieveStatement.QueryRow(id).Scan(&name, &style, &batchSizeL, &initialSg, &ibu, &ebc, &status, &toUnmarshall.StatusParams,
-		&toUnmarshall.MashingMalts, &mashMainWater, &mashNachguss, &mashTemp, &mashOutTemp, &toUnmarshall.MashingRasts,
+		&toUnmarshall.MashingMalts, &mashMainWater, &mashNachguss, &mashTemp, &mashOutTemp, &toUnmarshall.MashingRasts, &mashType, &toUnmarshall.MashingDecoctions,
 		&hopCooking, &toUnmarshall.HopHops, &toUnmarshall.HopAdd,
-		&toUnmarshall.Yeast, &fermTemp, &toUnmarshall.FermAdd, &fermCarbonation)
+		&toUnmarshall.Yeast, &fermTemp, &toUnmarshall.FermAdd, &fermCarbonation, &toUnmarshall.FermSchedule)
 	if err != nil {
 		return nil, err
 	}
@@ -126,6 +128,8 @@ func (s *PersistentStore) Retrieve(id string) (*recipe.Recipe, error) {
 			MashTemperature:    mashTemp,
 			MashOutTemperature: mashOutTemp,
 			Rasts:              unmarshaled.MashingRasts,
+			MashType:           mashType,
+			Decoctions:         unmarshaled.MashingDecoctions,
 		},
 		Hopping: recipe.HopInstructions{
 			TotalCookingTime:      hopCooking,
@@ -137,6 +141,7 @@ func (s *PersistentStore) Retrieve(id string) (*recipe.Recipe, error) {
 			Temperature:           fermTemp,
 			AdditionalIngredients: unmarshaled.FermAdd,
 			Carbonation:           fermCarbonation,
+			Schedule:              unmarshaled.FermSchedule,
 		},
 	}
 	r.SetStatus(status, unmarshaled.StatusParams...)
@@ -179,6 +184,38 @@ func (s *PersistentStore) Delete(id string) error {
 	return err
 }
 
+// Update replaces the definition of a stored recipe (name, style, ingredients and instructions)
+// The status and results of the recipe are kept
+func (s *PersistentStore) Update(id string, r *recipe.Recipe) error {
+	marshalled, err := s.marshalStructs(r)
+	if err != nil {
+		return err
+	}
+	res, err := s.dbClient.Exec(`UPDATE recipes SET
+		name = ?, style = ?, batch_size_l = ?, initial_sg = ?, ibu = ?, ebc = ?,
+		mash_malts = ?, mash_main_water = ?, mash_nachguss = ?, mash_temp = ?, mash_out_temp = ?, mash_rasts = ?, mash_type = ?, mash_decoctions = ?,
+		hop_cooking_time = ?, hop_hops = ?, hop_additional = ?,
+		ferm_yeast = ?, ferm_temp = ?, ferm_additional = ?, ferm_carbonation = ?, ferm_schedule = ?
+	WHERE id == ?
+	`, r.Name, r.Style, r.BatchSize, r.InitialSG, r.Bitterness, r.ColorEBC,
+		marshalled.MashingMalts, r.Mashing.MainWaterVolume, r.Mashing.Nachguss, r.Mashing.MashTemperature, r.Mashing.MashOutTemperature, marshalled.MashingRasts, r.Mashing.MashType, marshalled.MashingDecoctions,
+		r.Hopping.TotalCookingTime, marshalled.HopHops, marshalled.HopAdd,
+		marshalled.Yeast, r.Fermentation.Temperature, marshalled.FermAdd, r.Fermentation.Carbonation, marshalled.FermSchedule,
+		id,
+	)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return errors.New("recipe not found")
+	}
+	return nil
+}
+
 // UpdateStatus updates the status of a recipe in the store
 func (s *PersistentStore) UpdateStatus(id string, status recipe.RecipeStatus, statusParams ...string) error {
 	statusArgs, err := s.marshalStatusParams(statusParams...)
@@ -203,6 +240,8 @@ func (s *PersistentStore) columnNameFromType(resultType recipe.ResultType) strin
 		return "main_ferm_vol"
 	case recipe.ResultVolumeBeforeBoil:
 		return "vol_bb"
+	case recipe.ResultExtraBoilTime:
+		return "extra_boil_time"
 	default:
 		return ""
 	}
@@ -236,11 +275,11 @@ func (s *PersistentStore) RetrieveResult(id string, resultType recipe.ResultType
 func (s *PersistentStore) RetrieveResults(id string) (*recipe.RecipeResults, error) {
 	var actual recipe.RecipeResults
 	err := s.dbClient.QueryRow(`
-		SELECT hot_wort_vol, original_sg, final_sg, alcohol, main_ferm_vol, vol_bb
+		SELECT hot_wort_vol, original_sg, final_sg, alcohol, main_ferm_vol, vol_bb, extra_boil_time
 		FROM recipe_results WHERE recipe_id == ?`, id).Scan(
 		&actual.HotWortVolume, &actual.OriginalGravity,
 		&actual.FinalGravity, &actual.Alcohol, &actual.MainFermentationVolume,
-		&actual.VolumeBeforeBoil,
+		&actual.VolumeBeforeBoil, &actual.ExtraBoilTime,
 	)
 	if err != nil {
 		return nil, err
@@ -250,13 +289,13 @@ func (s *PersistentStore) RetrieveResults(id string) (*recipe.RecipeResults, err
 
 // AddMainFermSG adds a new specific gravity measurement to a given recipe
 func (s *PersistentStore) AddMainFermSG(id string, m *recipe.SGMeasurement) error {
-	_, err := s.dbClient.Exec(`INSERT INTO main_ferm_sgs (sg, date, recipe_id) VALUES (?, ?, ?)`, m.Value, m.Date, id)
+	_, err := s.dbClient.Exec(`INSERT INTO main_ferm_sgs (sg, date, temperature, device, recipe_id) VALUES (?, ?, ?, ?, ?)`, m.Value, m.Date, m.Temperature, m.Device, id)
 	return err
 }
 
 // RetrieveMainFermSGs returns all measured sgs for a recipe
 func (s *PersistentStore) RetrieveMainFermSGs(id string) ([]*recipe.SGMeasurement, error) {
-	rows, err := s.dbClient.Query(`SELECT sg, date FROM main_ferm_sgs WHERE recipe_id == ? ORDER BY id ASC`, id)
+	rows, err := s.dbClient.Query(`SELECT sg, date, temperature, device FROM main_ferm_sgs WHERE recipe_id == ? ORDER BY id ASC`, id)
 	if err != nil {
 		return nil, err
 	}
@@ -264,7 +303,7 @@ func (s *PersistentStore) RetrieveMainFermSGs(id string) ([]*recipe.SGMeasuremen
 	results := make([]*recipe.SGMeasurement, 0)
 	for rows.Next() {
 		var m recipe.SGMeasurement
-		err = rows.Scan(&m.Value, &m.Date)
+		err = rows.Scan(&m.Value, &m.Date, &m.Temperature, &m.Device)
 		if err != nil {
 			return nil, err
 		}
@@ -282,9 +321,10 @@ func (s *PersistentStore) AddDate(id string, date *time.Time, name string) error
 
 // RetrieveDates allows to retreive stored dates with its purpose (name).It can be used to store notification dates, or timers
 // It supports pattern in the name to retrieve multiple values
+// The dates are returned in the order they were added
 func (s *PersistentStore) RetrieveDates(id, namePattern string) ([]*time.Time, error) {
 	sanitizedPattern := strings.ReplaceAll(strings.ReplaceAll(namePattern, "_", "!_"), "%", "!%") + "%"
-	rows, err := s.dbClient.Query(`SELECT date FROM dates WHERE recipe_id == ? AND name LIKE ? ESCAPE '!'`, id, sanitizedPattern)
+	rows, err := s.dbClient.Query(`SELECT date FROM dates WHERE recipe_id == ? AND name LIKE ? ESCAPE '!' ORDER BY id`, id, sanitizedPattern)
 	if err != nil {
 		return nil, err
 	}
@@ -337,6 +337,93 @@ func TestUpdateStatus(t *testing.T) {
 	}
 }
 
+func TestUpdate(t *testing.T) {
+	require := require.New(t)
+	fileName := strings.ToLower(strings.TrimSpace(t.Name())) + ".sqlite"
+	db, err := sql.Open("sqlite3", "file:"+fileName+"?_foreign_keys=true")
+	require.NoError(err)
+	err = dbmigrations.RunMigrations(db, "migrations")
+	require.NoError(err)
+	store, err := NewPersistentStore(db)
+	require.NoError(err)
+	id, err := store.Store(&testRecipe)
+	require.NoError(err)
+	defer os.Remove(fileName)
+	err = store.UpdateStatus(id, recipe.RecipeStatusMashing, "rast", "1")
+	require.NoError(err)
+	testCases := []struct {
+		Name   string
+		ID     string
+		Modify func(r *recipe.Recipe)
+		Error  bool
+	}{
+		{
+			Name: "Rename and change hops",
+			ID:   id,
+			Modify: func(r *recipe.Recipe) {
+				r.Name = "Huell Saison v2"
+				r.Hopping.Hops = []recipe.Hops{{Name: "Saaz", Alpha: 3.5, Amount: 20, Duration: 60}}
+			},
+		},
+		{
+			Name: "Change rasts and yeast",
+			ID:   id,
+			Modify: func(r *recipe.Recipe) {
+				r.Mashing.Rasts = []recipe.Rast{{Temperature: 66, Duration: 60}}
+				r.Fermentation.Yeast = recipe.Yeast{Name: "Danstar Belle Saison", Amount: 11}
+			},
+		},
+		{
+			Name: "Mash by decoction",
+			ID:   id,
+			Modify: func(r *recipe.Recipe) {
+				r.Mashing.Rasts = []recipe.Rast{{Temperature: 52, Duration: 10}, {Temperature: 64, Duration: 30}}
+				r.Mashing.MashType = recipe.MashTypeDecoction
+				r.Mashing.Decoctions = []recipe.Decoction{{Rast: 1, Volume: 4, RestTemperature: 72, RestDuration: 10, BoilDuration: 15}}
+			},
+		},
+		{
+			Name: "Fermentation schedule",
+			ID:   id,
+			Modify: func(r *recipe.Recipe) {
+				r.Fermentation.Schedule = []recipe.FermentationStep{
+					{Name: "Primary", Temperature: 10, Days: 5},
+					{Name: "Diacetyl rest", Temperature: 14, Days: 2, FreeRise: true},
+					{Name: "Cold crash", Temperature: 2},
+				}
+			},
+		},
+		{
+			Name:   "Non existent recipe",
+			ID:     "100",
+			Modify: func(r *recipe.Recipe) {},
+			Error:  true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			r, err := store.Retrieve(id)
+			require.NoError(err)
+			tc.Modify(r)
+			err = store.Update(tc.ID, r)
+			if tc.Error {
+				require.Error(err)
+				return
+			}
+			require.NoError(err)
+			actual, err := store.Retrieve(id)
+			require.NoError(err)
+			require.Equal(r.Name, actual.Name)
+			require.Equal(r.Mashing, actual.Mashing)
+			require.Equal(r.Hopping, actual.Hopping)
+			require.Equal(r.Fermentation, actual.Fermentation)
+			status, params := actual.GetStatus()
+			require.Equal(recipe.RecipeStatusMashing, status)
+			require.Equal([]string{"rast", "1"}, params)
+		})
+	}
+}
+
 func TestUpdateResult(t *testing.T) {
 	require := require.New(t)
 	testCases := []struct {
@@ -407,6 +494,16 @@ func TestUpdateResult(t *testing.T) {
 			},
 			Error: false,
 		},
+		{
+			Name:        "Update extra boil time",
+			RecipeID:    "test",
+			UpdateType:  recipe.ResultExtraBoilTime,
+			UpdateValue: 15,
+			Expected: &recipe.RecipeResults{
+				ExtraBoilTime: 15,
+			},
+			Error: false,
+		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.Name, func(t *testing.T) {
@@ -490,6 +587,16 @@ func TestUpdateSGs(t *testing.T) {
 			},
 			Error: false,
 		},
+		{
+			Name: "Hydrometer readings",
+			ToAdd: map[string][]*recipe.SGMeasurement{
+				"recipe1": {
+					{Value: 1.013, Date: time.Now().Format("2006-01-02")},
+					{Value: 1.0125, Date: time.Now().Format("2006-01-02 15:04"), Temperature: 18.5, Device: "iSpindel001"},
+				},
+			},
+			Error: false,
+		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.Name, func(t *testing.T) {
@@ -557,6 +664,13 @@ func TestDates(t *testing.T) {
 			},
 			Error: false,
 		},
+		{
+			Name: "Dates in the order they were added",
+			ToAdd: map[string][]time.Time{
+				"recipe_1": {t4, t1, t3, t2},
+			},
+			Error: false,
+		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.Name, func(t *testing.T) {
@@ -591,7 +705,7 @@ func TestDates(t *testing.T) {
 					for i, d := range dates {
 						datesString[i] = d.Format(time.RFC3339)
 					}
-					require.ElementsMatch(datesString, realDatesString)
+					require.Equal(datesString, realDatesString)
 				}
 			}
 		})
@@ -773,3 +887,36 @@ func TestBoolFlags(t *testing.T) {
 		})
 	}
 }
+
+func TestLibrary(t *testing.T) {
+	require := require.New(t)
+	fileName := strings.ToLower(strings.TrimSpace(t.Name())) + ".sqlite"
+	db, err := sql.Open("sqlite3", "file:"+fileName+"?_foreign_keys=true")
+	require.NoError(err)
+	err = dbmigrations.RunMigrations(db, "migrations")
+	require.NoError(err)
+	store, err := NewPersistentStore(db)
+	require.NoError(err)
+	defer os.Remove(fileName)
+	id, err := store.AddToLibrary(&testRecipe)
+	require.NoError(err)
+	_, err = store.AddToLibrary(&recipe.Recipe{Name: "A Pils"})
+	require.NoError(err)
+	actual, err := store.RetrieveFromLibrary(id)
+	require.NoError(err)
+	require.Equal(id, actual.ID)
+	require.Equal(testRecipe.Name, actual.Name)
+	require.Equal(testRecipe.BatchSize, actual.BatchSize)
+	require.Equal(testRecipe.Mashing, actual.Mashing)
+	require.Equal(testRecipe.Hopping, actual.Hopping)
+	require.Equal(testRecipe.Fermentation, actual.Fermentation)
+	list, err := store.ListLibrary()
+	require.NoError(err)
+	require.Len(list, 2)
+	require.Equal("A Pils", list[0].Name)
+	require.Equal(testRecipe.Name, list[1].Name)
+	require.NoError(store.DeleteFromLibrary(id))
+	_, err = store.RetrieveFromLibrary(id)
+	require.Error(err)
+	require.Error(store.DeleteFromLibrary(id))
+}
@@ -2,8 +2,8 @@ package memory
 
 import (
 	"brewday/internal/summary"
-	"brewday/internal/tools"
 	"errors"
+	"strconv"
 	"sync"
 	"time"
 )
@@ -11,7 +11,8 @@ import (
 type SummaryMemoryStore struct {
 	lock      sync.Mutex
 	summaries map[string]*summary.Summary
-	stats     map[string]*summary.Statistics // In here stats is a backup, in case the summary is deleted
+	stats     map[string]*summary.Statistics // In here stats is a backup, in case the summary is deleted. It is keyed by recipe id
+	externals int                            // externals is the number of external stats, used to key them
 }
 
 func NewSummaryMemoryStore() *SummaryMemoryStore {
@@ -36,8 +37,9 @@ func (s *SummaryMemoryStore) AddSummary(recipeID, title string) error {
 	defer s.lock.Unlock()
 	summ := summary.NewSummary()
 	summ.Title = title
+	summ.Statistics = &summary.Statistics{RecipeName: title, RecipeID: recipeID}
 	s.summaries[recipeID] = summ
-	s.stats[tools.B64Encode(title)] = summ.Statistics
+	s.stats[recipeID] = summ.Statistics
 	return nil
 }
 
@@ -145,6 +147,22 @@ func (s *SummaryMemoryStore) AddVolumeBeforeBoil(id string, amount float32, note
 	return nil
 }
 
+// AddBoilAdjustment adds the correction decided for the boil to the summary
+func (s *SummaryMemoryStore) AddBoilAdjustment(id string, adjustment *summary.BoilAdjustment) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	sum, err := s.getSummary(id)
+	if err != nil {
+		return err
+	}
+	if sum.HoppingInfo == nil {
+		sum.HoppingInfo = &summary.HoppingInfo{}
+	}
+	stored := *adjustment
+	sum.HoppingInfo.BoilAdjustment = &stored
+	return nil
+}
+
 // AddVolumeAfterBoil adds the measured volume after boiling the wort to the summary
 func (s *SummaryMemoryStore) AddVolumeAfterBoil(id string, amount float32, notes string) error {
 	s.lock.Lock()
@@ -216,6 +234,38 @@ func (s *SummaryMemoryStore) AddYeastStart(id string, temperature, notes string)
 	return nil
 }
 
+// AddYeastPitch adds the pitch rate calculation to the summary
+func (s *SummaryMemoryStore) AddYeastPitch(id string, pitch *summary.YeastPitch) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	sum, err := s.getSummary(id)
+	if err != nil {
+		return err
+	}
+	if sum.YeastInfo == nil {
+		sum.YeastInfo = &summary.YeastInfo{}
+	}
+	stored := *pitch
+	sum.YeastInfo.Pitch = &stored
+	return nil
+}
+
+// AddYeastExpectation adds the final gravity and alcohol expected from the yeast to the summary
+func (s *SummaryMemoryStore) AddYeastExpectation(id string, expectation *summary.YeastExpectation) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	sum, err := s.getSummary(id)
+	if err != nil {
+		return err
+	}
+	if sum.YeastInfo == nil {
+		sum.YeastInfo = &summary.YeastInfo{}
+	}
+	stored := *expectation
+	sum.YeastInfo.Expectation = &stored
+	return nil
+}
+
 // AddMainFermentationSGMeasurement adds a SG measurement to the summary
 func (s *SummaryMemoryStore) AddMainFermentationSGMeasurement(id string, date string, gravity float32, final bool, notes string) error {
 	s.lock.Lock()
@@ -363,10 +413,10 @@ func (s *SummaryMemoryStore) AddEvaporation(id string, amount float32) error {
 		return err
 	}
 	if sum.Statistics == nil {
-		sum.Statistics = &summary.Statistics{}
+		sum.Statistics = &summary.Statistics{RecipeName: sum.Title, RecipeID: id}
 	}
 	sum.Statistics.Evaporation = amount
-	s.stats[tools.B64Encode(sum.Title)] = sum.Statistics
+	s.stats[id] = sum.Statistics
 	return nil
 }
 
@@ -379,10 +429,10 @@ func (s *SummaryMemoryStore) AddEfficiency(id string, efficiencyPercentage float
 		return err
 	}
 	if sum.Statistics == nil {
-		sum.Statistics = &summary.Statistics{}
+		sum.Statistics = &summary.Statistics{RecipeName: sum.Title, RecipeID: id}
 	}
 	sum.Statistics.Efficiency = efficiencyPercentage
-	s.stats[tools.B64Encode(sum.Title)] = sum.Statistics
+	s.stats[id] = sum.Statistics
 	return nil
 }
 
@@ -397,15 +447,13 @@ func (s *SummaryMemoryStore) GetSummary(id string) (*summary.Summary, error) {
 	return sum, nil
 }
 
-// GetAllStats returns all the statistics
-func (s *SummaryMemoryStore) GetAllStats() (map[string]*summary.Statistics, error) {
-	res := map[string]*summary.Statistics{}
-	for nb64, v := range s.stats {
-		decoded, err := tools.B64Decode(nb64)
-		if err != nil {
-			return nil, err
-		}
-		res[decoded] = v
+// GetAllStats returns the statistics of all the brews
+func (s *SummaryMemoryStore) GetAllStats() ([]*summary.Statistics, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	res := make([]*summary.Statistics, 0, len(s.stats))
+	for _, v := range s.stats {
+		res = append(res, v)
 	}
 	return res, nil
 }
@@ -419,14 +467,21 @@ func (s *SummaryMemoryStore) AddFinishedTime(id string, t time.Time) error {
 		return err
 	}
 	if sum.Statistics == nil {
-		sum.Statistics = &summary.Statistics{}
+		sum.Statistics = &summary.Statistics{RecipeName: sum.Title, RecipeID: id}
 	}
 	sum.Statistics.FinishedTime = t
-	s.stats[tools.B64Encode(sum.Title)] = sum.Statistics
+	s.stats[id] = sum.Statistics
 	return nil
 }
 
+// AddStatsExternal adds the statistics of a brew done outside the app
+// Several external brews can have the same recipe name
 func (s *SummaryMemoryStore) AddStatsExternal(recipeName string, stats *summary.Statistics) error {
-	s.stats[tools.B64Encode(recipeName)] = stats
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.externals++
+	st := *stats
+	st.RecipeName = recipeName
+	s.stats["external-"+strconv.Itoa(s.externals)] = &st
 	return nil
 }
@@ -70,6 +70,9 @@ func TestPrint(t *testing.T) {
 				},
 				HoppingInfo: &summary.HoppingInfo{
 					VolBeforeBoil: &summary.VolMeasurement{Volume: 13.10, Notes: "notes4"},
+					BoilAdjustment: &summary.BoilAdjustment{
+						Decision: summary.BoilDecisionExtend, PreBoilSG: 1.046, PostBoilSG: 1.052, PostBoilVolume: 11.5, ExtraBoilTime: 15, Notes: "notes4b",
+					},
 					HopInfos: []*summary.HopInfo{
 						{Name: "Karamellsirup", Grams: 450.00, Alpha: 0.00, Time: 60.00, TimeUnit: "minutes", Notes: "notes5"},
 						{Name: "Hallertauer Tradition", Grams: 15.00, Alpha: 5.50, Time: 60.00, TimeUnit: "minutes", Notes: "notes6"},
@@ -90,6 +93,14 @@ func TestPrint(t *testing.T) {
 				YeastInfo: &summary.YeastInfo{
 					Temperature: "20.00",
 					Notes:       "notes13",
+					Pitch: &summary.YeastPitch{
+						PitchRate: 0.75, Form: "liquid", Amount: 1, Viability: 72, CellsNeeded: 180, CellsAvailable: 72,
+						StarterModel: "stir_plate", StarterVolume: 1.5, StarterDME: 150, CellsPitched: 282,
+					},
+					Expectation: &summary.YeastExpectation{
+						Yeast: "Safale US-05", Lab: "Fermentis", Known: true, OriginalGravity: 1.054, MinAttenuation: 78, MaxAttenuation: 82,
+						MinFinalGravity: 1.010, MaxFinalGravity: 1.012, MinAlcohol: 5.5, MaxAlcohol: 5.8,
+					},
 				},
 				MainFermentationInfo: &summary.MainFermentationInfo{
 					SGs: []*summary.SGMeasurement{
@@ -145,6 +156,7 @@ lautern info 2
 ## Hopping
 
 - **Measured volume - Measured volume before boiling**: 13.10L (notes4)
+- **Boil correction**: expected SG 1.046 before boiling and 11.50L with SG 1.052 after the planned boil. Extended the boil by 15 minutes (notes4b)
 - **Karamellsirup**: 450.00g (0.00%% alpha) [60.00 minutes] (notes5)
 - **Hallertauer Tradition**: 15.00g (5.50%% alpha) [60.00 minutes] (notes6)
 - **Saazer**: 16.00g (3.60%% alpha) [20.00 minutes] (notes7)
@@ -166,6 +178,10 @@ Reached 20.00°C in 20.00 minutes (notes10)
 ## Yeast start
 
 - **Temperature**: 20.00°C
+- **Pitch rate**: 0.75 million cells/ml/°P, 180 billion cells needed
+- **Yeast**: 1 pack(s) with 72%% viability (72 billion cells)
+- **Starter**: 1.5L with 150g of DME (stir_plate), 282 billion cells pitched
+- **Expected FG**: 1.010-1.012 with 5.5-5.8%% alcohol, 78-82%% attenuation of Safale US-05 (Fermentis)
 
 notes13
 
@@ -16,9 +16,47 @@ type SummaryPersistentStore struct {
 }
 
 func NewSummaryPersistentStore(db *sql.DB) (*SummaryPersistentStore, error) {
-	return &SummaryPersistentStore{
+	s := &SummaryPersistentStore{
 		dbClient: db,
-	}, nil
+	}
+	err := s.linkLegacyStats()
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// linkLegacyStats sets the recipe id of the stats created when they were identified only by the recipe title
+// Titles were unique back then, so the oldest stats without recipe id and with the title of a summary belong to it
+// Summaries that already have stats are skipped, so external stats with the same title are not linked
+func (s *SummaryPersistentStore) linkLegacyStats() error {
+	rows, err := s.dbClient.Query(`SELECT recipe_id, title FROM summaries`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	summaries := make(map[string]string)
+	for rows.Next() {
+		var id, title string
+		err = rows.Scan(&id, &title)
+		if err != nil {
+			return err
+		}
+		summaries[id] = title
+	}
+	err = rows.Err()
+	if err != nil {
+		return err
+	}
+	for id, title := range summaries {
+		_, err = s.dbClient.Exec(`UPDATE stats SET recipe_id = ?
+			WHERE id == (SELECT MIN(id) FROM stats WHERE recipe_id IS NULL AND recipe_title == ?)
+			AND NOT EXISTS (SELECT 1 FROM stats WHERE recipe_id == ?)`, id, tools.B64Encode(title), id)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // AddSummary adds a summary for the given recipe id with the given title, it also inits an entry in the stats table
@@ -27,7 +65,7 @@ func (s *SummaryPersistentStore) AddSummary(recipeID, title string) error {
 	if err != nil {
 		return err
 	}
-	_, err = s.dbClient.Exec(`INSERT INTO stats (recipe_title) VALUES (?)`, tools.B64Encode(title))
+	_, err = s.dbClient.Exec(`INSERT INTO stats (recipe_title, recipe_id) VALUES (?, ?)`, tools.B64Encode(title), recipeID)
 	return err
 }
 
@@ -104,6 +142,19 @@ func (s *SummaryPersistentStore) AddVolumeBeforeBoil(id string, amount float32,
 	return err
 }
 
+// AddBoilAdjustment adds the correction decided for the boil to the summary
+func (s *SummaryPersistentStore) AddBoilAdjustment(id string, adjustment *summary.BoilAdjustment) error {
+	if id == "" {
+		return errors.New("invalid empty recipe id")
+	}
+	adjustmentBytes, err := json.Marshal(adjustment)
+	if err != nil {
+		return err
+	}
+	_, err = s.dbClient.Exec(`UPDATE summaries SET hopping_boil_adjustment = ? WHERE recipe_id == ?`, string(adjustmentBytes), id)
+	return err
+}
+
 // AddVolumeBeforeBoil adds the measured volume after boiling the wort to the summary
 func (s *SummaryPersistentStore) AddVolumeAfterBoil(id string, amount float32, notes string) error {
 	if id == "" {
@@ -148,6 +199,32 @@ func (s *SummaryPersistentStore) AddYeastStart(id string, temperature, notes str
 	return err
 }
 
+// AddYeastPitch adds the pitch rate calculation to the summary
+func (s *SummaryPersistentStore) AddYeastPitch(id string, pitch *summary.YeastPitch) error {
+	if id == "" {
+		return errors.New("invalid empty recipe id")
+	}
+	pitchBytes, err := json.Marshal(pitch)
+	if err != nil {
+		return err
+	}
+	_, err = s.dbClient.Exec(`UPDATE summaries SET yeast_pitch = ? WHERE recipe_id == ?`, string(pitchBytes), id)
+	return err
+}
+
+// AddYeastExpectation adds the final gravity and alcohol expected from the yeast to the summary
+func (s *SummaryPersistentStore) AddYeastExpectation(id string, expectation *summary.YeastExpectation) error {
+	if id == "" {
+		return errors.New("invalid empty recipe id")
+	}
+	expectationBytes, err := json.Marshal(expectation)
+	if err != nil {
+		return err
+	}
+	_, err = s.dbClient.Exec(`UPDATE summaries SET yeast_expectation = ? WHERE recipe_id == ?`, string(expectationBytes), id)
+	return err
+}
+
 // AddMainFermentationSGMeasurement adds a SG measurement to the summary
 func (s *SummaryPersistentStore) AddMainFermentationSGMeasurement(id string, date string, gravity float32, final bool, notes string) error {
 	if id == "" {
@@ -259,42 +336,34 @@ func (s *SummaryPersistentStore) AddSummarySecondary(id string, days int, notes
 	return err
 }
 
-func (s *SummaryPersistentStore) getRecipeTitleB64(id string) (string, error) {
+// updateStats sets a column of the stats of the given recipe id
+// The column name must not come from user input
+func (s *SummaryPersistentStore) updateStats(id, column string, value any) error {
 	if id == "" {
-		return "", errors.New("invalid empty recipe id")
+		return errors.New("invalid empty recipe id")
 	}
-	var title string
-	err := s.dbClient.QueryRow(`SELECT title FROM summaries WHERE recipe_id == ?`, id).Scan(&title)
+	res, err := s.dbClient.Exec(`UPDATE stats SET `+column+` = ? WHERE recipe_id == ?`, value, id)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
 	if err != nil {
-		return "", err
+		return err
 	}
-	return tools.B64Encode(title), nil
+	if affected == 0 {
+		return errors.New("no stats found for recipe id " + id)
+	}
+	return nil
 }
 
 // AddEvaporation adds an evaporation to the summary
 func (s *SummaryPersistentStore) AddEvaporation(id string, amount float32) error {
-	if id == "" {
-		return errors.New("invalid empty recipe id")
-	}
-	t, err := s.getRecipeTitleB64(id)
-	if err != nil {
-		return err
-	}
-	_, err = s.dbClient.Exec(`UPDATE stats SET evaporation = ? WHERE recipe_title == ?`, amount, t)
-	return err
+	return s.updateStats(id, "evaporation", amount)
 }
 
 // AddEfficiency adds the efficiency (sudhausausbeute) to the summary
 func (s *SummaryPersistentStore) AddEfficiency(id string, efficiencyPercentage float32) error {
-	if id == "" {
-		return errors.New("invalid empty recipe id")
-	}
-	t, err := s.getRecipeTitleB64(id)
-	if err != nil {
-		return err
-	}
-	_, err = s.dbClient.Exec(`UPDATE stats SET efficiency = ? WHERE recipe_title == ?`, efficiencyPercentage, t)
-	return err
+	return s.updateStats(id, "efficiency", efficiencyPercentage)
 }
 
 // GetSummary returns the summary
@@ -303,22 +372,22 @@ func (s *SummaryPersistentStore) GetSummary(id string) (*summary.Summary, error)
 		return nil, errors.New("invalid empty recipe id")
 	}
 	var title string
-	var mash_notes, mash_rasts, lautern_info, hopping_vol_bb_notes, hopping_hops, hopping_vol_ab_notes, cooling_notes, pre_ferm_vols, yeast_start_temp, yeast_start_notes, main_ferm_sgs, main_ferm_dry_hops, bottling_sugar_type, bottling_notes, sec_ferm_notes sql.NullString
+	var mash_notes, mash_rasts, lautern_info, hopping_vol_bb_notes, hopping_boil_adjustment, hopping_hops, hopping_vol_ab_notes, cooling_notes, pre_ferm_vols, yeast_start_temp, yeast_start_notes, yeast_pitch, yeast_expectation, main_ferm_sgs, main_ferm_dry_hops, bottling_sugar_type, bottling_notes, sec_ferm_notes sql.NullString
 	var mash_temp, hopping_vol_bb, hopping_vol_ab, cooling_temp, cooling_time, main_ferm_alcohol, bottling_pre_bottle_volume, bottling_carbonation, bottling_sugar_amount, bottling_water, bottling_temperature, bottling_alcohol, bottling_volume_bottled, evaporation, efficiency, lautern_duration, bottling_time_min sql.NullFloat64
 	var sec_ferm_days sql.NullInt32
 	err := s.dbClient.QueryRow(
 		`SELECT title, mash_temp, mash_notes, mash_rasts,
-		lautern_info, lautern_duration, hopping_vol_bb, hopping_vol_bb_notes, hopping_hops,
+		lautern_info, lautern_duration, hopping_vol_bb, hopping_vol_bb_notes, hopping_boil_adjustment, hopping_hops,
 		hopping_vol_ab, hopping_vol_ab_notes, cooling_temp, cooling_time,
-		cooling_notes, pre_ferm_vols, yeast_start_temp, yeast_start_notes,
+		cooling_notes, pre_ferm_vols, yeast_start_temp, yeast_start_notes, yeast_pitch, yeast_expectation,
 		main_ferm_sgs, main_ferm_alcohol, main_ferm_dry_hops, bottling_pre_bottle_volume,
 		bottling_carbonation, bottling_sugar_amount, bottling_sugar_type, bottling_water, bottling_temperature,
 		bottling_alcohol, bottling_volume_bottled, bottling_time_min, bottling_notes, sec_ferm_days,
 		sec_ferm_notes FROM summaries WHERE recipe_id == ?`, id).Scan(
 		&title, &mash_temp, &mash_notes, &mash_rasts,
-		&lautern_info, &lautern_duration, &hopping_vol_bb, &hopping_vol_bb_notes, &hopping_hops,
+		&lautern_info, &lautern_duration, &hopping_vol_bb, &hopping_vol_bb_notes, &hopping_boil_adjustment, &hopping_hops,
 		&hopping_vol_ab, &hopping_vol_ab_notes, &cooling_temp, &cooling_time,
-		&cooling_notes, &pre_ferm_vols, &yeast_start_temp, &yeast_start_notes,
+		&cooling_notes, &pre_ferm_vols, &yeast_start_temp, &yeast_start_notes, &yeast_pitch, &yeast_expectation,
 		&main_ferm_sgs, &main_ferm_alcohol, &main_ferm_dry_hops, &bottling_pre_bottle_volume,
 		&bottling_carbonation, &bottling_sugar_amount, &bottling_sugar_type, &bottling_water, &bottling_temperature,
 		&bottling_alcohol, &bottling_volume_bottled, &bottling_time_min, &bottling_notes, &sec_ferm_days,
@@ -327,7 +396,7 @@ func (s *SummaryPersistentStore) GetSummary(id string) (*summary.Summary, error)
 	if err != nil {
 		return nil, err
 	}
-	err = s.dbClient.QueryRow(`SELECT evaporation, efficiency FROM stats WHERE recipe_title == ?`, tools.B64Encode(title)).Scan(&evaporation, &efficiency)
+	err = s.dbClient.QueryRow(`SELECT evaporation, efficiency FROM stats WHERE recipe_id == ?`, id).Scan(&evaporation, &efficiency)
 	if err != nil {
 		return nil, err
 	}
@@ -336,6 +405,13 @@ func (s *SummaryPersistentStore) GetSummary(id string) (*summary.Summary, error)
 	if err != nil {
 		return nil, err
 	}
+	var boilAdjustment *summary.BoilAdjustment
+	if hopping_boil_adjustment.Valid {
+		err = json.Unmarshal([]byte(hopping_boil_adjustment.String), &boilAdjustment)
+		if err != nil {
+			return nil, err
+		}
+	}
 	var hopInfos, dryHopInfos []*summary.HopInfo
 	err = json.Unmarshal([]byte(s.sliceFromNullString(hopping_hops)), &hopInfos)
 	if err != nil {
@@ -350,6 +426,20 @@ func (s *SummaryPersistentStore) GetSummary(id string) (*summary.Summary, error)
 	if err != nil {
 		return nil, err
 	}
+	var yeastPitch *summary.YeastPitch
+	if yeast_pitch.Valid {
+		err = json.Unmarshal([]byte(yeast_pitch.String), &yeastPitch)
+		if err != nil {
+			return nil, err
+		}
+	}
+	var yeastExpectation *summary.YeastExpectation
+	if yeast_expectation.Valid {
+		err = json.Unmarshal([]byte(yeast_expectation.String), &yeastExpectation)
+		if err != nil {
+			return nil, err
+		}
+	}
 	var sgs []*summary.SGMeasurement
 	err = json.Unmarshal([]byte(s.sliceFromNullString(main_ferm_sgs)), &sgs)
 	if err != nil {
@@ -375,7 +465,8 @@ func (s *SummaryPersistentStore) GetSummary(id string) (*summary.Summary, error)
 				Volume: s.valueFromNullFloat(hopping_vol_ab),
 				Notes:  s.valueFromNullString(hopping_vol_ab_notes),
 			},
-			HopInfos: hopInfos,
+			BoilAdjustment: boilAdjustment,
+			HopInfos:       hopInfos,
 		},
 		CoolingInfo: &summary.CoolingInfo{
 			Temperature: s.valueFromNullFloat(cooling_temp),
@@ -386,6 +477,8 @@ func (s *SummaryPersistentStore) GetSummary(id string) (*summary.Summary, error)
 		YeastInfo: &summary.YeastInfo{
 			Temperature: s.valueFromNullString(yeast_start_temp),
 			Notes:       s.valueFromNullString(yeast_start_notes),
+			Pitch:       yeastPitch,
+			Expectation: yeastExpectation,
 		},
 		MainFermentationInfo: &summary.MainFermentationInfo{
 			SGs:        sgs,
@@ -416,45 +509,44 @@ func (s *SummaryPersistentStore) GetSummary(id string) (*summary.Summary, error)
 
 }
 
-// GetAllStats returns all the statistics
-func (s *SummaryPersistentStore) GetAllStats() (map[string]*summary.Statistics, error) {
-	rows, err := s.dbClient.Query(`SELECT recipe_title, evaporation, efficiency, finished_epoch FROM stats`)
+// GetAllStats returns the statistics of all the brews
+func (s *SummaryPersistentStore) GetAllStats() ([]*summary.Statistics, error) {
+	rows, err := s.dbClient.Query(`SELECT recipe_title, recipe_id, evaporation, efficiency, finished_epoch FROM stats ORDER BY id`)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	res := make(map[string]*summary.Statistics)
+	res := make([]*summary.Statistics, 0)
 	for rows.Next() {
 		r := summary.Statistics{}
 		var title string
+		var recipeID sql.NullString
+		var evaporation, efficiency sql.NullFloat64
 		var epoch sql.NullInt64
-		err = rows.Scan(&title, &r.Evaporation, &r.Efficiency, &epoch)
+		err = rows.Scan(&title, &recipeID, &evaporation, &efficiency, &epoch)
 		if err != nil {
 			return nil, err
 		}
-		r.FinishedTime = time.Unix(s.valueFromNullInt64(epoch), 0)
-		titleDecoded, err := tools.B64Decode(title)
+		r.RecipeName, err = tools.B64Decode(title)
 		if err != nil {
 			return nil, err
 		}
-		res[titleDecoded] = &r
+		r.RecipeID = s.valueFromNullString(recipeID)
+		r.Evaporation = s.valueFromNullFloat(evaporation)
+		r.Efficiency = s.valueFromNullFloat(efficiency)
+		r.FinishedTime = time.Unix(s.valueFromNullInt64(epoch), 0)
+		res = append(res, &r)
 	}
-	return res, nil
+	return res, rows.Err()
 }
 
 // AddFinishedTime adds the time when the recipe was done, mainly for statistics
 func (s *SummaryPersistentStore) AddFinishedTime(id string, t time.Time) error {
-	if id == "" {
-		return errors.New("invalid empty recipe id")
-	}
-	title, err := s.getRecipeTitleB64(id)
-	if err != nil {
-		return err
-	}
-	_, err = s.dbClient.Exec(`UPDATE stats SET finished_epoch = ? WHERE recipe_title == ?`, t.Unix(), title)
-	return err
+	return s.updateStats(id, "finished_epoch", t.Unix())
 }
 
+// AddStatsExternal adds the statistics of a brew done outside the app
+// Several external brews can have the same recipe name
 func (s *SummaryPersistentStore) AddStatsExternal(recipeName string, stats *summary.Statistics) error {
 	_, err := s.dbClient.Exec(`INSERT INTO stats (recipe_title, finished_epoch, evaporation, efficiency) VALUES (?, ?, ?, ?)`,
 		tools.B64Encode(recipeName),
@@ -670,6 +670,63 @@ func TestAddVolumeBeforeBoil(t *testing.T) {
 	}
 }
 
+func TestAddBoilAdjustment(t *testing.T) {
+	require := require.New(t)
+	fileName := strings.ToLower(strings.TrimSpace(t.Name())) + ".sqlite"
+	db, err := sql.Open("sqlite3", "file:"+fileName+"?_foreign_keys=true")
+	require.NoError(err)
+	provisionDB(t, db, []string{"recipe1", "recipe2"})
+	err = dbmigrations.RunMigrations(db, "migrations")
+	require.NoError(err)
+	store, err := NewSummaryPersistentStore(db)
+	require.NoError(err)
+	defer os.Remove(fileName)
+	require.NoError(store.AddSummary("1", "t1"))
+
+	testCases := []struct {
+		Name       string
+		RecipeID   string
+		Adjustment *summary.BoilAdjustment
+		Error      bool
+	}{
+		{
+			Name:     "Extended boil",
+			RecipeID: "1",
+			Adjustment: &summary.BoilAdjustment{
+				Decision: summary.BoilDecisionExtend, PreBoilSG: 1.042, PostBoilSG: 1.048, PostBoilVolume: 22.5, ExtraBoilTime: 20, Notes: "notes",
+			},
+		},
+		{
+			Name:     "Decision replaced",
+			RecipeID: "1",
+			Adjustment: &summary.BoilAdjustment{
+				Decision: summary.BoilDecisionKeep, PreBoilSG: 1.042, PostBoilSG: 1.048, PostBoilVolume: 22.5,
+			},
+		},
+		{
+			Name:       "Empty RecipeID",
+			RecipeID:   "",
+			Adjustment: &summary.BoilAdjustment{Decision: summary.BoilDecisionKeep},
+			Error:      true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			err = store.AddBoilAdjustment(tc.RecipeID, tc.Adjustment)
+			if tc.Error {
+				require.Error(err)
+				return
+			}
+			require.NoError(err)
+			var stored string
+			require.NoError(db.QueryRow(`SELECT hopping_boil_adjustment FROM summaries WHERE recipe_id = ?`, tc.RecipeID).Scan(&stored))
+			var actual summary.BoilAdjustment
+			require.NoError(json.Unmarshal([]byte(stored), &actual))
+			require.Equal(*tc.Adjustment, actual)
+		})
+	}
+}
+
 func TestAddVolumeAfterBoil(t *testing.T) {
 	require := require.New(t)
 	fileName := strings.ToLower(strings.TrimSpace(t.Name())) + ".sqlite"
@@ -1023,6 +1080,122 @@ func TestAddYeastStart(t *testing.T) {
 	}
 }
 
+func TestAddYeastPitch(t *testing.T) {
+	require := require.New(t)
+	fileName := strings.ToLower(strings.TrimSpace(t.Name())) + ".sqlite"
+	db, err := sql.Open("sqlite3", "file:"+fileName+"?_foreign_keys=true")
+	require.NoError(err)
+	provisionDB(t, db, []string{"recipe1", "recipe2"})
+	err = dbmigrations.RunMigrations(db, "migrations")
+	require.NoError(err)
+	store, err := NewSummaryPersistentStore(db)
+	require.NoError(err)
+	defer os.Remove(fileName)
+	require.NoError(store.AddSummary("1", "t1"))
+
+	testCases := []struct {
+		Name     string
+		RecipeID string
+		Pitch    *summary.YeastPitch
+		Error    bool
+	}{
+		{
+			Name:     "Dry yeast",
+			RecipeID: "1",
+			Pitch: &summary.YeastPitch{
+				PitchRate: 0.75, Form: "dry", Amount: 11.5, Viability: 100, CellsNeeded: 93, CellsAvailable: 115, StarterModel: "none", CellsPitched: 115,
+			},
+		},
+		{
+			Name:     "Liquid yeast with starter replaced",
+			RecipeID: "1",
+			Pitch: &summary.YeastPitch{
+				PitchRate: 1.5, Form: "liquid", Amount: 1, ProductionDate: "2026-08-18", Viability: 58, CellsNeeded: 360, CellsAvailable: 58,
+				StarterModel: "stir_plate", StarterVolume: 2.5, StarterDME: 250, CellsPitched: 408,
+			},
+		},
+		{
+			Name:     "Empty RecipeID",
+			RecipeID: "",
+			Pitch:    &summary.YeastPitch{PitchRate: 0.75},
+			Error:    true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			err = store.AddYeastPitch(tc.RecipeID, tc.Pitch)
+			if tc.Error {
+				require.Error(err)
+				return
+			}
+			require.NoError(err)
+			var stored string
+			require.NoError(db.QueryRow(`SELECT yeast_pitch FROM summaries WHERE recipe_id = ?`, tc.RecipeID).Scan(&stored))
+			var actual summary.YeastPitch
+			require.NoError(json.Unmarshal([]byte(stored), &actual))
+			require.Equal(*tc.Pitch, actual)
+		})
+	}
+}
+
+func TestAddYeastExpectation(t *testing.T) {
+	require := require.New(t)
+	fileName := strings.ToLower(strings.TrimSpace(t.Name())) + ".sqlite"
+	db, err := sql.Open("sqlite3", "file:"+fileName+"?_foreign_keys=true")
+	require.NoError(err)
+	provisionDB(t, db, []string{"recipe1", "recipe2"})
+	err = dbmigrations.RunMigrations(db, "migrations")
+	require.NoError(err)
+	store, err := NewSummaryPersistentStore(db)
+	require.NoError(err)
+	defer os.Remove(fileName)
+	require.NoError(store.AddSummary("1", "t1"))
+
+	testCases := []struct {
+		Name        string
+		RecipeID    string
+		Expectation *summary.YeastExpectation
+		Error       bool
+	}{
+		{
+			Name:     "Yeast in the database",
+			RecipeID: "1",
+			Expectation: &summary.YeastExpectation{
+				Yeast: "Safale US-05", Lab: "Fermentis", Known: true, OriginalGravity: 1.050, MinAttenuation: 78, MaxAttenuation: 82,
+				MinFinalGravity: 1.009, MaxFinalGravity: 1.011, MinAlcohol: 5.1, MaxAlcohol: 5.4,
+			},
+		},
+		{
+			Name:     "Unknown yeast replaced",
+			RecipeID: "1",
+			Expectation: &summary.YeastExpectation{
+				Yeast: "Hefe vom Nachbarn", OriginalGravity: 1.048, MinAttenuation: 75, MaxAttenuation: 75,
+				MinFinalGravity: 1.012, MaxFinalGravity: 1.012, MinAlcohol: 4.7, MaxAlcohol: 4.7,
+			},
+		},
+		{
+			Name:        "Empty RecipeID",
+			RecipeID:    "",
+			Expectation: &summary.YeastExpectation{Yeast: "US-05"},
+			Error:       true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			err = store.AddYeastExpectation(tc.RecipeID, tc.Expectation)
+			if tc.Error {
+				require.Error(err)
+				return
+			}
+			require.NoError(err)
+			var stored string
+			require.NoError(db.QueryRow(`SELECT yeast_expectation FROM summaries WHERE recipe_id = ?`, tc.RecipeID).Scan(&stored))
+			var actual summary.YeastExpectation
+			require.NoError(json.Unmarshal([]byte(stored), &actual))
+			require.Equal(*tc.Expectation, actual)
+		})
+	}
+}
 func TestAddMainFermentationAlcohol(t *testing.T) {
 	require := require.New(t)
 	fileName := strings.ToLower(strings.TrimSpace(t.Name())) + ".sqlite"
@@ -1867,7 +2040,10 @@ func TestGetSummary(t *testing.T) {
 				},
 				HoppingInfo: &summary.HoppingInfo{
 					VolBeforeBoil: &summary.VolMeasurement{Volume: 12.5, Notes: "notes 4"},
-					VolAfterBoil:  &summary.VolMeasurement{Volume: 9.4, Notes: "notes 5"},
+					BoilAdjustment: &summary.BoilAdjustment{
+						Decision: summary.BoilDecisionTopUp, PreBoilSG: 1.046, PostBoilSG: 1.056, PostBoilVolume: 11.2, TopUpWater: 0.8, Notes: "notes 4b",
+					},
+					VolAfterBoil: &summary.VolMeasurement{Volume: 9.4, Notes: "notes 5"},
 					HopInfos: []*summary.HopInfo{
 						{Name: "Amarillo", Grams: 20, Alpha: 6.8, Time: 60, TimeUnit: "minutes", Notes: "notes 6"},
 						{Name: "Galaxy", Grams: 40, Alpha: 5.8, Time: 10, TimeUnit: "minutes", Notes: "notes 7"},
@@ -1880,6 +2056,14 @@ func TestGetSummary(t *testing.T) {
 				},
 				YeastInfo: &summary.YeastInfo{
 					Temperature: "18-20", Notes: "notes 11",
+					Pitch: &summary.YeastPitch{
+						PitchRate: 0.75, Form: "liquid", Amount: 1, ProductionDate: "2023-01-10", Viability: 72,
+						CellsNeeded: 180, CellsAvailable: 72, StarterModel: "stir_plate", StarterVolume: 1.5, StarterDME: 150, CellsPitched: 282,
+					},
+					Expectation: &summary.YeastExpectation{
+						Yeast: "WLP001", Lab: "White Labs", Known: true, OriginalGravity: 1.054, MinAttenuation: 73, MaxAttenuation: 80,
+						MinFinalGravity: 1.011, MaxFinalGravity: 1.015, MinAlcohol: 5.1, MaxAlcohol: 5.7,
+					},
 				},
 				MainFermentationInfo: &summary.MainFermentationInfo{
 					SGs: []*summary.SGMeasurement{
@@ -1949,6 +2133,12 @@ func storeSummary(id string, summ *summary.Summary, store *SummaryPersistentStor
 	if err != nil {
 		return err
 	}
+	if summ.HoppingInfo.BoilAdjustment != nil {
+		err = store.AddBoilAdjustment(id, summ.HoppingInfo.BoilAdjustment)
+		if err != nil {
+			return err
+		}
+	}
 	err = store.AddVolumeAfterBoil(id, summ.HoppingInfo.VolAfterBoil.Volume, summ.HoppingInfo.VolAfterBoil.Notes)
 	if err != nil {
 		return err
@@ -1967,6 +2157,18 @@ func storeSummary(id string, summ *summary.Summary, store *SummaryPersistentStor
 	if err != nil {
 		return err
 	}
+	if summ.YeastInfo.Pitch != nil {
+		err = store.AddYeastPitch(id, summ.YeastInfo.Pitch)
+		if err != nil {
+			return err
+		}
+	}
+	if summ.YeastInfo.Expectation != nil {
+		err = store.AddYeastExpectation(id, summ.YeastInfo.Expectation)
+		if err != nil {
+			return err
+		}
+	}
 	for _, sg := range summ.MainFermentationInfo.SGs {
 		err = store.AddMainFermentationSGMeasurement(id, sg.Date, sg.SG, sg.Final, sg.Notes)
 		if err != nil {
@@ -2057,14 +2259,30 @@ func TestGetAllStats(t *testing.T) {
 				require.Error(err)
 			} else {
 				require.NoError(err)
-				for title, stat := range tc.Stats {
-					require.Equal(stat, actualStats[title])
+				actualStats = brewStats(actualStats)
+				require.Len(actualStats, len(tc.Stats))
+				for _, stat := range actualStats {
+					expected := *tc.Stats[stat.RecipeID]
+					expected.RecipeName = stat.RecipeID
+					expected.RecipeID = stat.RecipeID
+					require.Equal(&expected, stat)
 				}
 			}
 		})
 	}
 }
 
+// brewStats filters the stats of brews done in the app, skipping the ones seeded by the migrations
+func brewStats(stats []*summary.Statistics) []*summary.Statistics {
+	var res []*summary.Statistics
+	for _, s := range stats {
+		if s.RecipeID != "" {
+			res = append(res, s)
+		}
+	}
+	return res
+}
+
 func storeStats(stats map[string]*summary.Statistics, store *SummaryPersistentStore) error {
 	for id, stat := range stats {
 		err := store.AddSummary(id, id)
@@ -2207,7 +2425,7 @@ func TestAddStatsExternal(t *testing.T) {
 			Error: false,
 		},
 		{
-			Name: "Fail if repeated",
+			Name: "Same recipe twice",
 			ToAdd: []toAdd{
 				{
 					Name: "Recipe1", // Callers will encode, this does not handle this
@@ -2226,7 +2444,7 @@ func TestAddStatsExternal(t *testing.T) {
 					},
 				},
 			},
-			Error: true,
+			Error: false,
 		},
 	}
 	for _, tc := range testCases {
@@ -2252,12 +2470,82 @@ func TestAddStatsExternal(t *testing.T) {
 				require.Zero(errorCount)
 				realStats, err := store.GetAllStats()
 				require.NoError(err)
-				for _, s := range tc.ToAdd {
-					stats, ok := realStats[s.Name]
-					require.True(ok)
-					require.Equal(s.Stat, stats)
+				// The stats seeded by the migrations come first
+				realStats = realStats[len(realStats)-len(tc.ToAdd):]
+				for i, s := range tc.ToAdd {
+					expected := *s.Stat
+					expected.RecipeName = s.Name
+					require.Equal(&expected, realStats[i])
 				}
 			}
 		})
 	}
 }
+
+func TestStatsOfSeveralBrews(t *testing.T) {
+	require := require.New(t)
+	fileName := strings.ToLower(strings.TrimSpace(t.Name())) + ".sqlite"
+	db, err := sql.Open("sqlite3", "file:"+fileName+"?_foreign_keys=true")
+	require.NoError(err)
+	provisionDB(t, db, []string{"recipe1", "recipe2"})
+	err = dbmigrations.RunMigrations(db, "migrations")
+	require.NoError(err)
+	store, err := NewSummaryPersistentStore(db)
+	require.NoError(err)
+	defer os.Remove(fileName)
+	require.NoError(store.AddSummary("1", "Same Title"))
+	require.NoError(store.AddSummary("2", "Same Title"))
+	require.NoError(store.AddEfficiency("1", 60))
+	require.NoError(store.AddEfficiency("2", 70))
+	require.NoError(store.AddEvaporation("2", 12))
+	sum, err := store.GetSummary("1")
+	require.NoError(err)
+	require.Equal(float32(60), sum.Statistics.Efficiency)
+	require.Zero(sum.Statistics.Evaporation)
+	sum, err = store.GetSummary("2")
+	require.NoError(err)
+	require.Equal(float32(70), sum.Statistics.Efficiency)
+	require.Equal(float32(12), sum.Statistics.Evaporation)
+	stats, err := store.GetAllStats()
+	require.NoError(err)
+	stats = brewStats(stats)
+	require.Len(stats, 2)
+	for _, s := range stats {
+		require.Equal("Same Title", s.RecipeName)
+	}
+	require.Equal("1", stats[0].RecipeID)
+	require.Equal("2", stats[1].RecipeID)
+}
+
+func TestLinkLegacyStats(t *testing.T) {
+	require := require.New(t)
+	fileName := strings.ToLower(strings.TrimSpace(t.Name())) + ".sqlite"
+	db, err := sql.Open("sqlite3", "file:"+fileName+"?_foreign_keys=true")
+	require.NoError(err)
+	provisionDB(t, db, []string{"recipe1"})
+	err = dbmigrations.RunMigrations(db, "migrations")
+	require.NoError(err)
+	defer os.Remove(fileName)
+	// Stats as they were stored when they were identified by the title
+	_, err = db.Exec(`INSERT INTO summaries (title, recipe_id) VALUES (?, ?)`, "t1", "1")
+	require.NoError(err)
+	_, err = db.Exec(`INSERT INTO stats (recipe_title, efficiency) VALUES (?, ?)`, tools.B64Encode("t1"), 55)
+	require.NoError(err)
+	store, err := NewSummaryPersistentStore(db)
+	require.NoError(err)
+	require.NoError(store.AddEvaporation("1", 10))
+	sum, err := store.GetSummary("1")
+	require.NoError(err)
+	require.Equal(float32(55), sum.Statistics.Efficiency)
+	require.Equal(float32(10), sum.Statistics.Evaporation)
+	// External stats with the same title added later are not linked
+	require.NoError(store.AddStatsExternal("t1", &summary.Statistics{Efficiency: 80}))
+	store, err = NewSummaryPersistentStore(db)
+	require.NoError(err)
+	stats, err := store.GetAllStats()
+	require.NoError(err)
+	stats = stats[len(stats)-2:]
+	require.Equal("1", stats[0].RecipeID)
+	require.Equal("t1", stats[1].RecipeName)
+	require.Empty(stats[1].RecipeID)
+}
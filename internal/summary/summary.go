@@ -39,8 +39,33 @@ type LauternInfo struct {
 
 type HoppingInfo struct {
 	VolBeforeBoil *VolMeasurement
-	HopInfos      []*HopInfo
-	VolAfterBoil  *VolMeasurement
+	// BoilAdjustment is nil if no correction was proposed for the boil
+	BoilAdjustment *BoilAdjustment
+	HopInfos       []*HopInfo
+	VolAfterBoil   *VolMeasurement
+}
+
+const (
+	// BoilDecisionKeep keeps the planned boil
+	BoilDecisionKeep = "keep"
+	// BoilDecisionExtend extends the boil to evaporate more water
+	BoilDecisionExtend = "extend"
+	// BoilDecisionTopUp adds water before the boil
+	BoilDecisionTopUp = "top_up"
+)
+
+// BoilAdjustment is the correction decided after measuring the volume before boiling
+// The expected values are the ones predicted for the planned boil
+type BoilAdjustment struct {
+	Decision       string  `json:"decision,omitempty"`
+	PreBoilSG      float32 `json:"pre_boil_sg,omitempty"`
+	PostBoilSG     float32 `json:"post_boil_sg,omitempty"`
+	PostBoilVolume float32 `json:"post_boil_volume,omitempty"`
+	// ExtraBoilTime is in minutes
+	ExtraBoilTime float32 `json:"extra_boil_time,omitempty"`
+	// TopUpWater is in liters
+	TopUpWater float32 `json:"top_up_water,omitempty"`
+	Notes      string  `json:"notes,omitempty"`
 }
 
 type VolMeasurement struct {
@@ -72,6 +97,54 @@ type PreFermentationInfo struct {
 type YeastInfo struct {
 	Temperature string //String to allow for ranges like 18-20
 	Notes       string
+	// Pitch is nil if the pitch rate was not calculated
+	Pitch *YeastPitch
+	// Expectation is nil if the expected final gravity was not calculated
+	Expectation *YeastExpectation
+}
+
+// YeastPitch is the pitch rate calculation and the starter planned before pitching the yeast
+type YeastPitch struct {
+	// PitchRate is in million cells per ml and °P
+	PitchRate float32 `json:"pitch_rate,omitempty"`
+	// Form is one of the tools.YeastForm values
+	Form string `json:"form,omitempty"`
+	// Amount is in grams for dry yeast and in packs for liquid yeast
+	Amount float32 `json:"amount,omitempty"`
+	// ProductionDate is in the format 2006-01-02, empty if unknown
+	ProductionDate string `json:"production_date,omitempty"`
+	// Viability is in %
+	Viability float32 `json:"viability,omitempty"`
+	// CellsNeeded and CellsAvailable are in billions
+	CellsNeeded    float32 `json:"cells_needed,omitempty"`
+	CellsAvailable float32 `json:"cells_available,omitempty"`
+	// StarterModel is one of the tools.StarterModel values
+	StarterModel string `json:"starter_model,omitempty"`
+	// StarterVolume is in liters and StarterDME in grams
+	StarterVolume float32 `json:"starter_volume,omitempty"`
+	StarterDME    float32 `json:"starter_dme,omitempty"`
+	// CellsPitched are the cells in billions after the starter
+	CellsPitched float32 `json:"cells_pitched,omitempty"`
+}
+
+// YeastExpectation is the final gravity and alcohol expected from the attenuation of the yeast when it was pitched
+type YeastExpectation struct {
+	// Yeast is the name of the yeast. Lab is its laboratory, empty if the yeast is not in the yeast database
+	Yeast string `json:"yeast,omitempty"`
+	Lab   string `json:"lab,omitempty"`
+	// Known is false if the attenuation of the yeast is not known and a default one was assumed
+	Known bool `json:"known,omitempty"`
+	// OriginalGravity is in SG
+	OriginalGravity float32 `json:"original_gravity,omitempty"`
+	// MinAttenuation and MaxAttenuation are the apparent attenuation range in %
+	MinAttenuation float32 `json:"min_attenuation,omitempty"`
+	MaxAttenuation float32 `json:"max_attenuation,omitempty"`
+	// MinFinalGravity and MaxFinalGravity are in SG
+	MinFinalGravity float32 `json:"min_final_gravity,omitempty"`
+	MaxFinalGravity float32 `json:"max_final_gravity,omitempty"`
+	// MinAlcohol and MaxAlcohol are in %vol
+	MinAlcohol float32 `json:"min_alcohol,omitempty"`
+	MaxAlcohol float32 `json:"max_alcohol,omitempty"`
 }
 
 type MainFermentationInfo struct {
@@ -105,7 +178,13 @@ type SecondaryFermentationInfo struct {
 	Notes string
 }
 
+// Statistics are the values of a brew kept to compare it with other brews
+// Several brews of the same recipe have the same RecipeName but different RecipeID
 type Statistics struct {
+	// RecipeName is the name of the brewed recipe
+	RecipeName string
+	// RecipeID identifies the brew. It is empty for statistics of brews done outside the app
+	RecipeID     string
 	Evaporation  float32
 	Efficiency   float32
 	FinishedTime time.Time
@@ -0,0 +1,99 @@
+package memory
+
+import (
+	"errors"
+	"maps"
+	"sort"
+	"sync"
+
+	"brewday/internal/temperature"
+)
+
+// TemperatureMemoryStore represents a temperature store stored in memory
+type TemperatureMemoryStore struct {
+	lock        sync.Mutex
+	assignments map[string]string
+	readings    map[string][]*temperature.Reading
+}
+
+// NewTemperatureMemoryStore creates a new TemperatureMemoryStore
+func NewTemperatureMemoryStore() *TemperatureMemoryStore {
+	return &TemperatureMemoryStore{
+		assignments: make(map[string]string),
+		readings:    make(map[string][]*temperature.Reading),
+	}
+}
+
+// AssignSensor assigns a temperature sensor to the recipe whose fermentation it measures. An empty recipe id removes the assignment
+func (s *TemperatureMemoryStore) AssignSensor(sensor, recipeID string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if recipeID == "" {
+		delete(s.assignments, sensor)
+		return nil
+	}
+	s.assignments[sensor] = recipeID
+	return nil
+}
+
+// RetrieveSensorRecipe retrieves the id of the recipe a temperature sensor is assigned to
+// It returns an empty id if the sensor is not assigned
+func (s *TemperatureMemoryStore) RetrieveSensorRecipe(sensor string) (string, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.assignments[sensor], nil
+}
+
+// ListAssignments lists the recipe ids of the assigned temperature sensors, by sensor
+func (s *TemperatureMemoryStore) ListAssignments() (map[string]string, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return maps.Clone(s.assignments), nil
+}
+
+// AddReading adds a temperature reading to a recipe
+func (s *TemperatureMemoryStore) AddReading(recipeID string, reading *temperature.Reading) error {
+	if recipeID == "" {
+		return errors.New("invalid empty recipe id for adding temperature reading")
+	}
+	if reading == nil {
+		return errors.New("invalid nil temperature reading")
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	r := *reading
+	s.readings[recipeID] = append(s.readings[recipeID], &r)
+	return nil
+}
+
+// RetrieveReadings retrieves the temperature readings of a recipe, from the oldest to the newest
+func (s *TemperatureMemoryStore) RetrieveReadings(recipeID string) ([]*temperature.Reading, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	readings := make([]*temperature.Reading, 0, len(s.readings[recipeID]))
+	for _, r := range s.readings[recipeID] {
+		reading := *r
+		readings = append(readings, &reading)
+	}
+	sort.SliceStable(readings, func(i, j int) bool {
+		return readings[i].Date.Before(readings[j].Date)
+	})
+	return readings, nil
+}
+
+// RetrieveLastReading retrieves the newest temperature reading of a recipe, nil if there is none
+func (s *TemperatureMemoryStore) RetrieveLastReading(recipeID string) (*temperature.Reading, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	var last *temperature.Reading
+	for _, r := range s.readings[recipeID] {
+		if last == nil || !r.Date.Before(last.Date) {
+			last = r
+		}
+	}
+	if last == nil {
+		return nil, nil
+	}
+	reading := *last
+	return &reading, nil
+}
@@ -0,0 +1,236 @@
+package temperature
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// mqttKeepAlive is the default keep alive interval sent to the broker
+	mqttKeepAlive = 60 * time.Second
+	// mqttRetryInterval is how long to wait before connecting again after losing the connection to the broker
+	mqttRetryInterval = 30 * time.Second
+	// mqttDialTimeout is how long to wait for the connection to the broker
+	mqttDialTimeout = 10 * time.Second
+	// mqttDisconnectWait is how many milliseconds the client waits for pending work when it disconnects
+	mqttDisconnectWait = 250
+	// mqttMaxClientID is the longest client id every MQTT 3.1.1 broker accepts
+	mqttMaxClientID = 23
+)
+
+// MQTTSource reads the temperatures of a sensor published to an MQTT topic
+// It subscribes with QoS 0 and a clean session, the connection is handled by the Eclipse Paho client
+type MQTTSource struct {
+	// Sensor is the name of the sensor
+	Sensor string
+	// Broker is the address of the broker, tcp://host:port (or mqtt://) or ssl://host:port (or mqtts:// and tls://)
+	// The port defaults to 1883, or 8883 with TLS
+	Broker string
+	// Topic is the topic the readings are published to. It can have wildcards
+	Topic string
+	// Field is the OPTIONAL dot separated path of the temperature in a JSON message (e.g. DS18B20-2.Temperature)
+	Field string
+	// Username and Password are the OPTIONAL credentials for the broker
+	Username string
+	Password string
+	// KeepAlive is the OPTIONAL longest time without a packet from the app before the broker drops the connection
+	// Defaults to one minute
+	KeepAlive time.Duration
+}
+
+// keepAlive returns the keep alive interval of the connection, in whole seconds as sent to the broker
+func (s *MQTTSource) keepAlive() time.Duration {
+	if s.KeepAlive < time.Second {
+		return mqttKeepAlive
+	}
+	return s.KeepAlive.Truncate(time.Second)
+}
+
+// Run subscribes to the topic and records the temperature of every message until the context is cancelled
+// When the connection to the broker fails or is lost, it connects again after a while
+func (s *MQTTSource) Run(ctx context.Context, record func(Reading)) error {
+	opts, err := s.clientOptions(record)
+	if err != nil {
+		return err
+	}
+	client := mqtt.NewClient(opts)
+	// With the connect retry the token only completes once connected, the errors are logged by the handlers
+	client.Connect()
+	<-ctx.Done()
+	client.Disconnect(mqttDisconnectWait)
+	return nil
+}
+
+// clientOptions returns the options of the MQTT client, which subscribes to the topic each time it connects
+func (s *MQTTSource) clientOptions(record func(Reading)) (*mqtt.ClientOptions, error) {
+	broker, err := parseBroker(s.Broker)
+	if err != nil {
+		return nil, err
+	}
+	opts := mqtt.NewClientOptions().
+		AddBroker(broker).
+		SetClientID(s.clientID()).
+		SetUsername(s.Username).
+		SetPassword(s.Password).
+		SetCleanSession(true).
+		SetKeepAlive(s.keepAlive()).
+		SetConnectTimeout(mqttDialTimeout).
+		SetConnectRetry(true).
+		SetConnectRetryInterval(mqttRetryInterval).
+		SetAutoReconnect(true).
+		SetMaxReconnectInterval(mqttRetryInterval)
+	opts.SetOnConnectHandler(func(client mqtt.Client) {
+		token := client.Subscribe(s.Topic, 0, s.messageHandler(record))
+		token.Wait()
+		if token.Error() != nil {
+			log.Error().Str("sensor", s.Sensor).Str("topic", s.Topic).Err(token.Error()).Msg("could not subscribe to MQTT topic")
+			return
+		}
+		log.Info().Str("sensor", s.Sensor).Str("topic", s.Topic).Msg("subscribed to MQTT topic")
+	})
+	opts.SetConnectionLostHandler(func(_ mqtt.Client, err error) {
+		log.Error().Str("sensor", s.Sensor).Str("broker", s.Broker).Err(err).Msg("lost connection to MQTT broker")
+	})
+	return opts, nil
+}
+
+// messageHandler returns the handler that records the temperature of the messages of the topic
+func (s *MQTTSource) messageHandler(record func(Reading)) mqtt.MessageHandler {
+	return func(_ mqtt.Client, m mqtt.Message) {
+		temperature, err := ParsePayload(m.Payload(), s.Field)
+		if err != nil {
+			log.Error().Str("sensor", s.Sensor).Str("topic", m.Topic()).Err(err).Msg("invalid temperature in MQTT message")
+			return
+		}
+		record(Reading{Sensor: s.Sensor, Temperature: temperature, Date: time.Now()})
+	}
+}
+
+// clientID returns the id of the app for the broker, made of the letters and digits of the sensor name
+func (s *MQTTSource) clientID() string {
+	id := []byte("brewday")
+	for _, c := range []byte(strings.ToLower(s.Sensor)) {
+		if len(id) >= mqttMaxClientID {
+			break
+		}
+		if (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') {
+			id = append(id, c)
+		}
+	}
+	return string(id)
+}
+
+// parseBroker returns the URL of the broker for the client, with its scheme and port
+func parseBroker(broker string) (string, error) {
+	if !strings.Contains(broker, "://") {
+		broker = "tcp://" + broker
+	}
+	u, err := url.Parse(broker)
+	if err != nil {
+		return "", fmt.Errorf("invalid MQTT broker %s: %w", broker, err)
+	}
+	scheme := "tcp"
+	port := "1883"
+	switch u.Scheme {
+	case "tcp", "mqtt":
+	case "ssl", "tls", "mqtts":
+		scheme = "ssl"
+		port = "8883"
+	default:
+		return "", fmt.Errorf("invalid MQTT broker scheme %s", u.Scheme)
+	}
+	if u.Hostname() == "" {
+		return "", fmt.Errorf("invalid MQTT broker %s: no host", broker)
+	}
+	if u.Port() != "" {
+		port = u.Port()
+	}
+	return scheme + "://" + net.JoinHostPort(u.Hostname(), port), nil
+}
+
+// ParsePayload returns the temperature in °C of an MQTT message
+// The message is either the temperature alone (e.g. 19.5) or a JSON object. With a field (e.g. DS18B20-2.Temperature)
+// the temperature is read from that path, otherwise from the first temperature field in the order of the keys, at the
+// top level or in a nested object (e.g. {"DS18B20": {"Temperature": 19.5}} as sent by Tasmota)
+func ParsePayload(payload []byte, field string) (float32, error) {
+	text := strings.TrimSpace(string(payload))
+	value, err := strconv.ParseFloat(text, 32)
+	if err == nil {
+		return finiteTemperature(value, text)
+	}
+	var object map[string]any
+	err = json.Unmarshal([]byte(text), &object)
+	if err != nil {
+		return 0, fmt.Errorf("invalid temperature %q", text)
+	}
+	var ok bool
+	if field != "" {
+		value, ok = fieldTemperature(object, strings.Split(field, "."))
+	} else {
+		value, ok = findTemperature(object)
+	}
+	if !ok {
+		return 0, fmt.Errorf("no temperature in %q", text)
+	}
+	return finiteTemperature(value, text)
+}
+
+// finiteTemperature returns the temperature, or an error if it is not a finite number (e.g. NaN)
+func finiteTemperature(value float64, text string) (float32, error) {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return 0, fmt.Errorf("invalid temperature %q", text)
+	}
+	return float32(value), nil
+}
+
+// fieldTemperature returns the number at the path of keys in a JSON object
+func fieldTemperature(object map[string]any, path []string) (float64, bool) {
+	var value any = object
+	for _, key := range path {
+		nested, ok := value.(map[string]any)
+		if !ok {
+			return 0, false
+		}
+		value, ok = nested[key]
+		if !ok {
+			return 0, false
+		}
+	}
+	number, ok := value.(float64)
+	return number, ok
+}
+
+// findTemperature returns the number in the temperature field of a JSON object, looking into nested objects
+// The keys are sorted so that a message with several sensors always gives the same one
+func findTemperature(object map[string]any) (float64, bool) {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if number, ok := object[key].(float64); ok && strings.EqualFold(key, "temperature") {
+			return number, true
+		}
+	}
+	for _, key := range keys {
+		if nested, ok := object[key].(map[string]any); ok {
+			temperature, found := findTemperature(nested)
+			if found {
+				return temperature, true
+			}
+		}
+	}
+	return 0, false
+}
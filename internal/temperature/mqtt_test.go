@@ -0,0 +1,131 @@
+package temperature
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testMessage is an MQTT message received by the handler
+type testMessage struct {
+	topic   string
+	payload string
+}
+
+func (m *testMessage) Duplicate() bool   { return false }
+func (m *testMessage) Qos() byte         { return 0 }
+func (m *testMessage) Retained() bool    { return false }
+func (m *testMessage) Topic() string     { return m.topic }
+func (m *testMessage) MessageID() uint16 { return 0 }
+func (m *testMessage) Payload() []byte   { return []byte(m.payload) }
+func (m *testMessage) Ack()              {}
+
+func TestMQTTSourceOptions(t *testing.T) {
+	require := require.New(t)
+	source := &MQTTSource{
+		Sensor:   "Fermentation Fridge #1",
+		Broker:   "mqtts://broker.example.com",
+		Topic:    "brewery/fridge/#",
+		Username: "brewer",
+		Password: "secret",
+	}
+	opts, err := source.clientOptions(func(Reading) {})
+	require.NoError(err)
+	require.Len(opts.Servers, 1)
+	require.Equal("ssl://broker.example.com:8883", opts.Servers[0].String())
+	require.Equal("brewdayfermentationfrid", opts.ClientID)
+	require.Equal("brewer", opts.Username)
+	require.Equal("secret", opts.Password)
+	require.True(opts.CleanSession)
+	require.Equal(mqttKeepAlive, time.Duration(opts.KeepAlive)*time.Second)
+	// The keep alive is sent in whole seconds
+	source.KeepAlive = 90500 * time.Millisecond
+	opts, err = source.clientOptions(func(Reading) {})
+	require.NoError(err)
+	require.Equal(int64(90), opts.KeepAlive)
+
+	_, err = (&MQTTSource{Broker: "http://mosquitto"}).clientOptions(func(Reading) {})
+	require.Error(err)
+}
+
+func TestMQTTMessageHandler(t *testing.T) {
+	require := require.New(t)
+	var readings []Reading
+	handler := (&MQTTSource{Sensor: "fridge"}).messageHandler(func(r Reading) { readings = append(readings, r) })
+	handler(nil, &testMessage{topic: "brewery/fridge/temperature", payload: "19.5"})
+	handler(nil, &testMessage{topic: "brewery/fridge/state", payload: "on"})
+	handler(nil, &testMessage{topic: "tele/fridge/SENSOR", payload: `{"Time": "2026-10-17T08:00:00", "DS18B20": {"Id": "0000075A1B2C", "Temperature": 18.75}}`})
+	require.Len(readings, 2)
+	require.Equal("fridge", readings[0].Sensor)
+	require.Equal(float32(19.5), readings[0].Temperature)
+	require.Equal(float32(18.75), readings[1].Temperature)
+	require.False(readings[1].Date.IsZero())
+}
+
+func TestParseBroker(t *testing.T) {
+	type testCase struct {
+		Broker   string
+		Expected string
+		Error    bool
+	}
+	testCases := []testCase{
+		{Broker: "tcp://mosquitto:1884", Expected: "tcp://mosquitto:1884"},
+		{Broker: "mqtt://mosquitto", Expected: "tcp://mosquitto:1883"},
+		{Broker: "mosquitto", Expected: "tcp://mosquitto:1883"},
+		{Broker: "192.168.1.10:1883", Expected: "tcp://192.168.1.10:1883"},
+		{Broker: "mqtts://broker.example.com", Expected: "ssl://broker.example.com:8883"},
+		{Broker: "ssl://broker.example.com:8884", Expected: "ssl://broker.example.com:8884"},
+		{Broker: "http://mosquitto", Error: true},
+		{Broker: "tcp://", Error: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Broker, func(t *testing.T) {
+			require := require.New(t)
+			actual, err := parseBroker(tc.Broker)
+			if tc.Error {
+				require.Error(err)
+				return
+			}
+			require.NoError(err)
+			require.Equal(tc.Expected, actual)
+		})
+	}
+}
+
+func TestParsePayload(t *testing.T) {
+	type testCase struct {
+		Name     string
+		Payload  string
+		Field    string
+		Expected float32
+		Error    bool
+	}
+	testCases := []testCase{
+		{Name: "Number", Payload: " 19.5\n", Expected: 19.5},
+		{Name: "Negative number", Payload: "-1.5", Expected: -1.5},
+		{Name: "JSON", Payload: `{"temperature": 18, "humidity": 60}`, Expected: 18},
+		{Name: "Nested JSON", Payload: `{"Time": "2026-10-17T08:00:00", "DS18B20": {"Temperature": 12.25}, "TempUnit": "C"}`, Expected: 12.25},
+		{Name: "Several probes in the order of the keys", Payload: `{"DS18B20-2": {"Temperature": 4}, "DS18B20-1": {"Temperature": 19.5}}`, Expected: 19.5},
+		{Name: "Several probes with a field", Payload: `{"DS18B20-2": {"Temperature": 4}, "DS18B20-1": {"Temperature": 19.5}}`, Field: "DS18B20-2.Temperature", Expected: 4},
+		{Name: "Missing field", Payload: `{"DS18B20-1": {"Temperature": 19.5}}`, Field: "DS18B20-2.Temperature", Error: true},
+		{Name: "Field that is not a number", Payload: `{"DS18B20-1": {"Temperature": 19.5}}`, Field: "DS18B20-1", Error: true},
+		{Name: "NaN", Payload: "NaN", Error: true},
+		{Name: "Infinity", Payload: "-Inf", Error: true},
+		{Name: "JSON without temperature", Payload: `{"humidity": 60}`, Error: true},
+		{Name: "Temperature as text", Payload: `{"temperature": "18"}`, Error: true},
+		{Name: "Text", Payload: "on", Error: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			require := require.New(t)
+			actual, err := ParsePayload([]byte(tc.Payload), tc.Field)
+			if tc.Error {
+				require.Error(err)
+				return
+			}
+			require.NoError(err)
+			require.Equal(tc.Expected, actual)
+		})
+	}
+}
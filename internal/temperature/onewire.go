@@ -0,0 +1,86 @@
+package temperature
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// OneWireSource reads a 1-Wire temperature sensor (e.g. a DS18B20) from its sysfs file
+type OneWireSource struct {
+	// Sensor is the name of the sensor
+	Sensor string
+	// Path is the w1_slave or temperature file of the sensor
+	Path string
+	// Interval is how often the sensor is read
+	Interval time.Duration
+}
+
+// Run reads the sensor right away and then every interval until the context is cancelled
+// Failed reads are logged and skipped, the sensor may come back
+func (s *OneWireSource) Run(ctx context.Context, record func(Reading)) error {
+	if s.Interval <= 0 {
+		return fmt.Errorf("invalid interval %s for 1-Wire sensor %s", s.Interval, s.Sensor)
+	}
+	ticker := time.NewTicker(s.Interval)
+	defer ticker.Stop()
+	for {
+		temperature, err := ReadOneWire(s.Path)
+		if err != nil {
+			log.Error().Str("sensor", s.Sensor).Err(err).Msg("could not read 1-Wire sensor")
+		} else {
+			record(Reading{Sensor: s.Sensor, Temperature: temperature, Date: time.Now()})
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// ReadOneWire reads the temperature in °C of a 1-Wire sensor from its sysfs file
+func ReadOneWire(path string) (float32, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	return ParseOneWire(string(content))
+}
+
+// ParseOneWire parses the content of the sysfs file of a 1-Wire sensor and returns the temperature in °C
+// The w1_slave file has a line with the CRC check and a line ending with t= and the temperature in m°C:
+//
+//	72 01 4b 46 7f ff 0e 10 57 : crc=57 YES
+//	72 01 4b 46 7f ff 0e 10 57 t=23125
+//
+// The temperature file of newer kernels only has the temperature in m°C
+func ParseOneWire(content string) (float32, error) {
+	content = strings.TrimSpace(content)
+	raw := content
+	if strings.Contains(content, "crc=") {
+		lines := strings.Split(content, "\n")
+		if !strings.HasSuffix(strings.TrimSpace(lines[0]), "YES") {
+			return 0, fmt.Errorf("1-Wire reading failed the CRC check")
+		}
+		i := strings.LastIndex(content, "t=")
+		if i < 0 {
+			return 0, fmt.Errorf("1-Wire reading has no temperature")
+		}
+		raw = content[i+2:]
+	}
+	milli, err := strconv.Atoi(strings.TrimSpace(raw))
+	if err != nil {
+		return 0, fmt.Errorf("invalid 1-Wire temperature %q: %w", raw, err)
+	}
+	// 85 °C is the power-on value of a DS18B20, it means the conversion did not happen
+	if milli == 85000 {
+		return 0, fmt.Errorf("1-Wire sensor returned its power-on value")
+	}
+	return float32(milli) / 1000, nil
+}
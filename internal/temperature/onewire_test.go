@@ -0,0 +1,100 @@
+package temperature
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOneWire(t *testing.T) {
+	type testCase struct {
+		Name     string
+		Content  string
+		Expected float32
+		Error    bool
+	}
+	testCases := []testCase{
+		{
+			Name:     "w1_slave file",
+			Content:  "72 01 4b 46 7f ff 0e 10 57 : crc=57 YES\n72 01 4b 46 7f ff 0e 10 57 t=23125\n",
+			Expected: 23.125,
+		},
+		{
+			Name:     "Negative temperature",
+			Content:  "5e ff 4b 46 7f ff 02 10 0d : crc=0d YES\n5e ff 4b 46 7f ff 02 10 0d t=-10125\n",
+			Expected: -10.125,
+		},
+		{
+			Name:     "temperature file",
+			Content:  "18062\n",
+			Expected: 18.062,
+		},
+		{
+			Name:    "Failed CRC check",
+			Content: "72 01 4b 46 7f ff 0e 10 57 : crc=12 NO\n72 01 4b 46 7f ff 0e 10 57 t=23125\n",
+			Error:   true,
+		},
+		{
+			Name:    "Power-on value",
+			Content: "50 05 4b 46 7f ff 0c 10 1c : crc=1c YES\n50 05 4b 46 7f ff 0c 10 1c t=85000\n",
+			Error:   true,
+		},
+		{
+			Name:    "No temperature",
+			Content: "72 01 4b 46 7f ff 0e 10 57 : crc=57 YES\n",
+			Error:   true,
+		},
+		{
+			Name:    "Empty file",
+			Content: "",
+			Error:   true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			require := require.New(t)
+			actual, err := ParseOneWire(tc.Content)
+			if tc.Error {
+				require.Error(err)
+				return
+			}
+			require.NoError(err)
+			require.Equal(tc.Expected, actual)
+		})
+	}
+}
+
+func TestOneWireSourceRun(t *testing.T) {
+	require := require.New(t)
+	path := filepath.Join(t.TempDir(), "w1_slave")
+	require.NoError(os.WriteFile(path, []byte("72 01 4b 46 7f ff 0e 10 57 : crc=57 YES\n72 01 4b 46 7f ff 0e 10 57 t=19500\n"), 0o644))
+	source := &OneWireSource{Sensor: "fridge", Path: path, Interval: 10 * time.Millisecond}
+	readings := make(chan Reading, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- source.Run(ctx, func(r Reading) {
+			select {
+			case readings <- r:
+			default:
+			}
+		})
+	}()
+	first := <-readings
+	require.Equal("fridge", first.Sensor)
+	require.Equal(float32(19.5), first.Temperature)
+	require.NoError(os.WriteFile(path, []byte("20250"), 0o644))
+	require.Eventually(func() bool {
+		return (<-readings).Temperature == 20.25
+	}, time.Second, time.Millisecond)
+	cancel()
+	require.NoError(<-done)
+
+	_, err := ReadOneWire(filepath.Join(t.TempDir(), "missing"))
+	require.Error(err)
+	require.Error((&OneWireSource{Sensor: "fridge", Path: path}).Run(context.Background(), func(Reading) {}))
+}
@@ -0,0 +1,47 @@
+package temperature
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrSourceNotRunning is returned when a temperature is pushed to a source that is not running
+var ErrSourceNotRunning = errors.New("temperature source is not running")
+
+// PushSource is the source of a sensor whose readings are pushed to the app, e.g. by an HTTP request
+type PushSource struct {
+	sensor string
+	lock   sync.Mutex
+	record func(Reading)
+}
+
+// NewPushSource creates a PushSource for the sensor
+func NewPushSource(sensor string) *PushSource {
+	return &PushSource{sensor: sensor}
+}
+
+// Run passes the pushed temperatures to record until the context is cancelled
+func (s *PushSource) Run(ctx context.Context, record func(Reading)) error {
+	s.lock.Lock()
+	s.record = record
+	s.lock.Unlock()
+	<-ctx.Done()
+	s.lock.Lock()
+	s.record = nil
+	s.lock.Unlock()
+	return nil
+}
+
+// Push records a temperature in °C read at the given date
+func (s *PushSource) Push(temperature float32, date time.Time) error {
+	s.lock.Lock()
+	record := s.record
+	s.lock.Unlock()
+	if record == nil {
+		return ErrSourceNotRunning
+	}
+	record(Reading{Sensor: s.sensor, Temperature: temperature, Date: date})
+	return nil
+}
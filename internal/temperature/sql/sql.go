@@ -0,0 +1,113 @@
+package sql
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"brewday/internal/temperature"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+type TemperaturePersistentStore struct {
+	dbClient *sql.DB
+}
+
+// NewTemperaturePersistentStore creates a new TemperatureStore
+func NewTemperaturePersistentStore(db *sql.DB) (*TemperaturePersistentStore, error) {
+	return &TemperaturePersistentStore{
+		dbClient: db,
+	}, nil
+}
+
+// AssignSensor assigns a temperature sensor to the recipe whose fermentation it measures. An empty recipe id removes the assignment
+func (s *TemperaturePersistentStore) AssignSensor(sensor, recipeID string) error {
+	if recipeID == "" {
+		_, err := s.dbClient.Exec(`DELETE FROM temperature_sensors WHERE sensor == ?`, sensor)
+		return err
+	}
+	_, err := s.dbClient.Exec(`INSERT INTO temperature_sensors (sensor, recipe_id) VALUES (?, ?) ON CONFLICT(sensor) DO UPDATE SET recipe_id = excluded.recipe_id`,
+		sensor, recipeID)
+	return err
+}
+
+// RetrieveSensorRecipe retrieves the id of the recipe a temperature sensor is assigned to
+// It returns an empty id if the sensor is not assigned
+func (s *TemperaturePersistentStore) RetrieveSensorRecipe(sensor string) (string, error) {
+	var recipeID string
+	err := s.dbClient.QueryRow(`SELECT recipe_id FROM temperature_sensors WHERE sensor == ?`, sensor).Scan(&recipeID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	return recipeID, err
+}
+
+// ListAssignments lists the recipe ids of the assigned temperature sensors, by sensor
+func (s *TemperaturePersistentStore) ListAssignments() (map[string]string, error) {
+	rows, err := s.dbClient.Query(`SELECT sensor, recipe_id FROM temperature_sensors`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	assignments := make(map[string]string)
+	for rows.Next() {
+		var sensor, recipeID string
+		err := rows.Scan(&sensor, &recipeID)
+		if err != nil {
+			return nil, err
+		}
+		assignments[sensor] = recipeID
+	}
+	return assignments, rows.Err()
+}
+
+// AddReading adds a temperature reading to a recipe
+func (s *TemperaturePersistentStore) AddReading(recipeID string, reading *temperature.Reading) error {
+	if recipeID == "" {
+		return errors.New("invalid empty recipe id for adding temperature reading")
+	}
+	if reading == nil {
+		return errors.New("invalid nil temperature reading")
+	}
+	_, err := s.dbClient.Exec(`INSERT INTO temperature_readings (sensor, temperature, timestamp_unix, recipe_id) VALUES (?, ?, ?, ?)`,
+		reading.Sensor, reading.Temperature, reading.Date.Unix(), recipeID)
+	return err
+}
+
+// RetrieveReadings retrieves the temperature readings of a recipe, from the oldest to the newest
+func (s *TemperaturePersistentStore) RetrieveReadings(recipeID string) ([]*temperature.Reading, error) {
+	rows, err := s.dbClient.Query(`SELECT sensor, temperature, timestamp_unix FROM temperature_readings WHERE recipe_id == ? ORDER BY timestamp_unix ASC, id ASC`, recipeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	readings := []*temperature.Reading{}
+	for rows.Next() {
+		reading := &temperature.Reading{}
+		var ts int64
+		err := rows.Scan(&reading.Sensor, &reading.Temperature, &ts)
+		if err != nil {
+			return nil, err
+		}
+		reading.Date = time.Unix(ts, 0)
+		readings = append(readings, reading)
+	}
+	return readings, rows.Err()
+}
+
+// RetrieveLastReading retrieves the newest temperature reading of a recipe, nil if there is none
+func (s *TemperaturePersistentStore) RetrieveLastReading(recipeID string) (*temperature.Reading, error) {
+	row := s.dbClient.QueryRow(`SELECT sensor, temperature, timestamp_unix FROM temperature_readings WHERE recipe_id == ? ORDER BY timestamp_unix DESC, id DESC LIMIT 1`, recipeID)
+	reading := &temperature.Reading{}
+	var ts int64
+	err := row.Scan(&reading.Sensor, &reading.Temperature, &ts)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	reading.Date = time.Unix(ts, 0)
+	return reading, nil
+}
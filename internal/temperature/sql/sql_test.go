@@ -0,0 +1,92 @@
+package sql
+
+import (
+	"database/sql"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	dbmigrations "brewday/internal/db_migrations"
+	"brewday/internal/temperature"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestStore(t *testing.T) (*TemperaturePersistentStore, *sql.DB) {
+	fileName := strings.ToLower(strings.TrimSpace(t.Name())) + ".sqlite"
+	db, err := sql.Open("sqlite3", "file:"+fileName+"?_foreign_keys=true")
+	require.NoError(t, err)
+	require.NoError(t, dbmigrations.RunMigrations(db, "migrations"))
+	for _, name := range []string{"Helles", "Weizen"} {
+		_, err = db.Exec(`INSERT INTO recipes (name, status) VALUES (?, ?)`, name, 0)
+		require.NoError(t, err)
+	}
+	store, err := NewTemperaturePersistentStore(db)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		db.Close()
+		os.Remove(fileName)
+	})
+	return store, db
+}
+
+func TestAssignSensor(t *testing.T) {
+	require := require.New(t)
+	store, db := newTestStore(t)
+	id, err := store.RetrieveSensorRecipe("fridge")
+	require.NoError(err)
+	require.Empty(id)
+	require.NoError(store.AssignSensor("fridge", "1"))
+	require.NoError(store.AssignSensor("fridge", "2"))
+	require.NoError(store.AssignSensor("chamber", "1"))
+	require.Error(store.AssignSensor("cellar", "100"))
+	id, err = store.RetrieveSensorRecipe("fridge")
+	require.NoError(err)
+	require.Equal("2", id)
+	assignments, err := store.ListAssignments()
+	require.NoError(err)
+	require.Equal(map[string]string{"fridge": "2", "chamber": "1"}, assignments)
+	require.NoError(store.AssignSensor("chamber", ""))
+	_, err = db.Exec(`DELETE FROM recipes WHERE id == 2`)
+	require.NoError(err)
+	assignments, err = store.ListAssignments()
+	require.NoError(err)
+	require.Empty(assignments)
+}
+
+func TestReadings(t *testing.T) {
+	require := require.New(t)
+	store, db := newTestStore(t)
+	readings, err := store.RetrieveReadings("1")
+	require.NoError(err)
+	require.Empty(readings)
+	date := time.Unix(time.Date(2026, 10, 17, 8, 0, 0, 0, time.UTC).Unix(), 0)
+	require.NoError(store.AddReading("1", &temperature.Reading{Sensor: "fridge", Temperature: 18.5, Date: date.Add(time.Hour)}))
+	require.NoError(store.AddReading("1", &temperature.Reading{Sensor: "fridge", Temperature: 19.25, Date: date}))
+	require.NoError(store.AddReading("2", &temperature.Reading{Sensor: "chamber", Temperature: 12, Date: date}))
+	require.Error(store.AddReading("", &temperature.Reading{Sensor: "fridge", Temperature: 18, Date: date}))
+	require.Error(store.AddReading("1", nil))
+	require.Error(store.AddReading("100", &temperature.Reading{Sensor: "fridge", Temperature: 18, Date: date}))
+	readings, err = store.RetrieveReadings("1")
+	require.NoError(err)
+	require.Equal([]*temperature.Reading{
+		{Sensor: "fridge", Temperature: 19.25, Date: date},
+		{Sensor: "fridge", Temperature: 18.5, Date: date.Add(time.Hour)},
+	}, readings)
+	last, err := store.RetrieveLastReading("1")
+	require.NoError(err)
+	require.Equal(&temperature.Reading{Sensor: "fridge", Temperature: 18.5, Date: date.Add(time.Hour)}, last)
+	_, err = db.Exec(`DELETE FROM recipes WHERE id == 1`)
+	require.NoError(err)
+	readings, err = store.RetrieveReadings("1")
+	require.NoError(err)
+	require.Empty(readings)
+	last, err = store.RetrieveLastReading("1")
+	require.NoError(err)
+	require.Nil(last)
+	readings, err = store.RetrieveReadings("2")
+	require.NoError(err)
+	require.Len(readings, 1)
+}
@@ -0,0 +1,111 @@
+package temperature
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SourceType is the way the readings of a temperature sensor reach the app
+type SourceType string
+
+const (
+	// SourceTypeHTTP is a sensor whose readings are pushed to the app over HTTP (e.g. by a fermentation chamber controller)
+	SourceTypeHTTP SourceType = "http"
+	// SourceTypeOneWire is a 1-Wire sensor (e.g. a DS18B20) read from its sysfs file on the machine running the app
+	SourceTypeOneWire SourceType = "onewire"
+	// SourceTypeMQTT is a sensor whose readings are published to an MQTT topic
+	SourceTypeMQTT SourceType = "mqtt"
+)
+
+// DefaultInterval is how often a 1-Wire sensor is read if the sensor does not set it
+const DefaultInterval = time.Minute
+
+// Reading is a temperature read by a sensor
+type Reading struct {
+	// Sensor is the name of the sensor
+	Sensor string
+	// Temperature is in °C
+	Temperature float32
+	// Date is when the temperature was read
+	Date time.Time
+}
+
+// Source reads the temperatures of a sensor
+type Source interface {
+	// Run reads temperatures and passes them to record until the context is cancelled
+	// It only returns early if the source can not work at all
+	Run(ctx context.Context, record func(Reading)) error
+}
+
+// Sensor is a temperature sensor configured in the app
+type Sensor struct {
+	// Name of the sensor
+	Name string
+	// Type is how its readings reach the app
+	Type SourceType
+	// Path is the sysfs file of a 1-Wire sensor (e.g. /sys/bus/w1/devices/28-0000075a1b2c/w1_slave)
+	Path string
+	// Interval is how often a 1-Wire sensor is read
+	Interval time.Duration
+	// Broker is the address of the MQTT broker (e.g. tcp://localhost:1883)
+	Broker string
+	// Topic is the MQTT topic the readings are published to
+	Topic string
+	// Field is the OPTIONAL path of the temperature in the JSON messages of the MQTT topic (e.g. DS18B20-2.Temperature)
+	Field string
+	// Username and Password are the OPTIONAL credentials for the MQTT broker
+	Username string
+	Password string
+}
+
+// Validate checks that the sensor has what its type needs
+func (s *Sensor) Validate() error {
+	if strings.TrimSpace(s.Name) == "" {
+		return fmt.Errorf("temperature sensor name is missing")
+	}
+	switch s.Type {
+	case SourceTypeHTTP:
+	case SourceTypeOneWire:
+		if s.Path == "" {
+			return fmt.Errorf("1-Wire sensor %s has no path", s.Name)
+		}
+	case SourceTypeMQTT:
+		if s.Broker == "" || s.Topic == "" {
+			return fmt.Errorf("MQTT sensor %s needs a broker and a topic", s.Name)
+		}
+		_, err := parseBroker(s.Broker)
+		if err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("invalid temperature sensor type %s for %s", s.Type, s.Name)
+	}
+	return nil
+}
+
+// Matches returns whether a name is the one of the sensor, ignoring the case
+func (s *Sensor) Matches(name string) bool {
+	return strings.EqualFold(strings.TrimSpace(name), s.Name)
+}
+
+// NewSource creates the source that reads the temperatures of the sensor
+func NewSource(s Sensor) (Source, error) {
+	err := s.Validate()
+	if err != nil {
+		return nil, err
+	}
+	switch s.Type {
+	case SourceTypeOneWire:
+		interval := s.Interval
+		if interval <= 0 {
+			interval = DefaultInterval
+		}
+		return &OneWireSource{Sensor: s.Name, Path: s.Path, Interval: interval}, nil
+	case SourceTypeMQTT:
+		return &MQTTSource{Sensor: s.Name, Broker: s.Broker, Topic: s.Topic, Field: s.Field, Username: s.Username, Password: s.Password}, nil
+	default:
+		return NewPushSource(s.Name), nil
+	}
+}
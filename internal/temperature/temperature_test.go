@@ -0,0 +1,84 @@
+package temperature
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSensorValidate(t *testing.T) {
+	type testCase struct {
+		Name   string
+		Sensor Sensor
+		Error  bool
+	}
+	testCases := []testCase{
+		{Name: "HTTP", Sensor: Sensor{Name: "fridge", Type: SourceTypeHTTP}},
+		{Name: "1-Wire", Sensor: Sensor{Name: "fridge", Type: SourceTypeOneWire, Path: "/sys/bus/w1/devices/28-0000075a1b2c/w1_slave"}},
+		{Name: "1-Wire without path", Sensor: Sensor{Name: "fridge", Type: SourceTypeOneWire}, Error: true},
+		{Name: "MQTT", Sensor: Sensor{Name: "fridge", Type: SourceTypeMQTT, Broker: "tcp://localhost:1883", Topic: "brewery/fridge"}},
+		{Name: "MQTT without topic", Sensor: Sensor{Name: "fridge", Type: SourceTypeMQTT, Broker: "localhost"}, Error: true},
+		{Name: "MQTT with invalid broker", Sensor: Sensor{Name: "fridge", Type: SourceTypeMQTT, Broker: "http://localhost", Topic: "brewery/fridge"}, Error: true},
+		{Name: "No name", Sensor: Sensor{Name: " ", Type: SourceTypeHTTP}, Error: true},
+		{Name: "Invalid type", Sensor: Sensor{Name: "fridge", Type: "zigbee"}, Error: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			require := require.New(t)
+			err := tc.Sensor.Validate()
+			if tc.Error {
+				require.Error(err)
+				return
+			}
+			require.NoError(err)
+			source, err := NewSource(tc.Sensor)
+			require.NoError(err)
+			require.NotNil(source)
+		})
+	}
+}
+
+func TestNewSource(t *testing.T) {
+	require := require.New(t)
+	source, err := NewSource(Sensor{Name: "fridge", Type: SourceTypeOneWire, Path: "w1_slave"})
+	require.NoError(err)
+	require.Equal(&OneWireSource{Sensor: "fridge", Path: "w1_slave", Interval: DefaultInterval}, source)
+	source, err = NewSource(Sensor{Name: "fridge", Type: SourceTypeMQTT, Broker: "localhost", Topic: "fridge", Field: "DS18B20.Temperature", Username: "u", Password: "p"})
+	require.NoError(err)
+	require.Equal(&MQTTSource{Sensor: "fridge", Broker: "localhost", Topic: "fridge", Field: "DS18B20.Temperature", Username: "u", Password: "p"}, source)
+	source, err = NewSource(Sensor{Name: "fridge", Type: SourceTypeHTTP})
+	require.NoError(err)
+	require.IsType(&PushSource{}, source)
+	_, err = NewSource(Sensor{Name: "fridge"})
+	require.Error(err)
+}
+
+func TestSensorMatches(t *testing.T) {
+	require := require.New(t)
+	s := Sensor{Name: "Fridge"}
+	require.True(s.Matches("fridge "))
+	require.False(s.Matches("freezer"))
+}
+
+func TestPushSource(t *testing.T) {
+	require := require.New(t)
+	source := NewPushSource("fridge")
+	date := time.Date(2026, 10, 17, 8, 0, 0, 0, time.UTC)
+	require.ErrorIs(source.Push(18.5, date), ErrSourceNotRunning)
+
+	readings := make(chan Reading, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- source.Run(ctx, func(r Reading) { readings <- r })
+	}()
+	require.Eventually(func() bool {
+		return source.Push(18.5, date) == nil
+	}, time.Second, time.Millisecond)
+	require.Equal(Reading{Sensor: "fridge", Temperature: 18.5, Date: date}, <-readings)
+	cancel()
+	require.NoError(<-done)
+	require.ErrorIs(source.Push(18.5, date), ErrSourceNotRunning)
+}
@@ -0,0 +1,53 @@
+package tools
+
+import "math"
+
+// TinsethIBU returns the bitterness in IBU contributed by a single hop addition using Tinseth's formula
+// Input parameters are
+// - alpha: alpha acid percentage of the hop
+// - grams: amount of hops in grams
+// - minutes: boiling time of the hop in minutes
+// - gravity: specific gravity of the wort during the boil in SG
+// - volume: volume of the wort in liters
+// Based on https://realbeer.com/hops/research.html
+func TinsethIBU(alpha, grams, minutes, gravity, volume float32) float32 {
+	if volume <= 0 || minutes <= 0 {
+		return 0
+	}
+	bignessFactor := 1.65 * math.Pow(0.000125, float64(gravity-1))
+	boilTimeFactor := (1 - math.Exp(-0.04*float64(minutes))) / 4.15
+	concentration := float64(alpha / 100 * grams * 1000 / volume) // mg/l of alpha acids
+	return float32(bignessFactor * boilTimeFactor * concentration)
+}
+
+// RagerIBU returns the bitterness in IBU contributed by a single hop addition using Rager's formula
+// Input parameters are the same as for TinsethIBU
+// Based on https://www.realbeer.com/hops/FAQ.html
+func RagerIBU(alpha, grams, minutes, gravity, volume float32) float32 {
+	if volume <= 0 || minutes <= 0 {
+		return 0
+	}
+	utilization := (18.11 + 13.86*math.Tanh((float64(minutes)-31.32)/18.27)) / 100
+	var gravityAdjustment float64
+	if gravity > 1.050 {
+		gravityAdjustment = float64(gravity-1.050) / 0.2
+	}
+	concentration := float64(alpha / 100 * grams * 1000 / volume) // mg/l of alpha acids
+	return float32(utilization * concentration / (1 + gravityAdjustment))
+}
+
+// HopStandMinutes returns the boiling time in minutes that isomerizes as much alpha acid as a hop stand
+// Isomerization slows down below boiling following the Arrhenius equation, with the activation energy
+// measured by Malowicki. At 80 °C a hop stand isomerizes about 6 times slower than a boil
+// Input parameters are
+// - minutes: steep time of the hops in minutes
+// - temperature: temperature of the wort in °C
+func HopStandMinutes(minutes, temperature float32) float32 {
+	if minutes <= 0 {
+		return 0
+	}
+	const activation = 11858 // activation energy over the gas constant, in K
+	t := float64(min(temperature, 100)) + 273.15
+	relativeRate := math.Exp(activation/373.15 - activation/t)
+	return minutes * float32(relativeRate)
+}
@@ -0,0 +1,74 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTinsethIBU(t *testing.T) {
+	require := require.New(t)
+	testCases := []struct {
+		Name     string
+		Alpha    float32
+		Grams    float32
+		Minutes  float32
+		Gravity  float32
+		Volume   float32
+		Expected float32
+	}{
+		{Name: "60 min addition", Alpha: 5, Grams: 28.35, Minutes: 60, Gravity: 1.050, Volume: 18.93, Expected: 17.27},
+		{Name: "Higher gravity reduces utilization", Alpha: 5, Grams: 28.35, Minutes: 60, Gravity: 1.080, Volume: 18.93, Expected: 13.19},
+		{Name: "No boil", Alpha: 5, Grams: 28.35, Minutes: 0, Gravity: 1.050, Volume: 18.93, Expected: 0},
+		{Name: "No volume", Alpha: 5, Grams: 28.35, Minutes: 60, Gravity: 1.050, Volume: 0, Expected: 0},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			require.InDelta(tc.Expected, TinsethIBU(tc.Alpha, tc.Grams, tc.Minutes, tc.Gravity, tc.Volume), 0.05)
+		})
+	}
+}
+
+func TestRagerIBU(t *testing.T) {
+	require := require.New(t)
+	testCases := []struct {
+		Name     string
+		Alpha    float32
+		Grams    float32
+		Minutes  float32
+		Gravity  float32
+		Volume   float32
+		Expected float32
+	}{
+		{Name: "60 min addition", Alpha: 5, Grams: 28.35, Minutes: 60, Gravity: 1.050, Volume: 18.93, Expected: 23.08},
+		{Name: "Higher gravity reduces utilization", Alpha: 5, Grams: 28.35, Minutes: 60, Gravity: 1.080, Volume: 18.93, Expected: 20.07},
+		{Name: "No boil", Alpha: 5, Grams: 28.35, Minutes: 0, Gravity: 1.050, Volume: 18.93, Expected: 0},
+		{Name: "No volume", Alpha: 5, Grams: 28.35, Minutes: 60, Gravity: 1.050, Volume: 0, Expected: 0},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			require.InDelta(tc.Expected, RagerIBU(tc.Alpha, tc.Grams, tc.Minutes, tc.Gravity, tc.Volume), 0.1)
+		})
+	}
+}
+
+func TestHopStandMinutes(t *testing.T) {
+	require := require.New(t)
+	testCases := []struct {
+		Name        string
+		Minutes     float32
+		Temperature float32
+		Expected    float32
+	}{
+		{Name: "At boiling", Minutes: 20, Temperature: 100, Expected: 20},
+		{Name: "Above boiling is boiling", Minutes: 20, Temperature: 105, Expected: 20},
+		{Name: "Whirlpool at 80", Minutes: 30, Temperature: 80, Expected: 4.95},
+		{Name: "Hop stand at 70", Minutes: 30, Temperature: 70, Expected: 1.86},
+		{Name: "No steep", Minutes: 0, Temperature: 80, Expected: 0},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			require.InDelta(tc.Expected, HopStandMinutes(tc.Minutes, tc.Temperature), 0.05)
+		})
+	}
+}
@@ -78,3 +78,28 @@ func EBCtoHex(ebc float32) string {
 	srm := EBCtoSRM(ebc)
 	return SRMToHex(float64(srm))
 }
+
+// MaltColorUnits returns the malt color units (MCU) contributed by a single malt
+// Input parameters are
+// - grams: amount of malt in grams
+// - colorEBC: color of the malt in EBC
+// - volume: volume of the wort in liters
+func MaltColorUnits(grams, colorEBC, volume float32) float32 {
+	if volume <= 0 {
+		return 0
+	}
+	pounds := grams / 453.592
+	gallons := volume / 3.78541
+	lovibond := (EBCtoSRM(colorEBC) + 0.76) / 1.3546
+	return pounds * lovibond / gallons
+}
+
+// MoreyEBC returns the color of the beer in EBC given the total malt color units (MCU) using Morey's formula
+// Based on https://brewwiki.com/index.php/Estimating_Color
+func MoreyEBC(mcu float32) float32 {
+	if mcu <= 0 {
+		return 0
+	}
+	srm := 1.4922 * math.Pow(float64(mcu), 0.6859)
+	return SRMtoEBC(float32(srm))
+}
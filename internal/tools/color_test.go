@@ -0,0 +1,31 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMoreyEBC(t *testing.T) {
+	require := require.New(t)
+	testCases := []struct {
+		Name     string
+		Malts    [][2]float32
+		Volume   float32
+		Expected float32
+	}{
+		{Name: "Pilsner only", Malts: [][2]float32{{4500, 3.5}}, Volume: 20, Expected: 7.0},
+		{Name: "Pale ale with crystal", Malts: [][2]float32{{4500, 6}, {300, 120}}, Volume: 20, Expected: 15.2},
+		{Name: "No malts", Malts: nil, Volume: 20, Expected: 0},
+		{Name: "No volume", Malts: [][2]float32{{4500, 3.5}}, Volume: 0, Expected: 0},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			var mcu float32
+			for _, m := range tc.Malts {
+				mcu += MaltColorUnits(m[0], m[1], tc.Volume)
+			}
+			require.InDelta(tc.Expected, MoreyEBC(mcu), 0.1)
+		})
+	}
+}
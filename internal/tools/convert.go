@@ -1,5 +1,7 @@
 package tools
 
+import "math"
+
 // SGToPlato converts a specific gravity to a plato value
 func SGToPlato(sg float32) float32 {
 	if sg > 1.000 {
@@ -23,3 +25,29 @@ func EBCtoSRM(ebc float32) float32 {
 func SRMtoEBC(srm float32) float32 {
 	return srm * 1.97
 }
+
+// FahrenheitToCelsius converts a temperature in °F to °C
+func FahrenheitToCelsius(f float32) float32 {
+	return (f - 32) * 5 / 9
+}
+
+// CelsiusToFahrenheit converts a temperature in °C to °F
+func CelsiusToFahrenheit(c float32) float32 {
+	return c*9/5 + 32
+}
+
+// CO2VolumesToGramsPerLiter converts a carbonation level in volumes of CO2 to g/l
+func CO2VolumesToGramsPerLiter(volumes float32) float32 {
+	return volumes * 1.96
+}
+
+// GramsPerLiterToCO2Volumes converts a carbonation level in g/l to volumes of CO2
+func GramsPerLiterToCO2Volumes(gl float32) float32 {
+	return gl / 1.96
+}
+
+// RoundTo rounds a value to the given number of decimals
+func RoundTo(value float32, decimals int) float32 {
+	pow := math.Pow10(decimals)
+	return float32(math.Round(float64(value)*pow) / pow)
+}
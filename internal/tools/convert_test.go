@@ -0,0 +1,16 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoundTo(t *testing.T) {
+	require := require.New(t)
+	require.Equal(float32(71.3), RoundTo(71.26, 1))
+	require.Equal(float32(1.0484), RoundTo(1.048449, 4))
+	require.Equal(float32(24), RoundTo(23.999998, 2))
+	require.Equal(float32(-1.5), RoundTo(-1.46, 1))
+	require.Equal(float32(120), RoundTo(123, -1))
+}
@@ -37,3 +37,47 @@ func CorrectGravityAlcohol(originalGravity, apparentGravity, wcf float32) float3
 	resultPlato := (0.1808 * ogCorrected) + (0.8192 * agPlato)
 	return PlatoToSG(resultPlato)
 }
+
+// EstimateOriginalGravity returns the expected original gravity in SG of a wort
+// It is the inverse of CalculateEfficiencyPlato, so the efficiency is the brewhouse yield (Sudhausausbeute) in percent
+// Input parameters are
+// - totalMalt: total amount of malt in grams
+// - volume: volume of the wort in liters
+// - efficiency: brewhouse efficiency in percent
+func EstimateOriginalGravity(totalMalt, volume, efficiency float32) float32 {
+	if totalMalt <= 0 || volume <= 0 || efficiency <= 0 {
+		return 1
+	}
+	// plato * SG(plato) = extract, which is solved iteratively as SG depends on plato
+	extract := efficiency * totalMalt / (1000 * volume * 0.96)
+	sg := float32(1)
+	for range 10 {
+		sg = PlatoToSG(extract / sg)
+	}
+	return sg
+}
+
+// VolumeForGravity returns the volume of wort in liters that has the given gravity
+// It is the inverse of EstimateOriginalGravity for the volume, with the same units. It returns 0 if the gravity is not above 1
+func VolumeForGravity(totalMalt, gravity, efficiency float32) float32 {
+	plato := SGToPlato(gravity)
+	if plato <= 0 || totalMalt <= 0 || efficiency <= 0 {
+		return 0
+	}
+	return efficiency * totalMalt / (1000 * 0.96 * plato * gravity)
+}
+
+// ApparentAttenuation returns the apparent attenuation in percent of a beer, from its original and current gravity in SG
+// It is the share of the gravity points of the wort that the yeast fermented. It returns 0 if the original gravity is not above 1
+func ApparentAttenuation(originalGravity, gravity float32) float32 {
+	if originalGravity <= 1 {
+		return 0
+	}
+	return (originalGravity - gravity) * 100 / (originalGravity - 1)
+}
+
+// FinalGravityForAttenuation returns the final gravity in SG of a wort fermented with the given apparent attenuation in percent
+// It is the inverse of ApparentAttenuation for the gravity
+func FinalGravityForAttenuation(originalGravity, attenuation float32) float32 {
+	return originalGravity - (originalGravity-1)*attenuation/100
+}
@@ -26,3 +26,78 @@ func TestRefractometer(t *testing.T) {
 		})
 	}
 }
+
+func TestEstimateOriginalGravity(t *testing.T) {
+	require := require.New(t)
+	testCases := []struct {
+		Name       string
+		TotalMalt  float32
+		Volume     float32
+		Efficiency float32
+		Expected   float32
+	}{
+		{Name: "Typical pale ale", TotalMalt: 4500, Volume: 20, Efficiency: 65, Expected: 1.058},
+		{Name: "Lower efficiency", TotalMalt: 4500, Volume: 20, Efficiency: 55, Expected: 1.049},
+		{Name: "No malt", TotalMalt: 0, Volume: 20, Efficiency: 65, Expected: 1},
+		{Name: "No volume", TotalMalt: 4500, Volume: 0, Efficiency: 65, Expected: 1},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			actual := EstimateOriginalGravity(tc.TotalMalt, tc.Volume, tc.Efficiency)
+			require.InDelta(tc.Expected, actual, 0.001)
+			if tc.Expected > 1 {
+				// Must be consistent with the efficiency calculation
+				require.InDelta(tc.Efficiency, CalculateEfficiencySG(actual, tc.Volume, tc.TotalMalt), 0.5)
+			}
+		})
+	}
+}
+
+func TestVolumeForGravity(t *testing.T) {
+	require := require.New(t)
+	testCases := []struct {
+		Name       string
+		TotalMalt  float32
+		Gravity    float32
+		Efficiency float32
+		Expected   float32
+	}{
+		{Name: "Typical pale ale", TotalMalt: 4500, Gravity: 1.058, Efficiency: 65, Expected: 20},
+		{Name: "Pre-boil gravity", TotalMalt: 4500, Gravity: 1.049, Efficiency: 65, Expected: 23.9},
+		{Name: "Water", TotalMalt: 4500, Gravity: 1, Efficiency: 65, Expected: 0},
+		{Name: "No malt", TotalMalt: 0, Gravity: 1.058, Efficiency: 65, Expected: 0},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			actual := VolumeForGravity(tc.TotalMalt, tc.Gravity, tc.Efficiency)
+			require.InDelta(tc.Expected, actual, 0.2)
+			if tc.Expected > 0 {
+				require.InDelta(tc.Gravity, EstimateOriginalGravity(tc.TotalMalt, actual, tc.Efficiency), 0.0005)
+			}
+		})
+	}
+}
+
+func TestApparentAttenuation(t *testing.T) {
+	require := require.New(t)
+	testCases := []struct {
+		Name            string
+		OriginalGravity float32
+		Gravity         float32
+		Expected        float32
+	}{
+		{Name: "Typical ale", OriginalGravity: 1.048, Gravity: 1.012, Expected: 75},
+		{Name: "Not fermented", OriginalGravity: 1.048, Gravity: 1.048, Expected: 0},
+		{Name: "Dry", OriginalGravity: 1.050, Gravity: 0.998, Expected: 104},
+		{Name: "No original gravity", OriginalGravity: 0, Gravity: 1.012, Expected: 0},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			actual := ApparentAttenuation(tc.OriginalGravity, tc.Gravity)
+			require.InDelta(tc.Expected, actual, 0.01)
+			if tc.OriginalGravity > 1 {
+				require.InDelta(tc.Gravity, FinalGravityForAttenuation(tc.OriginalGravity, actual), 0.0001)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package tools
+
+import "strings"
+
+// ReferenceMaltPotential is the extract potential (in %) of an average base malt
+// Brewhouse efficiencies are given for malts with this potential
+const ReferenceMaltPotential float32 = 80
+
+// MaltProperties contains the typical values of a type of malt
+type MaltProperties struct {
+	// Potential is the extract potential in % of the weight
+	Potential float32
+	// Color is the typical color in EBC
+	Color float32
+}
+
+// maltTable is the lookup table for the properties of a malt based on keywords of its name
+// It is ordered so the more specific keywords are checked first (e.g. carapils before cara and pils)
+var maltTable = []struct {
+	keywords   []string
+	properties MaltProperties
+}{
+	{[]string{"carapils", "carafoam"}, MaltProperties{Potential: 75, Color: 4}},
+	{[]string{"carahell"}, MaltProperties{Potential: 75, Color: 25}},
+	{[]string{"carafa", "röst", "roest", "roasted", "black"}, MaltProperties{Potential: 65, Color: 1200}},
+	{[]string{"chocolate", "schoko"}, MaltProperties{Potential: 70, Color: 900}},
+	{[]string{"cara", "karamell", "crystal", "caramel"}, MaltProperties{Potential: 75, Color: 120}},
+	{[]string{"melanoidin"}, MaltProperties{Potential: 75, Color: 70}},
+	{[]string{"sauer", "acid"}, MaltProperties{Potential: 72, Color: 5}},
+	{[]string{"wiener", "vienna"}, MaltProperties{Potential: 80, Color: 8}},
+	{[]string{"münchner", "muenchner", "munich"}, MaltProperties{Potential: 80, Color: 20}},
+	{[]string{"weizen", "wheat"}, MaltProperties{Potential: 83, Color: 4}},
+	{[]string{"roggen", "rye"}, MaltProperties{Potential: 80, Color: 6}},
+	{[]string{"dinkel", "spelt"}, MaltProperties{Potential: 80, Color: 5}},
+	{[]string{"hafer", "oat"}, MaltProperties{Potential: 70, Color: 2}},
+	{[]string{"reis", "rice"}, MaltProperties{Potential: 85, Color: 1}},
+	{[]string{"zucker", "sugar", "dextrose", "glucose"}, MaltProperties{Potential: 100, Color: 0}},
+	{[]string{"honig", "honey"}, MaltProperties{Potential: 80, Color: 2}},
+	{[]string{"pale", "maris otter", "golden promise"}, MaltProperties{Potential: 81, Color: 6}},
+	{[]string{"pils", "lager"}, MaltProperties{Potential: 81, Color: 3.5}},
+}
+
+// LookupMalt returns the typical properties of a malt based on its name
+// If the malt is not known, it returns the properties of an average base malt and false
+func LookupMalt(name string) (MaltProperties, bool) {
+	lower := strings.ToLower(name)
+	for _, entry := range maltTable {
+		for _, keyword := range entry.keywords {
+			if strings.Contains(lower, keyword) {
+				return entry.properties, true
+			}
+		}
+	}
+	return MaltProperties{Potential: ReferenceMaltPotential}, false
+}
@@ -0,0 +1,30 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookupMalt(t *testing.T) {
+	require := require.New(t)
+	testCases := []struct {
+		Name     string
+		MaltName string
+		Expected MaltProperties
+		Known    bool
+	}{
+		{Name: "German pilsner", MaltName: "Pilsner Malz", Expected: MaltProperties{Potential: 81, Color: 3.5}, Known: true},
+		{Name: "Carapils is not a crystal malt", MaltName: "Carapils", Expected: MaltProperties{Potential: 75, Color: 4}, Known: true},
+		{Name: "Crystal", MaltName: "Crystal 60", Expected: MaltProperties{Potential: 75, Color: 120}, Known: true},
+		{Name: "Case insensitive", MaltName: "WEIZENMALZ hell", Expected: MaltProperties{Potential: 83, Color: 4}, Known: true},
+		{Name: "Unknown", MaltName: "Mystery", Expected: MaltProperties{Potential: ReferenceMaltPotential}, Known: false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			actual, known := LookupMalt(tc.MaltName)
+			require.Equal(tc.Expected, actual)
+			require.Equal(tc.Known, known)
+		})
+	}
+}
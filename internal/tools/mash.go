@@ -0,0 +1,109 @@
+package tools
+
+// GrainHeatCapacity is the specific heat of the grain relative to water
+const GrainHeatCapacity float32 = 0.41
+
+// GrainVolume is the volume in liters that one kilogram of grain adds to the mash
+const GrainVolume float32 = 0.67
+
+// BoilingTemperature is the temperature in °C of the boiling water and decoctions
+const BoilingTemperature float32 = 100
+
+// DecoctionBoilDuration is the boil duration in minutes of a decoction when the recipe does not give one
+const DecoctionBoilDuration float32 = 15
+
+// MashHeating is the way the mash is heated from one rast to the next one
+type MashHeating string
+
+const (
+	// MashHeatingDirect heats the mash tun directly (e.g. on a stove or with an electric kettle)
+	MashHeatingDirect MashHeating = "direct"
+	// MashHeatingInfusion adds boiling water to the mash
+	MashHeatingInfusion MashHeating = "infusion"
+	// MashHeatingDecoction pulls part of the mash, boils it and returns it
+	MashHeatingDecoction MashHeating = "decoction"
+)
+
+// MashHeatings are all the ways to heat the mash
+var MashHeatings = []MashHeating{MashHeatingDirect, MashHeatingInfusion, MashHeatingDecoction}
+
+// ValidMashHeating returns whether the given value is a known way to heat the mash
+func ValidMashHeating(h MashHeating) bool {
+	for _, mh := range MashHeatings {
+		if mh == h {
+			return true
+		}
+	}
+	return false
+}
+
+// StrikeTemperature returns the temperature in °C the water must have to reach the target temperature when mashing in
+// - grainKg is the weight of the grain in kg
+// - waterL is the volume of the mash water in liters
+// - grainTemp is the temperature of the grain and the mash tun in °C
+// - tunMass is the thermal mass of the mash tun in kg of water equivalent
+func StrikeTemperature(grainKg, waterL, grainTemp, targetTemp, tunMass float32) float32 {
+	if waterL <= 0 {
+		return targetTemp
+	}
+	return RoundTo(targetTemp+(GrainHeatCapacity*grainKg+tunMass)*(targetTemp-grainTemp)/waterL, 1)
+}
+
+// InfusionVolume returns the liters of water at waterTemp that must be added to raise the mash from currentTemp to targetTemp
+// The mash tun (tunMass in kg of water equivalent) is assumed to be at the temperature of the mash
+func InfusionVolume(grainKg, mashWaterL, currentTemp, targetTemp, tunMass, waterTemp float32) float32 {
+	if targetTemp <= currentTemp || waterTemp <= targetTemp {
+		return 0
+	}
+	return RoundTo((targetTemp-currentTemp)*(GrainHeatCapacity*grainKg+tunMass+mashWaterL)/(waterTemp-targetTemp), 1)
+}
+
+// MashVolume returns the volume in liters of a mash of the given grain and water
+func MashVolume(grainKg, waterL float32) float32 {
+	return waterL + GrainVolume*grainKg
+}
+
+// DecoctionVolume returns the liters of mash that must be pulled and boiled to raise the mash from currentTemp to targetTemp
+// once the decoction is returned. Heat losses are not taken into account
+func DecoctionVolume(mashVolume, currentTemp, targetTemp float32) float32 {
+	if targetTemp <= currentTemp || currentTemp >= BoilingTemperature {
+		return 0
+	}
+	return RoundTo(mashVolume*(targetTemp-currentTemp)/(BoilingTemperature-currentTemp), 1)
+}
+
+// MashStep is the heating from one temperature of the mash to the next one
+type MashStep struct {
+	// FromTemp is the temperature of the mash before the step in °C
+	FromTemp float32
+	// ToTemp is the temperature of the mash after the step in °C
+	ToTemp float32
+	// Infusion is the boiling water to add in liters, only when heating by infusion
+	Infusion float32
+	// Decoction is the mash to pull and boil in liters, only when heating by decoction
+	Decoction float32
+	// Water is the total water in the mash after the step in liters
+	Water float32
+}
+
+// PlanMashSteps calculates the steps to go from the mash in temperature through all the rast temperatures
+// When heating by infusion, the water added in a step is taken into account for the next ones
+func PlanMashSteps(heating MashHeating, grainKg, waterL, mashTemp float32, rastTemps []float32, tunMass float32) []MashStep {
+	steps := make([]MashStep, 0, len(rastTemps))
+	current := mashTemp
+	water := waterL
+	for _, target := range rastTemps {
+		step := MashStep{FromTemp: current, ToTemp: target}
+		switch heating {
+		case MashHeatingInfusion:
+			step.Infusion = InfusionVolume(grainKg, water, current, target, tunMass, BoilingTemperature)
+			water += step.Infusion
+		case MashHeatingDecoction:
+			step.Decoction = DecoctionVolume(MashVolume(grainKg, water), current, target)
+		}
+		step.Water = RoundTo(water, 1)
+		steps = append(steps, step)
+		current = target
+	}
+	return steps
+}
@@ -0,0 +1,65 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStrikeTemperature(t *testing.T) {
+	require := require.New(t)
+	testCases := []struct {
+		Name      string
+		GrainKg   float32
+		WaterL    float32
+		GrainTemp float32
+		Target    float32
+		TunMass   float32
+		Expected  float32
+	}{
+		{Name: "Without tun", GrainKg: 5, WaterL: 15, GrainTemp: 20, Target: 57, Expected: 62.1},
+		{Name: "With tun", GrainKg: 5, WaterL: 15, GrainTemp: 20, Target: 57, TunMass: 1.5, Expected: 65.8},
+		{Name: "Warm grain", GrainKg: 5, WaterL: 15, GrainTemp: 57, Target: 57, TunMass: 1.5, Expected: 57},
+		{Name: "No water", GrainKg: 5, WaterL: 0, GrainTemp: 20, Target: 57, Expected: 57},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			require.InDelta(tc.Expected, StrikeTemperature(tc.GrainKg, tc.WaterL, tc.GrainTemp, tc.Target, tc.TunMass), 0.001)
+		})
+	}
+}
+
+func TestInfusionAndDecoctionVolume(t *testing.T) {
+	require := require.New(t)
+	require.InDelta(2.8, InfusionVolume(5, 15, 57, 63, 0, 100), 0.001)
+	require.InDelta(3, InfusionVolume(5, 15, 57, 63, 1.5, 100), 0.001)
+	require.Zero(InfusionVolume(5, 15, 63, 57, 0, 100))
+	require.InDelta(18.35, MashVolume(5, 15), 0.001)
+	require.InDelta(2.6, DecoctionVolume(18.35, 57, 63), 0.001)
+	require.Zero(DecoctionVolume(18.35, 63, 63))
+}
+
+func TestPlanMashSteps(t *testing.T) {
+	require := require.New(t)
+	rasts := []float32{63, 72, 78}
+	direct := PlanMashSteps(MashHeatingDirect, 5, 15, 57, rasts, 0)
+	require.Equal([]MashStep{
+		{FromTemp: 57, ToTemp: 63, Water: 15},
+		{FromTemp: 63, ToTemp: 72, Water: 15},
+		{FromTemp: 72, ToTemp: 78, Water: 15},
+	}, direct)
+	infusion := PlanMashSteps(MashHeatingInfusion, 5, 15, 57, rasts, 0)
+	require.Equal([]MashStep{
+		{FromTemp: 57, ToTemp: 63, Infusion: 2.8, Water: 17.8},
+		{FromTemp: 63, ToTemp: 72, Infusion: 6.4, Water: 24.2},
+		{FromTemp: 72, ToTemp: 78, Infusion: 7.2, Water: 31.4},
+	}, infusion)
+	decoction := PlanMashSteps(MashHeatingDecoction, 5, 15, 57, rasts, 0)
+	require.Equal([]MashStep{
+		{FromTemp: 57, ToTemp: 63, Decoction: 2.6, Water: 15},
+		{FromTemp: 63, ToTemp: 72, Decoction: 4.5, Water: 15},
+		{FromTemp: 72, ToTemp: 78, Decoction: 3.9, Water: 15},
+	}, decoction)
+	require.True(ValidMashHeating(MashHeatingInfusion))
+	require.False(ValidMashHeating("microwave"))
+}
@@ -0,0 +1,266 @@
+package tools
+
+import "strings"
+
+// Contribution of the brewing salts in ppm of each ion per gram of salt dissolved in one liter
+const (
+	gypsumCalcium           = 232.8 // CaSO4·2H2O
+	gypsumSulfate           = 557.9 // CaSO4·2H2O
+	calciumChlorideCalcium  = 272.6 // CaCl2·2H2O
+	calciumChlorideChloride = 482.3 // CaCl2·2H2O
+	epsomMagnesium          = 98.6  // MgSO4·7H2O
+	epsomSulfate            = 389.6 // MgSO4·7H2O
+)
+
+// LacticAcidMEqPerML is the acid (in mEq) in one ml of 88% lactic acid
+const LacticAcidMEqPerML float32 = 11.8
+
+// acidMaltMEqPerGram is the acid (in mEq) of one gram of acidulated malt (around 3% lactic acid)
+const acidMaltMEqPerGram float32 = 0.33
+
+// maltBufferCapacity is the buffer capacity of the grist in mEq/(kg·pH)
+const maltBufferCapacity float32 = 40
+
+// WaterProfile is the concentration of the main ions of a water in ppm (mg/l)
+type WaterProfile struct {
+	Calcium     float32
+	Magnesium   float32
+	Sodium      float32
+	Chloride    float32
+	Sulfate     float32
+	Bicarbonate float32
+}
+
+// IsZero returns whether all the concentrations are zero (e.g. distilled water or an unknown profile)
+func (w WaterProfile) IsZero() bool {
+	return w == WaterProfile{}
+}
+
+// Alkalinity returns the alkalinity of the water in ppm as CaCO3
+func (w WaterProfile) Alkalinity() float32 {
+	return w.Bicarbonate * 50 / 61
+}
+
+// ResidualAlkalinity returns the residual alkalinity (Kolbach) in ppm as CaCO3
+func (w WaterProfile) ResidualAlkalinity() float32 {
+	return w.Alkalinity() - w.Calcium/1.4 - w.Magnesium/1.7
+}
+
+// SulfateChlorideRatio returns the ratio of sulfate to chloride. It is zero if there is no chloride
+func (w WaterProfile) SulfateChlorideRatio() float32 {
+	if w.Chloride == 0 {
+		return 0
+	}
+	return w.Sulfate / w.Chloride
+}
+
+// WaterTarget is the recommended water for a group of beer styles
+type WaterTarget struct {
+	// Name of the group of styles
+	Name string
+	// Profile is the target water profile
+	Profile WaterProfile
+	// MashPH is the target mash pH
+	MashPH float32
+}
+
+// DefaultWaterTarget is the target for styles that are not in the table, a balanced profile
+var DefaultWaterTarget = WaterTarget{
+	Name:    "Balanced",
+	Profile: WaterProfile{Calcium: 75, Magnesium: 10, Sodium: 15, Chloride: 75, Sulfate: 75},
+	MashPH:  5.4,
+}
+
+// waterTargetTable is the lookup table for the target water based on keywords of the style
+// It is ordered so the more specific styles are checked first (e.g. dunkelweizen before dunkel)
+var waterTargetTable = []struct {
+	keywords []string
+	target   WaterTarget
+}{
+	{[]string{"weizen", "weiß", "weiss", "wheat", "wit"}, WaterTarget{
+		Name:    "Wheat",
+		Profile: WaterProfile{Calcium: 60, Magnesium: 5, Sodium: 10, Chloride: 70, Sulfate: 40},
+		MashPH:  5.4,
+	}},
+	{[]string{"stout", "porter", "schwarz", "dunkel", "bock", "black"}, WaterTarget{
+		Name:    "Dark",
+		Profile: WaterProfile{Calcium: 80, Magnesium: 10, Sodium: 30, Chloride: 100, Sulfate: 60},
+		MashPH:  5.5,
+	}},
+	{[]string{"ipa", "pale", "bitter", "hop"}, WaterTarget{
+		Name:    "Hoppy",
+		Profile: WaterProfile{Calcium: 110, Magnesium: 15, Sodium: 10, Chloride: 60, Sulfate: 250},
+		MashPH:  5.3,
+	}},
+	{[]string{"märzen", "maerzen", "marzen", "oktoberfest", "amber", "brown", "scotch", "altbier"}, WaterTarget{
+		Name:    "Malty",
+		Profile: WaterProfile{Calcium: 70, Magnesium: 10, Sodium: 20, Chloride: 100, Sulfate: 50},
+		MashPH:  5.4,
+	}},
+	{[]string{"pils", "hell", "lager", "kölsch", "koelsch", "export"}, WaterTarget{
+		Name:    "Light lager",
+		Profile: WaterProfile{Calcium: 50, Magnesium: 5, Sodium: 5, Chloride: 50, Sulfate: 60},
+		MashPH:  5.4,
+	}},
+}
+
+// LookupWaterTarget returns the target water for a beer style based on its name
+// If the style is not known, it returns the DefaultWaterTarget and false
+func LookupWaterTarget(style string) (WaterTarget, bool) {
+	lower := strings.ToLower(style)
+	for _, entry := range waterTargetTable {
+		for _, keyword := range entry.keywords {
+			if strings.Contains(lower, keyword) {
+				return entry.target, true
+			}
+		}
+	}
+	return DefaultWaterTarget, false
+}
+
+// GristMalt is a malt of the grist used to estimate the mash pH
+type GristMalt struct {
+	Name string
+	// Amount in grams
+	Amount float32
+	// Color in EBC. If it is zero, a typical color based on the name is used
+	Color float32
+}
+
+// isAcidMalt returns whether the malt is acidulated malt (Sauermalz)
+func isAcidMalt(lowerName string) bool {
+	return strings.Contains(lowerName, "sauer") || strings.Contains(lowerName, "acid")
+}
+
+// isCrystalMalt returns whether the malt is a crystal (caramel) malt
+func isCrystalMalt(lowerName string) bool {
+	for _, k := range []string{"carafa", "carapils", "carafoam"} {
+		if strings.Contains(lowerName, k) {
+			return false
+		}
+	}
+	for _, k := range []string{"cara", "karamell", "crystal", "caramel"} {
+		if strings.Contains(lowerName, k) {
+			return true
+		}
+	}
+	return false
+}
+
+// MaltDistilledWaterPH returns the pH of a mash of the malt with distilled water
+// It is approximated from the color (in EBC) and the type of malt. If the color is zero, a typical one based on the name is used
+// Acidulated malt is treated as a base malt, its acid is taken into account in EstimateMashPH
+func MaltDistilledWaterPH(name string, colorEBC float32) float32 {
+	lower := strings.ToLower(name)
+	if colorEBC == 0 {
+		properties, _ := LookupMalt(name)
+		colorEBC = properties.Color
+	}
+	lovibond := (EBCtoSRM(colorEBC) + 0.76) / 1.3546
+	switch {
+	case isAcidMalt(lower):
+		return 5.7
+	case colorEBC >= 500:
+		return 4.7
+	case isCrystalMalt(lower):
+		return 5.22 - 0.00504*lovibond
+	default:
+		return 5.72 - 0.0086*lovibond
+	}
+}
+
+// EstimateMashPH estimates the pH of the mash of the grist with the given water
+// - mashVolume is the water used for mashing in liters
+// - acid is the acid added to the mash in mEq (e.g. from lactic acid)
+// The distilled water pH of the malts is shifted by the residual alkalinity of the water and the acid, using the buffer capacity of the grist
+func EstimateMashPH(grist []GristMalt, water WaterProfile, mashVolume, acid float32) float32 {
+	var weight, weightedPH, maltAcid float32
+	for _, m := range grist {
+		if m.Amount <= 0 {
+			continue
+		}
+		weight += m.Amount
+		weightedPH += m.Amount * MaltDistilledWaterPH(m.Name, m.Color)
+		if isAcidMalt(strings.ToLower(m.Name)) {
+			maltAcid += m.Amount * acidMaltMEqPerGram
+		}
+	}
+	if weight == 0 {
+		return 0
+	}
+	kg := weight / 1000
+	alkalinity := water.ResidualAlkalinity() / 50 * mashVolume
+	ph := weightedPH/weight + (alkalinity-maltAcid-acid)/(maltBufferCapacity*kg)
+	return RoundTo(ph, 2)
+}
+
+// WaterAdjustment contains the additions to reach a target water and the resulting mash pH
+type WaterAdjustment struct {
+	// Target is the water that should be reached
+	Target WaterTarget
+	// Gypsum is the amount of CaSO4·2H2O in grams for all the water
+	Gypsum float32
+	// CalciumChloride is the amount of CaCl2·2H2O in grams for all the water
+	CalciumChloride float32
+	// Epsom is the amount of MgSO4·7H2O in grams for all the water
+	Epsom float32
+	// LacticAcid is the amount of 88% lactic acid in ml for the mash water
+	LacticAcid float32
+	// Result is the water profile after adding the salts
+	Result WaterProfile
+	// MashPH is the estimated mash pH with the salts but without acid
+	MashPH float32
+	// AdjustedMashPH is the estimated mash pH with the salts and the lactic acid
+	AdjustedMashPH float32
+}
+
+// AdjustWater calculates the salt and lactic acid additions to get close to the target water and mash pH
+// - Epsom salt is used for the missing magnesium, gypsum for the missing sulfate and calcium chloride for the missing chloride
+// - Salts are only added, ions that are over the target (like sodium or bicarbonate) are not reduced
+// - Lactic acid is added to the mash water if the estimated mash pH is over the target
+// Volumes are in liters, the salts are given for both the mash and the sparge water
+func AdjustWater(source WaterProfile, target WaterTarget, mashVolume, spargeVolume float32, grist []GristMalt) WaterAdjustment {
+	adj := WaterAdjustment{Target: target}
+	result := source
+	epsom := positive(target.Profile.Magnesium-result.Magnesium) / epsomMagnesium
+	result.Magnesium += epsom * epsomMagnesium
+	result.Sulfate += epsom * epsomSulfate
+	gypsum := positive(target.Profile.Sulfate-result.Sulfate) / gypsumSulfate
+	result.Calcium += gypsum * gypsumCalcium
+	result.Sulfate += gypsum * gypsumSulfate
+	calciumChloride := positive(target.Profile.Chloride-result.Chloride) / calciumChlorideChloride
+	result.Calcium += calciumChloride * calciumChlorideCalcium
+	result.Chloride += calciumChloride * calciumChlorideChloride
+	volume := mashVolume + spargeVolume
+	adj.Epsom = RoundTo(epsom*volume, 1)
+	adj.Gypsum = RoundTo(gypsum*volume, 1)
+	adj.CalciumChloride = RoundTo(calciumChloride*volume, 1)
+	adj.Result = WaterProfile{
+		Calcium:     RoundTo(result.Calcium, 0),
+		Magnesium:   RoundTo(result.Magnesium, 0),
+		Sodium:      RoundTo(result.Sodium, 0),
+		Chloride:    RoundTo(result.Chloride, 0),
+		Sulfate:     RoundTo(result.Sulfate, 0),
+		Bicarbonate: RoundTo(result.Bicarbonate, 0),
+	}
+	adj.MashPH = EstimateMashPH(grist, result, mashVolume, 0)
+	adj.AdjustedMashPH = adj.MashPH
+	if adj.MashPH > target.MashPH {
+		var weight float32
+		for _, m := range grist {
+			weight += positive(m.Amount)
+		}
+		acid := (adj.MashPH - target.MashPH) * maltBufferCapacity * weight / 1000
+		adj.LacticAcid = RoundTo(acid/LacticAcidMEqPerML, 1)
+		adj.AdjustedMashPH = EstimateMashPH(grist, result, mashVolume, adj.LacticAcid*LacticAcidMEqPerML)
+	}
+	return adj
+}
+
+// positive returns the value if it is positive, zero otherwise
+func positive(value float32) float32 {
+	if value < 0 {
+		return 0
+	}
+	return value
+}
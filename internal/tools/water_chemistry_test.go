@@ -0,0 +1,90 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWaterProfile(t *testing.T) {
+	require := require.New(t)
+	w := WaterProfile{Calcium: 70, Magnesium: 17, Chloride: 40, Sulfate: 80, Bicarbonate: 305}
+	require.InDelta(250, w.Alkalinity(), 0.01)
+	require.InDelta(190, w.ResidualAlkalinity(), 0.01)
+	require.InDelta(2, w.SulfateChlorideRatio(), 0.001)
+	require.False(w.IsZero())
+	require.True(WaterProfile{}.IsZero())
+	require.Equal(float32(0), WaterProfile{Sulfate: 10}.SulfateChlorideRatio())
+}
+
+func TestLookupWaterTarget(t *testing.T) {
+	require := require.New(t)
+	testCases := []struct {
+		Style    string
+		Expected string
+		Known    bool
+	}{
+		{Style: "Hefeweizen hell", Expected: "Wheat", Known: true},
+		{Style: "Dunkelweizen", Expected: "Wheat", Known: true},
+		{Style: "Münchner Dunkel", Expected: "Dark", Known: true},
+		{Style: "American IPA", Expected: "Hoppy", Known: true},
+		{Style: "Märzen", Expected: "Malty", Known: true},
+		{Style: "German Pils", Expected: "Light lager", Known: true},
+		{Style: "Saison", Expected: "Balanced", Known: false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Style, func(t *testing.T) {
+			target, known := LookupWaterTarget(tc.Style)
+			require.Equal(tc.Expected, target.Name)
+			require.Equal(tc.Known, known)
+		})
+	}
+}
+
+func TestEstimateMashPH(t *testing.T) {
+	require := require.New(t)
+	pale := []GristMalt{{Name: "Pilsner", Amount: 4500}, {Name: "Munich", Amount: 500}}
+	testCases := []struct {
+		Name     string
+		Grist    []GristMalt
+		Water    WaterProfile
+		Acid     float32
+		Expected float32
+	}{
+		{Name: "Pale grist with distilled water", Grist: pale, Expected: 5.7},
+		{Name: "Alkaline water raises the pH", Grist: pale, Water: WaterProfile{Calcium: 80, Bicarbonate: 250}, Expected: 5.96},
+		{Name: "Acid lowers the pH", Grist: pale, Acid: 60, Expected: 5.4},
+		{Name: "Acidulated malt lowers the pH", Grist: append([]GristMalt{{Name: "Sauermalz", Amount: 100}}, pale...), Expected: 5.54},
+		{Name: "Roasted malt lowers the pH", Grist: append([]GristMalt{{Name: "Carafa III", Amount: 500}}, pale...), Expected: 5.61},
+		{Name: "Empty grist", Grist: nil, Expected: 0},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			require.InDelta(tc.Expected, EstimateMashPH(tc.Grist, tc.Water, 18, tc.Acid), 0.011)
+		})
+	}
+}
+
+func TestAdjustWater(t *testing.T) {
+	require := require.New(t)
+	grist := []GristMalt{{Name: "Pilsner", Amount: 4500}, {Name: "Munich", Amount: 500}}
+	source := WaterProfile{Calcium: 80, Magnesium: 10, Sodium: 10, Chloride: 20, Sulfate: 30, Bicarbonate: 250}
+	adj := AdjustWater(source, DefaultWaterTarget, 18, 14, grist)
+	require.InDelta(0, adj.Epsom, 0.001)
+	require.InDelta(2.6, adj.Gypsum, 0.001)
+	require.InDelta(3.6, adj.CalciumChloride, 0.001)
+	require.Equal(WaterProfile{Calcium: 130, Magnesium: 10, Sodium: 10, Chloride: 75, Sulfate: 75, Bicarbonate: 250}, adj.Result)
+	require.InDelta(5.89, adj.MashPH, 0.001)
+	require.InDelta(8.3, adj.LacticAcid, 0.001)
+	require.InDelta(5.4, adj.AdjustedMashPH, 0.011)
+
+	// Water over the target needs no additions
+	hard := WaterProfile{Calcium: 150, Magnesium: 30, Chloride: 100, Sulfate: 100}
+	dark, _ := LookupWaterTarget("Stout")
+	adj = AdjustWater(hard, dark, 18, 14, grist)
+	require.Zero(adj.Epsom)
+	require.Zero(adj.Gypsum)
+	require.Zero(adj.CalciumChloride)
+	require.Zero(adj.LacticAcid)
+	require.Equal(adj.MashPH, adj.AdjustedMashPH)
+}
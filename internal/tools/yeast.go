@@ -0,0 +1,151 @@
+package tools
+
+import (
+	"math"
+	"time"
+)
+
+// Pitch rates in million cells per ml of wort and degree Plato
+const (
+	// PitchRateAle is the pitch rate for ales
+	PitchRateAle float32 = 0.75
+	// PitchRateStrongAle is the pitch rate for ales of high gravity
+	PitchRateStrongAle float32 = 1
+	// PitchRateLager is the pitch rate for lagers
+	PitchRateLager float32 = 1.5
+	// PitchRateStrongLager is the pitch rate for lagers of high gravity
+	PitchRateStrongLager float32 = 2
+)
+
+// DryYeastCells is the number of cells in billions in a gram of dry yeast
+const DryYeastCells float32 = 10
+
+// LiquidYeastCells is the number of cells in billions in a fresh pack of liquid yeast
+const LiquidYeastCells float32 = 100
+
+// StarterDMEPerLiter is the dry malt extract in grams for each liter of starter, for a gravity of about 1.037
+const StarterDMEPerLiter float32 = 100
+
+// StarterStep is the difference in liters between the starter sizes considered when planning a starter
+const StarterStep float32 = 0.5
+
+// MaxStarterVolume is the biggest starter in liters considered when planning a starter
+const MaxStarterVolume float32 = 5
+
+// YeastForm is the form in which the yeast is bought
+type YeastForm string
+
+const (
+	// YeastFormDry is dry yeast, its amount is in grams
+	YeastFormDry YeastForm = "dry"
+	// YeastFormLiquid is liquid yeast, its amount is in packs
+	YeastFormLiquid YeastForm = "liquid"
+)
+
+// StarterModel is the model used to predict the growth of the yeast in a starter
+type StarterModel string
+
+const (
+	// StarterNone pitches the yeast directly
+	StarterNone StarterModel = "none"
+	// StarterSimple is a starter that is only shaken from time to time (Chris White's model)
+	StarterSimple StarterModel = "simple"
+	// StarterStirPlate is a starter on a stir plate (Kai Troester's model)
+	StarterStirPlate StarterModel = "stir_plate"
+)
+
+// StarterModels are all the ways to grow the yeast before pitching
+var StarterModels = []StarterModel{StarterNone, StarterSimple, StarterStirPlate}
+
+// viabilityLoss is the fraction of the cells that die each day, by form of the yeast
+var viabilityLoss = map[YeastForm]float32{
+	YeastFormDry:    0.0001,
+	YeastFormLiquid: 0.007,
+}
+
+// CellsNeeded returns the cells in billions to pitch in the given liters of wort
+// The pitch rate is in million cells per ml and °P
+func CellsNeeded(pitchRate, plato, volume float32) float32 {
+	return RoundTo(pitchRate*plato*volume, 1)
+}
+
+// YeastViability returns the fraction of the cells that are alive in yeast produced on the given date
+// Dry yeast barely loses viability, liquid yeast loses 0.7 % per day
+func YeastViability(form YeastForm, production, now time.Time) float32 {
+	if production.IsZero() || !production.Before(now) {
+		return 1
+	}
+	days := float32(now.Sub(production).Hours() / 24)
+	return RoundTo(positive(1-viabilityLoss[form]*days), 2)
+}
+
+// YeastCells returns the living cells in billions of the given amount of yeast
+// The amount is in grams for dry yeast and in packs for liquid yeast
+func YeastCells(form YeastForm, amount, viability float32) float32 {
+	perUnit := DryYeastCells
+	if form == YeastFormLiquid {
+		perUnit = LiquidYeastCells
+	}
+	return RoundTo(amount*perUnit*viability, 1)
+}
+
+// StarterGrowth returns the new cells in billions grown from the given cells (in billions) in a starter of the given liters
+func StarterGrowth(model StarterModel, cells, volume float32) float32 {
+	if cells <= 0 || volume <= 0 {
+		return 0
+	}
+	switch model {
+	case StarterSimple:
+		// Growth factor from the inoculation rate in million cells per ml
+		inoculation := float64(cells / volume)
+		growth := 12.54793776*math.Pow(inoculation, -0.4594858324) - 0.9994994906
+		return RoundTo(cells*float32(min(max(growth, 0), 6)), 1)
+	case StarterStirPlate:
+		// The growth depends on the cells per gram of extract
+		extract := volume * StarterDMEPerLiter
+		perGram := cells / extract
+		switch {
+		case perGram < 1.4:
+			return RoundTo(1.4*extract, 1)
+		case perGram < 3.5:
+			return RoundTo(extract*(2.33-0.67*perGram), 1)
+		}
+	}
+	return 0
+}
+
+// StarterPlan is the starter needed to grow enough cells
+type StarterPlan struct {
+	// Volume of the starter in liters
+	Volume float32
+	// DME is the dry malt extract in grams
+	DME float32
+	// Cells are the cells in billions after the starter
+	Cells float32
+	// Enough is false if even the biggest starter does not grow enough cells
+	Enough bool
+}
+
+// PlanStarter returns the smallest starter that grows the cells (in billions) to the needed ones
+// It returns a plan without a volume if there are already enough cells, and the biggest starter if none is enough
+func PlanStarter(model StarterModel, cells, needed float32) StarterPlan {
+	if cells >= needed {
+		return StarterPlan{Cells: cells, Enough: true}
+	}
+	plan := StarterPlan{Cells: cells}
+	if model != StarterSimple && model != StarterStirPlate {
+		return plan
+	}
+	for volume := StarterStep; volume <= MaxStarterVolume; volume += StarterStep {
+		plan = StarterPlan{
+			Volume: volume,
+			DME:    volume * StarterDMEPerLiter,
+			Cells:  RoundTo(cells+StarterGrowth(model, cells, volume), 1),
+		}
+		if plan.Cells >= needed {
+			plan.Enough = true
+			return plan
+		}
+	}
+	return plan
+}
@@ -0,0 +1,74 @@
+package tools
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCellsNeeded(t *testing.T) {
+	require := require.New(t)
+	require.InDelta(180, CellsNeeded(PitchRateAle, 12, 20), 0.001)
+	require.InDelta(360, CellsNeeded(PitchRateLager, 12, 20), 0.001)
+	require.Zero(CellsNeeded(PitchRateAle, 12, 0))
+}
+
+func TestYeastViability(t *testing.T) {
+	require := require.New(t)
+	now := time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		Name       string
+		Form       YeastForm
+		Production time.Time
+		Expected   float32
+	}{
+		{Name: "Liquid two months old", Form: YeastFormLiquid, Production: now.AddDate(0, 0, -60), Expected: 0.58},
+		{Name: "Liquid one year old", Form: YeastFormLiquid, Production: now.AddDate(-1, 0, 0), Expected: 0},
+		{Name: "Dry one year old", Form: YeastFormDry, Production: now.AddDate(-1, 0, 0), Expected: 0.96},
+		{Name: "Unknown date", Form: YeastFormLiquid, Expected: 1},
+		{Name: "Future date", Form: YeastFormLiquid, Production: now.AddDate(0, 0, 1), Expected: 1},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			require.InDelta(tc.Expected, YeastViability(tc.Form, tc.Production, now), 0.001)
+		})
+	}
+}
+
+func TestYeastCells(t *testing.T) {
+	require := require.New(t)
+	require.InDelta(115, YeastCells(YeastFormDry, 11.5, 1), 0.001)
+	require.InDelta(116, YeastCells(YeastFormLiquid, 2, 0.58), 0.001)
+}
+
+func TestStarterGrowth(t *testing.T) {
+	require := require.New(t)
+	testCases := []struct {
+		Name     string
+		Model    StarterModel
+		Cells    float32
+		Volume   float32
+		Expected float32
+	}{
+		{Name: "Simple", Model: StarterSimple, Cells: 100, Volume: 2, Expected: 108},
+		{Name: "Stir plate", Model: StarterStirPlate, Cells: 100, Volume: 2, Expected: 280},
+		{Name: "Stir plate crowded", Model: StarterStirPlate, Cells: 300, Volume: 1, Expected: 32},
+		{Name: "Stir plate too crowded", Model: StarterStirPlate, Cells: 400, Volume: 1, Expected: 0},
+		{Name: "No starter", Model: StarterNone, Cells: 100, Volume: 2, Expected: 0},
+		{Name: "No cells", Model: StarterSimple, Cells: 0, Volume: 2, Expected: 0},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			require.InDelta(tc.Expected, StarterGrowth(tc.Model, tc.Cells, tc.Volume), 0.001)
+		})
+	}
+}
+
+func TestPlanStarter(t *testing.T) {
+	require := require.New(t)
+	require.Equal(StarterPlan{Volume: 2.5, DME: 250, Cells: 408, Enough: true}, PlanStarter(StarterStirPlate, 58, 360))
+	require.Equal(StarterPlan{Volume: 5, DME: 500, Cells: 236}, PlanStarter(StarterSimple, 58, 360))
+	require.Equal(StarterPlan{Cells: 58}, PlanStarter(StarterNone, 58, 360))
+	require.Equal(StarterPlan{Cells: 115, Enough: true}, PlanStarter(StarterSimple, 115, 93))
+}
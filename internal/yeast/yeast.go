@@ -0,0 +1,95 @@
+package yeast
+
+import (
+	_ "embed"
+	"encoding/json"
+	"strings"
+	"unicode"
+)
+
+//go:embed yeasts.json
+var yeastsJSON []byte
+
+// minKeyLength is the length of the shortest product id or name matched in a yeast name
+// Shorter keys would match by chance
+const minKeyLength = 3
+
+// Strain is a yeast strain of the database with the values given by its lab
+type Strain struct {
+	// Name of the strain as sold by the lab
+	Name string `json:"name"`
+	// Lab is the laboratory that produces the strain
+	Lab string `json:"lab"`
+	// ProductID is the code of the strain in the catalogue of the lab (e.g. US-05, WLP001 or 1056)
+	ProductID string `json:"product_id"`
+	// MinAttenuation is the lowest apparent attenuation in %
+	MinAttenuation float32 `json:"min_attenuation"`
+	// MaxAttenuation is the highest apparent attenuation in %
+	MaxAttenuation float32 `json:"max_attenuation"`
+	// MinTemperature is the lowest recommended fermentation temperature in °C
+	MinTemperature float32 `json:"min_temperature"`
+	// MaxTemperature is the highest recommended fermentation temperature in °C
+	MaxTemperature float32 `json:"max_temperature"`
+	// Flocculation is low, medium or high
+	Flocculation string `json:"flocculation"`
+}
+
+// strains is the yeast database, loaded from the embedded yeasts.json
+var strains = mustParse(yeastsJSON)
+
+func mustParse(data []byte) []Strain {
+	var s []Strain
+	if err := json.Unmarshal(data, &s); err != nil {
+		panic("yeast: invalid yeasts.json: " + err.Error())
+	}
+	return s
+}
+
+// Strains returns all the strains of the database
+func Strains() []Strain {
+	s := make([]Strain, len(strains))
+	copy(s, strains)
+	return s
+}
+
+// Lookup returns the strain of the database matching the name of a yeast in a recipe
+// A strain matches if its product id or its name is part of the name, ignoring case, spaces and punctuation
+// (e.g. "Fermentis Safale US-05" and "us05" both match US-05). When several strains match, the one with the
+// longest match wins, and a match that also names the lab wins over one that does not
+// If no strain matches, it returns false
+func Lookup(name string) (Strain, bool) {
+	compactName := compact(name)
+	best, bestScore := -1, 0
+	for i, s := range strains {
+		score := 0
+		for _, key := range []string{compact(s.ProductID), compact(s.Name)} {
+			if len(key) >= minKeyLength && strings.Contains(compactName, key) {
+				score = max(score, len(key))
+			}
+		}
+		if score == 0 {
+			continue
+		}
+		if lab := compact(s.Lab); strings.Contains(compactName, lab) {
+			score += len(lab)
+		}
+		if score > bestScore {
+			best, bestScore = i, score
+		}
+	}
+	if best < 0 {
+		return Strain{}, false
+	}
+	return strains[best], true
+}
+
+// compact returns the lower case letters and digits of a string
+func compact(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,57 @@
+package yeast
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStrains(t *testing.T) {
+	require := require.New(t)
+	all := Strains()
+	require.NotEmpty(all)
+	ids := make(map[string]bool)
+	for _, s := range all {
+		require.NotEmpty(s.Name)
+		require.NotEmpty(s.Lab, s.Name)
+		require.False(ids[compact(s.ProductID)], "duplicate product id %s", s.ProductID)
+		ids[compact(s.ProductID)] = true
+		require.Greater(s.MinAttenuation, float32(0), s.Name)
+		require.LessOrEqual(s.MinAttenuation, s.MaxAttenuation, s.Name)
+		require.LessOrEqual(s.MaxAttenuation, float32(100), s.Name)
+		require.Greater(s.MinTemperature, float32(0), s.Name)
+		require.LessOrEqual(s.MinTemperature, s.MaxTemperature, s.Name)
+		require.Contains([]string{"low", "medium", "high"}, s.Flocculation, s.Name)
+	}
+	// the database can not be changed through the returned slice
+	all[0].Name = "changed"
+	require.NotEqual("changed", Strains()[0].Name)
+}
+
+func TestLookup(t *testing.T) {
+	type testCase struct {
+		Name      string
+		Yeast     string
+		Found     bool
+		ProductID string
+	}
+	testCases := []testCase{
+		{Name: "Lab and product id", Yeast: "Fermentis Safale US-05", Found: true, ProductID: "US-05"},
+		{Name: "Product id only", Yeast: "us05", Found: true, ProductID: "US-05"},
+		{Name: "Product id with a slash", Yeast: "Saflager W34/70", Found: true, ProductID: "W-34/70"},
+		{Name: "Name without product id", Yeast: "Lallemand Nottingham", Found: true, ProductID: "Nottingham"},
+		{Name: "Numeric product id", Yeast: "Wyeast 3068 Weihenstephan Weizen", Found: true, ProductID: "3068"},
+		{Name: "Lab decides between equal names", Yeast: "Wyeast 1968 London ESB", Found: true, ProductID: "1968"},
+		{Name: "Longest name wins", Yeast: "WLP029 German Ale/Kölsch", Found: true, ProductID: "WLP029"},
+		{Name: "Unknown yeast", Yeast: "Hefe vom Nachbarn", Found: false},
+		{Name: "Empty name", Yeast: "", Found: false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			require := require.New(t)
+			strain, found := Lookup(tc.Yeast)
+			require.Equal(tc.Found, found)
+			require.Equal(tc.ProductID, strain.ProductID)
+		})
+	}
+}
@@ -4,15 +4,27 @@ import (
 	"brewday/internal/app"
 	"brewday/internal/config"
 	dbmigrations "brewday/internal/db_migrations"
+	equipment_store_memory "brewday/internal/equipment/memory"
+	equipment_store_sql "brewday/internal/equipment/sql"
+	"brewday/internal/hydrometer"
+	hydrometer_store_memory "brewday/internal/hydrometer/memory"
+	hydrometer_store_sql "brewday/internal/hydrometer/sql"
+	inventory_store_memory "brewday/internal/inventory/memory"
+	inventory_store_sql "brewday/internal/inventory/sql"
 	"brewday/internal/notifications/gotify"
 	"brewday/internal/notifications/ha"
+	"brewday/internal/recipe"
 	"brewday/internal/render"
 	recipe_store_memory "brewday/internal/store/memory"
 	recipe_store_sql "brewday/internal/store/sql"
 	summary_store_memory "brewday/internal/summary/memory"
 	summary_store_sql "brewday/internal/summary/sql"
+	"brewday/internal/temperature"
+	temperature_store_memory "brewday/internal/temperature/memory"
+	temperature_store_sql "brewday/internal/temperature/sql"
 	tl_store_memory "brewday/internal/timeline/memory"
 	tl_store_sql "brewday/internal/timeline/sql"
+	"brewday/internal/tools"
 	"context"
 	"database/sql"
 	"embed"
@@ -78,10 +90,34 @@ func main() {
 			log.Fatal().Err(err).Msg("Error while initializing summary db store")
 		}
 		components.SummaryStore = ss
+		is, err := inventory_store_sql.NewInventoryPersistentStore(db)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Error while initializing inventory db store")
+		}
+		components.Inventory = is
+		es, err := equipment_store_sql.NewEquipmentPersistentStore(db)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Error while initializing equipment db store")
+		}
+		components.Equipment = es
+		hs, err := hydrometer_store_sql.NewHydrometerPersistentStore(db)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Error while initializing hydrometer db store")
+		}
+		components.Hydrometers = hs
+		ts, err := temperature_store_sql.NewTemperaturePersistentStore(db)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Error while initializing temperature db store")
+		}
+		components.Temperatures = ts
 	case "memory":
 		components.Store = recipe_store_memory.NewMemoryStore()
 		components.TL = tl_store_memory.NewTimelineMemoryStore()
 		components.SummaryStore = summary_store_memory.NewSummaryMemoryStore()
+		components.Inventory = inventory_store_memory.NewInventoryMemoryStore()
+		components.Equipment = equipment_store_memory.NewEquipmentMemoryStore()
+		components.Hydrometers = hydrometer_store_memory.NewHydrometerMemoryStore()
+		components.Temperatures = temperature_store_memory.NewTemperatureMemoryStore()
 	default:
 		log.Fatal().Msg("Invalid store type")
 	}
@@ -108,8 +144,48 @@ func main() {
 	}
 	// Add process configuration from config
 	components.Config = app.ProcessConfiguration{
-		LauternRestTimeMin: config.Process.LauternRestTimeMin,
-		RefractometerWCF:   config.Process.RefractometerWCF,
+		LauternRestTimeMin:   config.Process.LauternRestTimeMin,
+		RefractometerWCF:     config.Process.RefractometerWCF,
+		GrainTemperature:     config.Process.GrainTemperature,
+		TunThermalMass:       config.Process.TunThermalMass,
+		MashHeating:          tools.MashHeating(config.Process.MashHeating),
+		TemperatureTolerance: config.Process.TemperatureTolerance,
+		FermentationCompletion: recipe.CompletionCriteria{
+			Readings:       config.Process.StableReadings,
+			Days:           config.Process.StableDays,
+			Tolerance:      config.Process.StableTolerance,
+			MaxSlope:       config.Process.StableSlope,
+			MinAttenuation: config.Process.StableAttenuation,
+		},
+		SourceWater: tools.WaterProfile{
+			Calcium:     config.Water.Calcium,
+			Magnesium:   config.Water.Magnesium,
+			Sodium:      config.Water.Sodium,
+			Chloride:    config.Water.Chloride,
+			Sulfate:     config.Water.Sulfate,
+			Bicarbonate: config.Water.Bicarbonate,
+		},
+	}
+	for _, h := range config.Hydrometers {
+		components.Config.Hydrometers = append(components.Config.Hydrometers, hydrometer.Device{
+			Name:        h.Name,
+			Type:        hydrometer.DeviceType(h.Type),
+			Calibration: h.Calibration,
+			Unit:        hydrometer.GravityUnit(h.Unit),
+		})
+	}
+	for _, t := range config.TemperatureSensors {
+		components.Config.TemperatureSensors = append(components.Config.TemperatureSensors, temperature.Sensor{
+			Name:     t.Name,
+			Type:     temperature.SourceType(t.Type),
+			Path:     t.Path,
+			Interval: time.Duration(t.Interval) * time.Second,
+			Broker:   t.Broker,
+			Topic:    t.Topic,
+			Field:    t.Field,
+			Username: t.Username,
+			Password: t.Password,
+		})
 	}
 	app, err := app.NewApp(staticFS, components)
 	if err != nil {
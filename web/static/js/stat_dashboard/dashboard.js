@@ -14,7 +14,7 @@ function createBarChart(canvasId, label, valueKey, data, averageElementId) {
                 {
                     label: label,
                     data: filtered.map(row => row[valueKey]),
-                    customText: filtered.map(row => row.RecipeName)
+                    customText: filtered.map(row => row.BrewNumber > 1 ? `${row.RecipeName} (brew #${row.BrewNumber})` : row.RecipeName)
                 }
             ]
         },